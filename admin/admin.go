@@ -2,6 +2,7 @@
 Package admin :
 - has a rpc over http server listening on a unix socket
 - support other packages to expose any admin functionality over the above server
+- support other packages to expose admin functionality over the http admin routes, authenticated with the admin token
 
 # Example for registering admin handler from another package
 
@@ -33,6 +34,7 @@ package admin
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -67,6 +69,14 @@ func RegisterAdminHandler(name string, handler interface{}) {
 	_ = instance.rpcServer.RegisterName(name, handler) // @TODO fix ignored error
 }
 
+// RegisterHTTPHandler is used by other packages to
+// expose admin functions over the http admin routes, under HTTPPathPrefix followed by the name
+func RegisterHTTPHandler(name string, handler http.Handler) {
+	instance.httpHandlersMutex.Lock()
+	instance.httpHandlers[strings.ToLower(name)] = handler
+	instance.httpHandlersMutex.Unlock()
+}
+
 // RegisterStatusHandler expects object implementing PackageStatusHandler interface
 func RegisterStatusHandler(name string, handler PackageStatusHandler) {
 	instance.statusHandlersMutex.Lock()
@@ -77,9 +87,14 @@ func RegisterStatusHandler(name string, handler PackageStatusHandler) {
 type Admin struct {
 	statusHandlersMutex sync.RWMutex
 	statusHandlers      map[string]PackageStatusHandler
+	httpHandlersMutex   sync.RWMutex
+	httpHandlers        map[string]http.Handler
 	rpcServer           *rpc.Server
 }
 
+// HTTPPathPrefix is the path the http handlers of the packages are served under
+const HTTPPathPrefix = "/v1/admin/"
+
 var (
 	instance  *Admin
 	pkgLogger logger.Logger
@@ -88,6 +103,7 @@ var (
 func Init() {
 	instance = &Admin{
 		statusHandlers: make(map[string]PackageStatusHandler),
+		httpHandlers:   make(map[string]http.Handler),
 		rpcServer:      rpc.NewServer(),
 	}
 	_ = instance.rpcServer.Register(instance) // @TODO fix ignored error
@@ -241,6 +257,28 @@ func (*Admin) GetFormattedEnv(env string, reply *string) (err error) {
 	return nil
 }
 
+// HTTPHandler serves the http handlers registered by the packages, to be mounted under HTTPPathPrefix of the http admin routes.
+// Requests must have the admin token (Admin.token) as their basic auth username, and are all rejected if there isn't one.
+func HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := config.GetString("Admin.token", "")
+		username, _, ok := r.BasicAuth()
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(username), []byte(token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, HTTPPathPrefix), "/")
+		instance.httpHandlersMutex.RLock()
+		handler, ok := instance.httpHandlers[strings.ToLower(name)]
+		instance.httpHandlersMutex.RUnlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.StripPrefix(HTTPPathPrefix+name, handler).ServeHTTP(w, r)
+	})
+}
+
 // StartServer starts an HTTP server listening on unix socket and serving rpc communication
 func StartServer(ctx context.Context) error {
 	tmpDirPath, err := misc.CreateTMPDIR()
@@ -0,0 +1,37 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	Init()
+	RegisterHTTPHandler("Package", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	serve := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		if token != "" {
+			req.SetBasicAuth(token, "")
+		}
+		resp := httptest.NewRecorder()
+		HTTPHandler().ServeHTTP(resp, req)
+		return resp
+	}
+
+	require.Equal(t, http.StatusUnauthorized, serve("/v1/admin/package/status", "token").Code, "requests are rejected without an admin token")
+
+	t.Setenv("RSERVER_ADMIN_TOKEN", "token")
+	require.Equal(t, http.StatusUnauthorized, serve("/v1/admin/package/status", "").Code)
+	require.Equal(t, http.StatusUnauthorized, serve("/v1/admin/package/status", "other-token").Code)
+
+	resp := serve("/v1/admin/package/status", "token")
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "/status", resp.Body.String(), "the handler gets the path after its name")
+
+	require.Equal(t, http.StatusNotFound, serve("/v1/admin/other/status", "token").Code)
+}
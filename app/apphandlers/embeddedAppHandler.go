@@ -202,6 +202,12 @@ func (a *embeddedApp) StartRudderCore(ctx context.Context, options *app.Options)
 	}
 
 	proc := processor.New(ctx, &options.ClearDB, gwDBForProcessor, routerDB, batchRouterDB, errDB, multitenantStats, reportingI, transientSources, fileUploaderProvider, rsourcesService)
+	if processor.IsSuppressUserFeatureEnabled() && a.app.Features().SuppressUser != nil {
+		proc.SuppressUser, err = a.app.Features().SuppressUser.Setup(ctx, backendconfig.DefaultBackendConfig)
+		if err != nil {
+			return fmt.Errorf("could not setup suppress user feature: %w", err)
+		}
+	}
 	throttlerFactory, err := throttler.New(stats.Default)
 	if err != nil {
 		return fmt.Errorf("failed to create throttler factory: %w", err)
@@ -19,6 +19,7 @@ import (
 	"github.com/bugsnag/bugsnag-go/v2"
 	"github.com/gorilla/mux"
 
+	"github.com/rudderlabs/rudder-server/admin"
 	"github.com/rudderlabs/rudder-server/app"
 	"github.com/rudderlabs/rudder-server/app/cluster"
 	"github.com/rudderlabs/rudder-server/app/cluster/state"
@@ -211,6 +212,12 @@ func (a *processorApp) StartRudderCore(ctx context.Context, options *app.Options
 	}
 
 	p := proc.New(ctx, &options.ClearDB, gwDBForProcessor, routerDB, batchRouterDB, errDB, multitenantStats, reportingI, transientSources, fileUploaderProvider, rsourcesService)
+	if proc.IsSuppressUserFeatureEnabled() && a.app.Features().SuppressUser != nil {
+		p.SuppressUser, err = a.app.Features().SuppressUser.Setup(ctx, backendconfig.DefaultBackendConfig)
+		if err != nil {
+			return fmt.Errorf("could not setup suppress user feature: %w", err)
+		}
+	}
 	throttlerFactory, err := throttler.New(stats.Default)
 	if err != nil {
 		return fmt.Errorf("failed to create throttler factory: %w", err)
@@ -279,6 +286,7 @@ func (a *processorApp) startHealthWebHandler(ctx context.Context, db *jobsdb.Han
 	srvMux := mux.NewRouter()
 	srvMux.HandleFunc("/health", app.LivenessHandler(db))
 	srvMux.HandleFunc("/", app.LivenessHandler(db))
+	srvMux.PathPrefix(admin.HTTPPathPrefix).Handler(admin.HTTPHandler())
 	srv := &http.Server{
 		Addr:              ":" + strconv.Itoa(a.config.http.webPort),
 		Handler:           bugsnag.Handler(srvMux),
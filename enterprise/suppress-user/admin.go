@@ -0,0 +1,154 @@
+package suppression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
+)
+
+// Admin manages the suppressions over the admin interface and the http admin routes, without the control plane
+type Admin struct {
+	r *localRepository
+}
+
+// CheckInput is the user checked by [Admin.Check]
+type CheckInput struct {
+	WorkspaceID string
+	SourceID    string
+	UserID      string
+	AnonymousID string
+	Email       string
+}
+
+// Add adds the suppressions
+func (a *Admin) Add(suppressions []model.Suppression, reply *string) error {
+	if err := a.r.AddLocal(suppressions); err != nil {
+		return err
+	}
+	*reply = fmt.Sprintf("Processed %d suppressions", len(suppressions))
+	return nil
+}
+
+// Remove cancels the suppressions
+func (a *Admin) Remove(suppressions []model.Suppression, reply *string) error {
+	canceled := make([]model.Suppression, len(suppressions))
+	for i := range suppressions {
+		canceled[i] = suppressions[i]
+		canceled[i].Canceled = true
+	}
+	return a.Add(canceled, reply)
+}
+
+// Check replies whether the user is suppressed for the source
+func (a *Admin) Check(input CheckInput, reply *bool) error {
+	if input.WorkspaceID == "" {
+		input.WorkspaceID = a.r.defaultWorkspaceID
+	}
+	suppressed, err := a.r.SuppressedUser(input.WorkspaceID, input.SourceID, model.NewUser(input.UserID, input.AnonymousID, input.Email))
+	if err != nil {
+		return err
+	}
+	*reply = suppressed
+	return nil
+}
+
+// Backup writes a backup of the repository to the file at path, which can be restored or used as the seed of new repositories
+func (a *Admin) Backup(path string, reply *string) (err error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("could not create backup file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("could not close backup file: %w", closeErr)
+		}
+	}()
+	if err := a.r.Backup(f); err != nil {
+		if errors.Is(err, model.ErrNotSupported) {
+			return fmt.Errorf("backup not supported by the repository: %w", err)
+		}
+		return fmt.Errorf("could not write backup: %w", err)
+	}
+	*reply = "Backup written to " + path
+	return nil
+}
+
+// Restore restores the repository from the backup file at path
+func (a *Admin) Restore(path string, reply *string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open backup file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := a.r.Restore(f); err != nil {
+		return err
+	}
+	*reply = "Restored from " + path
+	return nil
+}
+
+// httpHandler serves the suppressions over the http admin routes:
+// GET / lists the suppressions of a workspace (workspaceId query parameter), POST / adds and POST /cancel cancels the suppressions of the body
+func (a *Admin) httpHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		root := r.URL.Path == "" || r.URL.Path == "/"
+		switch {
+		case r.Method == http.MethodGet && root:
+			a.listHandler(w, r)
+		case r.Method == http.MethodPost && root:
+			a.addHandler(w, r, a.Add)
+		case r.Method == http.MethodPost && r.URL.Path == "/cancel":
+			a.addHandler(w, r, a.Remove)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func (a *Admin) listHandler(w http.ResponseWriter, r *http.Request) {
+	workspaceID := r.URL.Query().Get("workspaceId")
+	if workspaceID == "" {
+		workspaceID = a.r.defaultWorkspaceID
+	}
+	if workspaceID == "" {
+		http.Error(w, "missing workspaceId", http.StatusBadRequest)
+		return
+	}
+	suppressions, err := a.r.List(workspaceID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	if suppressions == nil {
+		suppressions = []model.Suppression{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(suppressions)
+}
+
+// addHandler adds or cancels the suppressions of the request body, a json array
+func (a *Admin) addHandler(w http.ResponseWriter, r *http.Request, add func(suppressions []model.Suppression, reply *string) error) {
+	var suppressions []model.Suppression
+	if err := json.NewDecoder(r.Body).Decode(&suppressions); err != nil {
+		http.Error(w, fmt.Sprintf("invalid suppressions: %v", err), http.StatusBadRequest)
+		return
+	}
+	var reply string
+	if err := add(suppressions, &reply); err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	_, _ = w.Write([]byte(reply))
+}
+
+// errorStatus returns the status of the responses failing with the error of the repository
+func errorStatus(err error) int {
+	if errors.Is(err, model.ErrRestoring) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
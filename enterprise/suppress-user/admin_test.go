@@ -0,0 +1,132 @@
+package suppression
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalRepository(t *testing.T) {
+	r := newLocalRepository(NewMemoryRepository(logger.NOP), "default-ws")
+	require.NoError(t, r.Add([]model.Suppression{{WorkspaceID: "ws-1", UserID: "user-1"}}, []byte("token")))
+
+	t.Run("keeps the token", func(t *testing.T) {
+		require.NoError(t, r.AddLocal([]model.Suppression{{WorkspaceID: "ws-1", UserID: "user-2"}}))
+		token, err := r.GetToken()
+		require.NoError(t, err)
+		require.Equal(t, []byte("token"), token)
+		suppressed, err := r.Suppressed("ws-1", "user-2", "src-1")
+		require.NoError(t, err)
+		require.True(t, suppressed)
+	})
+
+	t.Run("default workspace", func(t *testing.T) {
+		require.NoError(t, r.AddLocal([]model.Suppression{{UserID: "user-3"}}))
+		suppressed, err := r.Suppressed("default-ws", "user-3", "src-1")
+		require.NoError(t, err)
+		require.True(t, suppressed)
+	})
+
+	t.Run("invalid suppressions", func(t *testing.T) {
+		require.ErrorContains(t, r.AddLocal([]model.Suppression{{UserID: "user-4"}, {WorkspaceID: "ws-1"}}), "suppression 2: empty value")
+		suppressed, err := r.Suppressed("default-ws", "user-4", "src-1")
+		require.NoError(t, err)
+		require.False(t, suppressed, "none of the suppressions is added")
+
+		multiTenant := newLocalRepository(NewMemoryRepository(logger.NOP), "")
+		require.ErrorContains(t, multiTenant.AddLocal([]model.Suppression{{UserID: "user-4"}}), "suppression 1: missing workspaceId")
+	})
+
+	t.Run("raw email", func(t *testing.T) {
+		require.NoError(t, r.AddLocal([]model.Suppression{{WorkspaceID: "ws-1", Field: model.FieldEmail, Value: " Jane@Example.com"}}))
+		suppressed, err := r.SuppressedUser("ws-1", "src-1", model.NewUser("", "", "jane@example.com"))
+		require.NoError(t, err)
+		require.True(t, suppressed)
+	})
+
+	t.Run("hashed email", func(t *testing.T) {
+		require.NoError(t, r.AddLocal([]model.Suppression{{WorkspaceID: "ws-1", Field: model.FieldEmail, Value: strings.ToUpper(model.HashEmail("john@example.com"))}}))
+		suppressed, err := r.SuppressedUser("ws-1", "src-1", model.NewUser("", "", "john@example.com"))
+		require.NoError(t, err)
+		require.True(t, suppressed)
+	})
+}
+
+func TestAdmin(t *testing.T) {
+	a := &Admin{r: newLocalRepository(NewMemoryRepository(logger.NOP), "ws-1")}
+	check := func(input CheckInput) bool {
+		var suppressed bool
+		require.NoError(t, a.Check(input, &suppressed))
+		return suppressed
+	}
+
+	var reply string
+	require.NoError(t, a.Add([]model.Suppression{
+		{UserID: "user-1"},
+		{Field: model.FieldEmail, Value: model.HashEmail("user@example.com"), SourceIDs: []string{"src-1"}},
+	}, &reply))
+	require.Equal(t, "Processed 2 suppressions", reply)
+	require.True(t, check(CheckInput{UserID: "user-1"}))
+	require.True(t, check(CheckInput{SourceID: "src-1", Email: "User@Example.com"}))
+	require.False(t, check(CheckInput{SourceID: "src-2", Email: "user@example.com"}))
+
+	backup := filepath.Join(t.TempDir(), "backup.json")
+	require.NoError(t, a.Backup(backup, &reply))
+	require.Equal(t, "Backup written to "+backup, reply)
+
+	require.NoError(t, a.Remove([]model.Suppression{{UserID: "user-1"}}, &reply))
+	require.False(t, check(CheckInput{UserID: "user-1"}))
+
+	require.NoError(t, a.Restore(backup, &reply))
+	require.Equal(t, "Restored from "+backup, reply)
+	require.True(t, check(CheckInput{UserID: "user-1"}))
+	require.True(t, check(CheckInput{SourceID: "src-1", Email: "user@example.com"}))
+
+	require.ErrorContains(t, a.Restore(filepath.Join(t.TempDir(), "missing.json"), &reply), "could not open backup file")
+}
+
+func TestAdminHTTPHandler(t *testing.T) {
+	handler := (&Admin{r: newLocalRepository(NewMemoryRepository(logger.NOP), "ws-1")}).httpHandler()
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return resp
+	}
+	list := func(path string) []model.Suppression {
+		t.Helper()
+		resp := serve(http.MethodGet, path, "")
+		require.Equal(t, http.StatusOK, resp.Code)
+		var suppressions []model.Suppression
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &suppressions))
+		return suppressions
+	}
+
+	require.Empty(t, list("/"))
+
+	resp := serve(http.MethodPost, "/", `[{"userId":"user-1"},{"workspaceId":"ws-2","field":"anonymousId","match":"prefix","value":"test-","sourceIds":["src-1"]}]`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, "Processed 2 suppressions", resp.Body.String())
+	require.Equal(t, []model.Suppression{
+		{WorkspaceID: "ws-1", UserID: "user-1", Field: model.FieldUserID, Match: model.MatchExact, Value: "user-1", SourceIDs: []string{}},
+	}, list("/"), "the default workspace is listed without a workspaceId")
+	require.Equal(t, []model.Suppression{
+		{WorkspaceID: "ws-2", Field: model.FieldAnonymousID, Match: model.MatchPrefix, Value: "test-", SourceIDs: []string{"src-1"}},
+	}, list("/?workspaceId=ws-2"))
+
+	resp = serve(http.MethodPost, "/cancel", `[{"userId":"user-1"}]`)
+	require.Equal(t, http.StatusOK, resp.Code)
+	require.Empty(t, list("/"))
+
+	require.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/", `{"userId":"user-1"}`).Code, "the body is an array of suppressions")
+	resp = serve(http.MethodPost, "/", `[{"workspaceId":"ws-1"}]`)
+	require.Equal(t, http.StatusBadRequest, resp.Code)
+	require.Contains(t, resp.Body.String(), "suppression 1: empty value")
+	require.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/", "").Code)
+}
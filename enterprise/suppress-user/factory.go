@@ -1,19 +1,26 @@
 package suppression
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"path"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
+	"github.com/rudderlabs/rudder-server/admin"
+
 	"github.com/rudderlabs/rudder-server/config"
 	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
 	"github.com/rudderlabs/rudder-server/rruntime"
 	"github.com/rudderlabs/rudder-server/utils/logger"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/utils/types"
+	"github.com/rudderlabs/rudder-server/utils/types/deployment"
 )
 
 type Factory struct {
@@ -40,8 +47,17 @@ func (m *Factory) Setup(ctx context.Context, backendConfig backendconfig.Backend
 		}
 		path := path.Join(tmpDir, "suppression")
 
-		// TODO: implement seeder source, to retrieve the initial state from a persisted backup
+		// the initial state can be seeded from a backup, e.g. one taken through the admin interface
 		var seederSource func() (io.Reader, error)
+		if seedFile := config.GetString("BackendConfig.Regulations.seedFile", ""); seedFile != "" {
+			seederSource = func() (io.Reader, error) {
+				data, err := os.ReadFile(seedFile)
+				if err != nil {
+					return nil, fmt.Errorf("could not read seed file: %w", err)
+				}
+				return bytes.NewReader(data), nil
+			}
+		}
 
 		repository, err = NewBadgerRepository(
 			path,
@@ -55,26 +71,58 @@ func (m *Factory) Setup(ctx context.Context, backendConfig backendconfig.Backend
 		repository = NewMemoryRepository(m.Log)
 	}
 
-	var pollInterval time.Duration
-	config.RegisterDurationConfigVariable(300, &pollInterval, true, time.Second, "BackendConfig.Regulations.pollInterval")
+	var defaultWorkspaceID string
+	if backendConfig.Identity().Type() == deployment.DedicatedType {
+		defaultWorkspaceID = backendConfig.Identity().ID()
+	}
+	local := newLocalRepository(repository, defaultWorkspaceID)
+	localAdmin := &Admin{r: local}
+	admin.RegisterAdminHandler("SuppressUser", localAdmin)
+	admin.RegisterHTTPHandler("suppressions", localAdmin.httpHandler())
+
+	var syncer *Syncer
+	if config.GetBool("BackendConfig.Regulations.syncEnabled", true) {
+		var pollInterval time.Duration
+		config.RegisterDurationConfigVariable(300, &pollInterval, true, time.Second, "BackendConfig.Regulations.pollInterval")
 
-	syncer, err := NewSyncer(
-		config.GetString("SUPPRESS_USER_BACKEND_URL", "https://api.rudderstack.com"),
-		backendConfig.Identity(),
-		repository,
-		WithLogger(m.Log),
-		WithHttpClient(&http.Client{Timeout: config.GetDuration("HttpClient.suppressUser.timeout", 30, time.Second)}),
-		WithPageSize(config.GetInt("BackendConfig.Regulations.pageSize", 5000)),
-		WithPollIntervalFn(func() time.Duration { return pollInterval }),
-	)
-	if err != nil {
-		return nil, err
+		var err error
+		syncer, err = NewSyncer(
+			config.GetString("SUPPRESS_USER_BACKEND_URL", "https://api.rudderstack.com"),
+			backendConfig.Identity(),
+			local,
+			WithLogger(m.Log),
+			WithHttpClient(&http.Client{Timeout: config.GetDuration("HttpClient.suppressUser.timeout", 30, time.Second)}),
+			WithPageSize(config.GetInt("BackendConfig.Regulations.pageSize", 5000)),
+			WithPollIntervalFn(func() time.Duration { return pollInterval }),
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		m.Log.Info("Syncing suppressions from the control plane is disabled")
 	}
 
 	h := newHandler(repository, m.Log)
 
+	g, gCtx := errgroup.WithContext(ctx)
+	if syncer != nil {
+		g.Go(func() error {
+			syncer.SyncLoop(gCtx)
+			return nil
+		})
+	}
+	if suppressionsFile := config.GetString("BackendConfig.Regulations.suppressionsFile", ""); suppressionsFile != "" {
+		watcher := newFileWatcher(suppressionsFile, local, m.Log)
+		g.Go(func() error {
+			if err := watcher.Run(gCtx); err != nil {
+				m.Log.Errorf("Suppressions file watcher stopped: %v", err)
+			}
+			return nil
+		})
+	}
 	rruntime.Go(func() {
-		syncer.SyncLoop(ctx)
+		<-ctx.Done()
+		_ = g.Wait()
 		_ = repository.Stop()
 	})
 
@@ -0,0 +1,206 @@
+package suppression
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// csvSourceIDsSeparator separates the source ids in the sourceIds column of csv files
+const csvSourceIDsSeparator = ";"
+
+// fileWatcher adds the suppressions of a local file to the repository, when it starts and every time the file changes.
+//
+// The file is either a csv file, with a header naming its columns (workspaceId, userId, sourceIds, field, match, value, canceled),
+// or a file with a json suppression per line (.jsonl, .ndjson). Suppressions removed from the file are not removed from
+// the repository, they need to be canceled instead.
+type fileWatcher struct {
+	path          string
+	r             *localRepository
+	log           logger.Logger
+	retryInterval time.Duration
+}
+
+func newFileWatcher(path string, r *localRepository, log logger.Logger) *fileWatcher {
+	return &fileWatcher{
+		path:          filepath.Clean(path),
+		r:             r,
+		log:           log,
+		retryInterval: time.Second,
+	}
+}
+
+// Run loads the file and watches it for changes, until the context is done
+func (w *fileWatcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not create file watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+	// watching the directory, since editors usually replace the file instead of writing it
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return fmt.Errorf("could not watch directory of suppressions file %q: %w", w.path, err)
+	}
+
+	w.load(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != w.path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			w.load(ctx)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.log.Errorf("Error watching suppressions file %q: %v", w.path, err)
+		}
+	}
+}
+
+// load adds the suppressions of the file to the repository, waiting for the repository if it is restoring
+func (w *fileWatcher) load(ctx context.Context) {
+	suppressions, err := readSuppressionsFile(w.path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			w.log.Errorf("Could not read suppressions file %q: %v", w.path, err)
+		}
+		return
+	}
+	for {
+		err = w.r.AddLocal(suppressions)
+		if !errors.Is(err, model.ErrRestoring) {
+			break
+		}
+		if err := misc.SleepCtx(ctx, w.retryInterval); err != nil {
+			return
+		}
+	}
+	if err != nil {
+		w.log.Errorf("Could not add suppressions of file %q: %v", w.path, err)
+		return
+	}
+	w.log.Infof("Added %d suppressions of file %q", len(suppressions), w.path)
+}
+
+// readSuppressionsFile reads the suppressions of the file, in the format of its extension
+func readSuppressionsFile(path string) ([]model.Suppression, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return parseCSVSuppressions(f)
+	case ".jsonl", ".ndjson", ".json":
+		return parseJSONLSuppressions(f)
+	default:
+		return nil, fmt.Errorf("unsupported suppressions file extension %q", ext)
+	}
+}
+
+// parseCSVSuppressions parses csv suppressions, naming their columns in the header
+func parseCSVSuppressions(r io.Reader) ([]model.Suppression, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read header: %w", err)
+	}
+	setters := make([]func(s *model.Suppression, value string) error, len(header))
+	for i, column := range header {
+		switch strings.TrimSpace(column) {
+		case "workspaceId":
+			setters[i] = func(s *model.Suppression, value string) error { s.WorkspaceID = value; return nil }
+		case "userId":
+			setters[i] = func(s *model.Suppression, value string) error { s.UserID = value; return nil }
+		case "sourceIds":
+			setters[i] = func(s *model.Suppression, value string) error {
+				s.SourceIDs = []string{}
+				for _, sourceID := range strings.Split(value, csvSourceIDsSeparator) {
+					if sourceID = strings.TrimSpace(sourceID); sourceID != "" {
+						s.SourceIDs = append(s.SourceIDs, sourceID)
+					}
+				}
+				return nil
+			}
+		case "field":
+			setters[i] = func(s *model.Suppression, value string) error { s.Field = value; return nil }
+		case "match":
+			setters[i] = func(s *model.Suppression, value string) error { s.Match = value; return nil }
+		case "value":
+			setters[i] = func(s *model.Suppression, value string) error { s.Value = value; return nil }
+		case "canceled":
+			setters[i] = func(s *model.Suppression, value string) (err error) {
+				if value != "" {
+					s.Canceled, err = strconv.ParseBool(value)
+				}
+				return err
+			}
+		default:
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+
+	var suppressions []model.Suppression
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return suppressions, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read line %d: %w", line, err)
+		}
+		s := model.Suppression{SourceIDs: []string{}}
+		for i, value := range record {
+			if err := setters[i](&s, strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("invalid %s at line %d: %w", header[i], line, err)
+			}
+		}
+		suppressions = append(suppressions, s)
+	}
+}
+
+// parseJSONLSuppressions parses a json suppression per line
+func parseJSONLSuppressions(r io.Reader) ([]model.Suppression, error) {
+	var suppressions []model.Suppression
+	decoder := json.NewDecoder(r)
+	for {
+		var s model.Suppression
+		err := decoder.Decode(&s)
+		if errors.Is(err, io.EOF) {
+			return suppressions, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not decode suppression %d: %w", len(suppressions)+1, err)
+		}
+		if s.SourceIDs == nil {
+			s.SourceIDs = []string{}
+		}
+		suppressions = append(suppressions, s)
+	}
+}
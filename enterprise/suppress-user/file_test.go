@@ -0,0 +1,121 @@
+package suppression
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		suppressions, err := parseCSVSuppressions(strings.NewReader(strings.Join([]string{
+			"workspaceId,userId,sourceIds,field,match,value,canceled",
+			"ws-1,user-1,,,,,",
+			"ws-1,,src-1; src-2,anonymousId,prefix,test-,false",
+			"ws-2,user-2,,,,,true",
+		}, "\n")))
+		require.NoError(t, err)
+		require.Equal(t, []model.Suppression{
+			{WorkspaceID: "ws-1", UserID: "user-1", SourceIDs: []string{}},
+			{WorkspaceID: "ws-1", SourceIDs: []string{"src-1", "src-2"}, Field: model.FieldAnonymousID, Match: model.MatchPrefix, Value: "test-"},
+			{WorkspaceID: "ws-2", UserID: "user-2", SourceIDs: []string{}, Canceled: true},
+		}, suppressions)
+	})
+
+	t.Run("csv with a subset of the columns", func(t *testing.T) {
+		suppressions, err := parseCSVSuppressions(strings.NewReader("userId\nuser-1\n"))
+		require.NoError(t, err)
+		require.Equal(t, []model.Suppression{{UserID: "user-1", SourceIDs: []string{}}}, suppressions)
+	})
+
+	t.Run("csv errors", func(t *testing.T) {
+		_, err := parseCSVSuppressions(strings.NewReader("userId,unknown\nuser-1,value\n"))
+		require.ErrorContains(t, err, `unknown column "unknown"`)
+
+		_, err = parseCSVSuppressions(strings.NewReader("userId,canceled\nuser-1,maybe\n"))
+		require.ErrorContains(t, err, "invalid canceled at line 2")
+
+		_, err = parseCSVSuppressions(strings.NewReader("userId,canceled\nuser-1\n"))
+		require.ErrorContains(t, err, "could not read line 2")
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		suppressions, err := parseJSONLSuppressions(strings.NewReader(strings.Join([]string{
+			`{"workspaceId":"ws-1","userId":"user-1"}`,
+			``,
+			`{"workspaceId":"ws-1","sourceIds":["src-1"],"field":"email","value":"hash","canceled":true}`,
+		}, "\n")))
+		require.NoError(t, err)
+		require.Equal(t, []model.Suppression{
+			{WorkspaceID: "ws-1", UserID: "user-1", SourceIDs: []string{}},
+			{WorkspaceID: "ws-1", SourceIDs: []string{"src-1"}, Field: model.FieldEmail, Value: "hash", Canceled: true},
+		}, suppressions)
+
+		_, err = parseJSONLSuppressions(strings.NewReader(`{"workspaceId":"ws-1","userId":"user-1"}` + "\n{"))
+		require.ErrorContains(t, err, "could not decode suppression 2")
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "suppressions.txt")
+		require.NoError(t, os.WriteFile(path, []byte("userId\nuser-1\n"), 0o600))
+		_, err := readSuppressionsFile(path)
+		require.ErrorContains(t, err, `unsupported suppressions file extension ".txt"`)
+	})
+}
+
+func TestFileWatcher(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "suppressions.csv")
+	require.NoError(t, os.WriteFile(path, []byte("workspaceId,userId\nws-1,user-1\n"), 0o600))
+
+	r := newLocalRepository(NewMemoryRepository(logger.NOP), "")
+	require.NoError(t, r.Add(nil, []byte("token")))
+	w := newFileWatcher(path, r, logger.NOP)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	defer func() {
+		cancel()
+		require.NoError(t, <-done)
+	}()
+
+	suppressed := func(userID string) func() bool {
+		return func() bool {
+			s, err := r.Suppressed("ws-1", userID, "src-1")
+			require.NoError(t, err)
+			return s
+		}
+	}
+	require.Eventually(t, suppressed("user-1"), 5*time.Second, 10*time.Millisecond, "the file is loaded on start")
+
+	t.Run("file written", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("workspaceId,userId,canceled\nws-1,user-1,true\nws-1,user-2,\n"), 0o600))
+		require.Eventually(t, suppressed("user-2"), 5*time.Second, 10*time.Millisecond)
+		require.False(t, suppressed("user-1")())
+	})
+
+	t.Run("file replaced", func(t *testing.T) {
+		tmp := filepath.Join(dir, "suppressions.tmp")
+		require.NoError(t, os.WriteFile(tmp, []byte("workspaceId,userId\nws-1,user-3\n"), 0o600))
+		require.NoError(t, os.Rename(tmp, path))
+		require.Eventually(t, suppressed("user-3"), 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("invalid file is ignored", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("userId\nuser-4\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "other.csv"), []byte("workspaceId,userId\nws-1,user-4\n"), 0o600))
+		require.Never(t, suppressed("user-4"), 100*time.Millisecond, 10*time.Millisecond)
+	})
+
+	token, err := r.GetToken()
+	require.NoError(t, err)
+	require.Equal(t, []byte("token"), token, "the token of the syncer is kept")
+}
@@ -30,3 +30,12 @@ func (h *handler) IsSuppressedUser(workspaceID, userID, sourceID string) bool {
 	}
 	return suppressed
 }
+
+func (h *handler) IsSuppressedUserEvent(workspaceID, sourceID, userID, anonymousID, email string) bool {
+	h.log.Debugf("IsSuppressedUserEvent called for workspace: %s, source %s, user %s, anonymous user %s", workspaceID, sourceID, userID, anonymousID)
+	suppressed, err := h.r.SuppressedUser(workspaceID, sourceID, model.NewUser(userID, anonymousID, email))
+	if err != nil && !errors.Is(err, model.ErrRestoring) {
+		h.log.Errorf("Suppression check failed for workspace: %s, user: %s, anonymous user: %s, source: %s: %w", workspaceID, userID, anonymousID, sourceID, err)
+	}
+	return suppressed
+}
@@ -6,11 +6,13 @@ import (
 	"io"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/dgraph-io/badger/v3"
 	"github.com/dgraph-io/badger/v3/options"
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/internal/pattern"
 	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
 	"github.com/rudderlabs/rudder-server/utils/logger"
 	"github.com/samber/lo"
@@ -19,6 +21,10 @@ import (
 // the key used in badgerdb to store the current token
 const tokenKey = "__token__"
 
+// the prefix of the keys used in badgerdb to store the suppressions matching users by prefix or wildcard,
+// followed by workspaceID:sourceID:field:match:value
+const patternKeyPrefix = "__pattern__:"
+
 // Opt is a function that configures a badgerdb repository
 type Opt func(*Repository)
 
@@ -51,6 +57,8 @@ type Repository struct {
 	seederSource func() (io.Reader, error)
 
 	db *badger.DB
+	// patterns are the suppressions matching users by prefix or wildcard, loaded from the db
+	patterns *pattern.Set
 
 	// lock to prevent concurrent access to db during restore
 	restoringLock sync.RWMutex
@@ -66,6 +74,7 @@ func NewRepository(basePath string, log logger.Logger, opts ...Opt) (*Repository
 		path:          path.Join(basePath, "badgerdbv3"),
 		maxGoroutines: 1,
 		maxSeedWait:   10 * time.Second,
+		patterns:      pattern.NewSet(),
 	}
 	for _, opt := range opts {
 		opt(b)
@@ -107,6 +116,11 @@ func (b *Repository) GetToken() ([]byte, error) {
 
 // Suppressed returns true if the given user is suppressed, false otherwise
 func (b *Repository) Suppressed(workspaceID, userID, sourceID string) (bool, error) {
+	return b.SuppressedUser(workspaceID, sourceID, &model.User{UserID: userID})
+}
+
+// SuppressedUser returns true if the user is suppressed by any of its fields, false otherwise
+func (b *Repository) SuppressedUser(workspaceID, sourceID string, user *model.User) (bool, error) {
 	b.restoringLock.RLock()
 	defer b.restoringLock.RUnlock()
 	if b.restoring {
@@ -116,29 +130,31 @@ func (b *Repository) Suppressed(workspaceID, userID, sourceID string) (bool, err
 		return false, badger.ErrDBClosed
 	}
 
-	keyPrefix := keyPrefix(workspaceID, userID)
+	var suppressed bool
 	err := b.db.View(func(txn *badger.Txn) error {
-		wildcardKey := keyPrefix + model.Wildcard
-		_, err := txn.Get([]byte(wildcardKey))
-		if err == nil {
-			return nil
-		}
-		if !errors.Is(err, badger.ErrKeyNotFound) {
-			return fmt.Errorf("could not get wildcard key %s: %w", wildcardKey, err)
-		}
-		sourceKey := keyPrefix + sourceID
-		if _, err = txn.Get([]byte(sourceKey)); err != nil {
-			return fmt.Errorf("could not get sourceID key %s: %w", sourceKey, err)
+		for _, field := range model.Fields {
+			value := user.Field(field)
+			if value == "" {
+				continue
+			}
+			keyPrefix := keyPrefix(workspaceID, userKey(field, value))
+			for _, key := range []string{keyPrefix + model.Wildcard, keyPrefix + sourceID} {
+				_, err := txn.Get([]byte(key))
+				if err == nil {
+					suppressed = true
+					return nil
+				}
+				if !errors.Is(err, badger.ErrKeyNotFound) {
+					return fmt.Errorf("could not get key %s: %w", key, err)
+				}
+			}
 		}
-		return err
+		return nil
 	})
 	if err != nil {
-		if errors.Is(err, badger.ErrKeyNotFound) {
-			return false, nil
-		}
 		return false, err
 	}
-	return true, nil
+	return suppressed || b.patterns.Matches(workspaceID, sourceID, user), nil
 }
 
 // Add adds the given suppressions to the repository
@@ -154,15 +170,28 @@ func (b *Repository) Add(suppressions []model.Suppression, token []byte) error {
 	wb := b.db.NewWriteBatch()
 	defer wb.Cancel()
 
+	var patternSuppressions []*model.Suppression
 	for i := range suppressions {
 		suppression := suppressions[i]
-		keyPrefix := keyPrefix(suppression.WorkspaceID, suppression.UserID)
-		var keys []string
-		if len(suppression.SourceIDs) == 0 {
-			keys = []string{keyPrefix + model.Wildcard}
+		if err := suppression.Validate(); err != nil {
+			b.log.Warnf("Skipping invalid suppression for workspace %s: %v", suppression.WorkspaceID, err)
+			continue
+		}
+		sourceIDs := suppression.SourceIDs
+		if len(sourceIDs) == 0 {
+			sourceIDs = []string{model.Wildcard}
+		}
+		keys := make([]string, len(sourceIDs))
+		if pattern.IsPattern(&suppression) {
+			patternSuppressions = append(patternSuppressions, &suppressions[i])
+			rule := pattern.RuleOf(&suppression)
+			for i, sourceID := range sourceIDs {
+				keys[i] = patternKey(rule, sourceID)
+			}
 		} else {
-			keys = make([]string, len(suppression.SourceIDs))
-			for i, sourceID := range suppression.SourceIDs {
+			field, _, value := suppression.Rule()
+			keyPrefix := keyPrefix(suppression.WorkspaceID, userKey(field, value))
+			for i, sourceID := range sourceIDs {
 				keys[i] = keyPrefix + sourceID
 			}
 		}
@@ -185,6 +214,87 @@ func (b *Repository) Add(suppressions []model.Suppression, token []byte) error {
 	if err := wb.Flush(); err != nil {
 		return fmt.Errorf("could not flush write batch: %w", err)
 	}
+	for _, suppression := range patternSuppressions {
+		b.patterns.Add(pattern.RuleOf(suppression), suppression.SourceIDs, suppression.Canceled)
+	}
+	return nil
+}
+
+// List returns the suppressions of the workspace
+func (b *Repository) List(workspaceID string) ([]model.Suppression, error) {
+	b.restoringLock.RLock()
+	defer b.restoringLock.RUnlock()
+	if b.restoring {
+		return nil, model.ErrRestoring
+	}
+	if b.db.IsClosed() {
+		return nil, badger.ErrDBClosed
+	}
+
+	type user struct{ field, value string }
+	users := make(map[user]map[string]struct{})
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = []byte(workspaceID + ":")
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := strings.TrimPrefix(string(it.Item().Key()), workspaceID+":")
+			separator := strings.LastIndex(key, ":")
+			if separator < 0 {
+				continue
+			}
+			field, value := parseUserKey(key[:separator])
+			u := user{field: field, value: value}
+			if _, ok := users[u]; !ok {
+				users[u] = make(map[string]struct{})
+			}
+			users[u][key[separator+1:]] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not list suppressions: %w", err)
+	}
+
+	var suppressions []model.Suppression
+	for u, sourceIDs := range users {
+		suppressions = append(suppressions, model.NewSuppressions(workspaceID, u.field, model.MatchExact, u.value, sourceIDs)...)
+	}
+	b.patterns.Range(func(rule pattern.Rule, sourceIDs map[string]struct{}) {
+		if rule.WorkspaceID == workspaceID {
+			suppressions = append(suppressions, model.NewSuppressions(rule.WorkspaceID, rule.Field, rule.Match, rule.Value, sourceIDs)...)
+		}
+	})
+	return suppressions, nil
+}
+
+// loadPatterns replaces the patterns in memory with the ones stored in the db
+func (b *Repository) loadPatterns() error {
+	patterns := pattern.NewSet()
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = []byte(patternKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := string(it.Item().Key())
+			parts := strings.SplitN(strings.TrimPrefix(key, patternKeyPrefix), ":", 5)
+			if len(parts) != 5 {
+				b.log.Warnf("Skipping invalid pattern key %s", key)
+				continue
+			}
+			rule := pattern.Rule{WorkspaceID: parts[0], Field: parts[2], Match: parts[3], Value: parts[4]}
+			patterns.Add(rule, []string{parts[1]}, false)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not load patterns: %w", err)
+	}
+	b.patterns.Replace(patterns)
 	return nil
 }
 
@@ -209,6 +319,9 @@ func (b *Repository) start() error {
 	if err != nil {
 		return err
 	}
+	if err := b.loadPatterns(); err != nil {
+		return err
+	}
 
 	if seeder != nil {
 		restoreDone := lo.Async(func() error {
@@ -283,7 +396,10 @@ func (b *Repository) Restore(r io.Reader) (err error) {
 			err = fmt.Errorf("panic during restore: %v", r)
 		}
 	}()
-	return b.db.Load(r, b.maxGoroutines)
+	if err := b.db.Load(r, b.maxGoroutines); err != nil {
+		return err
+	}
+	return b.loadPatterns()
 }
 
 func (b *Repository) setRestoring(restoring bool) {
@@ -309,3 +425,25 @@ func (l blogger) Warningf(fmt string, args ...interface{}) {
 func keyPrefix(workspaceID, userID string) string {
 	return fmt.Sprintf("%s:%s:", workspaceID, userID)
 }
+
+// userKey returns the key of the users matched exactly by the field value, the userId itself for suppressions by userId
+func userKey(field, value string) string {
+	if field == model.FieldUserID {
+		return value
+	}
+	return "__" + field + "__" + value
+}
+
+// parseUserKey returns the field and value of the users matched by the key, see [userKey]
+func parseUserKey(key string) (field, value string) {
+	for _, field := range []string{model.FieldAnonymousID, model.FieldEmail} {
+		if prefix := "__" + field + "__"; strings.HasPrefix(key, prefix) {
+			return field, strings.TrimPrefix(key, prefix)
+		}
+	}
+	return model.FieldUserID, key
+}
+
+func patternKey(rule pattern.Rule, sourceID string) string {
+	return patternKeyPrefix + strings.Join([]string{rule.WorkspaceID, sourceID, rule.Field, rule.Match, rule.Value}, ":")
+}
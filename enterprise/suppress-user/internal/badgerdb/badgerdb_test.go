@@ -108,6 +108,27 @@ func TestBadgerRepository(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("patterns are restored", func(t *testing.T) {
+		require.NoError(t, repo.Add([]model.Suppression{
+			{WorkspaceID: "workspace1", Field: model.FieldAnonymousID, Match: model.MatchWildcard, Value: "qa-*", SourceIDs: []string{"source1"}},
+		}, token))
+		var backup bytes.Buffer
+		require.NoError(t, repo.Backup(&backup))
+
+		seeded, err := badgerdb.NewRepository(path.Join(t.TempDir(), "badger-test-3"), logger.NOP, badgerdb.WithSeederSource(func() (io.Reader, error) {
+			return &backup, nil
+		}))
+		require.NoError(t, err)
+		defer func() { _ = seeded.Stop() }()
+
+		suppressed, err := seeded.SuppressedUser("workspace1", "source1", &model.User{AnonymousID: "qa-1"})
+		require.NoError(t, err)
+		require.True(t, suppressed)
+		suppressed, err = seeded.SuppressedUser("workspace1", "source2", &model.User{AnonymousID: "qa-1"})
+		require.NoError(t, err)
+		require.False(t, suppressed)
+	})
+
 	t.Run("try to restore invalid data", func(t *testing.T) {
 		r := bytes.NewBuffer([]byte("invalid data"))
 		require.Error(t, repo.Restore(r), "it should return an error when trying to restore invalid data")
@@ -1,9 +1,12 @@
 package memory
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"sync"
 
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/internal/pattern"
 	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
 	"github.com/rudderlabs/rudder-server/utils/logger"
 )
@@ -13,50 +16,89 @@ type Repository struct {
 	log            logger.Logger
 	token          []byte
 	suppressionsMu sync.RWMutex
-	suppressions   map[string]map[string]map[string]struct{}
+	suppressions   map[string]map[userKey]map[string]struct{}
+	patterns       *pattern.Set
+}
+
+// userKey is the field and value of the users matched exactly by a suppression
+type userKey struct {
+	field string
+	value string
+}
+
+// backup is the format of the backups of the repository
+type backup struct {
+	Token        []byte              `json:"token"`
+	Suppressions []model.Suppression `json:"suppressions"`
 }
 
 // NewRepository returns a new repository backed by memory.
 func NewRepository(log logger.Logger) *Repository {
 	m := &Repository{
 		log:          log,
-		suppressions: make(map[string]map[string]map[string]struct{}),
+		suppressions: make(map[string]map[userKey]map[string]struct{}),
+		patterns:     pattern.NewSet(),
 	}
 	return m
 }
 
 // GetToken returns the current token
 func (m *Repository) GetToken() ([]byte, error) {
+	m.suppressionsMu.RLock()
+	defer m.suppressionsMu.RUnlock()
 	return m.token, nil
 }
 
 // Suppressed returns true if the given user is suppressed, false otherwise
 func (m *Repository) Suppressed(workspaceID, userID, sourceID string) (bool, error) {
+	return m.SuppressedUser(workspaceID, sourceID, &model.User{UserID: userID})
+}
+
+// SuppressedUser returns true if the user is suppressed by any of its fields, false otherwise
+func (m *Repository) SuppressedUser(workspaceID, sourceID string, user *model.User) (bool, error) {
 	m.suppressionsMu.RLock()
 	defer m.suppressionsMu.RUnlock()
-	workspace, ok := m.suppressions[workspaceID]
-	if !ok {
-		return false, nil
-	}
-	sourceIDs, ok := workspace[userID]
-	if !ok {
-		return false, nil
-	}
-	if _, ok := sourceIDs[model.Wildcard]; ok {
-		return true, nil
-	}
-	if _, ok := sourceIDs[sourceID]; ok {
-		return true, nil
+	if workspace, ok := m.suppressions[workspaceID]; ok {
+		for _, field := range model.Fields {
+			value := user.Field(field)
+			if value == "" {
+				continue
+			}
+			sourceIDs, ok := workspace[userKey{field: field, value: value}]
+			if !ok {
+				continue
+			}
+			if _, ok := sourceIDs[model.Wildcard]; ok {
+				return true, nil
+			}
+			if _, ok := sourceIDs[sourceID]; ok {
+				return true, nil
+			}
+		}
 	}
-	return false, nil
+	return m.patterns.Matches(workspaceID, sourceID, user), nil
 }
 
 // Add adds the given suppressions to the repository
 func (m *Repository) Add(suppressions []model.Suppression, token []byte) error {
 	m.suppressionsMu.Lock()
 	defer m.suppressionsMu.Unlock()
+	m.add(suppressions)
+	m.token = token
+	return nil
+}
+
+func (m *Repository) add(suppressions []model.Suppression) {
 	for i := range suppressions {
 		suppression := suppressions[i]
+		if err := suppression.Validate(); err != nil {
+			m.log.Warnf("Skipping invalid suppression for workspace %s: %v", suppression.WorkspaceID, err)
+			continue
+		}
+		if pattern.IsPattern(&suppression) {
+			m.patterns.Add(pattern.RuleOf(&suppression), suppression.SourceIDs, suppression.Canceled)
+			continue
+		}
 		var keys []string
 		if len(suppression.SourceIDs) == 0 {
 			keys = []string{model.Wildcard}
@@ -66,13 +108,15 @@ func (m *Repository) Add(suppressions []model.Suppression, token []byte) error {
 		}
 		workspace, ok := m.suppressions[suppression.WorkspaceID]
 		if !ok {
-			workspace = make(map[string]map[string]struct{})
+			workspace = make(map[userKey]map[string]struct{})
 			m.suppressions[suppression.WorkspaceID] = workspace
 		}
-		user, ok := workspace[suppression.UserID]
+		field, _, value := suppression.Rule()
+		key := userKey{field: field, value: value}
+		user, ok := workspace[key]
 		if !ok {
 			user = make(map[string]struct{})
-			m.suppressions[suppression.WorkspaceID][suppression.UserID] = user
+			workspace[key] = user
 		}
 		if suppression.Canceled {
 			for _, key := range keys {
@@ -84,8 +128,6 @@ func (m *Repository) Add(suppressions []model.Suppression, token []byte) error {
 			}
 		}
 	}
-	m.token = token
-	return nil
 }
 
 // Stop is a no-op for the memory repository.
@@ -93,12 +135,54 @@ func (*Repository) Stop() error {
 	return nil
 }
 
-// Backup is not supported for the memory repository.
-func (*Repository) Backup(_ io.Writer) error {
-	return model.ErrNotSupported
+// Backup writes a backup of the repository to the given writer, as JSON
+func (m *Repository) Backup(w io.Writer) error {
+	m.suppressionsMu.RLock()
+	b := backup{Token: m.token}
+	for workspaceID, workspace := range m.suppressions {
+		for key, sourceIDs := range workspace {
+			b.Suppressions = append(b.Suppressions, model.NewSuppressions(workspaceID, key.field, model.MatchExact, key.value, sourceIDs)...)
+		}
+	}
+	m.suppressionsMu.RUnlock()
+	m.patterns.Range(func(rule pattern.Rule, sourceIDs map[string]struct{}) {
+		b.Suppressions = append(b.Suppressions, model.NewSuppressions(rule.WorkspaceID, rule.Field, rule.Match, rule.Value, sourceIDs)...)
+	})
+
+	if err := json.NewEncoder(w).Encode(b); err != nil {
+		return fmt.Errorf("could not write backup: %w", err)
+	}
+	return nil
+}
+
+// List returns the suppressions of the workspace
+func (m *Repository) List(workspaceID string) ([]model.Suppression, error) {
+	var suppressions []model.Suppression
+	m.suppressionsMu.RLock()
+	for key, sourceIDs := range m.suppressions[workspaceID] {
+		suppressions = append(suppressions, model.NewSuppressions(workspaceID, key.field, model.MatchExact, key.value, sourceIDs)...)
+	}
+	m.suppressionsMu.RUnlock()
+	m.patterns.Range(func(rule pattern.Rule, sourceIDs map[string]struct{}) {
+		if rule.WorkspaceID == workspaceID {
+			suppressions = append(suppressions, model.NewSuppressions(rule.WorkspaceID, rule.Field, rule.Match, rule.Value, sourceIDs)...)
+		}
+	})
+	return suppressions, nil
 }
 
-// Restore is not supported for the memory repository.
-func (*Repository) Restore(_ io.Reader) error {
-	return model.ErrNotSupported
+// Restore replaces the contents of the repository with the backup read from the given reader
+func (m *Repository) Restore(r io.Reader) error {
+	var b backup
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return fmt.Errorf("could not read backup: %w", err)
+	}
+
+	m.suppressionsMu.Lock()
+	defer m.suppressionsMu.Unlock()
+	m.suppressions = make(map[string]map[userKey]map[string]struct{})
+	m.patterns.Reset()
+	m.add(b.Suppressions)
+	m.token = b.Token
+	return nil
 }
@@ -1,10 +1,14 @@
 package memory_test
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/internal/memory"
 	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/internal/repotest"
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
 	"github.com/rudderlabs/rudder-server/utils/logger"
 )
 
@@ -12,3 +16,49 @@ import (
 func TestMemoryRepoSpec(t *testing.T) {
 	repotest.RunRepositoryTestSuite(t, memory.NewRepository(logger.NOP))
 }
+
+// TestMemoryRepoBackupRestore tests that the memory repository can be restored from its backup.
+func TestMemoryRepoBackupRestore(t *testing.T) {
+	repo := memory.NewRepository(logger.NOP)
+	require.NoError(t, repo.Add([]model.Suppression{
+		{WorkspaceID: "workspace1", UserID: "user1", SourceIDs: []string{}},
+		{WorkspaceID: "workspace1", UserID: "user1", SourceIDs: []string{"source1"}},
+		{WorkspaceID: "workspace1", UserID: "user2", SourceIDs: []string{"source1"}},
+		{WorkspaceID: "workspace1", UserID: "user2", SourceIDs: []string{"source1"}, Canceled: true},
+		{WorkspaceID: "workspace1", Field: model.FieldAnonymousID, Match: model.MatchPrefix, Value: "test-", SourceIDs: []string{"source2"}},
+	}, []byte("token")))
+
+	var backup bytes.Buffer
+	require.NoError(t, repo.Backup(&backup))
+
+	restored := memory.NewRepository(logger.NOP)
+	require.NoError(t, restored.Restore(&backup))
+
+	token, err := restored.GetToken()
+	require.NoError(t, err)
+	require.Equal(t, []byte("token"), token)
+
+	for _, tc := range []struct {
+		sourceID   string
+		user       *model.User
+		suppressed bool
+	}{
+		{"source3", &model.User{UserID: "user1"}, true},
+		{"source1", &model.User{UserID: "user2"}, false},
+		{"source2", &model.User{AnonymousID: "test-1"}, true},
+		{"source3", &model.User{AnonymousID: "test-1"}, false},
+	} {
+		suppressed, err := restored.SuppressedUser("workspace1", tc.sourceID, tc.user)
+		require.NoError(t, err)
+		require.Equal(t, tc.suppressed, suppressed, "%+v for %s", tc.user, tc.sourceID)
+	}
+
+	require.NoError(t, restored.Add([]model.Suppression{
+		{WorkspaceID: "workspace1", UserID: "user1", SourceIDs: []string{}, Canceled: true},
+	}, []byte("token2")))
+	suppressed, err := restored.Suppressed("workspace1", "user1", "source1")
+	require.NoError(t, err)
+	require.True(t, suppressed, "the suppression for the source is restored separately from the one for all sources")
+
+	require.Error(t, restored.Restore(bytes.NewBufferString("invalid data")))
+}
@@ -0,0 +1,116 @@
+package pattern
+
+import (
+	"sync"
+
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
+)
+
+// Rule is a suppression matching the users by prefix or wildcard
+type Rule struct {
+	WorkspaceID string
+	Field       string
+	Match       string
+	Value       string
+}
+
+// Set is the set of the suppressions matching the users by prefix or wildcard, which can't be looked up by key.
+// They are few, e.g. test accounts, so they are all kept in memory.
+type Set struct {
+	mu    sync.RWMutex
+	rules map[string]map[Rule]map[string]struct{} // workspaceID -> rule -> sourceIDs
+}
+
+// NewSet returns an empty set
+func NewSet() *Set {
+	return &Set{rules: make(map[string]map[Rule]map[string]struct{})}
+}
+
+// IsPattern returns true if the suppression matches the users by prefix or wildcard
+func IsPattern(suppression *model.Suppression) bool {
+	_, match, _ := suppression.Rule()
+	return match != model.MatchExact
+}
+
+// RuleOf returns the rule of a pattern suppression
+func RuleOf(suppression *model.Suppression) Rule {
+	field, match, value := suppression.Rule()
+	return Rule{WorkspaceID: suppression.WorkspaceID, Field: field, Match: match, Value: value}
+}
+
+// Add adds the rule for the sources, or removes it if canceled. The rule applies to all sources if sourceIDs is empty.
+func (s *Set) Add(rule Rule, sourceIDs []string, canceled bool) {
+	keys := sourceIDs
+	if len(keys) == 0 {
+		keys = []string{model.Wildcard}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	workspace, ok := s.rules[rule.WorkspaceID]
+	if !ok {
+		workspace = make(map[Rule]map[string]struct{})
+		s.rules[rule.WorkspaceID] = workspace
+	}
+	sources, ok := workspace[rule]
+	if !ok {
+		sources = make(map[string]struct{})
+		workspace[rule] = sources
+	}
+	for _, key := range keys {
+		if canceled {
+			delete(sources, key)
+		} else {
+			sources[key] = struct{}{}
+		}
+	}
+	if len(sources) == 0 {
+		delete(workspace, rule)
+	}
+}
+
+// Matches returns true if any of the rules of the workspace matches the user for the source
+func (s *Set) Matches(workspaceID, sourceID string, user *model.User) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for rule, sources := range s.rules[workspaceID] {
+		if _, ok := sources[model.Wildcard]; !ok {
+			if _, ok := sources[sourceID]; !ok {
+				continue
+			}
+		}
+		if value := user.Field(rule.Field); value != "" && model.MatchPattern(rule.Match, rule.Value, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Range calls fn for all the rules, along with their sources
+func (s *Set) Range(fn func(rule Rule, sourceIDs map[string]struct{})) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, workspace := range s.rules {
+		for rule, sourceIDs := range workspace {
+			fn(rule, sourceIDs)
+		}
+	}
+}
+
+// Replace replaces the rules with the ones of the other set
+func (s *Set) Replace(other *Set) {
+	other.mu.RLock()
+	rules := other.rules
+	other.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = rules
+}
+
+// Reset removes all the rules
+func (s *Set) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules = make(map[string]map[Rule]map[string]struct{})
+}
@@ -159,4 +159,91 @@ func RunRepositoryTestSuite(t *testing.T, repo suppression.Repository) {
 		require.NoError(t, err)
 		require.False(t, suppressed, "it should return false when trying to suppress a user that is no longer suppressed by an exact match suppression")
 	})
+
+	t.Run("suppressions by anonymousId, email and patterns", func(t *testing.T) {
+		require.NoError(t, repo.Add([]model.Suppression{
+			{
+				WorkspaceID: "workspaceY",
+				Field:       model.FieldAnonymousID,
+				Value:       "anonymous1",
+				SourceIDs:   []string{},
+			},
+			{
+				WorkspaceID: "workspaceY",
+				Field:       model.FieldEmail,
+				Value:       model.HashEmail("jane@example.com"),
+				SourceIDs:   []string{"source1"},
+			},
+			{
+				WorkspaceID: "workspaceY",
+				Match:       model.MatchPrefix,
+				Value:       "test-",
+				SourceIDs:   []string{},
+			},
+			{
+				WorkspaceID: "workspaceY",
+				Field:       model.FieldAnonymousID,
+				Match:       model.MatchWildcard,
+				Value:       "qa-*-bot",
+				SourceIDs:   []string{},
+			},
+			{
+				WorkspaceID: "workspaceY",
+				Field:       model.FieldEmail,
+				Match:       model.MatchPrefix,
+				Value:       "invalid, emails are hashed",
+				SourceIDs:   []string{},
+			},
+		}, token))
+
+		suppressed := func(sourceID string, user *model.User) bool {
+			t.Helper()
+			suppressed, err := repo.SuppressedUser("workspaceY", sourceID, user)
+			require.NoError(t, err)
+			return suppressed
+		}
+		require.True(t, suppressed("source2", model.NewUser("user1", "anonymous1", "")), "it should match by anonymousId")
+		require.True(t, suppressed("source1", model.NewUser("", "anonymous2", " Jane@Example.com")), "it should match by hashed email")
+		require.False(t, suppressed("source2", model.NewUser("", "anonymous2", "jane@example.com")), "it should match the email for its sources only")
+		require.True(t, suppressed("source2", model.NewUser("test-user", "", "")), "it should match by userId prefix")
+		require.False(t, suppressed("source2", model.NewUser("user-test-", "", "")))
+		require.True(t, suppressed("source2", model.NewUser("", "qa-42-bot", "")), "it should match by anonymousId wildcard")
+		require.False(t, suppressed("source2", model.NewUser("qa-42-bot", "qa-42-bots", "")))
+		require.False(t, suppressed("source2", model.NewUser("user2", "anonymous2", "john@example.com")))
+
+		s, err := repo.Suppressed("workspaceY", "test-user", "source1")
+		require.NoError(t, err)
+		require.True(t, s, "it should match userId patterns when suppressing by userId")
+
+		require.NoError(t, repo.Add([]model.Suppression{
+			{
+				WorkspaceID: "workspaceY",
+				Canceled:    true,
+				Match:       model.MatchPrefix,
+				Value:       "test-",
+				SourceIDs:   []string{},
+			},
+		}, token))
+		require.False(t, suppressed("source2", model.NewUser("test-user", "", "")), "it should not match by a canceled pattern")
+	})
+
+	t.Run("listing suppressions", func(t *testing.T) {
+		suppressions, err := repo.List("workspaceY")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []model.Suppression{
+			{WorkspaceID: "workspaceY", Field: model.FieldAnonymousID, Match: model.MatchExact, Value: "anonymous1", SourceIDs: []string{}},
+			{WorkspaceID: "workspaceY", Field: model.FieldEmail, Match: model.MatchExact, Value: model.HashEmail("jane@example.com"), SourceIDs: []string{"source1"}},
+			{WorkspaceID: "workspaceY", Field: model.FieldAnonymousID, Match: model.MatchWildcard, Value: "qa-*-bot", SourceIDs: []string{}},
+		}, suppressions, "it should list the suppressions of the workspace, without the canceled ones")
+
+		suppressions, err = repo.List("workspace2")
+		require.NoError(t, err)
+		require.Equal(t, []model.Suppression{
+			{WorkspaceID: "workspace2", UserID: "user2", Field: model.FieldUserID, Match: model.MatchExact, Value: "user2", SourceIDs: []string{"source1"}},
+		}, suppressions)
+
+		suppressions, err = repo.List("workspaceX")
+		require.NoError(t, err)
+		require.Empty(t, suppressions, "it should not list the suppressions canceled for all their sources")
+	})
 }
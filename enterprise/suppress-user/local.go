@@ -0,0 +1,57 @@
+package suppression
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
+)
+
+// localRepository lets suppressions be added locally, e.g. through the admin interface or a suppressions file,
+// besides the ones synced from the control plane, without changing the token the syncer resumes from.
+type localRepository struct {
+	Repository
+	// defaultWorkspaceID is used for the local suppressions without a workspace, empty if there isn't one (multi-tenant)
+	defaultWorkspaceID string
+	// mu serializes the additions, so that local ones can't set back the token of the syncer
+	mu sync.Mutex
+}
+
+func newLocalRepository(r Repository, defaultWorkspaceID string) *localRepository {
+	return &localRepository{Repository: r, defaultWorkspaceID: defaultWorkspaceID}
+}
+
+// Add adds the given suppressions to the repository
+func (r *localRepository) Add(suppressions []model.Suppression, token []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Repository.Add(suppressions, token)
+}
+
+// AddLocal validates and adds the given suppressions to the repository, keeping its current token.
+// Raw emails of email suppressions are hashed, since the emails of the users are matched by their hash.
+func (r *localRepository) AddLocal(suppressions []model.Suppression) error {
+	for i := range suppressions {
+		suppression := &suppressions[i]
+		if suppression.WorkspaceID == "" {
+			suppression.WorkspaceID = r.defaultWorkspaceID
+		}
+		if suppression.WorkspaceID == "" {
+			return fmt.Errorf("suppression %d: missing workspaceId", i+1)
+		}
+		if field, match, value := suppression.Rule(); field == model.FieldEmail && match == model.MatchExact && value != "" {
+			suppression.Value = model.NormalizeEmail(value)
+		}
+		if err := suppression.Validate(); err != nil {
+			return fmt.Errorf("suppression %d: %w", i+1, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, err := r.Repository.GetToken()
+	if err != nil {
+		return fmt.Errorf("could not get token: %w", err)
+	}
+	return r.Repository.Add(suppressions, token)
+}
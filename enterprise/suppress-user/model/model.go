@@ -1,6 +1,13 @@
 package model
 
-import "errors"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
 
 var (
 	ErrRestoring    = errors.New("repository is restoring")
@@ -8,9 +15,176 @@ var (
 )
 var Wildcard = "*"
 
+// Fields of the users matched by the suppressions
+const (
+	FieldUserID      = "userId"
+	FieldAnonymousID = "anonymousId"
+	// FieldEmail is matched against the hex encoded SHA-256 hash of the trimmed, lower cased email of the users, see [HashEmail]
+	FieldEmail = "email"
+)
+
+// Ways the suppressions match the field of the users
+const (
+	MatchExact  = "exact"
+	MatchPrefix = "prefix"
+	// MatchWildcard matches patterns where * matches any sequence of characters, e.g. test-*-user for userIds or anonymousIds
+	MatchWildcard = "wildcard"
+)
+
 type Suppression struct {
 	WorkspaceID string   `json:"workspaceId"`
 	Canceled    bool     `json:"canceled"`
 	UserID      string   `json:"userId"`
 	SourceIDs   []string `json:"sourceIds"`
+	// Field is the field of the users matched by the suppression, userId if empty
+	Field string `json:"field,omitempty"`
+	// Match is how the field is matched, exact if empty
+	Match string `json:"match,omitempty"`
+	// Value is the value or pattern matched against the field, the UserID if empty
+	Value string `json:"value,omitempty"`
+}
+
+// Rule returns the field, the way it is matched and the value matched by the suppression, with their defaults applied
+func (s *Suppression) Rule() (field, match, value string) {
+	field, match, value = s.Field, s.Match, s.Value
+	if field == "" {
+		field = FieldUserID
+	}
+	if match == "" {
+		match = MatchExact
+	}
+	if value == "" {
+		value = s.UserID
+	}
+	return field, match, value
+}
+
+// Validate returns an error if the suppression can't match any user
+func (s *Suppression) Validate() error {
+	field, match, value := s.Rule()
+	switch field {
+	case FieldUserID, FieldAnonymousID, FieldEmail:
+	default:
+		return fmt.Errorf("invalid field %q", field)
+	}
+	switch match {
+	case MatchExact:
+	case MatchPrefix, MatchWildcard:
+		if field == FieldEmail {
+			return fmt.Errorf("%s match not supported for hashed field %s", match, field)
+		}
+	default:
+		return fmt.Errorf("invalid match %q", match)
+	}
+	if value == "" {
+		return errors.New("empty value")
+	}
+	return nil
+}
+
+// NewSuppressions returns the suppressions of a rule for its sources, with a separate one for all sources (*)
+func NewSuppressions(workspaceID, field, match, value string, sourceIDs map[string]struct{}) []Suppression {
+	var suppressions []Suppression
+	newSuppression := func(sourceIDs []string) Suppression {
+		s := Suppression{WorkspaceID: workspaceID, Field: field, Match: match, Value: value, SourceIDs: sourceIDs}
+		if field == FieldUserID {
+			s.UserID = value
+		}
+		return s
+	}
+	var sources []string
+	for sourceID := range sourceIDs {
+		if sourceID == Wildcard {
+			suppressions = append(suppressions, newSuppression([]string{}))
+			continue
+		}
+		sources = append(sources, sourceID)
+	}
+	if len(sources) > 0 {
+		sort.Strings(sources)
+		suppressions = append(suppressions, newSuppression(sources))
+	}
+	return suppressions
+}
+
+// User is what identifies the user of an event, matched against the suppressions
+type User struct {
+	UserID      string
+	AnonymousID string
+	// EmailHash is the hash of the email of the user, see [HashEmail]
+	EmailHash string
+}
+
+// NewUser returns the user identified by the userId, anonymousId and email of an event
+func NewUser(userID, anonymousID, email string) *User {
+	return &User{UserID: userID, AnonymousID: anonymousID, EmailHash: HashEmail(email)}
+}
+
+// Field returns the value of the field of the user, empty if the user doesn't have it
+func (u *User) Field(field string) string {
+	switch field {
+	case FieldUserID:
+		return u.UserID
+	case FieldAnonymousID:
+		return u.AnonymousID
+	case FieldEmail:
+		return u.EmailHash
+	}
+	return ""
+}
+
+// Fields are the fields of the users matched by the suppressions
+var Fields = []string{FieldUserID, FieldAnonymousID, FieldEmail}
+
+// HashEmail returns the hex encoded SHA-256 hash of the trimmed, lower cased email, or an empty string for an empty email
+func HashEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(email))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeEmail returns the value of an email suppression as matched against the users: a hash is lower cased,
+// any other value is taken for a raw email and hashed, see [HashEmail]
+func NormalizeEmail(value string) string {
+	if len(value) == 2*sha256.Size {
+		if _, err := hex.DecodeString(value); err == nil {
+			return strings.ToLower(value)
+		}
+	}
+	return HashEmail(value)
+}
+
+// MatchPattern returns true if the value matches the pattern, by prefix or wildcard
+func MatchPattern(match, pattern, value string) bool {
+	switch match {
+	case MatchPrefix:
+		return strings.HasPrefix(value, pattern)
+	case MatchWildcard:
+		return matchWildcard(pattern, value)
+	}
+	return false
+}
+
+// matchWildcard matches the value against the pattern, where * matches any sequence of characters
+func matchWildcard(pattern, value string) bool {
+	parts := strings.Split(pattern, Wildcard)
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
 }
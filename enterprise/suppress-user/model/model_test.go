@@ -0,0 +1,47 @@
+package model_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/enterprise/suppress-user/model"
+)
+
+func TestMatchPattern(t *testing.T) {
+	for _, tc := range []struct {
+		match, pattern, value string
+		matches               bool
+	}{
+		{model.MatchPrefix, "test-", "test-user", true},
+		{model.MatchPrefix, "test-", "user-test-", false},
+		{model.MatchWildcard, "test-*", "test-user", true},
+		{model.MatchWildcard, "*@example.com", "jane@example.com", true},
+		{model.MatchWildcard, "*@example.com", "jane@example.org", false},
+		{model.MatchWildcard, "qa-*-bot-*", "qa-1-bot-2", true},
+		{model.MatchWildcard, "a*a", "a", false},
+		{model.MatchWildcard, "a*a", "aa", true},
+		{model.MatchWildcard, "exact", "exact", true},
+		{model.MatchWildcard, "exact", "exactly", false},
+		{model.MatchExact, "exact", "exact", false},
+	} {
+		require.Equal(t, tc.matches, model.MatchPattern(tc.match, tc.pattern, tc.value), "%s %q %q", tc.match, tc.pattern, tc.value)
+	}
+}
+
+func TestSuppressionValidate(t *testing.T) {
+	require.NoError(t, (&model.Suppression{UserID: "user1"}).Validate())
+	require.NoError(t, (&model.Suppression{Field: model.FieldEmail, Value: model.HashEmail("jane@example.com")}).Validate())
+	require.Error(t, (&model.Suppression{}).Validate())
+	require.Error(t, (&model.Suppression{Field: "phone", Value: "1"}).Validate())
+	require.Error(t, (&model.Suppression{Match: "regex", Value: "1"}).Validate())
+	require.Error(t, (&model.Suppression{Field: model.FieldEmail, Match: model.MatchPrefix, Value: "jane"}).Validate())
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	hash := model.HashEmail("jane@example.com")
+	require.Equal(t, hash, model.NormalizeEmail(hash))
+	require.Equal(t, hash, model.NormalizeEmail(strings.ToUpper(hash)))
+	require.Equal(t, hash, model.NormalizeEmail(" Jane@Example.com "))
+}
@@ -5,3 +5,7 @@ type NOOP struct{}
 func (*NOOP) IsSuppressedUser(_, _, _ string) bool {
 	return false
 }
+
+func (*NOOP) IsSuppressedUserEvent(_, _, _, _, _ string) bool {
+	return false
+}
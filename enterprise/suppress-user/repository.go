@@ -23,6 +23,12 @@ type Repository interface {
 	// Suppressed returns true if the given user is suppressed, false otherwise
 	Suppressed(workspaceID, userID, sourceID string) (bool, error)
 
+	// SuppressedUser returns true if the user is suppressed by any of its fields, matched exactly or by pattern, false otherwise
+	SuppressedUser(workspaceID, sourceID string, user *model.User) (bool, error)
+
+	// List returns the suppressions of the workspace
+	List(workspaceID string) ([]model.Suppression, error)
+
 	// Backup writes a backup of the repository to the given writer
 	Backup(w io.Writer) error
 
@@ -0,0 +1,264 @@
+package event_schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff"
+
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+)
+
+// Kinds of changes detected between the schema versions of an event model
+const (
+	PropertyAdded   = "property_added"
+	PropertyRemoved = "property_removed"
+	TypeChanged     = "type_changed"
+)
+
+// SchemaChangeT is a change of a property between two schema versions
+type SchemaChangeT struct {
+	Kind         string
+	Property     string
+	PreviousType string `json:",omitempty"`
+	Type         string `json:",omitempty"`
+}
+
+// Breaking returns true if the change can break the consumers of the event, i.e. a property was removed or changed its type
+func (c SchemaChangeT) Breaking() bool {
+	return c.Kind != PropertyAdded
+}
+
+// SchemaDriftT is the notification sent when an event model gets a new schema version, which differs from the schema of the model
+type SchemaDriftT struct {
+	WriteKey        string
+	EventType       string
+	EventIdentifier string
+	EventModelID    string
+	VersionID       string
+	DetectedAt      time.Time
+	Breaking        bool
+	Changes         []SchemaChangeT
+}
+
+// driftSubscriptionT is the subscription of a write key to the drift notifications, see EventSchemas.driftNotifications
+type driftSubscriptionT struct {
+	WebhookURL   string `json:"webhookUrl"`
+	BreakingOnly bool   `json:"breakingOnly"`
+}
+
+// detectSchemaChanges returns the changes of the properties to the current schema, sorted by property.
+// Properties and types are new if they aren't in the merged schema of the event model, where the types are lists of the types seen,
+// while properties are removed if they are in the latest schema version, since a version rarely has all the properties ever seen.
+func detectSchemaChanges(merged, latest, current map[string]string) []SchemaChangeT {
+	var changes []SchemaChangeT
+	for property, propertyType := range current {
+		mergedType, ok := merged[property]
+		if !ok {
+			changes = append(changes, SchemaChangeT{Kind: PropertyAdded, Property: property, Type: propertyType})
+			continue
+		}
+		if !containsType(mergedType, propertyType) {
+			changes = append(changes, SchemaChangeT{Kind: TypeChanged, Property: property, PreviousType: mergedType, Type: propertyType})
+		}
+	}
+	for property, latestType := range latest {
+		if _, ok := current[property]; !ok {
+			changes = append(changes, SchemaChangeT{Kind: PropertyRemoved, Property: property, PreviousType: latestType})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Property != changes[j].Property {
+			return changes[i].Property < changes[j].Property
+		}
+		return changes[i].Kind < changes[j].Kind
+	})
+	return changes
+}
+
+// containsType returns true if the type is one of the comma separated types
+func containsType(types, propertyType string) bool {
+	for _, t := range strings.Split(types, ",") {
+		if t == propertyType {
+			return true
+		}
+	}
+	return false
+}
+
+// detectSchemaDrift compares the new schema version of the event model with the schema of the model, merged from its previous versions,
+// and with the latest seen of them, and notifies about the changes. It must be called before merging and caching the new version.
+// Nothing is detected for the first schema version of an event model.
+func (manager *EventSchemaManagerT) detectSchemaDrift(eventModel *EventModelT, schemaVersion *SchemaVersionT) {
+	if !driftDetectionEnabled {
+		return
+	}
+	mergedSchema := make(map[string]string)
+	if err := json.Unmarshal(eventModel.Schema, &mergedSchema); err != nil {
+		pkgLogger.Errorf("[EventSchemas] Failed to unmarshal schema of event model %s: %v", eventModel.UUID, err)
+		return
+	}
+	if len(mergedSchema) == 0 {
+		return
+	}
+	latestSchema := make(map[string]string)
+	if latestVersion := manager.latestSeenVersion(eventModel.UUID); latestVersion != nil {
+		if err := json.Unmarshal(latestVersion.Schema, &latestSchema); err != nil {
+			pkgLogger.Errorf("[EventSchemas] Failed to unmarshal schema of version %s: %v", latestVersion.UUID, err)
+			return
+		}
+	}
+	schema := make(map[string]string)
+	if err := json.Unmarshal(schemaVersion.Schema, &schema); err != nil {
+		pkgLogger.Errorf("[EventSchemas] Failed to unmarshal schema of version %s: %v", schemaVersion.UUID, err)
+		return
+	}
+	changes := detectSchemaChanges(mergedSchema, latestSchema, schema)
+	if len(changes) == 0 {
+		return
+	}
+
+	drift := &SchemaDriftT{
+		WriteKey:        eventModel.WriteKey,
+		EventType:       eventModel.EventType,
+		EventIdentifier: eventModel.EventIdentifier,
+		EventModelID:    eventModel.UUID,
+		VersionID:       schemaVersion.UUID,
+		DetectedAt:      timeutil.Now(),
+		Changes:         changes,
+	}
+	for _, change := range changes {
+		drift.Breaking = drift.Breaking || change.Breaking()
+		stats.Default.NewTaggedStat("event_schema_drift", stats.CountType, stats.Tags{
+			"module":          "event_schemas",
+			"writeKey":        drift.WriteKey,
+			"eventIdentifier": drift.EventIdentifier,
+			"kind":            change.Kind,
+			"breaking":        fmt.Sprint(change.Breaking()),
+		}).Increment()
+	}
+	manager.driftNotifier.notify(drift)
+}
+
+// driftNotifierT sends the drift notifications to the webhooks of the write keys subscribed to them
+type driftNotifierT struct {
+	client        *http.Client
+	subscriptions func() map[string]interface{}
+	queue         chan *SchemaDriftT
+}
+
+func newDriftNotifier() *driftNotifierT {
+	return &driftNotifierT{
+		client:        &http.Client{Timeout: driftWebhookTimeout},
+		subscriptions: func() map[string]interface{} { return driftSubscriptions },
+		queue:         make(chan *SchemaDriftT, 1000),
+	}
+}
+
+// subscription returns the subscription of the write key, or the one of all write keys (*)
+func (n *driftNotifierT) subscription(writeKey string) (*driftSubscriptionT, bool) {
+	var value interface{}
+	for key, v := range n.subscriptions() {
+		// keys of maps in config files are lower cased
+		if key == writeKey || strings.EqualFold(key, writeKey) {
+			value = v
+			break
+		}
+		if key == "*" {
+			value = v
+		}
+	}
+	if value == nil {
+		return nil, false
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		pkgLogger.Errorf("[EventSchemas] Invalid drift subscription of write key %s: %v", writeKey, err)
+		return nil, false
+	}
+	var subscription driftSubscriptionT
+	if err := json.Unmarshal(raw, &subscription); err != nil || subscription.WebhookURL == "" {
+		pkgLogger.Errorf("[EventSchemas] Invalid drift subscription of write key %s: %s", writeKey, raw)
+		return nil, false
+	}
+	return &subscription, true
+}
+
+// notify queues the drift for the webhook of the subscription of its write key, if any, dropping it if the queue is full
+func (n *driftNotifierT) notify(drift *SchemaDriftT) {
+	subscription, ok := n.subscription(drift.WriteKey)
+	if !ok || (subscription.BreakingOnly && !drift.Breaking) {
+		return
+	}
+	select {
+	case n.queue <- drift:
+	default:
+		pkgLogger.Warnf("[EventSchemas] Dropping schema drift notification of event model %s, queue is full", drift.EventModelID)
+		stats.Default.NewTaggedStat("event_schema_drift_notifications_dropped", stats.CountType, stats.Tags{"module": "event_schemas", "writeKey": drift.WriteKey}).Increment()
+	}
+}
+
+// run sends the queued notifications until the context is done
+func (n *driftNotifierT) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case drift := <-n.queue:
+			subscription, ok := n.subscription(drift.WriteKey)
+			if !ok {
+				continue
+			}
+			if err := n.send(ctx, subscription.WebhookURL, drift); err != nil {
+				pkgLogger.Errorf("[EventSchemas] Failed to send schema drift notification of event model %s: %v", drift.EventModelID, err)
+				stats.Default.NewTaggedStat("event_schema_drift_notifications_failed", stats.CountType, stats.Tags{"module": "event_schemas", "writeKey": drift.WriteKey}).Increment()
+			}
+		}
+	}
+}
+
+// send posts the drift to the webhook, retrying on failures
+func (n *driftNotifierT) send(ctx context.Context, url string, drift *SchemaDriftT) error {
+	body, err := json.Marshal(drift)
+	if err != nil {
+		return err
+	}
+	operation := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := n.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			return backoff.Permanent(fmt.Errorf("webhook responded with status %d", resp.StatusCode))
+		}
+		return nil
+	}
+	return backoff.Retry(operation, backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3), ctx))
+}
+
+// latestSeenVersion returns the last seen of the cached schema versions of the event model, nil if there isn't any
+func (manager *EventSchemaManagerT) latestSeenVersion(modelID string) *SchemaVersionT {
+	var latestSeenVersion *SchemaVersionT
+	for _, schemaVersion := range manager.schemaVersionMap[modelID] {
+		if latestSeenVersion == nil || schemaVersion.LastSeen.After(latestSeenVersion.LastSeen) {
+			latestSeenVersion = schemaVersion
+		}
+	}
+	return latestSeenVersion
+}
@@ -0,0 +1,137 @@
+package event_schema
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectSchemaChanges(t *testing.T) {
+	changes := detectSchemaChanges(
+		map[string]string{"event": "string", "properties.price": "float64", "properties.coupon": "string", "properties.tax": "float64"},
+		map[string]string{"event": "string", "properties.price": "float64", "properties.coupon": "string"},
+		map[string]string{"event": "string", "properties.price": "string", "properties.currency": "string"},
+	)
+	require.Equal(t, []SchemaChangeT{
+		{Kind: PropertyRemoved, Property: "properties.coupon", PreviousType: "string"},
+		{Kind: PropertyAdded, Property: "properties.currency", Type: "string"},
+		{Kind: TypeChanged, Property: "properties.price", PreviousType: "float64", Type: "string"},
+	}, changes)
+	require.True(t, changes[0].Breaking())
+	require.False(t, changes[1].Breaking())
+	require.True(t, changes[2].Breaking())
+
+	require.Empty(t, detectSchemaChanges(map[string]string{"event": "string"}, map[string]string{"event": "string"}, map[string]string{"event": "string"}))
+	require.Empty(t, detectSchemaChanges(map[string]string{"properties.total": "float64,int"}, map[string]string{"properties.total": "float64"}, map[string]string{"properties.total": "int"}), "any of the merged types")
+	require.Empty(t, detectSchemaChanges(map[string]string{"event": "string", "properties.tax": "float64"}, map[string]string{"event": "string"}, map[string]string{"properties.tax": "float64", "event": "string"}), "properties seen in older versions")
+	require.Equal(t, []SchemaChangeT{
+		{Kind: TypeChanged, Property: "properties.total", PreviousType: "float64,int64", Type: "int"},
+	}, detectSchemaChanges(map[string]string{"properties.total": "float64,int64"}, map[string]string{"properties.total": "int64"}, map[string]string{"properties.total": "int"}))
+}
+
+func TestDriftSubscription(t *testing.T) {
+	Init2()
+	n := newDriftNotifier()
+	n.subscriptions = func() map[string]interface{} {
+		return map[string]interface{}{
+			"writekey-1": map[string]interface{}{"webhookUrl": "http://one", "breakingOnly": true},
+			"*":          map[string]interface{}{"webhookUrl": "http://all"},
+			"writeKey-3": map[string]interface{}{"breakingOnly": true},
+		}
+	}
+
+	subscription, ok := n.subscription("writeKey-1")
+	require.True(t, ok, "write keys are matched regardless of their case")
+	require.Equal(t, &driftSubscriptionT{WebhookURL: "http://one", BreakingOnly: true}, subscription)
+
+	subscription, ok = n.subscription("writeKey-2")
+	require.True(t, ok)
+	require.Equal(t, &driftSubscriptionT{WebhookURL: "http://all"}, subscription)
+
+	_, ok = n.subscription("writeKey-3")
+	require.False(t, ok, "subscriptions without a webhook are invalid")
+
+	n.notify(&SchemaDriftT{WriteKey: "writeKey-1"})
+	require.Len(t, n.queue, 0, "non breaking drifts are skipped by breaking only subscriptions")
+	n.notify(&SchemaDriftT{WriteKey: "writeKey-1", Breaking: true})
+	n.notify(&SchemaDriftT{WriteKey: "writeKey-2"})
+	require.Len(t, n.queue, 2)
+}
+
+func TestSchemaDriftNotifications(t *testing.T) {
+	Init2()
+	driftDetectionEnabled = true
+	defer func() { driftDetectionEnabled = false }()
+
+	received := make(chan SchemaDriftT, 10)
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var drift SchemaDriftT
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&drift))
+		received <- drift
+	}))
+	defer srv.Close()
+
+	manager := getEventSchemaManager(nil, false)
+	manager.driftNotifier.subscriptions = func() map[string]interface{} {
+		return map[string]interface{}{"my-write-key": map[string]interface{}{"webhookUrl": srv.URL}}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		manager.driftNotifier.run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	manager.handleEvent("my-write-key", EventT{"type": "track", "event": "Order Completed", "properties": map[string]interface{}{"price": 10.5, "coupon": "SALE"}})
+	manager.handleEvent("my-write-key", EventT{"type": "track", "event": "Order Completed", "properties": map[string]interface{}{"price": 20.5, "coupon": "SALE"}})
+	require.Len(t, manager.driftNotifier.queue, 0, "no drift for the first schema version, nor for known ones")
+
+	manager.handleEvent("my-write-key", EventT{"type": "track", "event": "Order Completed", "properties": map[string]interface{}{"price": "20.5", "currency": "USD"}})
+
+	receive := func() SchemaDriftT {
+		t.Helper()
+		select {
+		case drift := <-received:
+			return drift
+		case <-time.After(10 * time.Second):
+			t.Fatal("drift notification not received")
+		}
+		return SchemaDriftT{}
+	}
+	drift := receive()
+	eventModel := manager.eventModelMap["my-write-key"]["track"]["Order Completed"]
+	require.Equal(t, "my-write-key", drift.WriteKey)
+	require.Equal(t, "track", drift.EventType)
+	require.Equal(t, "Order Completed", drift.EventIdentifier)
+	require.Equal(t, eventModel.UUID, drift.EventModelID)
+	require.NotEmpty(t, drift.VersionID)
+	require.True(t, drift.Breaking)
+	require.Equal(t, []SchemaChangeT{
+		{Kind: PropertyRemoved, Property: "properties.coupon", PreviousType: "string"},
+		{Kind: PropertyAdded, Property: "properties.currency", Type: "string"},
+		{Kind: TypeChanged, Property: "properties.price", PreviousType: "float64", Type: "string"},
+	}, drift.Changes)
+	require.Equal(t, 2, attempts, "failed notifications are retried")
+
+	manager.handleEvent("my-write-key", EventT{"type": "track", "event": "Order Completed", "properties": map[string]interface{}{"price": 30.5, "currency": "USD"}})
+	manager.handleEvent("my-write-key", EventT{"type": "track", "event": "Order Completed", "properties": map[string]interface{}{"price": 40.5}})
+	drift = receive()
+	require.Equal(t, []SchemaChangeT{
+		{Kind: PropertyRemoved, Property: "properties.currency", PreviousType: "string"},
+	}, drift.Changes, "properties are removed from the latest version only, types and properties seen in any previous version aren't new")
+}
@@ -121,6 +121,7 @@ type EventSchemaManagerT struct {
 	eventModelLock       sync.RWMutex
 	schemaVersionLock    sync.RWMutex
 	disableInMemoryCache bool
+	driftNotifier        *driftNotifierT
 }
 
 type OffloadedModelT struct {
@@ -161,6 +162,14 @@ var (
 	offloadLoopInterval             time.Duration
 	offloadThreshold                time.Duration
 	areEventSchemasPopulated        bool
+	driftDetectionEnabled           bool
+	driftSubscriptions              map[string]interface{}
+	driftWebhookTimeout             time.Duration
+	trackingPlanRequiredRatio       float64
+	trackingPlanMaxEnumValues       int
+	trackingPlanEnumCoverage        float64
+	trackingPlanEnumMinCount        int
+	trackingPlanMaxExamples         int
 )
 
 const (
@@ -197,6 +206,15 @@ func loadConfig() {
 	config.RegisterBoolConfigVariable(false, &shouldCaptureNilAsUnknowns, true, "EventSchemas.captureUnknowns")
 	config.RegisterDurationConfigVariable(60, &offloadLoopInterval, true, time.Second, []string{"EventSchemas.offloadLoopInterval"}...)
 	config.RegisterDurationConfigVariable(1800, &offloadThreshold, true, time.Second, []string{"EventSchemas.offloadThreshold"}...)
+	config.RegisterBoolConfigVariable(false, &driftDetectionEnabled, true, "EventSchemas.driftDetection")
+	// writeKey (or * for all write keys) -> {"webhookUrl": "...", "breakingOnly": false}
+	config.RegisterStringMapConfigVariable(nil, &driftSubscriptions, true, "EventSchemas.driftNotifications")
+	driftWebhookTimeout = config.GetDuration("EventSchemas.driftWebhookTimeout", 10, time.Second)
+	config.RegisterFloat64ConfigVariable(1, &trackingPlanRequiredRatio, true, "EventSchemas.trackingPlan.requiredRatio")
+	config.RegisterIntConfigVariable(10, &trackingPlanMaxEnumValues, true, 1, "EventSchemas.trackingPlan.maxEnumValues")
+	config.RegisterFloat64ConfigVariable(0.95, &trackingPlanEnumCoverage, true, "EventSchemas.trackingPlan.enumCoverage")
+	config.RegisterIntConfigVariable(50, &trackingPlanEnumMinCount, true, 1, "EventSchemas.trackingPlan.enumMinCount")
+	config.RegisterIntConfigVariable(3, &trackingPlanMaxExamples, true, 1, "EventSchemas.trackingPlan.maxExamples")
 
 	if adminPassword == "rudderstack" {
 		pkgLogger.Warn("[EventSchemas] You are using default password. Please change it by setting env variable RUDDER_ADMIN_PASSWORD")
@@ -497,6 +515,7 @@ func (manager *EventSchemaManagerT) createModel(writeKey, eventType, eventIdenti
 func (manager *EventSchemaManagerT) createSchema(schema map[string]string, schemaHash string, eventModel *EventModelT, totalSchemaVersions int, archiveOldestLastSeenVersion func()) *SchemaVersionT {
 	versionID := uuid.New().String()
 	schemaVersion := manager.NewSchemaVersion(versionID, schema, schemaHash, eventModel.UUID)
+	manager.detectSchemaDrift(eventModel, schemaVersion)
 	eventModel.mergeSchema(schemaVersion)
 
 	if totalSchemaVersions >= schemaVersionPerEventModelLimit {
@@ -1137,6 +1156,7 @@ func getEventSchemaManager(dbHandle *sql.DB, disableInMemoryCache bool) *EventSc
 		disableInMemoryCache: disableInMemoryCache,
 		eventModelMap:        make(EventModelMapT),
 		schemaVersionMap:     make(SchemaVersionMapT),
+		driftNotifier:        newDriftNotifier(),
 	}
 }
 
@@ -1169,5 +1189,9 @@ func (manager *EventSchemaManagerT) Setup() {
 		manager.offloadEventSchemas()
 	})
 
+	rruntime.GoForWarehouse(func() {
+		manager.driftNotifier.run(context.TODO())
+	})
+
 	pkgLogger.Info("[EventSchemas] Set up eventSchemas successful.")
 }
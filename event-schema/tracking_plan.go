@@ -0,0 +1,424 @@
+package event_schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jeremywohl/flatten"
+
+	"github.com/rudderlabs/rudder-server/gateway/response"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+const jsonSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// trackingPlanEventT is what is observed of an event model, to build its definition in the tracking plan
+type trackingPlanEventT struct {
+	eventModel *EventModelT
+	// keyCounts is the number of events seen with each flattened key, across the schema versions of the event model
+	keyCounts map[string]int64
+	// totalCount is the number of events seen, across the schema versions of the event model
+	totalCount int64
+	// metadata has the frequent values of the keys and the sampled events of the event model, nil if it isn't available
+	metadata *MetaDataT
+}
+
+// trackingPlanBuilderT builds tracking plan drafts, as JSON Schema draft-07 documents, from the observed event models
+type trackingPlanBuilderT struct {
+	// requiredRatio is the minimum ratio of the events having a property, for the property to be required
+	requiredRatio float64
+	// maxEnumValues is the maximum number of values of the string properties detected as enums
+	maxEnumValues int
+	// enumCoverage is the minimum ratio of the events whose value is one of the frequent values of a string property, for it to be detected as an enum
+	enumCoverage float64
+	// enumMinCount is the minimum number of events seen before detecting enums
+	enumMinCount int64
+	// maxExamples is the maximum number of example values of a property
+	maxExamples int
+}
+
+func newTrackingPlanBuilder() *trackingPlanBuilderT {
+	return &trackingPlanBuilderT{
+		requiredRatio: trackingPlanRequiredRatio,
+		maxEnumValues: trackingPlanMaxEnumValues,
+		enumCoverage:  trackingPlanEnumCoverage,
+		enumMinCount:  int64(trackingPlanEnumMinCount),
+		maxExamples:   trackingPlanMaxExamples,
+	}
+}
+
+// GetTrackingPlan returns a tracking plan draft for the write key, as a JSON Schema draft-07 document
+// with a definition for each of its event models
+func (manager *EventSchemaManagerT) GetTrackingPlan(w http.ResponseWriter, r *http.Request) {
+	err := handleBasicAuth(r)
+	if err != nil {
+		http.Error(w, response.MakeResponse(err.Error()), 400)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, response.MakeResponse("Only HTTP GET method is supported"), 400)
+		return
+	}
+
+	writeKey := r.URL.Query().Get("WriteKey")
+	if writeKey == "" {
+		http.Error(w, response.MakeResponse("Mandatory field: WriteKey missing"), 400)
+		return
+	}
+
+	eventModels := manager.fetchEventModelsByWriteKey(writeKey)
+	if len(eventModels) == 0 {
+		http.Error(w, response.MakeResponse("No event models exists to create a tracking plan."), 404)
+		return
+	}
+
+	events := make([]*trackingPlanEventT, 0, len(eventModels))
+	for _, eventModel := range eventModels {
+		event, err := manager.trackingPlanEvent(eventModel)
+		if err != nil {
+			pkgLogger.Errorf("Error while getting key counts: %v for ID: %v", err, eventModel.ID)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	trackingPlanJSON, err := json.Marshal(newTrackingPlanBuilder().build(writeKey, events))
+	if err != nil {
+		http.Error(w, response.MakeResponse("Internal Error: Failed to Marshal tracking plan"), 500)
+		return
+	}
+
+	_, _ = w.Write(trackingPlanJSON)
+}
+
+// trackingPlanEvent returns what is observed of the event model
+func (manager *EventSchemaManagerT) trackingPlanEvent(eventModel *EventModelT) (*trackingPlanEventT, error) {
+	event := &trackingPlanEventT{eventModel: eventModel}
+	var err error
+	if event.keyCounts, err = manager.getKeyCounts(eventModel.UUID); err != nil {
+		return nil, err
+	}
+	for _, schemaVersion := range manager.fetchSchemaVersionsByEventID(eventModel.UUID) {
+		event.totalCount += schemaVersion.TotalCount
+	}
+	if event.metadata, err = manager.fetchMetadataByEventModelID(eventModel.UUID); err != nil {
+		// the metadata is only available once the event model is flushed, the tracking plan is built without enums and examples
+		pkgLogger.Debugf("No metadata for event model %s: %v", eventModel.UUID, err)
+	}
+	return event, nil
+}
+
+// build returns the tracking plan of the write key, with a definition for each event and a oneOf referencing all of them
+func (b *trackingPlanBuilderT) build(writeKey string, events []*trackingPlanEventT) map[string]interface{} {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].eventModel.EventType != events[j].eventModel.EventType {
+			return events[i].eventModel.EventType < events[j].eventModel.EventType
+		}
+		return events[i].eventModel.EventIdentifier < events[j].eventModel.EventIdentifier
+	})
+
+	definitions := make(map[string]interface{})
+	oneOf := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		definition, err := b.eventDefinition(event)
+		if err != nil {
+			pkgLogger.Errorf("Error while building tracking plan definition: %v for ID: %v", err, event.eventModel.ID)
+			continue
+		}
+		name := definitionName(event.eventModel)
+		for i := 2; definitions[name] != nil; i++ {
+			name = fmt.Sprintf("%s_%d", definitionName(event.eventModel), i)
+		}
+		definitions[name] = definition
+		oneOf = append(oneOf, map[string]interface{}{"$ref": "#/definitions/" + name})
+	}
+
+	return map[string]interface{}{
+		"$schema":     jsonSchemaDraft07,
+		"title":       fmt.Sprintf("Tracking plan draft of write key %s", writeKey),
+		"description": fmt.Sprintf("Generated from %d observed event models", len(oneOf)),
+		"definitions": definitions,
+		"oneOf":       oneOf,
+	}
+}
+
+var definitionNameRegex = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// definitionName returns the name of the definition of the event model, which can be used as is in a $ref
+func definitionName(eventModel *EventModelT) string {
+	name := eventModel.EventType
+	if eventModel.EventIdentifier != "" {
+		name += "_" + eventModel.EventIdentifier
+	}
+	return definitionNameRegex.ReplaceAllString(name, "_")
+}
+
+// eventDefinition returns the schema of the event, with the type and name of the event as constants,
+// along with the schema of its properties (track, page, screen) or traits (identify, group)
+func (b *trackingPlanBuilderT) eventDefinition(event *trackingPlanEventT) (map[string]interface{}, error) {
+	eventModel := event.eventModel
+	properties := map[string]interface{}{
+		"type": map[string]interface{}{"const": eventModel.EventType},
+	}
+	required := []string{"type"}
+	title := eventModel.EventType
+	switch eventModel.EventType {
+	case "track":
+		properties["event"] = map[string]interface{}{"const": eventModel.EventIdentifier}
+		required = append(required, "event")
+		title = eventModel.EventIdentifier
+	case "page", "screen":
+		if name, ok := event.pageName(); ok {
+			properties["name"] = map[string]interface{}{"const": name}
+			required = append(required, "name")
+		}
+	}
+
+	var rootKey string
+	switch eventModel.EventType {
+	case "track", "screen", "page":
+		rootKey = "properties"
+	case "identify", "group":
+		rootKey = "traits"
+	}
+	if rootKey != "" {
+		flattenedSch := make(map[string]string)
+		if err := json.Unmarshal(eventModel.Schema, &flattenedSch); err != nil {
+			return nil, fmt.Errorf("unmarshalling schema: %w", err)
+		}
+		// the leaves of the unflattened schema are their flattened keys, to look up their types, counts and values
+		flattenedKeys := make(map[string]interface{})
+		for key := range flattenedSch {
+			if strings.HasPrefix(key, rootKey+".") {
+				flattenedKeys[key] = key
+			}
+		}
+		if len(flattenedKeys) > 0 {
+			unflattenedKeys, err := unflatten(flattenedKeys)
+			if err != nil {
+				return nil, fmt.Errorf("unflattening schema: %w", err)
+			}
+			if root, ok := unflattenedKeys[rootKey].(map[string]interface{}); ok {
+				s := &trackingPlanSchemaT{builder: b, event: event, types: flattenedSch, examples: sampledValues(event.metadata)}
+				rootSchema := s.nodeSchema(root)
+				// only the properties or traits are closed, the events having other keys, e.g. context, and nested objects being open
+				if rootSchema["type"] == "object" {
+					rootSchema["additionalProperties"] = false
+				}
+				properties[rootKey] = rootSchema
+				if b.isRequired(s.nodeCount(root), event.totalCount) {
+					required = append(required, rootKey)
+				}
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"title":       title,
+		"description": fmt.Sprintf("%s event observed %d times", eventModel.EventType, event.totalCount),
+		"type":        "object",
+		"properties":  properties,
+		"required":    required,
+	}, nil
+}
+
+// pageName returns the name of the page or screen events of the model, if they all have the same one
+func (event *trackingPlanEventT) pageName() (string, bool) {
+	if event.eventModel.EventIdentifier != "" {
+		return event.eventModel.EventIdentifier, true
+	}
+	if event.metadata == nil || event.totalCount == 0 || event.keyCounts["name"] != event.totalCount {
+		return "", false
+	}
+	if items := event.metadata.Counters["name"]; len(items) == 1 {
+		return items[0].Value, true
+	}
+	return "", false
+}
+
+// isRequired returns true if a key seen count times, out of the total count of its parent, is required
+func (b *trackingPlanBuilderT) isRequired(count, total int64) bool {
+	return total > 0 && float64(count) >= b.requiredRatio*float64(total)
+}
+
+// trackingPlanSchemaT builds the schema of the properties or traits of an event
+type trackingPlanSchemaT struct {
+	builder  *trackingPlanBuilderT
+	event    *trackingPlanEventT
+	types    map[string]string
+	examples map[string][]interface{}
+}
+
+// nodeCount returns the number of events having the node, i.e. its most frequent key
+func (s *trackingPlanSchemaT) nodeCount(node interface{}) int64 {
+	switch node := node.(type) {
+	case string:
+		return s.event.keyCounts[node]
+	case map[string]interface{}:
+		var count int64
+		for _, child := range node {
+			if c := s.nodeCount(child); c > count {
+				count = c
+			}
+		}
+		return count
+	}
+	return 0
+}
+
+// nodeSchema returns the schema of a node of the unflattened keys, with the properties of objects required
+// if they are present in enough of the events having the object
+func (s *trackingPlanSchemaT) nodeSchema(node interface{}) map[string]interface{} {
+	switch node := node.(type) {
+	case string:
+		return s.leafSchema(node)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(node))
+		for key := range node {
+			keys = append(keys, key)
+		}
+		if checkIfArray(node) {
+			// items are described by the first element, as in the json schemas of the event models
+			sort.Slice(keys, func(i, j int) bool {
+				a, _ := strconv.Atoi(keys[i])
+				b, _ := strconv.Atoi(keys[j])
+				return a < b
+			})
+			return map[string]interface{}{
+				"type":  "array",
+				"items": s.nodeSchema(node[keys[0]]),
+			}
+		}
+		sort.Strings(keys)
+		count := s.nodeCount(node)
+		properties := make(map[string]interface{}, len(node))
+		required := make([]string, 0)
+		for _, key := range keys {
+			properties[key] = s.nodeSchema(node[key])
+			if s.builder.isRequired(s.nodeCount(node[key]), count) {
+				required = append(required, key)
+			}
+		}
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]interface{}{}
+}
+
+// leafSchema returns the schema of the flattened key, with its types, enum and examples
+func (s *trackingPlanSchemaT) leafSchema(key string) map[string]interface{} {
+	types := jsonSchemaTypes(s.types[key])
+	schema := map[string]interface{}{
+		"type": types,
+	}
+	if len(types) == 1 && types[0] == "string" {
+		if enum := s.enum(key); len(enum) > 0 {
+			schema["enum"] = enum
+		}
+	}
+	if examples := s.examples[key]; len(examples) > 0 {
+		if len(examples) > s.builder.maxExamples {
+			examples = examples[:s.builder.maxExamples]
+		}
+		schema["examples"] = examples
+	}
+	return schema
+}
+
+// enum returns the values of the key if they are few and cover most of the events, nil otherwise
+func (s *trackingPlanSchemaT) enum(key string) []string {
+	if s.event.metadata == nil || s.event.keyCounts[key] < s.builder.enumMinCount {
+		return nil
+	}
+	items := s.event.metadata.Counters[key]
+	if len(items) == 0 || len(items) > s.builder.maxEnumValues {
+		return nil
+	}
+	var coverage float64
+	enum := make([]string, 0, len(items))
+	for _, item := range items {
+		coverage += item.Frequency
+		enum = append(enum, item.Value)
+	}
+	if coverage < s.builder.enumCoverage {
+		return nil
+	}
+	sort.Strings(enum)
+	return enum
+}
+
+// jsonSchemaTypes returns the json schema types of the comma separated go types of a key
+func jsonSchemaTypes(goTypes string) []string {
+	types := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, goType := range strings.Split(goTypes, ",") {
+		var t string
+		switch goType {
+		case "":
+			continue
+		case "unknown":
+			// nil values, captured as unknowns
+			t = "null"
+		case "[]interface {}":
+			t = "array"
+		default:
+			t = misc.GetJsonSchemaDTFromGoDT(goType)
+		}
+		if _, ok := seen[t]; !ok {
+			seen[t] = struct{}{}
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// sampledValues returns the distinct values of the flattened keys of the sampled events, in the order they are sampled
+func sampledValues(metadata *MetaDataT) map[string][]interface{} {
+	values := make(map[string][]interface{})
+	if metadata == nil {
+		return values
+	}
+	seen := make(map[string]map[string]struct{})
+	for _, sampledEvent := range metadata.SampledEvents {
+		var event map[string]interface{}
+		switch e := sampledEvent.(type) {
+		case map[string]interface{}:
+			event = e
+		case EventT:
+			event = e
+		default:
+			continue
+		}
+		flattenedEvent, err := flatten.Flatten(event, "", flatten.DotStyle)
+		if err != nil {
+			continue
+		}
+		for key, value := range flattenedEvent {
+			if value == nil {
+				continue
+			}
+			if _, ok := seen[key]; !ok {
+				seen[key] = make(map[string]struct{})
+			}
+			v := fmt.Sprintf("%v", value)
+			if _, ok := seen[key][v]; ok {
+				continue
+			}
+			seen[key][v] = struct{}{}
+			values[key] = append(values[key], value)
+		}
+	}
+	return values
+}
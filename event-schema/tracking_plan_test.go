@@ -0,0 +1,186 @@
+package event_schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackingPlan(t *testing.T) {
+	Init2()
+	b := &trackingPlanBuilderT{
+		requiredRatio: 1,
+		maxEnumValues: 3,
+		enumCoverage:  0.95,
+		enumMinCount:  10,
+		maxExamples:   2,
+	}
+
+	orderCompleted := &trackingPlanEventT{
+		eventModel: &EventModelT{
+			UUID:            "model-1",
+			EventType:       "track",
+			EventIdentifier: "Order Completed",
+			Schema: json.RawMessage(`{
+				"type": "string", "event": "string", "anonymousId": "string",
+				"properties.currency": "string",
+				"properties.total": "float64,int",
+				"properties.coupon": "string",
+				"properties.products.0.sku": "string",
+				"properties.products.0.quantity": "float64",
+				"properties.products.1.sku": "string"
+			}`),
+		},
+		totalCount: 100,
+		keyCounts: map[string]int64{
+			"type": 100, "event": 100, "anonymousId": 100,
+			"properties.currency":            100,
+			"properties.total":               100,
+			"properties.coupon":              20,
+			"properties.products.0.sku":      100,
+			"properties.products.0.quantity": 60,
+			"properties.products.1.sku":      30,
+		},
+		metadata: &MetaDataT{
+			Counters: map[string][]*CounterItem{
+				"properties.currency": {{Value: "USD", Frequency: 0.7}, {Value: "EUR", Frequency: 0.3}},
+				"properties.coupon":   {{Value: "SALE", Frequency: 0.6}, {Value: "VIP", Frequency: 0.2}},
+			},
+			SampledEvents: []interface{}{
+				map[string]interface{}{"properties": map[string]interface{}{"currency": "USD", "total": 10.5}},
+				map[string]interface{}{"properties": map[string]interface{}{"currency": "USD", "total": float64(20)}},
+				map[string]interface{}{"properties": map[string]interface{}{"currency": "EUR", "total": 30.5}},
+			},
+		},
+	}
+	identify := &trackingPlanEventT{
+		eventModel: &EventModelT{
+			UUID:      "model-2",
+			EventType: "identify",
+			Schema:    json.RawMessage(`{"type": "string", "traits.email": "string"}`),
+		},
+		totalCount: 5,
+		keyCounts:  map[string]int64{"type": 5, "traits.email": 4},
+	}
+	page := &trackingPlanEventT{
+		eventModel: &EventModelT{UUID: "model-3", EventType: "page", Schema: json.RawMessage(`{"type": "string"}`)},
+		totalCount: 1,
+		keyCounts:  map[string]int64{"type": 1},
+	}
+
+	trackingPlan := b.build("my-write-key", []*trackingPlanEventT{page, orderCompleted, identify})
+	actual, err := json.Marshal(trackingPlan)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"title": "Tracking plan draft of write key my-write-key",
+		"description": "Generated from 3 observed event models",
+		"definitions": {
+			"identify": {
+				"title": "identify",
+				"description": "identify event observed 5 times",
+				"type": "object",
+				"properties": {
+					"type": {"const": "identify"},
+					"traits": {
+						"type": "object",
+						"additionalProperties": false,
+						"properties": {
+							"email": {"type": ["string"]}
+						},
+						"required": ["email"]
+					}
+				},
+				"required": ["type"]
+			},
+			"page": {
+				"title": "page",
+				"description": "page event observed 1 times",
+				"type": "object",
+				"properties": {
+					"type": {"const": "page"}
+				},
+				"required": ["type"]
+			},
+			"track_Order_Completed": {
+				"title": "Order Completed",
+				"description": "track event observed 100 times",
+				"type": "object",
+				"properties": {
+					"type": {"const": "track"},
+					"event": {"const": "Order Completed"},
+					"properties": {
+						"type": "object",
+						"additionalProperties": false,
+						"properties": {
+							"coupon": {"type": ["string"]},
+							"currency": {"type": ["string"], "enum": ["EUR", "USD"], "examples": ["USD", "EUR"]},
+							"total": {"type": ["number", "integer"], "examples": [10.5, 20]},
+							"products": {
+								"type": "array",
+								"items": {
+									"type": "object",
+									"properties": {
+										"sku": {"type": ["string"]},
+										"quantity": {"type": ["number"]}
+									},
+									"required": ["sku"]
+								}
+							}
+						},
+						"required": ["currency", "products", "total"]
+					}
+				},
+				"required": ["type", "event", "properties"]
+			}
+		},
+		"oneOf": [
+			{"$ref": "#/definitions/identify"},
+			{"$ref": "#/definitions/page"},
+			{"$ref": "#/definitions/track_Order_Completed"}
+		]
+	}`, string(actual))
+
+	t.Run("enums need enough events", func(t *testing.T) {
+		b := *b
+		b.enumMinCount = 101
+		definitions := b.build("my-write-key", []*trackingPlanEventT{orderCompleted})["definitions"].(map[string]interface{})
+		currency := definitions["track_Order_Completed"].(map[string]interface{})["properties"].(map[string]interface{})["properties"].(map[string]interface{})["properties"].(map[string]interface{})["currency"]
+		require.NotContains(t, currency, "enum")
+	})
+
+	t.Run("page names", func(t *testing.T) {
+		screen := &trackingPlanEventT{
+			eventModel: &EventModelT{EventType: "screen", Schema: json.RawMessage(`{"type": "string", "name": "string", "properties.0": "string"}`)},
+			totalCount: 10,
+			keyCounts:  map[string]int64{"type": 10, "name": 10, "properties.0": 10},
+			metadata:   &MetaDataT{Counters: map[string][]*CounterItem{"name": {{Value: "Home", Frequency: 1}}}},
+		}
+		definition := b.build("my-write-key", []*trackingPlanEventT{screen})["definitions"].(map[string]interface{})["screen"].(map[string]interface{})
+		properties := definition["properties"].(map[string]interface{})
+		require.Equal(t, map[string]interface{}{"const": "Home"}, properties["name"], "the name of the screens is pinned if they all have the same one")
+		require.Equal(t, []string{"type", "name", "properties"}, definition["required"])
+		require.NotContains(t, properties["properties"], "additionalProperties", "only objects are closed")
+
+		screen.metadata.Counters["name"] = append(screen.metadata.Counters["name"], &CounterItem{Value: "Cart", Frequency: 0.1})
+		definition = b.build("my-write-key", []*trackingPlanEventT{screen})["definitions"].(map[string]interface{})["screen"].(map[string]interface{})
+		require.NotContains(t, definition["properties"], "name")
+	})
+
+	t.Run("conflicting definition names", func(t *testing.T) {
+		a := &trackingPlanEventT{eventModel: &EventModelT{EventType: "track", EventIdentifier: "signed up", Schema: json.RawMessage(`{}`)}}
+		b := &trackingPlanEventT{eventModel: &EventModelT{EventType: "track", EventIdentifier: "signed/up", Schema: json.RawMessage(`{}`)}}
+		trackingPlan := (&trackingPlanBuilderT{}).build("my-write-key", []*trackingPlanEventT{a, b})
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"$ref": "#/definitions/track_signed_up"},
+			map[string]interface{}{"$ref": "#/definitions/track_signed_up_2"},
+		}, trackingPlan["oneOf"])
+	})
+}
+
+func TestJSONSchemaTypes(t *testing.T) {
+	require.Equal(t, []string{"integer", "number"}, jsonSchemaTypes("int,float64,float32"))
+	require.Equal(t, []string{"string", "null"}, jsonSchemaTypes("string,unknown"))
+	require.Equal(t, []string{"array", "boolean"}, jsonSchemaTypes("[]interface {},bool"))
+}
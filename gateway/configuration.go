@@ -29,8 +29,8 @@ func loadConfig() {
 	config.RegisterIntConfigVariable(4000, &maxReqSize, true, 1024, "Gateway.maxReqSizeInKB")
 	// Enable rate limit on incoming events. false by default
 	config.RegisterBoolConfigVariable(false, &enableRateLimit, true, "Gateway.enableRateLimit")
-	// Enable suppress user feature. false by default
-	config.RegisterBoolConfigVariable(true, &enableSuppressUserFeature, false, "Gateway.enableSuppressUserFeature")
+	// Maximum difference between the timestamp of signed requests and the gateway time
+	config.RegisterDurationConfigVariable(5, &maxSignatureClockSkew, true, time.Minute, "Gateway.hmac.maxClockSkew")
 	// EventSchemas feature. false by default
 	config.RegisterBoolConfigVariable(false, &enableEventSchemasFeature, false, "EventSchemas.enableEventSchemasFeature")
 	// Time period for diagnosis ticker
@@ -70,10 +70,3 @@ func SetEnableRateLimit(b bool) bool {
 	enableRateLimit = b
 	return prev
 }
-
-// SetEnableSuppressUserFeature overrides enableSuppressUserFeature configuration and returns previous value
-func SetEnableSuppressUserFeature(b bool) bool {
-	prev := enableSuppressUserFeature
-	enableSuppressUserFeature = b
-	return prev
-}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -32,6 +33,8 @@ import (
 	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
 	event_schema "github.com/rudderlabs/rudder-server/event-schema"
 	"github.com/rudderlabs/rudder-server/gateway/response"
+	"github.com/rudderlabs/rudder-server/gateway/sourceauth"
+	"github.com/rudderlabs/rudder-server/gateway/throttler"
 	"github.com/rudderlabs/rudder-server/gateway/webhook"
 	"github.com/rudderlabs/rudder-server/jobsdb"
 	"github.com/rudderlabs/rudder-server/middleware"
@@ -67,6 +70,7 @@ has a `done` channel that receives a response(error if any)
 */
 type webRequestT struct {
 	done           chan<- string
+	writer         *http.ResponseWriter
 	reqType        string
 	requestPayload []byte
 	writeKey       string
@@ -74,6 +78,13 @@ type webRequestT struct {
 	userIDHeader   string
 }
 
+// sourceAuthT is how the requests of a source are authenticated on top of their write key.
+// Sources with an invalid auth config have err set, and their requests are rejected.
+type sourceAuthT struct {
+	auth *sourceauth.Auth
+	err  error
+}
+
 type batchWebRequestT struct {
 	batchRequest []*webRequestT
 }
@@ -86,10 +97,11 @@ var (
 	enabledWriteKeyWebhookMap                                                         map[string]string
 	enabledWriteKeyWorkspaceMap                                                       map[string]string
 	sourceIDToNameMap                                                                 map[string]string
+	writeKeySourceAuthMap                                                             map[string]sourceAuthT
 	configSubscriberLock                                                              sync.RWMutex
 	maxReqSize                                                                        int
 	enableRateLimit                                                                   bool
-	enableSuppressUserFeature                                                         bool
+	maxSignatureClockSkew                                                             time.Duration
 	enableEventSchemasFeature                                                         bool
 	diagnosisTickerTime                                                               time.Duration
 	ReadTimeout                                                                       time.Duration
@@ -155,6 +167,8 @@ type HandleT struct {
 	recvCount                    uint64
 	backendConfig                backendconfig.BackendConfig
 	rateLimiter                  ratelimiter.RateLimiter
+	throttler                    *throttler.Throttler
+	trustedProxies               []*net.IPNet
 
 	stats                                         stats.Stats
 	batchSizeStat                                 stats.Measurement
@@ -175,7 +189,6 @@ type HandleT struct {
 	webRequestBatchCount                                       uint64
 	userWebRequestWorkers                                      []*userWebRequestWorkerT
 	webhookHandler                                             *webhook.HandleT
-	suppressUserHandler                                        types.UserSuppression
 	eventSchemaHandler                                         types.EventSchemasI
 	versionHandler                                             func(w http.ResponseWriter, r *http.Request)
 	logger                                                     logger.Logger
@@ -538,14 +551,15 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 				continue
 			}
 
-			if enableRateLimit {
-				// In case of "batch" requests, if rate-limiter returns true for LimitReached, just drop the event batch and continue.
-				if gateway.rateLimiter.LimitReached(workspaceId) {
-					req.done <- response.GetStatus(response.TooManyRequests)
-					preDbStoreCount++
-					misc.IncrementMapByKey(workspaceDropRequestStats, sourceTag, 1)
-					continue
-				}
+			// In case of "batch" requests, if rate-limiter returns true for LimitReached, reject the whole event batch
+			if enableRateLimit && gateway.rateLimiter.LimitReached(workspaceId) {
+				gateway.rejectRateLimited(req, ratelimiter.RetryAfter())
+				sourceTagMap[sourceTag]["reason"] = "rateLimited"
+				preDbStoreCount++
+				misc.IncrementMapByKey(workspaceDropRequestStats, sourceTag, 1)
+				misc.IncrementMapByKey(sourceFailStats, sourceTag, 1)
+				misc.IncrementMapByKey(sourceFailEventStats, sourceTag, totalEventsInReq)
+				continue
 			}
 
 			// set anonymousId if not set in payload
@@ -623,13 +637,13 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 				continue
 			}
 
-			if enableSuppressUserFeature && gateway.suppressUserHandler != nil {
-				userID := gjson.GetBytes(body, "batch.0.userId").String()
-				if gateway.suppressUserHandler.IsSuppressedUser(workspaceId, userID, sourceID) {
-					req.done <- ""
-					preDbStoreCount++
-					continue
-				}
+			if limited, retryAfter := gateway.checkRateLimitPolicies(body, workspaceId, writeKey, ipAddr, totalEventsInReq); limited {
+				gateway.rejectRateLimited(req, retryAfter)
+				sourceTagMap[sourceTag]["reason"] = "rateLimited"
+				preDbStoreCount++
+				misc.IncrementMapByKey(sourceFailStats, sourceTag, 1)
+				misc.IncrementMapByKey(sourceFailEventStats, sourceTag, totalEventsInReq)
+				continue
 			}
 
 			body, _ = sjson.SetBytes(body, "requestIP", ipAddr)
@@ -715,6 +729,38 @@ func (gateway *HandleT) userWebRequestWorkerProcess(userWebRequestWorker *userWe
 	}
 }
 
+// checkRateLimitPolicies returns true along with the time to wait before retrying, if the request exceeds any of the rate limit policies.
+// The user of the request is the one of its first event. Requests are allowed if the limits can't be checked.
+func (gateway *HandleT) checkRateLimitPolicies(body []byte, workspaceID, writeKey, ipAddr string, events int) (bool, time.Duration) {
+	if gateway.throttler == nil {
+		return false, 0
+	}
+	userID := gjson.GetBytes(body, "batch.0.userId").String()
+	if userID == "" {
+		userID = gjson.GetBytes(body, "batch.0.anonymousId").String()
+	}
+	limited, retryAfter, policy, err := gateway.throttler.CheckLimitReached(context.TODO(), throttler.Request{
+		WorkspaceID: workspaceID,
+		WriteKey:    writeKey,
+		UserID:      userID,
+		IP:          ipAddr,
+		Events:      int64(events),
+	})
+	if err != nil {
+		gateway.logger.Errorf("Checking rate limit policy %s for writeKey %s: %v", policy, writeKey, err)
+		return false, 0
+	}
+	return limited, retryAfter
+}
+
+// rejectRateLimited responds to the request with 429, telling the client when to retry
+func (*HandleT) rejectRateLimited(req *webRequestT, retryAfter time.Duration) {
+	if req.writer != nil {
+		(*req.writer).Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	req.done <- response.GetStatus(response.TooManyRequests)
+}
+
 func (*HandleT) isValidWriteKey(writeKey string) bool {
 	configSubscriberLock.RLock()
 	defer configSubscriberLock.RUnlock()
@@ -1170,9 +1216,50 @@ func (gateway *HandleT) getPayloadAndWriteKey(_ http.ResponseWriter, r *http.Req
 		})
 		return []byte{}, writeKey, err
 	}
+	if reason, err := gateway.authenticateSource(r, writeKey, payload); err != nil {
+		sourceTag := gateway.getSourceTagFromWriteKey(writeKey)
+		misc.IncrementMapByKey(sourceFailStats, sourceTag, 1)
+		gateway.updateSourceStats(sourceFailStats, "gateway.write_key_failed_requests", map[string]map[string]string{
+			sourceTag: {
+				"reqType":  reqType,
+				"reason":   reason,
+				"sourceID": sourceID,
+				"writeKey": writeKey,
+			},
+		})
+		gateway.updateSourceStats(sourceFailStats, "gateway.write_key_requests", map[string]map[string]string{
+			sourceTag: {
+				"reqType":  reqType,
+				"sourceID": sourceID,
+				"writeKey": writeKey,
+			},
+		})
+		return []byte{}, writeKey, err
+	}
 	return payload, writeKey, err
 }
 
+// authenticateSource checks the IP allowlist and the signature of the request, if the source of the write key requires them.
+// It returns the reason of the failure for stats along with the error.
+func (gateway *HandleT) authenticateSource(r *http.Request, writeKey string, payload []byte) (string, error) {
+	configSubscriberLock.RLock()
+	sourceAuth, ok := writeKeySourceAuthMap[writeKey]
+	configSubscriberLock.RUnlock()
+	if !ok {
+		return "", nil
+	}
+	if sourceAuth.err != nil {
+		return "invalidSourceAuthConfig", errors.New(response.InvalidSignature)
+	}
+	if err := sourceAuth.auth.AllowIP(sourceauth.ClientIP(r, gateway.trustedProxies)); err != nil {
+		return "ipNotAllowed", errors.New(response.SourceIPNotAllowed)
+	}
+	if err := sourceAuth.auth.VerifySignature(r.Header, payload, time.Now(), maxSignatureClockSkew); err != nil {
+		return "invalidSignature", errors.New(response.InvalidSignature)
+	}
+	return "", nil
+}
+
 func (gateway *HandleT) pixelWebHandler(w http.ResponseWriter, r *http.Request, reqType string) {
 	gateway.pixelWebRequestHandler(gateway.rrh, w, r, reqType)
 }
@@ -1236,7 +1323,7 @@ func (gateway *HandleT) pixelWebRequestHandler(rh RequestHandler, w http.Respons
 }
 
 // ProcessRequest on ImportRequestHandler splits payload by user and throws them into the webrequestQ and waits for all their responses before returning
-func (*ImportRequestHandler) ProcessRequest(gateway *HandleT, w *http.ResponseWriter, r *http.Request, _ string, payload []byte, writeKey string) string {
+func (*ImportRequestHandler) ProcessRequest(gateway *HandleT, _ *http.ResponseWriter, r *http.Request, _ string, payload []byte, writeKey string) string {
 	usersPayload, payloadError := gateway.getUsersPayload(payload)
 	if payloadError != nil {
 		return payloadError.Error()
@@ -1244,7 +1331,8 @@ func (*ImportRequestHandler) ProcessRequest(gateway *HandleT, w *http.ResponseWr
 	count := len(usersPayload)
 	done := make(chan string, count)
 	for key := range usersPayload {
-		gateway.addToWebRequestQ(w, r, done, "batch", usersPayload[key], writeKey)
+		// the requests of the users are processed concurrently, so they don't share the response writer
+		gateway.addToWebRequestQ(nil, r, done, "batch", usersPayload[key], writeKey)
 	}
 
 	var interimMsgs []string
@@ -1477,6 +1565,7 @@ func (gateway *HandleT) StartWebHandler(ctx context.Context) error {
 		srvMux.HandleFunc("/schemas/event-version/{VersionID}/metadata", WithContentType("application/json; charset=utf-8", gateway.eventSchemaWebHandler(gateway.eventSchemaHandler.GetSchemaVersionMetadata))).Methods("GET")
 		srvMux.HandleFunc("/schemas/event-version/{VersionID}/missing-keys", WithContentType("application/json; charset=utf-8", gateway.eventSchemaWebHandler(gateway.eventSchemaHandler.GetSchemaVersionMissingKeys))).Methods("GET")
 		srvMux.HandleFunc("/schemas/event-models/json-schemas", WithContentType("application/json; charset=utf-8", gateway.eventSchemaWebHandler(gateway.eventSchemaHandler.GetJsonSchemas))).Methods("GET")
+		srvMux.HandleFunc("/schemas/tracking-plan", WithContentType("application/json; charset=utf-8", gateway.eventSchemaWebHandler(gateway.eventSchemaHandler.GetTrackingPlan))).Methods("GET")
 	}
 
 	srvMux.HandleFunc("/v1/pending-events", WithContentType("application/json; charset=utf-8", gateway.pendingEventsHandler)).Methods("POST")
@@ -1526,6 +1615,7 @@ func (gateway *HandleT) StartAdminHandler(ctx context.Context) error {
 		middleware.LimitConcurrentRequests(maxConcurrentRequests),
 	)
 	srvMux.HandleFunc("/v1/pending-events", gateway.pendingEventsHandler).Methods("POST")
+	srvMux.PathPrefix(admin.HTTPPathPrefix).Handler(admin.HTTPHandler())
 
 	srv := &http.Server{
 		Addr:    ":" + strconv.Itoa(adminWebPort),
@@ -1544,15 +1634,30 @@ func (gateway *HandleT) backendConfigSubscriber() {
 			newEnabledWriteKeyWebhookMap   = map[string]string{}
 			newEnabledWriteKeyWorkspaceMap = map[string]string{}
 			newSourceIDToNameMap           = map[string]string{}
+			newSourcePolicies              = map[string][]throttler.Policy{}
+			newWriteKeySourceAuthMap       = map[string]sourceAuthT{}
 		)
 		config := data.Data.(map[string]backendconfig.ConfigT)
 		for workspaceID, wsConfig := range config {
 			for _, source := range wsConfig.Sources {
 				newSourceIDToNameMap[source.ID] = source.Name
 				newWriteKeysSourceMap[source.WriteKey] = source
+				if auth, err := sourceauth.New(source.Config); err != nil {
+					gateway.logger.Errorf("Invalid auth config of source %s, rejecting its requests: %v", source.ID, err)
+					newWriteKeySourceAuthMap[source.WriteKey] = sourceAuthT{err: err}
+				} else if auth != nil {
+					newWriteKeySourceAuthMap[source.WriteKey] = sourceAuthT{auth: auth}
+				}
 
 				if source.Enabled {
 					newEnabledWriteKeyWorkspaceMap[source.WriteKey] = workspaceID
+					policies, err := throttler.SourcePolicies(workspaceID, source.WriteKey, source.Config)
+					if err != nil {
+						gateway.logger.Errorf("Rate limit policies of source %s: %v", source.ID, err)
+					}
+					if len(policies) > 0 {
+						newSourcePolicies[source.WriteKey] = policies
+					}
 					if source.SourceDefinition.Category == "webhook" {
 						newEnabledWriteKeyWebhookMap[source.WriteKey] = source.SourceDefinition.Name
 						gateway.webhookHandler.Register(source.SourceDefinition.Name)
@@ -1565,7 +1670,11 @@ func (gateway *HandleT) backendConfigSubscriber() {
 		enabledWriteKeyWebhookMap = newEnabledWriteKeyWebhookMap
 		enabledWriteKeyWorkspaceMap = newEnabledWriteKeyWorkspaceMap
 		sourceIDToNameMap = newSourceIDToNameMap
+		writeKeySourceAuthMap = newWriteKeySourceAuthMap
 		configSubscriberLock.Unlock()
+		if gateway.throttler != nil {
+			gateway.throttler.SetSourcePolicies(newSourcePolicies)
+		}
 	}
 }
 
@@ -1578,7 +1687,7 @@ Finds the worker for a particular userID and queues the webrequest with the work
 
 They are further batched together in userWebRequestBatcher
 */
-func (gateway *HandleT) addToWebRequestQ(_ *http.ResponseWriter, req *http.Request, done chan string, reqType string, requestPayload []byte, writeKey string) {
+func (gateway *HandleT) addToWebRequestQ(writer *http.ResponseWriter, req *http.Request, done chan string, reqType string, requestPayload []byte, writeKey string) {
 	userIDHeader := req.Header.Get("AnonymousId")
 	workerKey := userIDHeader
 	if userIDHeader == "" {
@@ -1588,7 +1697,7 @@ func (gateway *HandleT) addToWebRequestQ(_ *http.ResponseWriter, req *http.Reque
 	}
 	userWebRequestWorker := gateway.findUserWebRequestWorker(workerKey)
 	ipAddr := misc.GetIPFromReq(req)
-	webReq := webRequestT{done: done, reqType: reqType, requestPayload: requestPayload, writeKey: writeKey, ipAddr: ipAddr, userIDHeader: userIDHeader}
+	webReq := webRequestT{done: done, writer: writer, reqType: reqType, requestPayload: requestPayload, writeKey: writeKey, ipAddr: ipAddr, userIDHeader: userIDHeader}
 	userWebRequestWorker.webRequestQ <- &webReq
 }
 
@@ -1665,6 +1774,14 @@ func (gateway *HandleT) Setup(
 	gateway.processRequestTime = gateway.stats.NewStat("gateway.process_request_time", stats.TimerType)
 	gateway.backendConfig = backendConfig
 	gateway.rateLimiter = rateLimiter
+	var err error
+	if gateway.throttler, err = throttler.New(gateway.stats); err != nil {
+		return fmt.Errorf("could not setup rate limit policies: %w", err)
+	}
+	// the X-Forwarded-For header is only trusted when set by these proxies
+	if gateway.trustedProxies, err = sourceauth.ParseTrustedProxies(config.GetString("Gateway.trustedProxies", "")); err != nil {
+		return fmt.Errorf("invalid Gateway.trustedProxies: %w", err)
+	}
 	gateway.userWorkerBatchRequestQ = make(chan *userWorkerBatchRequestT, maxDBBatchSize)
 	gateway.batchUserWorkerBatchRequestQ = make(chan *batchUserWorkerBatchRequestT, maxDBWriterProcess)
 	gateway.emptyAnonIdHeaderStat = gateway.stats.NewStat("gateway.empty_anonymous_id_header", stats.CountType)
@@ -1690,14 +1807,6 @@ func (gateway *HandleT) Setup(
 	admin.RegisterStatusHandler("Gateway", &gatewayAdmin)
 	admin.RegisterAdminHandler("Gateway", &gatewayRPCHandler)
 
-	if enableSuppressUserFeature && gateway.application.Features().SuppressUser != nil {
-		var err error
-		gateway.suppressUserHandler, err = application.Features().SuppressUser.Setup(ctx, gateway.backendConfig)
-		if err != nil {
-			return fmt.Errorf("could not setup suppress user feature: %w", err)
-		}
-	}
-
 	if enableEventSchemasFeature {
 		gateway.eventSchemaHandler = event_schema.GetInstance()
 	}
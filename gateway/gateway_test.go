@@ -10,7 +10,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
@@ -27,12 +29,13 @@ import (
 	"github.com/rudderlabs/rudder-server/config"
 	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
 	"github.com/rudderlabs/rudder-server/gateway/response"
+	"github.com/rudderlabs/rudder-server/gateway/sourceauth"
 	"github.com/rudderlabs/rudder-server/jobsdb"
 	mocksApp "github.com/rudderlabs/rudder-server/mocks/app"
 	mocksBackendConfig "github.com/rudderlabs/rudder-server/mocks/config/backend-config"
 	mocksJobsDB "github.com/rudderlabs/rudder-server/mocks/jobsdb"
 	mocksRateLimiter "github.com/rudderlabs/rudder-server/mocks/rate-limiter"
-	mocksTypes "github.com/rudderlabs/rudder-server/mocks/utils/types"
+	ratelimiter "github.com/rudderlabs/rudder-server/rate-limiter"
 	"github.com/rudderlabs/rudder-server/services/rsources"
 	"github.com/rudderlabs/rudder-server/utils/logger"
 	"github.com/rudderlabs/rudder-server/utils/misc"
@@ -47,12 +50,14 @@ const (
 	WriteKeyEmpty             = ""
 	SourceIDEnabled           = "enabled-source"
 	SourceIDDisabled          = "disabled-source"
+	WriteKeySigned            = "signed-write-key"
+	SourceIDSigned            = "signed-source"
+	SigningSecret             = "signing-secret"
 	TestRemoteAddressWithPort = "test.com:80"
 	TestRemoteAddress         = "test.com"
 
-	SuppressedUserID = "suppressed-user-2"
-	NormalUserID     = "normal-user-1"
-	WorkspaceID      = "workspace"
+	NormalUserID = "normal-user-1"
+	WorkspaceID  = "workspace"
 )
 
 var testTimeout = 15 * time.Second
@@ -71,6 +76,16 @@ var sampleBackendConfig = backendconfig.ConfigT{
 			WriteKey: WriteKeyEnabled,
 			Enabled:  true,
 		},
+		{
+			ID:       SourceIDSigned,
+			WriteKey: WriteKeySigned,
+			Enabled:  true,
+			Config: map[string]interface{}{
+				sourceauth.ModeConfigKey:      sourceauth.ModeHMAC,
+				sourceauth.SecretsConfigKey:   []interface{}{SigningSecret},
+				sourceauth.AllowlistConfigKey: []interface{}{"10.0.0.0/8"},
+			},
+		},
 	},
 }
 
@@ -84,23 +99,12 @@ type testContext struct {
 	mockRateLimiter   *mocksRateLimiter.MockRateLimiter
 
 	mockVersionHandler func(w http.ResponseWriter, r *http.Request)
-
-	// Enterprise mocks
-	mockSuppressUser        *mocksTypes.MockUserSuppression
-	mockSuppressUserFeature *mocksApp.MockSuppressUserFeature
 }
 
 func (c *testContext) initializeAppFeatures() {
 	c.mockApp.EXPECT().Features().Return(&app.Features{}).AnyTimes()
 }
 
-func (c *testContext) initializeEnterpriseAppFeatures() {
-	enterpriseFeatures := &app.Features{
-		SuppressUser: c.mockSuppressUserFeature,
-	}
-	c.mockApp.EXPECT().Features().Return(enterpriseFeatures).AnyTimes()
-}
-
 func setAllowReqsWithoutUserIDAndAnonymousID(allow bool) {
 	allowReqsWithoutUserIDAndAnonymousID = allow
 }
@@ -166,68 +170,10 @@ func initGW() {
 	admin.Init()
 	logger.Reset()
 	misc.Init()
+	ratelimiter.Init()
 	Init()
 }
 
-var _ = Describe("Gateway Enterprise", func() {
-	initGW()
-
-	var c *testContext
-
-	BeforeEach(func() {
-		c = &testContext{}
-		c.Setup()
-
-		c.mockSuppressUser = mocksTypes.NewMockUserSuppression(c.mockCtrl)
-		c.mockSuppressUserFeature = mocksApp.NewMockSuppressUserFeature(c.mockCtrl)
-		c.initializeEnterpriseAppFeatures()
-
-		c.mockSuppressUserFeature.EXPECT().Setup(gomock.Any(), gomock.Any()).AnyTimes().Return(c.mockSuppressUser, nil)
-		c.mockSuppressUser.EXPECT().IsSuppressedUser(WorkspaceID, NormalUserID, SourceIDEnabled).Return(false).AnyTimes()
-		c.mockSuppressUser.EXPECT().IsSuppressedUser(WorkspaceID, SuppressedUserID, SourceIDEnabled).Return(true).AnyTimes()
-
-		// setup common environment, override in BeforeEach when required
-		SetEnableRateLimit(false)
-		SetEnableSuppressUserFeature(true)
-		SetEnableEventSchemasFeature(false)
-		// SetUserWebRequestBatchTimeout(time.Second)
-		// SetMaxDBBatchSize(1)
-	})
-
-	AfterEach(func() {
-		c.Finish()
-	})
-
-	Context("Suppress users", func() {
-		gateway := &HandleT{}
-
-		BeforeEach(func() {
-			err := gateway.Setup(context.Background(), c.mockApp, c.mockBackendConfig, c.mockJobsDB, nil, c.mockVersionHandler, rsources.NewNoOpService())
-			Expect(err).To(BeNil())
-		})
-
-		It("should not accept events from suppress users", func() {
-			suppressedUserEventData := fmt.Sprintf(`{"batch":[{"userId":%q}]}`, SuppressedUserID)
-			// Why GET
-			expectHandlerResponse(gateway.webBatchHandler, authorizedRequest(WriteKeyEnabled, bytes.NewBufferString(suppressedUserEventData)), 200, "OK")
-		})
-
-		It("should accept events from normal users", func() {
-			allowedUserEventData := fmt.Sprintf(`{"batch":[{"userId":%q}]}`, NormalUserID)
-
-			c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any(), gomock.Any()).Times(1).Do(func(ctx context.Context, f func(tx jobsdb.StoreSafeTx) error) {
-				_ = f(jobsdb.EmptyStoreSafeTx())
-			}).Return(nil)
-			mockCall := c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).Times(1)
-			tFunc := c.asyncHelper.ExpectAndNotifyCallbackWithName("store-job")
-			mockCall.Do(func(context.Context, interface{}, interface{}) { tFunc() })
-
-			// Why GET
-			expectHandlerResponse(gateway.webBatchHandler, authorizedRequest(WriteKeyEnabled, bytes.NewBufferString(allowedUserEventData)), 200, "OK")
-		})
-	})
-})
-
 var _ = Describe("Gateway", func() {
 	initGW()
 
@@ -402,7 +348,124 @@ var _ = Describe("Gateway", func() {
 		It("should reject messages if rate limit is reached for workspace", func() {
 			c.mockRateLimiter.EXPECT().LimitReached(WorkspaceID).Return(true).Times(1)
 
-			expectHandlerResponse(gateway.webAliasHandler, authorizedRequest(WriteKeyEnabled, bytes.NewBufferString("{}")), 429, response.TooManyRequests+"\n")
+			testutils.RunTestWithTimeout(func() {
+				rr := httptest.NewRecorder()
+				gateway.webAliasHandler(rr, authorizedRequest(WriteKeyEnabled, bytes.NewBufferString("{}")))
+
+				Expect(rr.Code).To(Equal(http.StatusTooManyRequests))
+				Expect(rr.Body.String()).To(Equal(response.TooManyRequests + "\n"))
+				// the oldest of the 12 buckets of the 60 minutes window expires after 5 minutes
+				Expect(rr.Header().Get("Retry-After")).To(Equal("300"))
+			}, testTimeout)
+		})
+	})
+
+	Context("Rate limit policies", func() {
+		var gateway *HandleT
+
+		BeforeEach(func() {
+			gateway = &HandleT{}
+			err := gateway.Setup(context.Background(), c.mockApp, c.mockBackendConfig, c.mockJobsDB, nil, c.mockVersionHandler, rsources.NewNoOpService())
+			Expect(err).To(BeNil())
+
+			policiesFile := filepath.Join(GinkgoT().TempDir(), "policies.yaml")
+			Expect(os.WriteFile(policiesFile, []byte(`
+policies:
+  - name: sources
+    scope: source
+    limit: 1
+    window: 1h
+`), 0o600)).To(Succeed())
+			Expect(gateway.throttler.LoadPoliciesFile(policiesFile)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			err := gateway.Shutdown()
+			Expect(err).To(BeNil())
+		})
+
+		It("should reject messages with Retry-After once the limit of the source is reached", func() {
+			c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any(), gomock.Any()).AnyTimes().Do(func(ctx context.Context, f func(tx jobsdb.StoreSafeTx) error) {
+				_ = f(jobsdb.EmptyStoreSafeTx())
+			}).Return(nil)
+			c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).AnyTimes()
+
+			var rr *httptest.ResponseRecorder
+			testutils.RunTestWithTimeout(func() {
+				// the limiter allows a burst of up to one request more than the limit
+				for i := 0; i < 3; i++ {
+					rr = httptest.NewRecorder()
+					gateway.webAliasHandler(rr, authorizedRequest(WriteKeyEnabled, bytes.NewBufferString(fmt.Sprintf(`{"userId":%q}`, NormalUserID))))
+					if rr.Code != http.StatusOK {
+						break
+					}
+				}
+			}, testTimeout)
+
+			Expect(rr.Code).To(Equal(http.StatusTooManyRequests))
+			Expect(rr.Body.String()).To(Equal(response.TooManyRequests + "\n"))
+			retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+			Expect(err).To(BeNil())
+			Expect(retryAfter).To(BeNumerically(">", 0))
+			Expect(retryAfter).To(BeNumerically("<=", 3600))
+		})
+	})
+
+	Context("Source authentication", func() {
+		var gateway *HandleT
+
+		BeforeEach(func() {
+			config.Set("Gateway.trustedProxies", "172.16.0.0/12")
+			gateway = &HandleT{}
+			err := gateway.Setup(context.Background(), c.mockApp, c.mockBackendConfig, c.mockJobsDB, nil, c.mockVersionHandler, rsources.NewNoOpService())
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			config.Set("Gateway.trustedProxies", "")
+			err := gateway.Shutdown()
+			Expect(err).To(BeNil())
+		})
+
+		body := []byte(fmt.Sprintf(`{"userId":%q}`, NormalUserID))
+		// request returns a request forwarded by a trusted proxy for the ip
+		request := func(ip string, sign bool) *http.Request {
+			req := authorizedRequest(WriteKeySigned, bytes.NewBuffer(body))
+			req.RemoteAddr = "172.16.0.1:4321"
+			req.Header.Set("X-Forwarded-For", ip)
+			if sign {
+				timestamp := time.Now().Unix()
+				req.Header.Set(sourceauth.TimestampHeader, strconv.FormatInt(timestamp, 10))
+				req.Header.Set(sourceauth.SignatureHeader, sourceauth.Sign(SigningSecret, timestamp, body))
+			}
+			return req
+		}
+
+		It("should reject requests without signature", func() {
+			expectHandlerResponse(gateway.webAliasHandler, request("10.1.2.3", false), 401, response.InvalidSignature+"\n")
+		})
+
+		It("should reject signed requests from IPs not in the allowlist", func() {
+			expectHandlerResponse(gateway.webAliasHandler, request("192.168.1.1", true), 403, response.SourceIPNotAllowed+"\n")
+		})
+
+		It("should reject signed requests spoofing an IP in the allowlist", func() {
+			// the client prepends the spoofed address to the one appended by the proxy
+			expectHandlerResponse(gateway.webAliasHandler, request("10.1.2.3, 192.168.1.1", true), 403, response.SourceIPNotAllowed+"\n")
+
+			// clients which are not trusted proxies can't set the header
+			req := request("10.1.2.3", true)
+			req.RemoteAddr = "192.168.1.1:4321"
+			expectHandlerResponse(gateway.webAliasHandler, req, 403, response.SourceIPNotAllowed+"\n")
+		})
+
+		It("should accept signed requests from IPs in the allowlist", func() {
+			c.mockJobsDB.EXPECT().WithStoreSafeTx(gomock.Any(), gomock.Any()).Times(1).Do(func(ctx context.Context, f func(tx jobsdb.StoreSafeTx) error) {
+				_ = f(jobsdb.EmptyStoreSafeTx())
+			}).Return(nil)
+			c.mockJobsDB.EXPECT().StoreWithRetryEachInTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(jobsToEmptyErrors).Times(1)
+
+			expectHandlerResponse(gateway.webAliasHandler, request("10.1.2.3", true), 200, "OK")
 		})
 	})
 
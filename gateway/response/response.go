@@ -24,6 +24,10 @@ const (
 	RequestBodyTooLarge = "Request size exceeds max limit"
 	// InvalidWriteKey - Invalid Write Key
 	InvalidWriteKey = "Invalid Write Key"
+	// InvalidSignature - Request signature doesn't match any of the secrets of the source
+	InvalidSignature = "Invalid request signature"
+	// SourceIPNotAllowed - Request IP is not in the allowlist of the source
+	SourceIPNotAllowed = "Request IP not allowed for source"
 	// InvalidJSON - Invalid JSON
 	InvalidJSON = "Invalid JSON"
 	// InvalidWebhookSource - Source does not accept webhook events
@@ -72,6 +76,8 @@ var statusMap = map[string]status{
 	RequestBodyTooLarge:     {message: RequestBodyTooLarge, code: http.StatusRequestEntityTooLarge},
 	InvalidWriteKey:         {message: InvalidWriteKey, code: http.StatusUnauthorized},
 	SourceDisabled:          {message: SourceDisabled, code: http.StatusNotFound},
+	InvalidSignature:        {message: InvalidSignature, code: http.StatusUnauthorized},
+	SourceIPNotAllowed:      {message: SourceIPNotAllowed, code: http.StatusForbidden},
 	InvalidJSON:             {message: InvalidJSON, code: http.StatusBadRequest},
 	// webhook specific status
 	InvalidWebhookSource:                           {message: InvalidWebhookSource, code: http.StatusNotFound},
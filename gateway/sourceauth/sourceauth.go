@@ -0,0 +1,290 @@
+package sourceauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Auth modes of the sources
+const (
+	// ModeWriteKey authenticates requests by the write key of their basic auth only
+	ModeWriteKey = "writeKey"
+	// ModeHMAC authenticates requests by the write key and an HMAC signature of their body, signed with a secret of the source
+	ModeHMAC = "hmac"
+)
+
+// Keys of the auth settings in the config of a source
+const (
+	ModeConfigKey      = "authMode"
+	SecretsConfigKey   = "hmacSecrets"
+	AllowlistConfigKey = "ipAllowlist"
+)
+
+// Headers of the signed requests
+const (
+	SignatureHeader = "X-Rudder-Signature"
+	TimestampHeader = "X-Rudder-Timestamp"
+	// KeyIDHeader optionally selects the secret the request is signed with, all the active ones are tried otherwise
+	KeyIDHeader = "X-Rudder-Key-Id"
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid request signature")
+	ErrIPNotAllowed     = errors.New("request IP not allowed")
+)
+
+// Secret is an HMAC secret of a source. Secrets are active within their optional time range,
+// so that a new secret can be activated before the previous one expires.
+type Secret struct {
+	ID          string    `json:"id"`
+	Secret      string    `json:"secret"`
+	ActiveFrom  time.Time `json:"activeFrom"`
+	ActiveUntil time.Time `json:"activeUntil"`
+}
+
+func (s *Secret) active(now time.Time) bool {
+	return (s.ActiveFrom.IsZero() || !now.Before(s.ActiveFrom)) && (s.ActiveUntil.IsZero() || now.Before(s.ActiveUntil))
+}
+
+// Auth is how the requests of a source are authenticated, on top of their write key
+type Auth struct {
+	mode      string
+	secrets   []Secret
+	allowlist []*net.IPNet
+}
+
+// New returns the auth of a source from its config, or nil if the source authenticates by write key only from any IP
+func New(sourceConfig map[string]interface{}) (*Auth, error) {
+	auth := &Auth{mode: ModeWriteKey}
+	if mode, ok := sourceConfig[ModeConfigKey].(string); ok && mode != "" {
+		auth.mode = mode
+	}
+
+	switch auth.mode {
+	case ModeWriteKey:
+	case ModeHMAC:
+		secrets, err := parseSecrets(sourceConfig[SecretsConfigKey])
+		if err != nil {
+			return nil, err
+		}
+		if len(secrets) == 0 {
+			return nil, fmt.Errorf("no %s configured with auth mode %s", SecretsConfigKey, ModeHMAC)
+		}
+		auth.secrets = secrets
+	default:
+		return nil, fmt.Errorf("invalid auth mode %q", auth.mode)
+	}
+
+	allowlist, err := parseIPNets(AllowlistConfigKey, sourceConfig[AllowlistConfigKey])
+	if err != nil {
+		return nil, err
+	}
+	auth.allowlist = allowlist
+
+	if auth.mode == ModeWriteKey && len(auth.allowlist) == 0 {
+		return nil, nil
+	}
+	return auth, nil
+}
+
+// parseSecrets accepts a list of secrets, either plain strings always active or objects
+func parseSecrets(value interface{}) ([]Secret, error) {
+	values, ok := value.([]interface{})
+	if !ok {
+		if value == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid %s of type %T", SecretsConfigKey, value)
+	}
+
+	secrets := make([]Secret, 0, len(values))
+	for i, value := range values {
+		secret := Secret{ID: strconv.Itoa(i)}
+		switch value := value.(type) {
+		case string:
+			secret.Secret = value
+		case map[string]interface{}:
+			if id, ok := value["id"].(string); ok && id != "" {
+				secret.ID = id
+			}
+			secret.Secret, _ = value["secret"].(string)
+			for key, t := range map[string]*time.Time{"activeFrom": &secret.ActiveFrom, "activeUntil": &secret.ActiveUntil} {
+				s, _ := value[key].(string)
+				if s == "" {
+					continue
+				}
+				parsed, err := time.Parse(time.RFC3339, s)
+				if err != nil {
+					return nil, fmt.Errorf("parsing %s of secret %s: %w", key, secret.ID, err)
+				}
+				*t = parsed
+			}
+		default:
+			return nil, fmt.Errorf("invalid secret %d of type %T", i, value)
+		}
+		if secret.Secret == "" {
+			return nil, fmt.Errorf("empty secret %s", secret.ID)
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// ParseTrustedProxies parses the comma separated CIDRs or IPs of the proxies in front of the gateway
+func ParseTrustedProxies(value string) ([]*net.IPNet, error) {
+	return parseIPNets("trusted proxies", value)
+}
+
+// parseIPNets accepts a list or a comma separated string of CIDRs or IPs, name is the setting they are parsed from
+func parseIPNets(name string, value interface{}) ([]*net.IPNet, error) {
+	var entries []string
+	switch value := value.(type) {
+	case nil:
+	case string:
+		entries = strings.Split(value, ",")
+	case []interface{}:
+		for _, entry := range value {
+			entry, ok := entry.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid %s entry of type %T", name, entry)
+			}
+			entries = append(entries, entry)
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s of type %T", name, value)
+	}
+
+	var ipNets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP %q in %s", entry, name)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ipNets = append(ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q in %s: %w", entry, name, err)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	return ipNets, nil
+}
+
+// ClientIP returns the IP of the client of the request. The X-Forwarded-For header is only read for the requests of trusted proxies,
+// taking its rightmost address which is not a trusted proxy, as the ones to its left are set by the client and can be spoofed.
+func ClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !contains(trustedProxies, ip) {
+		return ip
+	}
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		ip = hop
+		if !contains(trustedProxies, ip) {
+			break
+		}
+	}
+	return ip
+}
+
+func contains(ipNets []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, ipNet := range ipNets {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mode returns the auth mode of the source
+func (a *Auth) Mode() string {
+	return a.mode
+}
+
+// AllowIP returns an error if the source restricts the IPs of its requests and the ip is not allowed
+func (a *Auth) AllowIP(ip string) error {
+	if len(a.allowlist) == 0 || contains(a.allowlist, ip) {
+		return nil
+	}
+	return ErrIPNotAllowed
+}
+
+// VerifySignature returns an error if the source signs its requests and the signature of the request is not valid for any of its active secrets.
+// Signatures older or newer than maxClockSkew are rejected, so that captured requests can't be replayed later on.
+func (a *Auth) VerifySignature(header http.Header, body []byte, now time.Time, maxClockSkew time.Duration) error {
+	if a.mode != ModeHMAC {
+		return nil
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if skew := now.Sub(time.Unix(timestamp, 0)); skew > maxClockSkew || skew < -maxClockSkew {
+		return ErrInvalidSignature
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header.Get(SignatureHeader), signaturePrefix))
+	if err != nil || len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+
+	keyID := header.Get(KeyIDHeader)
+	for i := range a.secrets {
+		secret := &a.secrets[i]
+		if keyID != "" && secret.ID != keyID || !secret.active(now) {
+			continue
+		}
+		if hmac.Equal(signature, mac(secret.Secret, timestamp, body)) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Sign returns the signature header value of the body signed with the secret at the unix timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, timestamp, body))
+}
+
+func mac(secret string, timestamp int64, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,149 @@
+package sourceauth_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/gateway/sourceauth"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("write key only", func(t *testing.T) {
+		auth, err := sourceauth.New(map[string]interface{}{})
+		require.NoError(t, err)
+		require.Nil(t, auth)
+	})
+
+	t.Run("write key with allowlist", func(t *testing.T) {
+		auth, err := sourceauth.New(map[string]interface{}{
+			sourceauth.AllowlistConfigKey: "10.0.0.0/8, 192.168.1.1",
+		})
+		require.NoError(t, err)
+		require.Equal(t, sourceauth.ModeWriteKey, auth.Mode())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, sourceConfig := range map[string]map[string]interface{}{
+			"mode":       {sourceauth.ModeConfigKey: "password"},
+			"no secrets": {sourceauth.ModeConfigKey: sourceauth.ModeHMAC},
+			"empty secret": {
+				sourceauth.ModeConfigKey:    sourceauth.ModeHMAC,
+				sourceauth.SecretsConfigKey: []interface{}{""},
+			},
+			"secret time": {
+				sourceauth.ModeConfigKey:    sourceauth.ModeHMAC,
+				sourceauth.SecretsConfigKey: []interface{}{map[string]interface{}{"secret": "s", "activeFrom": "yesterday"}},
+			},
+			"cidr": {sourceauth.AllowlistConfigKey: []interface{}{"10.0.0.0/33"}},
+			"ip":   {sourceauth.AllowlistConfigKey: []interface{}{"10.0.0"}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := sourceauth.New(sourceConfig)
+				require.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestAllowIP(t *testing.T) {
+	auth, err := sourceauth.New(map[string]interface{}{
+		sourceauth.AllowlistConfigKey: []interface{}{"10.0.0.0/8", "192.168.1.1", "2001:db8::/32"},
+	})
+	require.NoError(t, err)
+
+	for _, ip := range []string{"10.1.2.3", "192.168.1.1", "2001:db8::1"} {
+		require.NoError(t, auth.AllowIP(ip), ip)
+	}
+	for _, ip := range []string{"11.1.2.3", "192.168.1.2", "2001:db9::1", "", "invalid"} {
+		require.ErrorIs(t, auth.AllowIP(ip), sourceauth.ErrIPNotAllowed, ip)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	trustedProxies, err := sourceauth.ParseTrustedProxies("172.16.0.0/12, 192.168.0.1")
+	require.NoError(t, err)
+	_, err = sourceauth.ParseTrustedProxies("172.16.0.0/33")
+	require.Error(t, err)
+
+	request := func(remoteAddr string, forwardedFor ...string) *http.Request {
+		r := &http.Request{RemoteAddr: remoteAddr, Header: http.Header{}}
+		for _, value := range forwardedFor {
+			r.Header.Add("X-Forwarded-For", value)
+		}
+		return r
+	}
+
+	require.Equal(t, "10.1.2.3", sourceauth.ClientIP(request("10.1.2.3:1234"), trustedProxies))
+	require.Equal(t, "10.1.2.3", sourceauth.ClientIP(request("10.1.2.3:1234", "192.168.1.1"), trustedProxies), "the header of untrusted clients is ignored")
+	require.Equal(t, "10.1.2.3", sourceauth.ClientIP(request("10.1.2.3:1234", "192.168.1.1"), nil), "the header is ignored without trusted proxies")
+	require.Equal(t, "10.1.2.3", sourceauth.ClientIP(request("172.16.0.1:1234", "10.1.2.3"), trustedProxies))
+	require.Equal(t, "10.1.2.3", sourceauth.ClientIP(request("172.16.0.1:1234", "192.168.1.1, 10.1.2.3, 192.168.0.1"), trustedProxies), "trusted proxies are skipped")
+	require.Equal(t, "10.1.2.3", sourceauth.ClientIP(request("172.16.0.1:1234", "192.168.1.1", "10.1.2.3"), trustedProxies), "multiple headers are read in order")
+	require.Equal(t, "192.168.0.1", sourceauth.ClientIP(request("172.16.0.1:1234", "192.168.0.1"), trustedProxies), "the leftmost address is taken if all are trusted")
+	require.Equal(t, "172.16.0.1", sourceauth.ClientIP(request("172.16.0.1:1234"), trustedProxies))
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"userId":"user-1"}`)
+	auth, err := sourceauth.New(map[string]interface{}{
+		sourceauth.ModeConfigKey: sourceauth.ModeHMAC,
+		sourceauth.SecretsConfigKey: []interface{}{
+			map[string]interface{}{"id": "old", "secret": "old-secret", "activeUntil": "2022-10-02T00:00:00Z"},
+			map[string]interface{}{"id": "new", "secret": "new-secret", "activeFrom": "2022-09-30T00:00:00Z"},
+			map[string]interface{}{"id": "next", "secret": "next-secret", "activeFrom": "2022-11-01T00:00:00Z"},
+		},
+	})
+	require.NoError(t, err)
+
+	signed := func(secret, keyID string, timestamp time.Time, body []byte) http.Header {
+		header := http.Header{}
+		header.Set(sourceauth.TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+		header.Set(sourceauth.SignatureHeader, sourceauth.Sign(secret, timestamp.Unix(), body))
+		if keyID != "" {
+			header.Set(sourceauth.KeyIDHeader, keyID)
+		}
+		return header
+	}
+	verify := func(header http.Header, body []byte) error {
+		return auth.VerifySignature(header, body, now, 5*time.Minute)
+	}
+
+	t.Run("overlapping active secrets", func(t *testing.T) {
+		require.NoError(t, verify(signed("old-secret", "", now, body), body))
+		require.NoError(t, verify(signed("new-secret", "", now, body), body))
+		require.NoError(t, verify(signed("new-secret", "new", now, body), body))
+	})
+
+	t.Run("inactive secret", func(t *testing.T) {
+		require.ErrorIs(t, verify(signed("next-secret", "", now, body), body), sourceauth.ErrInvalidSignature)
+	})
+
+	t.Run("key id of another secret", func(t *testing.T) {
+		require.ErrorIs(t, verify(signed("new-secret", "old", now, body), body), sourceauth.ErrInvalidSignature)
+	})
+
+	t.Run("unknown secret", func(t *testing.T) {
+		require.ErrorIs(t, verify(signed("leaked-write-key", "", now, body), body), sourceauth.ErrInvalidSignature)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		require.ErrorIs(t, verify(signed("new-secret", "", now, body), []byte(`{"userId":"user-2"}`)), sourceauth.ErrInvalidSignature)
+	})
+
+	t.Run("replayed request", func(t *testing.T) {
+		require.ErrorIs(t, verify(signed("new-secret", "", now.Add(-time.Hour), body), body), sourceauth.ErrInvalidSignature)
+		require.ErrorIs(t, verify(signed("new-secret", "", now.Add(time.Hour), body), body), sourceauth.ErrInvalidSignature)
+	})
+
+	t.Run("missing headers", func(t *testing.T) {
+		require.ErrorIs(t, verify(http.Header{}, body), sourceauth.ErrInvalidSignature)
+		header := signed("new-secret", "", now, body)
+		header.Del(sourceauth.SignatureHeader)
+		require.ErrorIs(t, verify(header, body), sourceauth.ErrInvalidSignature)
+	})
+}
@@ -0,0 +1,292 @@
+package throttler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/internal/throttling"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+const (
+	throttlingAlgoTypeGCRA      = "gcra"
+	throttlingAlgoTypeRedisGCRA = "redis-gcra"
+)
+
+// Scopes of the policies, i.e. what the events are counted by
+const (
+	ScopeWorkspace = "workspace"
+	ScopeSource    = "source"
+	ScopeUser      = "user"
+	ScopeIP        = "ip"
+)
+
+var pkgLogger = logger.NewLogger().Child("gateway").Child("throttler")
+
+// SourceConfigKey is the key of the policies in the config of a source in backend config
+const SourceConfigKey = "rateLimitPolicies"
+
+type limiter interface {
+	// AllowAfter returns true if the limit is not exceeded, false otherwise along with the time to wait before retrying.
+	AllowAfter(ctx context.Context, cost, rate, window int64, key string) (bool, time.Duration, func(context.Context) error, error)
+}
+
+// Policy limits the number of events of the requests it matches, counted by its scope
+type Policy struct {
+	Name  string `json:"name" yaml:"name"`
+	Scope string `json:"scope" yaml:"scope"`
+	// WorkspaceID and WriteKey restrict the requests matched by the policy, all are matched if empty
+	WorkspaceID string `json:"workspaceId" yaml:"workspaceId"`
+	WriteKey    string `json:"writeKey" yaml:"writeKey"`
+	// Limit is the number of events allowed in the window
+	Limit int64 `json:"limit" yaml:"limit"`
+	// Window is a duration like 1m, 1h
+	Window string `json:"window" yaml:"window"`
+
+	window time.Duration
+}
+
+type policiesFile struct {
+	Policies []Policy `yaml:"policies"`
+}
+
+func (p *Policy) validate() error {
+	switch p.Scope {
+	case ScopeWorkspace, ScopeSource, ScopeUser, ScopeIP:
+	default:
+		return fmt.Errorf("invalid scope %q of policy %q", p.Scope, p.Name)
+	}
+	if p.Limit < 1 {
+		return fmt.Errorf("limit of policy %q must be greater than 0", p.Name)
+	}
+	window, err := time.ParseDuration(p.Window)
+	if err != nil {
+		return fmt.Errorf("parsing window of policy %q: %w", p.Name, err)
+	}
+	if window < time.Second {
+		return fmt.Errorf("window of policy %q must be at least 1s", p.Name)
+	}
+	p.window = window
+	return nil
+}
+
+func (p *Policy) matches(req *Request) bool {
+	return (p.WorkspaceID == "" || p.WorkspaceID == req.WorkspaceID) && (p.WriteKey == "" || p.WriteKey == req.WriteKey)
+}
+
+// key returns the key the events of the request are counted by, or false if the request has nothing to count by
+func (p *Policy) key(req *Request) (string, bool) {
+	var value string
+	switch p.Scope {
+	case ScopeWorkspace:
+		value = req.WorkspaceID
+	case ScopeSource:
+		value = req.WriteKey
+	case ScopeUser:
+		value = req.UserID
+	case ScopeIP:
+		value = req.IP
+	}
+	if value == "" {
+		return "", false
+	}
+	return strings.Join([]string{"gateway", p.Name, p.Scope, req.WorkspaceID, p.WriteKey, value}, ":"), true
+}
+
+// Request is what the policies are matched against
+type Request struct {
+	WorkspaceID string
+	WriteKey    string
+	UserID      string
+	IP          string
+	// Events is the number of events of the request, counted against the limits
+	Events int64
+}
+
+// Throttler limits the requests of the gateway by the policies of the local file and of the sources
+type Throttler struct {
+	limiter limiter
+	stats   stats.Stats
+
+	mu             sync.RWMutex
+	filePolicies   []Policy
+	sourcePolicies map[string][]Policy // map key is the writeKey
+}
+
+// New constructs a new Throttler, loading the policies of the file set by Gateway.throttler.policiesFile if any
+func New(stats stats.Stats) (*Throttler, error) {
+	var redisClient *redis.Client
+	if config.IsSet("Gateway.throttler.redis.addr") {
+		redisClient = redis.NewClient(&redis.Options{
+			Addr:     config.GetString("Gateway.throttler.redis.addr", "localhost:6379"),
+			Username: config.GetString("Gateway.throttler.redis.username", ""),
+			Password: config.GetString("Gateway.throttler.redis.password", ""),
+		})
+	}
+
+	opts := []throttling.Option{throttling.WithStatsCollector(stats)}
+	throttlingAlgorithm := config.GetString("Gateway.throttler.algorithm", throttlingAlgoTypeGCRA)
+	switch throttlingAlgorithm {
+	case throttlingAlgoTypeGCRA:
+		opts = append(opts, throttling.WithInMemoryGCRA(0))
+	case throttlingAlgoTypeRedisGCRA:
+		if redisClient == nil {
+			return nil, fmt.Errorf("redis client is nil with algorithm %s", throttlingAlgorithm)
+		}
+		opts = append(opts, throttling.WithRedisGCRA(redisClient, 0))
+	default:
+		return nil, fmt.Errorf("invalid throttling algorithm: %s", throttlingAlgorithm)
+	}
+	l, err := throttling.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create throttler: %w", err)
+	}
+
+	t := &Throttler{limiter: l, stats: stats}
+	if policiesFile := config.GetString("Gateway.throttler.policiesFile", ""); policiesFile != "" {
+		if err := t.LoadPoliciesFile(policiesFile); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// LoadPoliciesFile replaces the policies of the local file with the ones of the YAML file at path
+func (t *Throttler) LoadPoliciesFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading rate limit policies file: %w", err)
+	}
+	var file policiesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parsing rate limit policies file: %w", err)
+	}
+	for i := range file.Policies {
+		if err := file.Policies[i].validate(); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.filePolicies = file.Policies
+	return nil
+}
+
+// SourcePolicies returns the policies configured in the config of a source, restricted to the source.
+// Invalid policies are skipped along with an error.
+func SourcePolicies(workspaceID, writeKey string, sourceConfig map[string]interface{}) ([]Policy, error) {
+	raw, ok := sourceConfig[SourceConfigKey]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling rate limit policies: %w", err)
+	}
+	var configured []Policy
+	if err := json.Unmarshal(data, &configured); err != nil {
+		return nil, fmt.Errorf("parsing rate limit policies: %w", err)
+	}
+
+	var (
+		policies []Policy
+		errs     []string
+	)
+	for _, policy := range configured {
+		policy.WorkspaceID = workspaceID
+		policy.WriteKey = writeKey
+		if policy.Name == "" {
+			policy.Name = "source-" + policy.Scope
+		}
+		if err := policy.validate(); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		policies = append(policies, policy)
+	}
+	if len(errs) > 0 {
+		return policies, fmt.Errorf("invalid rate limit policies: %s", strings.Join(errs, ", "))
+	}
+	return policies, nil
+}
+
+// SetSourcePolicies replaces the policies of the sources, keyed by their writeKey
+func (t *Throttler) SetSourcePolicies(sourcePolicies map[string][]Policy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sourcePolicies = sourcePolicies
+}
+
+// CheckLimitReached returns true along with the time to wait before retrying, if any of the policies matching the request is exceeded.
+// The events of a limited request are not counted against any policy, i.e. they are returned to the policies checked before the exceeded one.
+func (t *Throttler) CheckLimitReached(ctx context.Context, req Request) (limited bool, retryAfter time.Duration, policy string, retErr error) {
+	// the policies are replaced, never modified, so they can be checked without holding the lock
+	t.mu.RLock()
+	filePolicies, sourcePolicies := t.filePolicies, t.sourcePolicies[req.WriteKey]
+	t.mu.RUnlock()
+
+	var returns []func(context.Context) error
+	for _, policies := range [][]Policy{filePolicies, sourcePolicies} {
+		for i := range policies {
+			limited, retryAfter, tr, err := t.checkPolicy(ctx, &policies[i], &req)
+			if err != nil || limited {
+				for _, tr := range returns {
+					if err := tr(ctx); err != nil {
+						pkgLogger.Warnf("Returning events of request of writeKey %s to rate limit policies: %v", req.WriteKey, err)
+					}
+				}
+				return limited, retryAfter, policies[i].Name, err
+			}
+			if tr != nil {
+				returns = append(returns, tr)
+			}
+		}
+	}
+	return false, 0, "", nil
+}
+
+// checkPolicy counts the events of the request against the policy, returning how to return them if they are allowed
+func (t *Throttler) checkPolicy(ctx context.Context, p *Policy, req *Request) (limited bool, retryAfter time.Duration, tr func(context.Context) error, err error) {
+	if !p.matches(req) {
+		return false, 0, nil, nil
+	}
+	key, ok := p.key(req)
+	if !ok {
+		return false, 0, nil, nil
+	}
+
+	// requests with more events than the limit would never be allowed, they are allowed once the limit is fully available
+	cost := req.Events
+	if cost < 1 {
+		cost = 1
+	} else if cost > p.Limit {
+		cost = p.Limit
+	}
+	allowed, retryAfter, tr, err := t.limiter.AllowAfter(ctx, cost, p.Limit, int64(p.window.Seconds()), key)
+	if err != nil {
+		return false, 0, nil, fmt.Errorf("could not limit: %w", err)
+	}
+	if allowed {
+		return false, 0, tr, nil
+	}
+	if retryAfter <= 0 {
+		retryAfter = p.window
+	}
+	t.stats.NewTaggedStat("gateway.rate_limited_requests", stats.CountType, stats.Tags{
+		"policy":      p.Name,
+		"scope":       p.Scope,
+		"workspaceId": req.WorkspaceID,
+	}).Increment()
+	return true, retryAfter, nil, nil
+}
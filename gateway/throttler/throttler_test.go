@@ -0,0 +1,191 @@
+package throttler_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/gateway/throttler"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/services/stats/memstats"
+)
+
+func newThrottler(t *testing.T, policies string) (*throttler.Throttler, *memstats.Store) {
+	t.Helper()
+	config.Reset()
+
+	store := memstats.New()
+	th, err := throttler.New(store)
+	require.NoError(t, err)
+
+	if policies != "" {
+		path := filepath.Join(t.TempDir(), "policies.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(policies), 0o600))
+		require.NoError(t, th.LoadPoliciesFile(path))
+	}
+	return th, store
+}
+
+// allowedUntilLimited returns the number of requests allowed until one is limited, the GCRA limiters allow up to one more for bursts
+func allowedUntilLimited(t *testing.T, th *throttler.Throttler, req throttler.Request) int {
+	t.Helper()
+	for allowed := 0; allowed < 100; allowed++ {
+		limited, _, _, err := th.CheckLimitReached(context.Background(), req)
+		require.NoError(t, err)
+		if limited {
+			return allowed
+		}
+	}
+	t.Fatal("request never limited")
+	return 0
+}
+
+func TestThrottler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no policies", func(t *testing.T) {
+		th, _ := newThrottler(t, "")
+		limited, _, _, err := th.CheckLimitReached(ctx, throttler.Request{WorkspaceID: "ws-1", WriteKey: "wk-1", Events: 1000})
+		require.NoError(t, err)
+		require.False(t, limited)
+	})
+
+	t.Run("file policy by source", func(t *testing.T) {
+		th, store := newThrottler(t, `
+policies:
+  - name: sources
+    scope: source
+    workspaceId: ws-1
+    limit: 2
+    window: 1h
+`)
+		req := throttler.Request{WorkspaceID: "ws-1", WriteKey: "wk-1", Events: 1}
+		allowed := allowedUntilLimited(t, th, req)
+		require.GreaterOrEqual(t, allowed, 2)
+		require.LessOrEqual(t, allowed, 3)
+
+		limited, retryAfter, policy, err := th.CheckLimitReached(ctx, req)
+		require.NoError(t, err)
+		require.True(t, limited)
+		require.Equal(t, "sources", policy)
+		require.Greater(t, retryAfter, time.Duration(0))
+		require.LessOrEqual(t, retryAfter, time.Hour)
+		require.EqualValues(t, 1, store.Get("gateway.rate_limited_requests", stats.Tags{
+			"policy":      "sources",
+			"scope":       throttler.ScopeSource,
+			"workspaceId": "ws-1",
+		}).LastValue())
+
+		limited, _, _, err = th.CheckLimitReached(ctx, throttler.Request{WorkspaceID: "ws-1", WriteKey: "wk-2", Events: 2})
+		require.NoError(t, err)
+		require.False(t, limited, "other sources are counted separately")
+
+		limited, _, _, err = th.CheckLimitReached(ctx, throttler.Request{WorkspaceID: "ws-2", WriteKey: "wk-1", Events: 2})
+		require.NoError(t, err)
+		require.False(t, limited, "other workspaces are not matched")
+	})
+
+	t.Run("requests with more events than the limit", func(t *testing.T) {
+		th, _ := newThrottler(t, `
+policies:
+  - name: workspaces
+    scope: workspace
+    limit: 10
+    window: 1m
+`)
+		limited, _, _, err := th.CheckLimitReached(ctx, throttler.Request{WorkspaceID: "ws-1", Events: 100})
+		require.NoError(t, err)
+		require.False(t, limited)
+
+		require.LessOrEqual(t, allowedUntilLimited(t, th, throttler.Request{WorkspaceID: "ws-1", Events: 1}), 1)
+	})
+
+	t.Run("source policies by user", func(t *testing.T) {
+		th, _ := newThrottler(t, "")
+		policies, err := throttler.SourcePolicies("ws-1", "wk-1", map[string]interface{}{
+			throttler.SourceConfigKey: []interface{}{
+				map[string]interface{}{"scope": "user", "limit": 1, "window": "1m"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, policies, 1)
+		require.Equal(t, "wk-1", policies[0].WriteKey)
+		th.SetSourcePolicies(map[string][]throttler.Policy{"wk-1": policies})
+
+		req := throttler.Request{WorkspaceID: "ws-1", WriteKey: "wk-1", UserID: "user-1", Events: 1}
+		require.GreaterOrEqual(t, allowedUntilLimited(t, th, req), 1)
+
+		req.UserID = "user-2"
+		limited, _, _, err := th.CheckLimitReached(ctx, req)
+		require.NoError(t, err)
+		require.False(t, limited)
+
+		req.UserID = ""
+		for i := 0; i < 5; i++ {
+			limited, _, _, err = th.CheckLimitReached(ctx, req)
+			require.NoError(t, err)
+			require.False(t, limited, "requests without user are not limited by user")
+		}
+	})
+
+	t.Run("limited requests are not counted by the other policies", func(t *testing.T) {
+		th, _ := newThrottler(t, `
+policies:
+  - name: workspaces
+    scope: workspace
+    limit: 5
+    window: 1h
+`)
+		policies, err := throttler.SourcePolicies("ws-1", "wk-1", map[string]interface{}{
+			throttler.SourceConfigKey: []interface{}{
+				map[string]interface{}{"scope": "user", "limit": 1, "window": "1h"},
+			},
+		})
+		require.NoError(t, err)
+		th.SetSourcePolicies(map[string][]throttler.Policy{"wk-1": policies})
+
+		req := throttler.Request{WorkspaceID: "ws-1", WriteKey: "wk-1", UserID: "user-1", Events: 1}
+		require.GreaterOrEqual(t, allowedUntilLimited(t, th, req), 1)
+		for i := 0; i < 10; i++ {
+			limited, _, policy, err := th.CheckLimitReached(ctx, req)
+			require.NoError(t, err)
+			require.True(t, limited)
+			require.Equal(t, "source-user", policy)
+		}
+
+		for _, userID := range []string{"user-2", "user-3", "user-4"} {
+			req.UserID = userID
+			limited, _, _, err := th.CheckLimitReached(ctx, req)
+			require.NoError(t, err)
+			require.False(t, limited, "the events limited by the user policy are returned to the workspace policy")
+		}
+	})
+}
+
+func TestInvalidPolicies(t *testing.T) {
+	th, _ := newThrottler(t, "")
+	path := filepath.Join(t.TempDir(), "policies.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+policies:
+  - name: invalid
+    scope: country
+    limit: 1
+    window: 1m
+`), 0o600))
+	require.Error(t, th.LoadPoliciesFile(path))
+
+	policies, err := throttler.SourcePolicies("ws-1", "wk-1", map[string]interface{}{
+		throttler.SourceConfigKey: []interface{}{
+			map[string]interface{}{"scope": "ip", "limit": 1, "window": "1m"},
+			map[string]interface{}{"scope": "ip", "limit": 0, "window": "1m"},
+			map[string]interface{}{"scope": "ip", "limit": 1, "window": "forever"},
+		},
+	})
+	require.Error(t, err)
+	require.Len(t, policies, 1, "valid policies are kept")
+}
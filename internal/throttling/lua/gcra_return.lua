@@ -0,0 +1,34 @@
+--[[
+Returns the cost of an allowed request to the GCRA limiter of the key, moving its theoretical arrival time back.
+The key is deleted if the theoretical arrival time is not in the future anymore, as if nothing was consumed.
+--]]
+
+local rate_limit_key = KEYS[1]
+local rate = ARGV[1]
+local period = ARGV[2] * 1000 * 1000 -- converting to microseconds
+local cost = tonumber(ARGV[3])
+local emission_interval = period / rate
+local increment = emission_interval * cost
+
+-- see gcra.lua for the conversion of the current time
+local jan_1_2017 = 1483228800 * 1000 * 1000 -- in microseconds precision
+local current_time = redis.call("TIME")
+local microseconds = current_time[2]
+while string.len(microseconds) < 6 do
+    microseconds = "0" .. microseconds
+end
+current_time = tonumber(current_time[1] .. microseconds) - jan_1_2017
+
+local tat = redis.call("GET", rate_limit_key)
+if not tat then
+    return 0
+end
+
+local new_tat = tonumber(tat) - increment
+local reset_after = new_tat - current_time
+if reset_after > 0 then
+    redis.call("SET", rate_limit_key, new_tat, "EX", math.ceil(reset_after))
+else
+    redis.call("DEL", rate_limit_key)
+end
+return 1
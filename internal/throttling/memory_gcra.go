@@ -19,19 +19,31 @@ type gcra struct {
 }
 
 func (g *gcra) limit(key string, cost, burst, rate, period int64) (
-	bool, error,
+	bool, time.Duration, error,
 ) {
 	rl, err := g.getLimiter(key, burst, rate, period)
 	if err != nil {
-		return false, err
+		return false, 0, err
 	}
 
-	limited, _, err := rl.RateLimit("key", int(cost))
+	limited, res, err := rl.RateLimit("key", int(cost))
 	if err != nil {
-		return false, fmt.Errorf("could not rate limit: %w", err)
+		return false, 0, fmt.Errorf("could not rate limit: %w", err)
 	}
 
-	return !limited, nil
+	return !limited, res.RetryAfter, nil
+}
+
+// refund returns the cost of an allowed request, moving the theoretical arrival time of the key back
+func (g *gcra) refund(key string, cost, burst, rate, period int64) error {
+	rl, err := g.getLimiter(key, burst, rate, period)
+	if err != nil {
+		return err
+	}
+	if _, _, err := rl.RateLimit("key", -int(cost)); err != nil {
+		return fmt.Errorf("could not refund rate limit: %w", err)
+	}
+	return nil
 }
 
 func (g *gcra) getLimiter(key string, burst, rate, period int64) (*throttled.GCRARateLimiter, error) {
@@ -7,9 +7,30 @@ import (
 	"github.com/go-redis/redis/v8"
 )
 
-type unsupportedReturn struct{}
+// gcraReturn refunds the cost of a request allowed by the in-memory GCRA limiter
+type gcraReturn struct {
+	gcra                      *gcra
+	key                       string
+	cost, burst, rate, window int64
+}
+
+func (r *gcraReturn) Return(_ context.Context) error {
+	return r.gcra.refund(r.key, r.cost, r.burst, r.rate, r.window)
+}
+
+// gcraRedisReturn refunds the cost of a request allowed by the Redis GCRA limiter
+type gcraRedisReturn struct {
+	key                string
+	cost, rate, window int64
+	scripter           redis.Scripter
+}
 
-func (*unsupportedReturn) Return(_ context.Context) error { return nil }
+func (r *gcraRedisReturn) Return(ctx context.Context) error {
+	if _, err := gcraReturnRedisScript.Run(ctx, r.scripter, []string{r.key}, r.rate, r.window, r.cost).Result(); err != nil {
+		return fmt.Errorf("could not run GCRA return Redis script: %v", err)
+	}
+	return nil
+}
 
 type redisSortedSetRemover interface {
 	ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
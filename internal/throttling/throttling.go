@@ -23,6 +23,9 @@ var (
 	//go:embed lua/gcra.lua
 	gcraLua         string
 	gcraRedisScript *redis.Script
+	//go:embed lua/gcra_return.lua
+	gcraReturnLua         string
+	gcraReturnRedisScript *redis.Script
 	//go:embed lua/sortedset.lua
 	sortedSetLua    string
 	sortedSetScript *redis.Script
@@ -30,6 +33,7 @@ var (
 
 func init() {
 	gcraRedisScript = redis.NewScript(gcraLua)
+	gcraReturnRedisScript = redis.NewScript(gcraReturnLua)
 	sortedSetScript = redis.NewScript(sortedSetLua)
 }
 
@@ -90,17 +94,8 @@ func New(options ...Option) (*Limiter, error) {
 func (l *Limiter) Allow(ctx context.Context, cost, rate, window int64, key string) (
 	bool, func(context.Context) error, error,
 ) {
-	if cost < 1 {
-		return false, nil, fmt.Errorf("cost must be greater than 0")
-	}
-	if rate < 1 {
-		return false, nil, fmt.Errorf("rate must be greater than 0")
-	}
-	if window < 1 {
-		return false, nil, fmt.Errorf("window must be greater than 0")
-	}
-	if key == "" {
-		return false, nil, fmt.Errorf("key must not be empty")
+	if err := validate(cost, rate, window, key); err != nil {
+		return false, nil, err
 	}
 	switch {
 	case l.useGCRA:
@@ -121,6 +116,43 @@ func (l *Limiter) Allow(ctx context.Context, cost, rate, window int64, key strin
 	}
 }
 
+// AllowAfter returns true if the limit is not exceeded, false otherwise along with the time to wait before retrying.
+// Only the GCRA algorithms know the time to wait, the other ones return 0 when the limit is exceeded.
+func (l *Limiter) AllowAfter(ctx context.Context, cost, rate, window int64, key string) (
+	bool, time.Duration, func(context.Context) error, error,
+) {
+	if err := validate(cost, rate, window, key); err != nil {
+		return false, 0, nil, err
+	}
+	if !l.useGCRA {
+		allowed, tr, err := l.Allow(ctx, cost, rate, window, key)
+		return allowed, 0, tr, err
+	}
+	if l.redisSpeaker != nil {
+		defer l.getTimer(key, "redis-gcra", rate, window)()
+		_, allowed, retryAfter, tr, err := l.runRedisGCRA(ctx, cost, rate, window, key)
+		return allowed, retryAfter, tr, err
+	}
+	defer l.getTimer(key, "gcra", rate, window)()
+	return l.gcraLimitAfter(ctx, cost, rate, window, key)
+}
+
+func validate(cost, rate, window int64, key string) error {
+	if cost < 1 {
+		return fmt.Errorf("cost must be greater than 0")
+	}
+	if rate < 1 {
+		return fmt.Errorf("rate must be greater than 0")
+	}
+	if window < 1 {
+		return fmt.Errorf("window must be greater than 0")
+	}
+	if key == "" {
+		return fmt.Errorf("key must not be empty")
+	}
+	return nil
+}
+
 func (l *Limiter) redisSortedSet(ctx context.Context, cost, rate, window int64, key string) (
 	time.Duration, bool, func(context.Context) error, error,
 ) {
@@ -161,6 +193,13 @@ func (l *Limiter) redisSortedSet(ctx context.Context, cost, rate, window int64,
 
 func (l *Limiter) redisGCRA(ctx context.Context, cost, rate, window int64, key string) (
 	time.Duration, bool, func(context.Context) error, error,
+) {
+	redisTime, allowed, _, tr, err := l.runRedisGCRA(ctx, cost, rate, window, key)
+	return redisTime, allowed, tr, err
+}
+
+func (l *Limiter) runRedisGCRA(ctx context.Context, cost, rate, window int64, key string) (
+	time.Duration, bool, time.Duration, func(context.Context) error, error,
 ) {
 	burst := rate
 	if l.gcraBurst > 0 {
@@ -168,51 +207,66 @@ func (l *Limiter) redisGCRA(ctx context.Context, cost, rate, window int64, key s
 	}
 	res, err := gcraRedisScript.Run(ctx, l.redisSpeaker, []string{key}, burst, rate, window, cost).Result()
 	if err != nil {
-		return 0, false, nil, fmt.Errorf("could not run GCRA Redis script: %v", err)
+		return 0, false, 0, nil, fmt.Errorf("could not run GCRA Redis script: %v", err)
 	}
 
 	result, ok := res.([]interface{})
 	if !ok {
-		return 0, false, nil, fmt.Errorf("unexpected result from GCRA Redis script of type %T: %v", res, res)
+		return 0, false, 0, nil, fmt.Errorf("unexpected result from GCRA Redis script of type %T: %v", res, res)
 	}
 	if len(result) != 5 {
-		return 0, false, nil, fmt.Errorf("unexpected result from GCRA Redis scrip of length %d: %+v", len(result), result)
+		return 0, false, 0, nil, fmt.Errorf("unexpected result from GCRA Redis scrip of length %d: %+v", len(result), result)
 	}
 
 	t, ok := result[0].(int64)
 	if !ok {
-		return 0, false, nil, fmt.Errorf("unexpected result[0] from GCRA Redis script of type %T: %v", result[0], result[0])
+		return 0, false, 0, nil, fmt.Errorf("unexpected result[0] from GCRA Redis script of type %T: %v", result[0], result[0])
 	}
 	redisTime := time.Duration(t) * time.Microsecond
 
 	allowed, ok := result[1].(int64)
 	if !ok {
-		return redisTime, false, nil, fmt.Errorf("unexpected result[1] from GCRA Redis script of type %T: %v", result[1], result[1])
+		return redisTime, false, 0, nil, fmt.Errorf("unexpected result[1] from GCRA Redis script of type %T: %v", result[1], result[1])
 	}
 	if allowed < 1 { // limit exceeded
-		return redisTime, false, nil, nil
+		retryAfter, ok := result[3].(string)
+		if !ok {
+			return redisTime, false, 0, nil, fmt.Errorf("unexpected result[3] from GCRA Redis script of type %T: %v", result[3], result[3])
+		}
+		retryAfterMicros, err := strconv.ParseFloat(retryAfter, 64)
+		if err != nil {
+			return redisTime, false, 0, nil, fmt.Errorf("unexpected result[3] from GCRA Redis script: %w", err)
+		}
+		return redisTime, false, time.Duration(retryAfterMicros) * time.Microsecond, nil, nil
 	}
 
-	r := &unsupportedReturn{}
-	return redisTime, true, r.Return, nil
+	r := &gcraRedisReturn{key: key, cost: cost, rate: rate, window: window, scripter: l.redisSpeaker}
+	return redisTime, true, 0, r.Return, nil
 }
 
-func (l *Limiter) gcraLimit(_ context.Context, cost, rate, window int64, key string) (
+func (l *Limiter) gcraLimit(ctx context.Context, cost, rate, window int64, key string) (
 	bool, func(context.Context) error, error,
+) {
+	allowed, _, tr, err := l.gcraLimitAfter(ctx, cost, rate, window, key)
+	return allowed, tr, err
+}
+
+func (l *Limiter) gcraLimitAfter(_ context.Context, cost, rate, window int64, key string) (
+	bool, time.Duration, func(context.Context) error, error,
 ) {
 	burst := rate
 	if l.gcraBurst > 0 {
 		burst = l.gcraBurst
 	}
-	allowed, err := l.gcra.limit(key, cost, burst, rate, window)
+	allowed, retryAfter, err := l.gcra.limit(key, cost, burst, rate, window)
 	if err != nil {
-		return false, nil, fmt.Errorf("could not limit: %w", err)
+		return false, 0, nil, fmt.Errorf("could not limit: %w", err)
 	}
 	if !allowed {
-		return false, nil, nil // limit exceeded
+		return false, retryAfter, nil, nil // limit exceeded
 	}
-	r := &unsupportedReturn{}
-	return true, r.Return, nil
+	r := &gcraReturn{gcra: l.gcra, key: key, cost: cost, burst: burst, rate: rate, window: window}
+	return true, 0, r.Return, nil
 }
 
 func (l *Limiter) goRateLimit(_ context.Context, cost, rate, window int64, key string) (
@@ -195,6 +195,41 @@ func TestGCRABurstAsRate(t *testing.T) {
 	)
 }
 
+func TestAllowAfter(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	var (
+		ctx      = context.Background()
+		rc       = bootstrapRedis(ctx, t, pool)
+		limiters = map[string]*Limiter{
+			"gcra":       newLimiter(t, WithInMemoryGCRA(0)),
+			"gcra redis": newLimiter(t, WithRedisGCRA(rc, 0)),
+		}
+		rate   int64 = 2
+		window int64 = 10
+	)
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			key := rand.UniqueString(10)
+			for i := int64(0); i < rate; i++ {
+				allowed, retryAfter, _, err := l.AllowAfter(ctx, 1, rate, window, key)
+				require.NoError(t, err)
+				require.True(t, allowed)
+				require.Zero(t, retryAfter)
+			}
+
+			allowed, retryAfter, ret, err := l.AllowAfter(ctx, 1, rate, window, key)
+			require.NoError(t, err)
+			require.False(t, allowed)
+			require.Nil(t, ret)
+			require.Greater(t, retryAfter, time.Duration(0))
+			require.LessOrEqual(t, retryAfter, time.Duration(window/rate)*time.Second)
+		})
+	}
+}
+
 func TestReturn(t *testing.T) {
 	pool, err := dockertest.NewPool("")
 	require.NoError(t, err)
@@ -265,6 +300,51 @@ func TestReturn(t *testing.T) {
 	}
 }
 
+func TestGCRAReturn(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	var (
+		ctx      = context.Background()
+		rc       = bootstrapRedis(ctx, t, pool)
+		limiters = map[string]*Limiter{
+			"gcra":       newLimiter(t, WithInMemoryGCRA(0)),
+			"gcra redis": newLimiter(t, WithRedisGCRA(rc, 0)),
+		}
+		rate   int64 = 10
+		window int64 = 60
+	)
+
+	for name, l := range limiters {
+		t.Run(name, func(t *testing.T) {
+			key := rand.UniqueString(10)
+			var tokens []func(context.Context) error
+			for {
+				allowed, returner, err := l.Allow(ctx, 1, rate, window, key)
+				require.NoError(t, err)
+				if !allowed {
+					break
+				}
+				tokens = append(tokens, returner)
+			}
+			require.NotEmpty(t, tokens)
+
+			allowed, _, err := l.Allow(ctx, 1, rate, window, key)
+			require.NoError(t, err)
+			require.False(t, allowed)
+
+			require.NoError(t, tokens[0](ctx))
+			allowed, _, err = l.Allow(ctx, 1, rate, window, key)
+			require.NoError(t, err)
+			require.True(t, allowed, "the cost of the returned request is available again")
+
+			allowed, _, err = l.Allow(ctx, 1, rate, window, key)
+			require.NoError(t, err)
+			require.False(t, allowed)
+		})
+	}
+}
+
 func TestBadData(t *testing.T) {
 	pool, err := dockertest.NewPool("")
 	require.NoError(t, err)
@@ -50,6 +50,20 @@ func (mr *MockUserSuppressionMockRecorder) IsSuppressedUser(arg0, arg1, arg2 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSuppressedUser", reflect.TypeOf((*MockUserSuppression)(nil).IsSuppressedUser), arg0, arg1, arg2)
 }
 
+// IsSuppressedUserEvent mocks base method.
+func (m *MockUserSuppression) IsSuppressedUserEvent(arg0, arg1, arg2, arg3, arg4 string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSuppressedUserEvent", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsSuppressedUserEvent indicates an expected call of IsSuppressedUserEvent.
+func (mr *MockUserSuppressionMockRecorder) IsSuppressedUserEvent(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSuppressedUserEvent", reflect.TypeOf((*MockUserSuppression)(nil).IsSuppressedUserEvent), arg0, arg1, arg2, arg3, arg4)
+}
+
 // MockReportingI is a mock of ReportingI interface.
 type MockReportingI struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,167 @@
+package processor
+
+import (
+	"strings"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+// consentCategoryConfigs are the lists of consent categories a destination can require in its config,
+// along with the field holding the category in their items.
+// Items can also be plain strings.
+var consentCategoryConfigs = map[string]string{
+	"consentCategories":        "consentCategory",
+	"oneTrustCookieCategories": "oneTrustCookieCategory",
+	"ketchConsentPurposes":     "purpose",
+}
+
+// getConsentCategories returns the consent categories the users must have consented to, for their events to be sent to the destination
+func getConsentCategories(destination *backendconfig.DestinationT) []string {
+	var categories []string
+	for configKey, field := range consentCategoryConfigs {
+		items, ok := destination.Config[configKey].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			var category string
+			switch item := item.(type) {
+			case string:
+				category = item
+			case map[string]interface{}:
+				category, _ = item[field].(string)
+			}
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+	return categories
+}
+
+// consentDenied returns true if the consent management info of the event, in context.consentManagement, denies any of the categories.
+// Categories are denied when listed in deniedConsentIds, or missing from allowedConsentIds when the event lists them.
+// Events without consent management info are not denied.
+func consentDenied(event types.SingularEventT, categories []string) bool {
+	eventContext, ok := event["context"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	consentManagement, ok := eventContext["consentManagement"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+
+	denied := consentIDs(consentManagement["deniedConsentIds"])
+	allowed := consentIDs(consentManagement["allowedConsentIds"])
+	for _, category := range categories {
+		if _, ok := denied[category]; ok {
+			return true
+		}
+		if _, ok := allowed[category]; len(allowed) > 0 && !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func consentIDs(value interface{}) map[string]struct{} {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	ids := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if id, ok := item.(string); ok {
+			ids[strings.TrimSpace(id)] = struct{}{}
+		}
+	}
+	return ids
+}
+
+// consentFilterReport counts the events in and out of the consent filter, reported as diff metrics
+type consentFilterReport struct {
+	inCountMap         map[string]int64
+	inCountMetadataMap map[string]MetricMetadata
+	outCountMap        map[string]int64
+	// droppedCountMap is the number of events dropped by destination ID
+	droppedCountMap map[string]int
+	// trackingPlanEnabledMap are the sources whose events went through the tracking plan validation before the consent filter
+	trackingPlanEnabledMap map[SourceIDT]bool
+}
+
+func newConsentFilterReport(trackingPlanEnabledMap map[SourceIDT]bool) *consentFilterReport {
+	return &consentFilterReport{
+		inCountMap:             make(map[string]int64),
+		inCountMetadataMap:     make(map[string]MetricMetadata),
+		outCountMap:            make(map[string]int64),
+		droppedCountMap:        make(map[string]int),
+		trackingPlanEnabledMap: trackingPlanEnabledMap,
+	}
+}
+
+func (r *consentFilterReport) add(metadata *transformer.MetadataT, dropped bool) {
+	key := strings.Join([]string{
+		metadata.SourceID,
+		metadata.DestinationID,
+		metadata.SourceBatchID,
+		metadata.EventName,
+		metadata.EventType,
+	}, METRICKEYDELIMITER)
+	if _, ok := r.inCountMetadataMap[key]; !ok {
+		r.inCountMetadataMap[key] = MetricMetadata{sourceID: metadata.SourceID, destinationID: metadata.DestinationID, sourceBatchID: metadata.SourceBatchID, sourceTaskID: metadata.SourceTaskID, sourceTaskRunID: metadata.SourceTaskRunID, sourceJobID: metadata.SourceJobID, sourceJobRunID: metadata.SourceJobRunID, sourceDefinitionID: metadata.SourceDefinitionID, destinationDefinitionID: metadata.DestinationDefinitionID, sourceCategory: metadata.SourceCategory}
+	}
+	r.inCountMap[key]++
+	if dropped {
+		misc.IncrementMapByKey(r.droppedCountMap, metadata.DestinationID, 1)
+		return
+	}
+	r.outCountMap[key]++
+}
+
+func (proc *HandleT) reportConsentFilter(r *consentFilterReport) []*types.PUReportedMetric {
+	for destID, count := range r.droppedCountMap {
+		proc.statsFactory.NewTaggedStat("processor.consent_filtered_events", stats.CountType, stats.Tags{
+			"destinationId": destID,
+		}).Count(count)
+	}
+	if !proc.isReportingEnabled() {
+		return nil
+	}
+	return r.diffMetrics()
+}
+
+// diffMetrics returns the events dropped by the consent filter, reported against the previous stage of their source
+func (r *consentFilterReport) diffMetrics() []*types.PUReportedMetric {
+	inCountMaps := make(map[string]map[string]int64)
+	for key, inCount := range r.inCountMap {
+		inPU := lastFilterPU(r.trackingPlanEnabledMap[SourceIDT(r.inCountMetadataMap[key].sourceID)], false)
+		if _, ok := inCountMaps[inPU]; !ok {
+			inCountMaps[inPU] = make(map[string]int64)
+		}
+		inCountMaps[inPU][key] = inCount
+	}
+	var diffMetrics []*types.PUReportedMetric
+	for inPU, inCountMap := range inCountMaps {
+		diffMetrics = append(diffMetrics, getDiffMetrics(inPU, types.CONSENT_FILTER, r.inCountMetadataMap, inCountMap, r.outCountMap, map[string]int64{})...)
+	}
+	return diffMetrics
+}
+
+// lastFilterPU returns the last PU filtering the events of a destination before the user transformer.
+// The consent filter only reports the destinations requiring consent categories, so the events of the others
+// come from the tracking plan validator or the destination filter.
+func lastFilterPU(trackingPlanEnabled, consentFilterEnabled bool) string {
+	switch {
+	case consentFilterEnabled:
+		return types.CONSENT_FILTER
+	case trackingPlanEnabled:
+		return types.TRACKINGPLAN_VALIDATOR
+	default:
+		return types.DESTINATION_FILTER
+	}
+}
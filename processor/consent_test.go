@@ -0,0 +1,192 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/enterprise/reporting"
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+	"github.com/rudderlabs/rudder-server/services/transientsource"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+func TestGetConsentCategories(t *testing.T) {
+	t.Run("No consent categories", func(t *testing.T) {
+		destination := backendconfig.DestinationT{Config: map[string]interface{}{}}
+		assert.Empty(t, getConsentCategories(&destination))
+	})
+
+	t.Run("OneTrust, Ketch and generic categories", func(t *testing.T) {
+		destination := backendconfig.DestinationT{Config: map[string]interface{}{
+			"oneTrustCookieCategories": []interface{}{
+				map[string]interface{}{"oneTrustCookieCategory": "C0001"},
+				map[string]interface{}{"oneTrustCookieCategory": ""},
+			},
+			"ketchConsentPurposes": []interface{}{
+				map[string]interface{}{"purpose": "analytics"},
+			},
+			"consentCategories": []interface{}{"marketing", " "},
+		}}
+		assert.ElementsMatch(t, []string{"C0001", "analytics", "marketing"}, getConsentCategories(&destination))
+	})
+}
+
+func TestConsentDenied(t *testing.T) {
+	event := func(consentManagement map[string]interface{}) types.SingularEventT {
+		return types.SingularEventT{
+			"context": map[string]interface{}{"consentManagement": consentManagement},
+		}
+	}
+	categories := []string{"C0001", "C0002"}
+
+	t.Run("No consent management info", func(t *testing.T) {
+		assert.False(t, consentDenied(types.SingularEventT{}, categories))
+		assert.False(t, consentDenied(types.SingularEventT{"context": map[string]interface{}{}}, categories))
+	})
+
+	t.Run("All categories allowed", func(t *testing.T) {
+		assert.False(t, consentDenied(event(map[string]interface{}{
+			"allowedConsentIds": []interface{}{"C0001", "C0002", "C0003"},
+		}), categories))
+	})
+
+	t.Run("A category is not allowed", func(t *testing.T) {
+		assert.True(t, consentDenied(event(map[string]interface{}{
+			"allowedConsentIds": []interface{}{"C0001"},
+		}), categories))
+	})
+
+	t.Run("A category is denied", func(t *testing.T) {
+		assert.True(t, consentDenied(event(map[string]interface{}{
+			"deniedConsentIds": []interface{}{"C0002"},
+		}), categories))
+	})
+
+	t.Run("Other categories are denied", func(t *testing.T) {
+		assert.False(t, consentDenied(event(map[string]interface{}{
+			"deniedConsentIds": []interface{}{"C0004"},
+		}), categories))
+	})
+}
+
+func TestConsentFilterReport(t *testing.T) {
+	report := newConsentFilterReport(map[SourceIDT]bool{"source-2": true})
+	metadata := transformer.MetadataT{SourceID: "source-1", DestinationID: "dest-1", EventName: "Product Viewed", EventType: "track"}
+	report.add(&metadata, false)
+	report.add(&metadata, true)
+	report.add(&metadata, true)
+
+	metrics := report.diffMetrics()
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, types.DESTINATION_FILTER, metrics[0].PUDetails.InPU)
+	assert.Equal(t, types.CONSENT_FILTER, metrics[0].PUDetails.PU)
+	assert.EqualValues(t, -2, metrics[0].StatusDetail.Count)
+	assert.Equal(t, map[string]int{"dest-1": 2}, report.droppedCountMap)
+
+	t.Run("Sources with a tracking plan", func(t *testing.T) {
+		metadata := metadata
+		metadata.SourceID = "source-2"
+		report.add(&metadata, true)
+
+		metrics := report.diffMetrics()
+		require.Len(t, metrics, 2)
+		for _, metric := range metrics {
+			if metric.ConnectionDetails.SourceID == "source-2" {
+				assert.Equal(t, types.TRACKINGPLAN_VALIDATOR, metric.PUDetails.InPU)
+			}
+		}
+	})
+}
+
+// TestConsentFilterReportingChain checks that the stages after the consent filter report it as their input,
+// unless the destination requires no consent categories and isn't reported by the consent filter.
+func TestConsentFilterReportingChain(t *testing.T) {
+	initProcessor()
+	proc := &HandleT{
+		reporting:        &reporting.NOOP{},
+		reportingEnabled: true,
+		transientSources: transientsource.NewEmptyService(),
+		logger:           logger.NOP,
+	}
+	metadata := transformer.MetadataT{SourceID: "source-1", DestinationID: "dest-1", MessageID: "message-1", EventName: "Product Viewed", EventType: "track"}
+	destination := backendconfig.DestinationT{ID: "dest-1"}
+	eventsByMessageID := map[string]types.SingularEventWithReceivedAt{
+		"message-1": {SingularEvent: types.SingularEventT{"event": "Product Viewed"}},
+	}
+	response := transformer.ResponseT{
+		Events:       []transformer.TransformerResponseT{{Output: map[string]interface{}{"event": "Product Viewed"}, Metadata: metadata, StatusCode: 200}},
+		FailedEvents: []transformer.TransformerResponseT{{Metadata: metadata, StatusCode: 400}},
+	}
+
+	// inPUs are the input of the stages, keyed by stage
+	inPUs := make(map[string]string)
+	record := func(metrics []*types.PUReportedMetric) {
+		t.Helper()
+		require.NotEmpty(t, metrics)
+		for _, metric := range metrics {
+			inPUs[metric.PUDetails.PU] = metric.PUDetails.InPU
+		}
+	}
+
+	report := newConsentFilterReport(map[SourceIDT]bool{})
+	report.add(&metadata, true)
+	record(report.diffMetrics())
+
+	// recordStages records the stages after the filters, whose last PU is filterPU
+	recordStages := func(t *testing.T, filterPU string) {
+		t.Helper()
+		for _, transformationEnabled := range []bool{true, false} {
+			_, metrics, _, _ := proc.getDestTransformerEvents(response, &transformer.MetadataT{}, &destination, transformer.UserTransformerStage, filterPU, transformationEnabled)
+			record(metrics)
+			_, metrics, _ = proc.getFailedEventJobs(response, &transformer.MetadataT{}, eventsByMessageID, transformer.UserTransformerStage, filterPU, transformationEnabled)
+			record(metrics)
+			assert.Equal(t, filterPU, inPUs[types.USER_TRANSFORMER])
+
+			_, metrics, _, _ = proc.getDestTransformerEvents(response, &transformer.MetadataT{}, &destination, transformer.EventFilterStage, filterPU, transformationEnabled)
+			record(metrics)
+			_, metrics, _ = proc.getFailedEventJobs(response, &transformer.MetadataT{}, eventsByMessageID, transformer.DestTransformerStage, filterPU, transformationEnabled)
+			record(metrics)
+			if transformationEnabled {
+				assert.Equal(t, types.USER_TRANSFORMER, inPUs[types.EVENT_FILTER])
+			} else {
+				assert.Equal(t, filterPU, inPUs[types.EVENT_FILTER], "the events go from the last filter to the event filter without user transformation")
+			}
+		}
+	}
+
+	t.Run("consent categories", func(t *testing.T) {
+		report := newConsentFilterReport(map[SourceIDT]bool{})
+		report.add(&metadata, true)
+		record(report.diffMetrics())
+		recordStages(t, lastFilterPU(false, true))
+
+		assert.Equal(t, map[string]string{
+			types.CONSENT_FILTER:   types.DESTINATION_FILTER,
+			types.USER_TRANSFORMER: types.CONSENT_FILTER,
+			types.EVENT_FILTER:     types.CONSENT_FILTER,
+			types.DEST_TRANSFORMER: types.EVENT_FILTER,
+		}, inPUs)
+	})
+
+	t.Run("no consent categories", func(t *testing.T) {
+		inPUs = make(map[string]string)
+		recordStages(t, lastFilterPU(false, false))
+		assert.Equal(t, map[string]string{
+			types.USER_TRANSFORMER: types.DESTINATION_FILTER,
+			types.EVENT_FILTER:     types.DESTINATION_FILTER,
+			types.DEST_TRANSFORMER: types.EVENT_FILTER,
+		}, inPUs, "the consent filter isn't reported")
+
+		inPUs = make(map[string]string)
+		recordStages(t, lastFilterPU(true, false))
+		assert.Equal(t, map[string]string{
+			types.USER_TRANSFORMER: types.TRACKINGPLAN_VALIDATOR,
+			types.EVENT_FILTER:     types.TRACKINGPLAN_VALIDATOR,
+			types.DEST_TRANSFORMER: types.EVENT_FILTER,
+		}, inPUs)
+	})
+}
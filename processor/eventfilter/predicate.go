@@ -0,0 +1,423 @@
+package eventfilter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+)
+
+// PredicateConfigKey is the key of the event filter expression in the config of a destination
+const PredicateConfigKey = "eventFilterExpression"
+
+// Predicate is a boolean expression over the fields of the events, e.g.
+//
+//	properties.revenue > 0 AND (context.app.name == "web" OR NOT context.library.name contains "ios")
+//
+// Fields are dot separated paths, compared with ==, !=, >, >=, <, <= or contains to strings, numbers, true, false or null.
+// A field by itself is true if it is present and not null, false, 0 or "".
+// Numbers are compared as numbers, strings lexicographically; comparisons of different types are only true for !=.
+type Predicate struct {
+	expression string
+	root       node
+}
+
+// GetPredicate returns the event filter predicate of the destination, or false if it has none
+func GetPredicate(destination *backendconfig.DestinationT) (*Predicate, bool, error) {
+	expression, _ := destination.Config[PredicateConfigKey].(string)
+	if strings.TrimSpace(expression) == "" {
+		return nil, false, nil
+	}
+	predicate, err := ParsePredicate(expression)
+	if err != nil {
+		return nil, false, err
+	}
+	return predicate, true, nil
+}
+
+// ParsePredicate parses the expression of a predicate
+func ParsePredicate(expression string) (*Predicate, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, fmt.Errorf("parsing event filter expression %q: %w", expression, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing event filter expression %q: %w", expression, err)
+	}
+	return &Predicate{expression: expression, root: root}, nil
+}
+
+// String returns the expression of the predicate
+func (p *Predicate) String() string {
+	return p.expression
+}
+
+// Match returns true if the event matches the predicate
+func (p *Predicate) Match(event map[string]interface{}) bool {
+	return p.root.eval(event)
+}
+
+type node interface {
+	eval(event map[string]interface{}) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n *andNode) eval(event map[string]interface{}) bool {
+	return n.left.eval(event) && n.right.eval(event)
+}
+
+type orNode struct{ left, right node }
+
+func (n *orNode) eval(event map[string]interface{}) bool {
+	return n.left.eval(event) || n.right.eval(event)
+}
+
+type notNode struct{ operand node }
+
+func (n *notNode) eval(event map[string]interface{}) bool {
+	return !n.operand.eval(event)
+}
+
+type truthyNode struct{ path []string }
+
+func (n *truthyNode) eval(event map[string]interface{}) bool {
+	switch value := lookup(event, n.path).(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != ""
+	}
+	return true
+}
+
+type comparisonNode struct {
+	path     []string
+	operator string
+	value    interface{}
+}
+
+func (n *comparisonNode) eval(event map[string]interface{}) bool {
+	field := lookup(event, n.path)
+	if n.operator == opContains {
+		s, ok := field.(string)
+		return ok && strings.Contains(s, n.value.(string))
+	}
+
+	var cmp int
+	switch value := n.value.(type) {
+	case nil:
+		if field != nil {
+			return n.operator == opNotEqual
+		}
+	case bool:
+		b, ok := field.(bool)
+		if !ok {
+			return n.operator == opNotEqual
+		}
+		if b != value {
+			cmp = 1
+		}
+	case float64:
+		f, ok := toFloat(field)
+		if !ok {
+			return n.operator == opNotEqual
+		}
+		switch {
+		case f < value:
+			cmp = -1
+		case f > value:
+			cmp = 1
+		}
+	case string:
+		s, ok := field.(string)
+		if !ok {
+			return n.operator == opNotEqual
+		}
+		cmp = strings.Compare(s, value)
+	}
+
+	switch n.operator {
+	case opEqual:
+		return cmp == 0
+	case opNotEqual:
+		return cmp != 0
+	case opGreater:
+		return cmp > 0
+	case opGreaterOrEqual:
+		return cmp >= 0
+	case opLess:
+		return cmp < 0
+	case opLessOrEqual:
+		return cmp <= 0
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	}
+	return 0, false
+}
+
+func lookup(event map[string]interface{}, path []string) interface{} {
+	var value interface{} = event
+	for _, key := range path {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+const (
+	opEqual          = "=="
+	opNotEqual       = "!="
+	opGreater        = ">"
+	opGreaterOrEqual = ">="
+	opLess           = "<"
+	opLessOrEqual    = "<="
+	opContains       = "contains"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOperator
+	tokenOpenParen
+	tokenCloseParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpenParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenCloseParen, text: ")"})
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			s, err := strconv.Unquote(string(runes[i : j+1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s: %w", string(runes[i:j+1]), err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: s})
+			i = j + 1
+		case strings.ContainsRune("=!<>", c):
+			op := string(c)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				op += "="
+			}
+			switch op {
+			case opEqual, opNotEqual, opGreater, opGreaterOrEqual, opLess, opLessOrEqual:
+			default:
+				return nil, fmt.Errorf("invalid operator %q", op)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op})
+			i += len(op)
+		default:
+			j := i
+			for ; j < len(runes) && !unicode.IsSpace(runes[j]) && !strings.ContainsRune(`()"=!<>`, runes[j]); j++ {
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[i:j])})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peekKeyword(keyword string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenWord && strings.EqualFold(p.tokens[p.pos].text, keyword)
+}
+
+func (p *parser) next() (token, error) {
+	if p.pos >= len(p.tokens) {
+		return token{}, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	return p.tokens[p.pos-1], nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("AND") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peekKeyword("NOT") {
+		p.pos++
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case tokenOpenParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, err := p.next(); err != nil || t.kind != tokenCloseParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return n, nil
+	case tokenWord:
+		if isKeyword(t.text) {
+			return nil, fmt.Errorf("unexpected %q", t.text)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected %q, expecting a field", t.text)
+	}
+
+	path := strings.Split(t.text, ".")
+	for _, key := range path {
+		if key == "" {
+			return nil, fmt.Errorf("invalid field %q", t.text)
+		}
+	}
+
+	var operator string
+	switch {
+	case p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator:
+		operator = p.tokens[p.pos].text
+	case p.peekKeyword(opContains):
+		operator = opContains
+	default:
+		return &truthyNode{path: path}, nil
+	}
+	p.pos++
+
+	t, err = p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := literal(t)
+	if err != nil {
+		return nil, err
+	}
+	switch value.(type) {
+	case string:
+	case nil, bool:
+		if operator != opEqual && operator != opNotEqual {
+			return nil, fmt.Errorf("operator %s not supported for %s", operator, t.text)
+		}
+	default:
+		if operator == opContains {
+			return nil, fmt.Errorf("operator %s only supported for strings", operator)
+		}
+	}
+	return &comparisonNode{path: path, operator: operator, value: value}, nil
+}
+
+func isKeyword(word string) bool {
+	for _, keyword := range []string{"AND", "OR", "NOT", opContains} {
+		if strings.EqualFold(word, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func literal(t token) (interface{}, error) {
+	if t.kind == tokenString {
+		return t.text, nil
+	}
+	if t.kind != tokenWord {
+		return nil, fmt.Errorf("unexpected %q, expecting a value", t.text)
+	}
+	switch t.text {
+	case "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	f, err := strconv.ParseFloat(t.text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q", t.text)
+	}
+	return f, nil
+}
@@ -0,0 +1,89 @@
+package eventfilter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/eventfilter"
+)
+
+func TestPredicate(t *testing.T) {
+	event := map[string]interface{}{
+		"type":  "track",
+		"event": "Order Completed",
+		"properties": map[string]interface{}{
+			"revenue":  49.9,
+			"currency": "USD",
+			"test":     false,
+		},
+		"context": map[string]interface{}{
+			"app": map[string]interface{}{"name": "web"},
+		},
+	}
+
+	for expression, match := range map[string]bool{
+		`properties.revenue > 0 AND context.app.name == "web"`:            true,
+		`properties.revenue > 50 AND context.app.name == "web"`:           false,
+		`properties.revenue > 50 OR context.app.name == "web"`:            true,
+		`properties.revenue >= 49.9 AND properties.revenue <= 49.9`:       true,
+		`properties.revenue < 10`:                                         false,
+		`properties.currency != "EUR"`:                                    true,
+		`properties.currency == 1`:                                        false,
+		`properties.currency != 1`:                                        true,
+		`event contains "Order"`:                                          true,
+		`NOT event contains "Order"`:                                      false,
+		`properties.test == false`:                                        true,
+		`properties.test`:                                                 false,
+		`properties.coupon == null`:                                       true,
+		`properties.coupon != null`:                                       false,
+		`properties.coupon > 0`:                                           false,
+		`context.app.name.version == "1"`:                                 false,
+		`type == "identify" or (type == "track" and not properties.test)`: true,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			predicate, err := eventfilter.ParsePredicate(expression)
+			require.NoError(t, err)
+			require.Equal(t, match, predicate.Match(event))
+		})
+	}
+}
+
+func TestParsePredicateErrors(t *testing.T) {
+	for _, expression := range []string{
+		`properties.revenue >`,
+		`properties.revenue => 1`,
+		`(type == "track"`,
+		`type == "track")`,
+		`type == "track`,
+		`type == track`,
+		`AND type == "track"`,
+		`properties..revenue > 0`,
+		`properties.test > true`,
+		`properties.revenue contains 1`,
+	} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := eventfilter.ParsePredicate(expression)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestGetPredicate(t *testing.T) {
+	_, ok, err := eventfilter.GetPredicate(&backendconfig.DestinationT{Config: map[string]interface{}{}})
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	predicate, ok, err := eventfilter.GetPredicate(&backendconfig.DestinationT{Config: map[string]interface{}{
+		eventfilter.PredicateConfigKey: `type == "track"`,
+	}})
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, `type == "track"`, predicate.String())
+
+	_, _, err = eventfilter.GetPredicate(&backendconfig.DestinationT{Config: map[string]interface{}{
+		eventfilter.PredicateConfigKey: `type ==`,
+	}})
+	require.Error(t, err)
+}
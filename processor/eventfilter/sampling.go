@@ -0,0 +1,134 @@
+package eventfilter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+)
+
+// Keys of the sampling settings in the config of a destination
+const (
+	// SamplingRateConfigKey is the rate of the events sent to the destination, between 0 and 1
+	SamplingRateConfigKey = "samplingRate"
+	// EventSamplingRatesConfigKey overrides the rate for some events, e.g.
+	//
+	//	"eventSamplingRates": [
+	//		{
+	//			"event": "Page Viewed",
+	//			"rate": 0.1
+	//		}
+	//	]
+	EventSamplingRatesConfigKey = "eventSamplingRates"
+)
+
+// Sampler keeps a deterministic sample of the events, by hashing their anonymousId or userId,
+// so that either all or none of the events of a user are kept for the same rate.
+type Sampler struct {
+	rate       float64
+	eventRates map[string]float64
+}
+
+// GetSampler returns the sampler of the destination, or false if it keeps all the events
+func GetSampler(destination *backendconfig.DestinationT) (*Sampler, bool, error) {
+	sampler := &Sampler{rate: 1}
+	if value, ok := destination.Config[SamplingRateConfigKey]; ok {
+		rate, err := parseRate(value)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid %s: %w", SamplingRateConfigKey, err)
+		}
+		sampler.rate = rate
+	}
+
+	if value, ok := destination.Config[EventSamplingRatesConfigKey]; ok {
+		eventRates, ok := value.([]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("invalid %s of type %T", EventSamplingRatesConfigKey, value)
+		}
+		sampler.eventRates = make(map[string]float64, len(eventRates))
+		for _, eventRate := range eventRates {
+			eventRate, ok := eventRate.(map[string]interface{})
+			if !ok {
+				return nil, false, fmt.Errorf("invalid %s entry of type %T", EventSamplingRatesConfigKey, eventRate)
+			}
+			event, _ := eventRate["event"].(string)
+			if event == "" {
+				return nil, false, fmt.Errorf("missing event in %s", EventSamplingRatesConfigKey)
+			}
+			rate, err := parseRate(eventRate["rate"])
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid rate of event %q: %w", event, err)
+			}
+			sampler.eventRates[event] = rate
+		}
+	}
+
+	if sampler.rate == 1 && len(sampler.eventRates) == 0 {
+		return nil, false, nil
+	}
+	return sampler, true, nil
+}
+
+// parseRate accepts numbers or strings, since the destination settings are often stored as strings
+func parseRate(value interface{}) (float64, error) {
+	var rate float64
+	switch value := value.(type) {
+	case float64:
+		rate = value
+	case int:
+		rate = float64(value)
+	case string:
+		var err error
+		rate, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid rate %q", value)
+		}
+	default:
+		return 0, fmt.Errorf("invalid rate of type %T", value)
+	}
+	if math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate %v must be between 0 and 1", rate)
+	}
+	return rate, nil
+}
+
+// Rate returns the sample rate of the event
+func (s *Sampler) Rate(event map[string]interface{}) float64 {
+	if name, ok := event["event"].(string); ok {
+		if rate, ok := s.eventRates[name]; ok {
+			return rate
+		}
+	}
+	return s.rate
+}
+
+// Sample returns true if the event is kept, along with its sample rate
+func (s *Sampler) Sample(event map[string]interface{}) (bool, float64) {
+	rate := s.Rate(event)
+	switch rate {
+	case 0:
+		return false, rate
+	case 1:
+		return true, rate
+	}
+	return sampleKey(event) < rate, rate
+}
+
+// sampleKey returns a number in [0, 1) uniformly distributed across users, the same one for all the events of a user.
+// The anonymousId is preferred, since it is kept when the user gets identified, falling back to the userId.
+// Events without a user are sampled by their messageId.
+func sampleKey(event map[string]interface{}) float64 {
+	var key string
+	for _, field := range []string{"anonymousId", "userId", "messageId"} {
+		if value, ok := event[field]; ok && value != nil && value != "" {
+			key = fmt.Sprint(value)
+			break
+		}
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
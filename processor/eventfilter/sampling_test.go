@@ -0,0 +1,97 @@
+package eventfilter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/eventfilter"
+)
+
+func TestGetSampler(t *testing.T) {
+	for name, config := range map[string]map[string]interface{}{
+		"no sampling": {},
+		"full rate":   {eventfilter.SamplingRateConfigKey: 1.0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, ok, err := eventfilter.GetSampler(&backendconfig.DestinationT{Config: config})
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	}
+
+	for name, config := range map[string]map[string]interface{}{
+		"rate above 1":     {eventfilter.SamplingRateConfigKey: 1.5},
+		"negative rate":    {eventfilter.SamplingRateConfigKey: "-0.1"},
+		"rate not number":  {eventfilter.SamplingRateConfigKey: "half"},
+		"event rates type": {eventfilter.EventSamplingRatesConfigKey: "Page Viewed"},
+		"missing event": {eventfilter.EventSamplingRatesConfigKey: []interface{}{
+			map[string]interface{}{"rate": 0.5},
+		}},
+		"event rate": {eventfilter.EventSamplingRatesConfigKey: []interface{}{
+			map[string]interface{}{"event": "Page Viewed", "rate": 2.0},
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := eventfilter.GetSampler(&backendconfig.DestinationT{Config: config})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSampler(t *testing.T) {
+	sampler, ok, err := eventfilter.GetSampler(&backendconfig.DestinationT{Config: map[string]interface{}{
+		eventfilter.SamplingRateConfigKey: "0.25",
+		eventfilter.EventSamplingRatesConfigKey: []interface{}{
+			map[string]interface{}{"event": "Order Completed", "rate": 1.0},
+			map[string]interface{}{"event": "Heartbeat", "rate": 0.0},
+		},
+	}})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	t.Run("events of a user are all kept or dropped", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			userID := fmt.Sprintf("user-%d", i)
+			kept, rate := sampler.Sample(map[string]interface{}{"userId": userID, "event": "Page Viewed", "messageId": "1"})
+			require.Equal(t, 0.25, rate)
+			for j := 0; j < 5; j++ {
+				k, _ := sampler.Sample(map[string]interface{}{"userId": userID, "event": "Product Viewed", "messageId": fmt.Sprint(j)})
+				require.Equal(t, kept, k)
+			}
+		}
+	})
+
+	t.Run("events of a user before and after being identified are all kept or dropped", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			anonymousID := fmt.Sprintf("anonymous-%d", i)
+			kept, _ := sampler.Sample(map[string]interface{}{"anonymousId": anonymousID, "event": "Page Viewed"})
+			k, _ := sampler.Sample(map[string]interface{}{"anonymousId": anonymousID, "userId": fmt.Sprintf("user-%d", i), "event": "Page Viewed"})
+			require.Equal(t, kept, k)
+		}
+	})
+
+	t.Run("sample rate", func(t *testing.T) {
+		var kept int
+		const users = 10000
+		for i := 0; i < users; i++ {
+			if k, _ := sampler.Sample(map[string]interface{}{"anonymousId": fmt.Sprintf("anonymous-%d", i)}); k {
+				kept++
+			}
+		}
+		require.InDelta(t, 0.25*users, kept, 0.02*users)
+	})
+
+	t.Run("event rates", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			userID := fmt.Sprintf("user-%d", i)
+			kept, rate := sampler.Sample(map[string]interface{}{"userId": userID, "event": "Order Completed"})
+			require.True(t, kept)
+			require.Equal(t, 1.0, rate)
+			kept, _ = sampler.Sample(map[string]interface{}{"userId": userID, "event": "Heartbeat"})
+			require.False(t, kept)
+		}
+	})
+}
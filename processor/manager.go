@@ -28,6 +28,7 @@ type LifecycleManager struct {
 	ReportingI       types.ReportingI         // need not initialize again
 	BackendConfig    backendconfig.BackendConfig
 	Transformer      transformer.Transformer
+	SuppressUser     types.UserSuppression // suppression of the users whose events are dropped, if any
 	transientSources transientsource.Service
 	fileuploader     fileuploader.Provider
 	rsourcesService  rsources.JobService
@@ -40,6 +41,9 @@ func (proc *LifecycleManager) Start() error {
 	if proc.Transformer != nil {
 		proc.HandleT.transformer = proc.Transformer
 	}
+	if proc.SuppressUser != nil {
+		proc.HandleT.suppressUserHandler = proc.SuppressUser
+	}
 
 	proc.HandleT.Setup(
 		proc.BackendConfig, proc.gatewayDB, proc.routerDB, proc.batchRouterDB, proc.errDB,
@@ -27,6 +27,7 @@ import (
 	"github.com/rudderlabs/rudder-server/jobsdb"
 	"github.com/rudderlabs/rudder-server/processor/eventfilter"
 	"github.com/rudderlabs/rudder-server/processor/integrations"
+	"github.com/rudderlabs/rudder-server/processor/redaction"
 	"github.com/rudderlabs/rudder-server/processor/stash"
 	"github.com/rudderlabs/rudder-server/processor/transformer"
 	"github.com/rudderlabs/rudder-server/router"
@@ -65,6 +66,7 @@ type HandleT struct {
 	logger                    logger.Logger
 	eventSchemaHandler        types.EventSchemasI
 	dedupHandler              dedup.DedupI
+	suppressUserHandler       types.UserSuppression
 	reporting                 types.ReportingI
 	reportingEnabled          bool
 	multitenantI              multitenant.MultiTenantI
@@ -472,6 +474,7 @@ var (
 	enableEventSchemasFeature bool
 	enableEventSchemasAPIOnly bool
 	enableDedup               bool
+	enableSuppressUserFeature bool
 	enableEventCount          bool
 	transformTimesPQLength    int
 	captureEventNameStats     bool
@@ -497,6 +500,8 @@ func loadConfig() {
 	config.RegisterIntConfigVariable(200, &userTransformBatchSize, true, 1, "Processor.userTransformBatchSize")
 	// Enable dedup of incoming events by default
 	config.RegisterBoolConfigVariable(false, &enableDedup, false, "Dedup.enableDedup")
+	// Enable suppress user feature, keeping the gateway key it was configured with before. true by default
+	config.RegisterBoolConfigVariable(true, &enableSuppressUserFeature, false, "Processor.enableSuppressUserFeature", "Gateway.enableSuppressUserFeature")
 	config.RegisterBoolConfigVariable(true, &enableEventCount, true, "Processor.enableEventCount")
 	// EventSchemas feature. false by default
 	config.RegisterBoolConfigVariable(false, &enableEventSchemasFeature, false, "EventSchemas.enableEventSchemasFeature")
@@ -582,6 +587,11 @@ func SetFeaturesRetryAttempts(overrideAttempts int) {
 	featuresRetryMaxAttempts = overrideAttempts
 }
 
+// IsSuppressUserFeatureEnabled returns true if the events of suppressed users are dropped by the processor
+func IsSuppressUserFeatureEnabled() bool {
+	return enableSuppressUserFeature
+}
+
 func (proc *HandleT) backendConfigSubscriber() {
 	ch := proc.backendConfig.Subscribe(context.TODO(), backendconfig.TopicProcessConfig)
 	for data := range ch {
@@ -591,15 +601,49 @@ func (proc *HandleT) backendConfigSubscriber() {
 		writeKeyDestinationMap = make(map[string][]backendconfig.DestinationT)
 		writeKeySourceMap = map[string]backendconfig.SourceT{}
 		destinationIDtoTypeMap = make(map[string]string)
+		destinationConsentCategoriesMap = make(map[string][]string)
+		sourceRedactionRulesMap = make(map[string]redaction.Rules)
+		destinationRedactionRulesMap = make(map[string]redaction.Rules)
+		destinationPredicatesMap = make(map[string]*eventfilter.Predicate)
+		destinationSamplersMap = make(map[string]*eventfilter.Sampler)
 		for workspaceID, wConfig := range config {
 			for i := range wConfig.Sources {
 				source := &wConfig.Sources[i]
 				writeKeySourceMap[source.WriteKey] = *source
 				if source.Enabled {
 					writeKeyDestinationMap[source.WriteKey] = source.Destinations
+					rules, err := redaction.Parse(source.Config)
+					if err != nil {
+						pkgLogger.Errorf("Invalid redaction rules of source %s: %v", source.ID, err)
+					}
+					if len(rules) > 0 {
+						sourceRedactionRulesMap[source.ID] = rules
+					}
 					for j := range source.Destinations {
 						destination := &source.Destinations[j]
 						destinationIDtoTypeMap[destination.ID] = destination.DestinationDefinition.Name
+						if categories := getConsentCategories(destination); len(categories) > 0 {
+							destinationConsentCategoriesMap[destination.ID] = categories
+						}
+						rules, err := redaction.Parse(destination.Config)
+						if err != nil {
+							pkgLogger.Errorf("Invalid redaction rules of destination %s: %v", destination.ID, err)
+						}
+						if len(rules) > 0 {
+							destinationRedactionRulesMap[destination.ID] = rules
+						}
+						predicate, ok, err := eventfilter.GetPredicate(destination)
+						if err != nil {
+							pkgLogger.Errorf("Invalid event filter expression of destination %s, its events are not filtered by it: %v", destination.ID, err)
+						} else if ok {
+							destinationPredicatesMap[destination.ID] = predicate
+						}
+						sampler, ok, err := eventfilter.GetSampler(destination)
+						if err != nil {
+							pkgLogger.Errorf("Invalid sampling rates of destination %s, its events are not sampled: %v", destination.ID, err)
+						} else if ok {
+							destinationSamplersMap[destination.ID] = sampler
+						}
 					}
 				}
 			}
@@ -627,6 +671,49 @@ func getSourceByWriteKey(writeKey string) (*backendconfig.SourceT, error) {
 	return &source, err
 }
 
+// destinationConsentCategoriesMap are the consent categories required by the destinations, keyed by destination ID
+var destinationConsentCategoriesMap map[string][]string
+
+func getDestinationConsentCategories(destinationID string) []string {
+	configSubscriberLock.RLock()
+	defer configSubscriberLock.RUnlock()
+	return destinationConsentCategoriesMap[destinationID]
+}
+
+// destinationPredicatesMap are the event filter predicates of the destinations, keyed by destination ID.
+// Destinations with an invalid expression have none.
+var destinationPredicatesMap map[string]*eventfilter.Predicate
+
+func getDestinationPredicate(destinationID string) *eventfilter.Predicate {
+	configSubscriberLock.RLock()
+	defer configSubscriberLock.RUnlock()
+	return destinationPredicatesMap[destinationID]
+}
+
+// destinationSamplersMap are the samplers of the destinations, keyed by destination ID.
+// Destinations with invalid sampling rates have none.
+var destinationSamplersMap map[string]*eventfilter.Sampler
+
+func getDestinationSampler(destinationID string) *eventfilter.Sampler {
+	configSubscriberLock.RLock()
+	defer configSubscriberLock.RUnlock()
+	return destinationSamplersMap[destinationID]
+}
+
+// sourceRedactionRulesMap and destinationRedactionRulesMap are the redaction rules of the sources and destinations, keyed by their ID
+var sourceRedactionRulesMap, destinationRedactionRulesMap map[string]redaction.Rules
+
+// getRedactionRules returns the redaction rules applied to the events sent from the source to the destination, the ones of the source first
+func getRedactionRules(sourceID, destinationID string) redaction.Rules {
+	configSubscriberLock.RLock()
+	defer configSubscriberLock.RUnlock()
+	sourceRules, destinationRules := sourceRedactionRulesMap[sourceID], destinationRedactionRulesMap[destinationID]
+	if len(destinationRules) == 0 {
+		return sourceRules
+	}
+	return append(append(redaction.Rules{}, sourceRules...), destinationRules...)
+}
+
 func getEnabledDestinations(writeKey, destinationName string) []backendconfig.DestinationT {
 	configSubscriberLock.RLock()
 	defer configSubscriberLock.RUnlock()
@@ -801,7 +888,7 @@ func recordEventDeliveryStatus(jobsByDestID map[string][]*jobsdb.JobT) {
 	}
 }
 
-func (proc *HandleT) getDestTransformerEvents(response transformer.ResponseT, commonMetaData *transformer.MetadataT, destination *backendconfig.DestinationT, stage string, trackingPlanEnabled, userTransformationEnabled bool) ([]transformer.TransformerEventT, []*types.PUReportedMetric, map[string]int64, map[string]MetricMetadata) {
+func (proc *HandleT) getDestTransformerEvents(response transformer.ResponseT, commonMetaData *transformer.MetadataT, destination *backendconfig.DestinationT, stage, filterPU string, userTransformationEnabled bool) ([]transformer.TransformerEventT, []*types.PUReportedMetric, map[string]int64, map[string]MetricMetadata) {
 	successMetrics := make([]*types.PUReportedMetric, 0)
 	connectionDetailsMap := make(map[string]*types.ConnectionDetails)
 	statusDetailsMap := make(map[string]*types.StatusDetail)
@@ -844,11 +931,7 @@ func (proc *HandleT) getDestTransformerEvents(response transformer.ResponseT, co
 
 		var inPU, pu string
 		if stage == transformer.UserTransformerStage {
-			if trackingPlanEnabled {
-				inPU = types.TRACKINGPLAN_VALIDATOR
-			} else {
-				inPU = types.DESTINATION_FILTER
-			}
+			inPU = filterPU
 			pu = types.USER_TRANSFORMER
 		} else if stage == transformer.TrackingPlanValidationStage {
 			inPU = types.DESTINATION_FILTER
@@ -857,11 +940,7 @@ func (proc *HandleT) getDestTransformerEvents(response transformer.ResponseT, co
 			if userTransformationEnabled {
 				inPU = types.USER_TRANSFORMER
 			} else {
-				if trackingPlanEnabled {
-					inPU = types.TRACKINGPLAN_VALIDATOR
-				} else {
-					inPU = types.DESTINATION_FILTER
-				}
+				inPU = filterPU
 			}
 			pu = types.EVENT_FILTER
 		}
@@ -950,7 +1029,7 @@ func (proc *HandleT) updateMetricMaps(countMetadataMap map[string]MetricMetadata
 	}
 }
 
-func (proc *HandleT) getFailedEventJobs(response transformer.ResponseT, commonMetaData *transformer.MetadataT, eventsByMessageID map[string]types.SingularEventWithReceivedAt, stage string, transformationEnabled, trackingPlanEnabled bool) ([]*jobsdb.JobT, []*types.PUReportedMetric, map[string]int64) {
+func (proc *HandleT) getFailedEventJobs(response transformer.ResponseT, commonMetaData *transformer.MetadataT, eventsByMessageID map[string]types.SingularEventWithReceivedAt, stage, filterPU string, transformationEnabled bool) ([]*jobsdb.JobT, []*types.PUReportedMetric, map[string]int64) {
 	failedMetrics := make([]*types.PUReportedMetric, 0)
 	connectionDetailsMap := make(map[string]*types.ConnectionDetails)
 	statusDetailsMap := make(map[string]*types.StatusDetail)
@@ -1040,22 +1119,14 @@ func (proc *HandleT) getFailedEventJobs(response transformer.ResponseT, commonMe
 			if transformationEnabled {
 				inPU = types.USER_TRANSFORMER
 			} else {
-				if trackingPlanEnabled {
-					inPU = types.TRACKINGPLAN_VALIDATOR
-				} else {
-					inPU = types.DESTINATION_FILTER
-				}
+				inPU = filterPU
 			}
 			pu = types.EVENT_FILTER
 		} else if stage == transformer.DestTransformerStage {
 			inPU = types.EVENT_FILTER
 			pu = types.DEST_TRANSFORMER
 		} else if stage == transformer.UserTransformerStage {
-			if trackingPlanEnabled {
-				inPU = types.TRACKINGPLAN_VALIDATOR
-			} else {
-				inPU = types.DESTINATION_FILTER
-			}
+			inPU = filterPU
 			pu = types.USER_TRANSFORMER
 		} else if stage == transformer.TrackingPlanValidationStage {
 			inPU = types.DESTINATION_FILTER
@@ -1075,6 +1146,26 @@ func (proc *HandleT) getFailedEventJobs(response transformer.ResponseT, commonMe
 	return failedEventsToStore, failedMetrics, failedCountMap
 }
 
+// getFilteredEventsMetrics returns the metrics of the events filtered out by the event filter, reported with the reason they were filtered for, along with their counts
+func (proc *HandleT) getFilteredEventsMetrics(response transformer.ResponseT, inPU string) ([]*types.PUReportedMetric, map[string]int64) {
+	filteredMetrics := make([]*types.PUReportedMetric, 0)
+	connectionDetailsMap := make(map[string]*types.ConnectionDetails)
+	statusDetailsMap := make(map[string]*types.StatusDetail)
+	filteredCountMap := make(map[string]int64)
+	for i := range response.FilteredEvents {
+		proc.updateMetricMaps(nil, filteredCountMap, connectionDetailsMap, statusDetailsMap, &response.FilteredEvents[i], types.FilterStatus, []byte(`{}`))
+	}
+	for k, cd := range connectionDetailsMap {
+		m := &types.PUReportedMetric{
+			ConnectionDetails: *cd,
+			PUDetails:         *types.CreatePUDetails(inPU, types.EVENT_FILTER, false, false),
+			StatusDetail:      statusDetailsMap[k],
+		}
+		filteredMetrics = append(filteredMetrics, m)
+	}
+	return filteredMetrics, filteredCountMap
+}
+
 func (proc *HandleT) updateSourceEventStatsDetailed(event types.SingularEventT, writeKey string) {
 	// Any panics in this function are captured and ignore sending the stat
 	defer func() {
@@ -1175,6 +1266,8 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 	uniqueMessageIds := make(map[string]struct{})
 	uniqueMessageIdsBySrcDestKey := make(map[string]map[string]struct{})
 	sourceDupStats := make(map[string]int)
+	strategyDupStats := make(map[dedupStatKey]int)
+	sourceSuppressedStats := make(map[string]int)
 
 	reportMetrics := make([]*types.PUReportedMetric, 0)
 	inCountMap := make(map[string]int64)
@@ -1200,27 +1293,35 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 		receivedAt := gjson.Get(string(batchEvent.EventPayload), "receivedAt").Time()
 
 		if ok {
-			var duplicateIndexes []int
+			var (
+				duplicateStrategies map[int]string
+				contentHashes       []string
+			)
 			if enableDedup {
-				var allMessageIdsInBatch []string
-				for _, singularEvent := range singularEvents {
-					allMessageIdsInBatch = append(allMessageIdsInBatch, misc.GetStringifiedData(singularEvent["messageId"]))
-				}
-				duplicateIndexes = proc.dedupHandler.FindDuplicates(allMessageIdsInBatch, uniqueMessageIds)
+				duplicateStrategies, contentHashes = proc.findDuplicateEvents(writeKey, singularEvents, uniqueMessageIds)
 			}
 
 			// Iterate through all the events in the batch
 			for eventIndex, singularEvent := range singularEvents {
 				messageId := misc.GetStringifiedData(singularEvent["messageId"])
-				if enableDedup && misc.Contains(duplicateIndexes, eventIndex) {
-					proc.logger.Debugf("Dropping event with duplicate messageId: %s", messageId)
+				if strategy, ok := duplicateStrategies[eventIndex]; ok {
+					proc.logger.Debugf("Dropping event with duplicate %s: %s", strategy, messageId)
 					misc.IncrementMapByKey(sourceDupStats, writeKey, 1)
+					strategyDupStats[dedupStatKey{writeKey: writeKey, strategy: strategy}]++
+					continue
+				}
+				if proc.isSuppressedUserEvent(batchEvent, singularEvent) {
+					proc.logger.Debugf("Dropping event of suppressed user: %s", messageId)
+					misc.IncrementMapByKey(sourceSuppressedStats, writeKey, 1)
 					continue
 				}
 
 				proc.updateSourceEventStatsDetailed(singularEvent, writeKey)
 
 				uniqueMessageIds[messageId] = struct{}{}
+				if contentHashes != nil && contentHashes[eventIndex] != "" {
+					uniqueMessageIds[contentHashes[eventIndex]] = struct{}{}
+				}
 				// We count this as one, not destination specific ones
 				totalEvents++
 				eventsByMessageID[messageId] = types.SingularEventWithReceivedAt{
@@ -1338,6 +1439,7 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 	// REPORTING - GATEWAY metrics - END
 
 	proc.stats.statNumEvents.Count(totalEvents)
+	proc.updateSourceStats(sourceSuppressedStats, "processor.write_key_suppressed_events")
 
 	marshalTime := time.Since(marshalStart)
 	defer proc.stats.marshalSingularEvents.SendTiming(marshalTime)
@@ -1358,6 +1460,8 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 	// TRACKING PLAN - END
 
 	// The below part further segregates events by sourceID and DestinationID.
+	// Events are not sent to the destinations requiring consent categories the users didn't consent to.
+	consentReport := newConsentFilterReport(trackingPlanEnabledMap)
 	for writeKeyT, eventList := range validatedEventsByWriteKey {
 		for idx := range eventList {
 			event := &eventList[idx]
@@ -1386,6 +1490,13 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 					shallowEventCopy.Metadata.DestinationType = destination.DestinationDefinition.Name
 					filterConfig(&shallowEventCopy, destination)
 					metadata := shallowEventCopy.Metadata
+					if categories := getDestinationConsentCategories(destination.ID); len(categories) > 0 {
+						dropped := consentDenied(singularEvent, categories)
+						consentReport.add(&metadata, dropped)
+						if dropped {
+							continue
+						}
+					}
 					srcAndDestKey := getKeyFromSourceAndDest(metadata.SourceID, metadata.DestinationID)
 					// We have at-least one event so marking it good
 					_, ok := groupedEvents[srcAndDestKey]
@@ -1403,6 +1514,8 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 		}
 	}
 
+	reportMetrics = append(reportMetrics, proc.reportConsentFilter(consentReport)...)
+
 	if len(statusList) != len(jobList) {
 		panic(fmt.Errorf("len(statusList):%d != len(jobList):%d", len(statusList), len(jobList)))
 	}
@@ -1420,6 +1533,7 @@ func (proc *HandleT) processJobsForDest(subJobs subJob, parsedEventList [][]type
 		statusList,
 		procErrorJobs,
 		sourceDupStats,
+		strategyDupStats,
 		uniqueMessageIds,
 
 		totalEvents,
@@ -1440,7 +1554,9 @@ type transformationMessage struct {
 	statusList                   []*jobsdb.JobStatusT
 	procErrorJobs                []*jobsdb.JobT
 	sourceDupStats               map[string]int
-	uniqueMessageIds             map[string]struct{}
+	strategyDupStats             map[dedupStatKey]int
+	// uniqueMessageIds are the dedup keys of the events kept, i.e. their messageIds and content hashes
+	uniqueMessageIds map[string]struct{}
 
 	totalEvents int
 	start       time.Time
@@ -1512,6 +1628,7 @@ func (proc *HandleT) transformations(in *transformationMessage) *storeMessage {
 
 		in.reportMetrics,
 		in.sourceDupStats,
+		in.strategyDupStats,
 		in.uniqueMessageIds,
 		in.totalEvents,
 		in.start,
@@ -1530,6 +1647,7 @@ type storeMessage struct {
 
 	reportMetrics    []*types.PUReportedMetric
 	sourceDupStats   map[string]int
+	strategyDupStats map[dedupStatKey]int
 	uniqueMessageIds map[string]struct{}
 
 	totalEvents int
@@ -1675,16 +1793,7 @@ func (proc *HandleT) Store(in *storeMessage) {
 
 			if enableDedup {
 				proc.updateSourceStats(in.sourceDupStats, "processor.write_key_duplicate_events")
-				if len(in.uniqueMessageIds) > 0 {
-					var dedupedMessageIdsAcrossJobs []string
-					for k := range in.uniqueMessageIds {
-						dedupedMessageIdsAcrossJobs = append(dedupedMessageIdsAcrossJobs, k)
-					}
-					err = proc.dedupHandler.MarkProcessed(dedupedMessageIdsAcrossJobs)
-					if err != nil {
-						return err
-					}
-				}
+				proc.updateDedupStrategyStats(in.strategyDupStats)
 			}
 			return nil
 		})
@@ -1692,6 +1801,16 @@ func (proc *HandleT) Store(in *storeMessage) {
 	if err != nil {
 		panic(err)
 	}
+	// messageIds are marked as processed only once their jobs are stored, so that they are read again if the store fails
+	if enableDedup && len(in.uniqueMessageIds) > 0 {
+		var dedupedMessageIdsAcrossJobs []string
+		for k := range in.uniqueMessageIds {
+			dedupedMessageIdsAcrossJobs = append(dedupedMessageIdsAcrossJobs, k)
+		}
+		if err := proc.dedupHandler.MarkProcessed(dedupedMessageIdsAcrossJobs); err != nil {
+			panic(fmt.Errorf("marking messageIds as processed: %w", err))
+		}
+	}
 	proc.stats.statDBW.Since(beforeStoreStatus)
 	dbWriteTime := time.Since(beforeStoreStatus)
 	// DB write throughput per second.
@@ -1715,6 +1834,30 @@ type transformSrcDestOutput struct {
 	errorsPerDestID map[string][]*jobsdb.JobT
 }
 
+// applyRedactionRules redacts the messages of the events by the rules of the source and the destination.
+// Messages are replaced by redacted copies, since they are shared between destinations.
+func (proc *HandleT) applyRedactionRules(sourceID, destID string, events []transformer.TransformerEventT) {
+	rules := getRedactionRules(sourceID, destID)
+	if len(rules) == 0 {
+		return
+	}
+	appliedByAction := make(map[string]int)
+	for i := range events {
+		message, applied := rules.Apply(events[i].Message)
+		events[i].Message = message
+		for action, n := range applied {
+			appliedByAction[action] += n
+		}
+	}
+	for action, n := range appliedByAction {
+		proc.statsFactory.NewTaggedStat("processor.redaction_rules_applied", stats.CountType, stats.Tags{
+			"sourceId":      sourceID,
+			"destinationId": destID,
+			"action":        action,
+		}).Count(n)
+	}
+}
+
 func (proc *HandleT) transformSrcDest(
 	ctx context.Context,
 	// main inputs
@@ -1751,7 +1894,7 @@ func (proc *HandleT) transformSrcDest(
 	transformationEnabled := len(destination.Transformations) > 0
 	configSubscriberLock.RUnlock()
 
-	trackingPlanEnabled := trackingPlanEnabledMap[SourceIDT(sourceID)]
+	filterPU := lastFilterPU(trackingPlanEnabledMap[SourceIDT(sourceID)], len(getDestinationConsentCategories(destID)) > 0)
 
 	var inCountMap map[string]int64
 	var inCountMetadataMap map[string]MetricMetadata
@@ -1805,8 +1948,8 @@ func (proc *HandleT) transformSrcDest(
 			var successMetrics []*types.PUReportedMetric
 			var successCountMap map[string]int64
 			var successCountMetadataMap map[string]MetricMetadata
-			eventsToTransform, successMetrics, successCountMap, successCountMetadataMap = proc.getDestTransformerEvents(response, commonMetaData, destination, transformer.UserTransformerStage, trackingPlanEnabled, transformationEnabled)
-			failedJobs, failedMetrics, failedCountMap := proc.getFailedEventJobs(response, commonMetaData, eventsByMessageID, transformer.UserTransformerStage, transformationEnabled, trackingPlanEnabled)
+			eventsToTransform, successMetrics, successCountMap, successCountMetadataMap = proc.getDestTransformerEvents(response, commonMetaData, destination, transformer.UserTransformerStage, filterPU, transformationEnabled)
+			failedJobs, failedMetrics, failedCountMap := proc.getFailedEventJobs(response, commonMetaData, eventsByMessageID, transformer.UserTransformerStage, filterPU, transformationEnabled)
 			proc.saveFailedJobs(failedJobs)
 			if _, ok := procErrorJobsByDestID[destID]; !ok {
 				procErrorJobsByDestID[destID] = make([]*jobsdb.JobT, 0)
@@ -1822,7 +1965,7 @@ func (proc *HandleT) transformSrcDest(
 			// REPORTING - START
 			if proc.isReportingEnabled() {
 				diffMetrics := getDiffMetrics(
-					types.DESTINATION_FILTER,
+					filterPU,
 					types.USER_TRANSFORMER,
 					inCountMetadataMap,
 					inCountMap,
@@ -1871,27 +2014,27 @@ func (proc *HandleT) transformSrcDest(
 	var successMetrics []*types.PUReportedMetric
 	var successCountMap map[string]int64
 	var successCountMetadataMap map[string]MetricMetadata
-	eventsToTransform, successMetrics, successCountMap, successCountMetadataMap = proc.getDestTransformerEvents(response, commonMetaData, destination, transformer.EventFilterStage, trackingPlanEnabled, transformationEnabled)
-	failedJobs, failedMetrics, failedCountMap := proc.getFailedEventJobs(response, commonMetaData, eventsByMessageID, transformer.EventFilterStage, transformationEnabled, trackingPlanEnabled)
+	eventsToTransform, successMetrics, successCountMap, successCountMetadataMap = proc.getDestTransformerEvents(response, commonMetaData, destination, transformer.EventFilterStage, filterPU, transformationEnabled)
+	failedJobs, failedMetrics, failedCountMap := proc.getFailedEventJobs(response, commonMetaData, eventsByMessageID, transformer.EventFilterStage, filterPU, transformationEnabled)
 	proc.saveFailedJobs(failedJobs)
 	proc.logger.Debug("Supported messages filtering output size", len(eventsToTransform))
 
 	// REPORTING - START
 	if proc.isReportingEnabled() {
-		var inPU string
+		inPU := filterPU
 		if transformationEnabled {
 			inPU = types.USER_TRANSFORMER
-		} else {
-			if trackingPlanEnabled {
-				inPU = types.TRACKINGPLAN_VALIDATOR
-			} else {
-				inPU = types.DESTINATION_FILTER
-			}
 		}
 
-		diffMetrics := getDiffMetrics(inPU, types.EVENT_FILTER, inCountMetadataMap, inCountMap, successCountMap, failedCountMap)
+		filteredMetrics, filteredCountMap := proc.getFilteredEventsMetrics(response, inPU)
+		// filtered events are reported by themselves, not as a diff
+		for key, count := range failedCountMap {
+			filteredCountMap[key] += count
+		}
+		diffMetrics := getDiffMetrics(inPU, types.EVENT_FILTER, inCountMetadataMap, inCountMap, successCountMap, filteredCountMap)
 		reportMetrics = append(reportMetrics, successMetrics...)
 		reportMetrics = append(reportMetrics, failedMetrics...)
+		reportMetrics = append(reportMetrics, filteredMetrics...)
 		reportMetrics = append(reportMetrics, diffMetrics...)
 
 		// successCountMap will be inCountMap for destination transform
@@ -1903,10 +2046,15 @@ func (proc *HandleT) transformSrcDest(
 	eventFilterStat.numEvents.Count(len(eventsToTransform))
 	eventFilterStat.numOutputSuccessEvents.Count(len(response.Events))
 	eventFilterStat.numOutputFailedEvents.Count(len(failedJobs))
+	proc.statsFactory.NewTaggedStat("proc_event_filter_filtered_count", stats.CountType, buildStatTags(sourceID, workspaceID, destination, EVENT_FILTER)).Count(len(response.FilteredEvents))
 	eventFilterStat.transformTime.Since(s)
 
 	// Filtering events based on the supported message types - END
 
+	// Redacting the fields of the events after user transformation, so that they are redacted in its output too,
+	// and after filtering, so that the filters match the original values of the fields
+	proc.applyRedactionRules(sourceID, destID, eventsToTransform)
+
 	if len(eventsToTransform) == 0 {
 		return transformSrcDestOutput{
 			destJobs:        destJobs,
@@ -1947,7 +2095,7 @@ func (proc *HandleT) transformSrcDest(
 
 			failedJobs, failedMetrics, failedCountMap := proc.getFailedEventJobs(
 				response, commonMetaData, eventsByMessageID,
-				transformer.DestTransformerStage, transformationEnabled, trackingPlanEnabled,
+				transformer.DestTransformerStage, filterPU, transformationEnabled,
 			)
 			destTransformationStat.numEvents.Count(len(eventsToTransform))
 			destTransformationStat.numOutputSuccessEvents.Count(len(response.Events))
@@ -2111,6 +2259,10 @@ func ConvertToFilteredTransformerResponse(events []transformer.TransformerEventT
 	supportedMessageTypesCache := make(map[string]*cacheValue)
 	supportedMessageEventsCache := make(map[string]*cacheValue)
 
+	predicateCache := make(map[string]*eventfilter.Predicate)
+	samplerCache := make(map[string]*eventfilter.Sampler)
+	var filteredEvents []transformer.TransformerResponseT
+
 	// filter unsupported message types
 	var resp transformer.TransformerResponseT
 	var errMessage string
@@ -2162,12 +2314,41 @@ func ConvertToFilteredTransformerResponse(events []transformer.TransformerEventT
 			}
 
 		}
+
+		// filter events not matching the event filter expression of the destination
+		predicate, ok := predicateCache[event.Destination.ID]
+		if !ok {
+			predicate = getDestinationPredicate(event.Destination.ID)
+			predicateCache[event.Destination.ID] = predicate
+		}
+		if predicate != nil && !predicate.Match(event.Message) {
+			errMessage = fmt.Sprintf("Event not matching the event filter expression: %s", predicate)
+			resp = transformer.TransformerResponseT{Output: event.Message, StatusCode: types.FilterEventCode, Metadata: event.Metadata, Error: errMessage}
+			filteredEvents = append(filteredEvents, resp)
+			continue
+		}
+
+		// sample events by the sampling rates of the destination
+		sampler, ok := samplerCache[event.Destination.ID]
+		if !ok {
+			sampler = getDestinationSampler(event.Destination.ID)
+			samplerCache[event.Destination.ID] = sampler
+		}
+		if sampler != nil {
+			if kept, rate := sampler.Sample(event.Message); !kept {
+				errMessage = fmt.Sprintf("Event sampled out at rate %v", rate)
+				resp = transformer.TransformerResponseT{Output: event.Message, StatusCode: types.FilterEventCode, Metadata: event.Metadata, Error: errMessage}
+				filteredEvents = append(filteredEvents, resp)
+				continue
+			}
+		}
+
 		// allow event
 		resp = transformer.TransformerResponseT{Output: event.Message, StatusCode: 200, Metadata: event.Metadata}
 		responses = append(responses, resp)
 	}
 
-	return transformer.ResponseT{Events: responses, FailedEvents: failedEvents}
+	return transformer.ResponseT{Events: responses, FailedEvents: failedEvents, FilteredEvents: filteredEvents}
 }
 
 func (proc *HandleT) addToTransformEventByTimePQ(event *TransformRequestT, pq *transformRequestPQ) {
@@ -2501,6 +2682,7 @@ func (proc *HandleT) mainPipeline(ctx context.Context) {
 				mergedJob.uniqueMessageIds = make(map[string]struct{})
 				mergedJob.procErrorJobsByDestID = make(map[string][]*jobsdb.JobT)
 				mergedJob.sourceDupStats = make(map[string]int)
+				mergedJob.strategyDupStats = make(map[dedupStatKey]int)
 
 				mergedJob.start = subJob.start
 				firstSubJob = false
@@ -2531,6 +2713,9 @@ func subJobMerger(mergedJob, subJob *storeMessage) *storeMessage {
 	for tag, count := range subJob.sourceDupStats {
 		mergedJob.sourceDupStats[tag] += count
 	}
+	for key, count := range subJob.strategyDupStats {
+		mergedJob.strategyDupStats[key] += count
+	}
 	for id := range subJob.uniqueMessageIds {
 		mergedJob.uniqueMessageIds[id] = struct{}{}
 	}
@@ -2558,6 +2743,87 @@ func (proc *HandleT) updateSourceStats(sourceStats map[string]int, bucket string
 	}
 }
 
+// dedupStatKey is the source and the dedup strategy the duplicate events are counted by
+type dedupStatKey struct {
+	writeKey string
+	strategy string
+}
+
+func (proc *HandleT) updateDedupStrategyStats(strategyStats map[dedupStatKey]int) {
+	for key, count := range strategyStats {
+		tags := map[string]string{
+			"source":   key.writeKey,
+			"strategy": key.strategy,
+		}
+		proc.statsFactory.NewTaggedStat("processor.dedup_duplicate_events", stats.CountType, tags).Count(count)
+	}
+}
+
+// isSuppressedUserEvent returns true if the user of the event is suppressed, matching it by its userId, anonymousId or email
+func (proc *HandleT) isSuppressedUserEvent(job *jobsdb.JobT, event types.SingularEventT) bool {
+	if !enableSuppressUserFeature || proc.suppressUserHandler == nil {
+		return false
+	}
+	email := misc.GetStringifiedData(misc.MapLookup(event, "context", "traits", "email"))
+	if email == "" {
+		email = misc.GetStringifiedData(misc.MapLookup(event, "traits", "email"))
+	}
+	return proc.suppressUserHandler.IsSuppressedUserEvent(
+		job.WorkspaceId,
+		gjson.GetBytes(job.Parameters, "source_id").Str,
+		misc.GetStringifiedData(event["userId"]),
+		misc.GetStringifiedData(event["anonymousId"]),
+		email,
+	)
+}
+
+// findDuplicateEvents returns the dedup strategy each duplicate event of the batch is found by, keyed by the index of the event.
+// Sources deduplicating by content hash also get the content hashes of the events, which are looked up along with their messageIds,
+// in the same dedup window. Events duplicated by both are accounted to their messageId.
+// Events without any of the hashed fields have no content hash, and are deduplicated by their messageId only.
+func (proc *HandleT) findDuplicateEvents(writeKey string, singularEvents []types.SingularEventT, uniqueMessageIds map[string]struct{}) (duplicateStrategies map[int]string, contentHashes []string) {
+	keyConfig := dedup.KeyConfig{Strategy: dedup.KeyStrategyMessageID}
+	var sourceID string
+	if source, err := getSourceByWriteKey(writeKey); err == nil {
+		keyConfig = dedup.GetKeyConfig(source.Config)
+		sourceID = source.ID
+	}
+
+	dedupKeys := make([]string, 0, len(singularEvents))
+	// hashEventIndexes are the indexes of the events of the content hashes in dedupKeys, after their messageIds
+	var hashEventIndexes []int
+	for _, singularEvent := range singularEvents {
+		dedupKeys = append(dedupKeys, misc.GetStringifiedData(singularEvent["messageId"]))
+	}
+	if keyConfig.Strategy == dedup.KeyStrategyContentHash {
+		contentHashes = make([]string, len(singularEvents))
+		for eventIndex, singularEvent := range singularEvents {
+			contentHashes[eventIndex] = keyConfig.ContentHash(sourceID, singularEvent)
+			if contentHashes[eventIndex] != "" {
+				dedupKeys = append(dedupKeys, contentHashes[eventIndex])
+				hashEventIndexes = append(hashEventIndexes, eventIndex)
+			}
+		}
+	}
+
+	duplicateIndexes := proc.dedupHandler.FindDuplicates(dedupKeys, uniqueMessageIds)
+	duplicateStrategies = make(map[int]string, len(duplicateIndexes))
+	for _, idx := range duplicateIndexes {
+		if idx < len(singularEvents) {
+			duplicateStrategies[idx] = dedup.KeyStrategyMessageID
+		}
+	}
+	for _, idx := range duplicateIndexes {
+		if hashIndex := idx - len(singularEvents); hashIndex >= 0 && hashIndex < len(hashEventIndexes) {
+			eventIndex := hashEventIndexes[hashIndex]
+			if _, ok := duplicateStrategies[eventIndex]; !ok {
+				duplicateStrategies[eventIndex] = dedup.KeyStrategyContentHash
+			}
+		}
+	}
+	return duplicateStrategies, contentHashes
+}
+
 func (proc *HandleT) isReportingEnabled() bool {
 	return proc.reporting != nil && proc.reportingEnabled
 }
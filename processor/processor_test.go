@@ -26,6 +26,7 @@ import (
 	mockDedup "github.com/rudderlabs/rudder-server/mocks/services/dedup"
 	mocksMultitenant "github.com/rudderlabs/rudder-server/mocks/services/multitenant"
 	mockReportingTypes "github.com/rudderlabs/rudder-server/mocks/utils/types"
+	"github.com/rudderlabs/rudder-server/processor/eventfilter"
 	"github.com/rudderlabs/rudder-server/processor/integrations"
 	"github.com/rudderlabs/rudder-server/processor/stash"
 	"github.com/rudderlabs/rudder-server/processor/transformer"
@@ -857,6 +858,62 @@ var _ = Describe("Processor", func() {
 			processor.multitenantI = c.MockMultitenantHandle
 			handlePendingGatewayJobs(processor)
 		})
+
+		It("should not mark messageIds as processed when storing the jobs fails, so that they are processed when the jobs are read again", func() {
+			unprocessedJobsList := []*jobsdb.JobT{
+				{
+					UUID:      uuid.New(),
+					JobID:     1010,
+					CreatedAt: time.Date(2020, 0o4, 28, 23, 26, 0o0, 0o0, time.UTC),
+					ExpireAt:  time.Date(2020, 0o4, 28, 23, 26, 0o0, 0o0, time.UTC),
+					CustomVal: gatewayCustomVal[0],
+					EventPayload: createBatchPayload(WriteKeyEnabled, "2001-01-02T02:23:45.000Z", []mockEventData{
+						{
+							id:                        "1",
+							jobid:                     1010,
+							originalTimestamp:         "2000-01-02T01:23:45",
+							expectedOriginalTimestamp: "2000-01-02T01:23:45.000Z",
+							sentAt:                    "2000-01-02 01:23",
+							expectedSentAt:            "2000-01-02T01:23:00.000Z",
+							expectedReceivedAt:        "2001-01-02T02:23:45.000Z",
+							integrations:              map[string]bool{"All": false, "enabled-destination-c-definition-display-name": true},
+						},
+					}),
+					EventCount: 1,
+					Parameters: createBatchParameters(SourceIDEnabled),
+				},
+			}
+
+			mockTransformer := mocksTransformer.NewMockTransformer(c.mockCtrl)
+			mockTransformer.EXPECT().Setup().Times(1)
+
+			c.mockGatewayJobsDB.EXPECT().GetUnprocessed(gomock.Any(), gomock.Any()).Return(jobsdb.JobsResult{Jobs: unprocessedJobsList}, nil).Times(2)
+			c.MockDedup.EXPECT().FindDuplicates(gomock.Any(), gomock.Any()).Return([]int{}).Times(2)
+			c.mockRouterJobsDB.EXPECT().WithStoreSafeTx(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, f func(tx jobsdb.StoreSafeTx) error) {
+				_ = f(jobsdb.EmptyStoreSafeTx())
+			}).Return(nil).Times(2)
+			c.mockRouterJobsDB.EXPECT().StoreInTx(gomock.Any(), gomock.Any(), gomock.Len(1)).Times(2)
+			c.MockRsourcesService.EXPECT().IncrementStats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+			c.MockMultitenantHandle.EXPECT().ReportProcLoopAddStats(gomock.Any(), gomock.Any()).Times(2)
+
+			// the first update of the gateway jobs statuses fails, the second one succeeds
+			failedUpdate := c.mockGatewayJobsDB.EXPECT().WithUpdateSafeTx(gomock.Any(), gomock.Any()).Return(fmt.Errorf("store failed")).Times(1)
+			succeededUpdate := c.mockGatewayJobsDB.EXPECT().WithUpdateSafeTx(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, f func(tx jobsdb.UpdateSafeTx) error) {
+				_ = f(jobsdb.EmptyUpdateSafeTx())
+			}).Return(nil).Times(1).After(failedUpdate)
+			c.mockGatewayJobsDB.EXPECT().UpdateJobStatusInTx(gomock.Any(), gomock.Any(), gomock.Len(len(unprocessedJobsList)), gatewayCustomVal, nil).Times(1)
+			c.MockDedup.EXPECT().MarkProcessed([]string{"message-1"}).Times(1).After(succeededUpdate)
+
+			processor := &HandleT{
+				transformer: mockTransformer,
+			}
+
+			Setup(processor, c, true, false)
+			processor.dedupHandler = c.MockDedup
+			processor.multitenantI = c.MockMultitenantHandle
+			Expect(func() { processor.handlePendingGatewayJobs() }).To(Panic())
+			handlePendingGatewayJobs(processor)
+		})
 	})
 
 	Context("transformations", func() {
@@ -1799,6 +1856,143 @@ var _ = Describe("Static Function Tests", func() {
 			response := ConvertToFilteredTransformerResponse(events, true)
 			Expect(response).To(Equal(expectedResponse))
 		})
+
+		It("Should filter out events not matching the event filter expression", func() {
+			destinationConfig := backendconfig.DestinationT{
+				ID: "some-destination-id",
+				Config: map[string]interface{}{
+					"eventFilterExpression": `properties.revenue > 0 AND context.app.name == "web"`,
+				},
+			}
+			invalidDestinationConfig := backendconfig.DestinationT{
+				ID: "some-other-destination-id",
+				Config: map[string]interface{}{
+					"eventFilterExpression": `properties.revenue >`,
+				},
+			}
+			// the expressions are parsed once when the backend config is loaded, invalid ones are skipped
+			predicate, ok, err := eventfilter.GetPredicate(&destinationConfig)
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeTrue())
+			_, _, err = eventfilter.GetPredicate(&invalidDestinationConfig)
+			Expect(err).NotTo(BeNil())
+			destinationPredicatesMap = map[string]*eventfilter.Predicate{destinationConfig.ID: predicate}
+			defer func() { destinationPredicatesMap = nil }()
+
+			events := []transformer.TransformerEventT{
+				{
+					Metadata: transformer.MetadataT{MessageID: "message-1"},
+					Message: map[string]interface{}{
+						"properties": map[string]interface{}{"revenue": 10.0},
+						"context":    map[string]interface{}{"app": map[string]interface{}{"name": "web"}},
+					},
+					Destination: destinationConfig,
+				},
+				{
+					Metadata: transformer.MetadataT{MessageID: "message-2"},
+					Message: map[string]interface{}{
+						"properties": map[string]interface{}{"revenue": 0.0},
+						"context":    map[string]interface{}{"app": map[string]interface{}{"name": "web"}},
+					},
+					Destination: destinationConfig,
+				},
+				{
+					Metadata: transformer.MetadataT{MessageID: "message-3"},
+					Message: map[string]interface{}{
+						"properties": map[string]interface{}{"revenue": 10.0},
+					},
+					Destination: invalidDestinationConfig,
+				},
+			}
+			response := ConvertToFilteredTransformerResponse(events, false)
+			Expect(response.Events).To(Equal([]transformer.TransformerResponseT{
+				{
+					Output:     events[0].Message,
+					StatusCode: 200,
+					Metadata:   events[0].Metadata,
+				},
+				{
+					Output:     events[2].Message,
+					StatusCode: 200,
+					Metadata:   events[2].Metadata,
+				},
+			}))
+			Expect(response.FilteredEvents).To(Equal([]transformer.TransformerResponseT{
+				{
+					Output:     events[1].Message,
+					StatusCode: types.FilterEventCode,
+					Metadata:   events[1].Metadata,
+					Error:      `Event not matching the event filter expression: properties.revenue > 0 AND context.app.name == "web"`,
+				},
+			}))
+			Expect(response.FailedEvents).To(BeEmpty(), "the events of destinations with an invalid expression are not failed")
+		})
+
+		It("Should sample out events by the sampling rates of the destination", func() {
+			destinationConfig := backendconfig.DestinationT{
+				ID: "some-destination-id",
+				Config: map[string]interface{}{
+					"samplingRate": 1.0,
+					"eventSamplingRates": []interface{}{
+						map[string]interface{}{"event": "Heartbeat", "rate": 0.0},
+					},
+				},
+			}
+			invalidDestinationConfig := backendconfig.DestinationT{
+				ID: "some-other-destination-id",
+				Config: map[string]interface{}{
+					"samplingRate": 2.0,
+				},
+			}
+			// the sampling rates are parsed once when the backend config is loaded, invalid ones are skipped
+			sampler, ok, err := eventfilter.GetSampler(&destinationConfig)
+			Expect(err).To(BeNil())
+			Expect(ok).To(BeTrue())
+			_, _, err = eventfilter.GetSampler(&invalidDestinationConfig)
+			Expect(err).NotTo(BeNil())
+			destinationSamplersMap = map[string]*eventfilter.Sampler{destinationConfig.ID: sampler}
+			defer func() { destinationSamplersMap = nil }()
+
+			events := []transformer.TransformerEventT{
+				{
+					Metadata:    transformer.MetadataT{MessageID: "message-1"},
+					Message:     map[string]interface{}{"userId": "user-1", "event": "Order Completed"},
+					Destination: destinationConfig,
+				},
+				{
+					Metadata:    transformer.MetadataT{MessageID: "message-2"},
+					Message:     map[string]interface{}{"userId": "user-1", "event": "Heartbeat"},
+					Destination: destinationConfig,
+				},
+				{
+					Metadata:    transformer.MetadataT{MessageID: "message-3"},
+					Message:     map[string]interface{}{"userId": "user-1", "event": "Heartbeat"},
+					Destination: invalidDestinationConfig,
+				},
+			}
+			response := ConvertToFilteredTransformerResponse(events, false)
+			Expect(response.Events).To(Equal([]transformer.TransformerResponseT{
+				{
+					Output:     events[0].Message,
+					StatusCode: 200,
+					Metadata:   events[0].Metadata,
+				},
+				{
+					Output:     events[2].Message,
+					StatusCode: 200,
+					Metadata:   events[2].Metadata,
+				},
+			}))
+			Expect(response.FilteredEvents).To(Equal([]transformer.TransformerResponseT{
+				{
+					Output:     events[1].Message,
+					StatusCode: types.FilterEventCode,
+					Metadata:   events[1].Metadata,
+					Error:      "Event sampled out at rate 0",
+				},
+			}))
+			Expect(response.FailedEvents).To(BeEmpty(), "the events of destinations with invalid sampling rates are not failed")
+		})
 	})
 })
 
@@ -0,0 +1,289 @@
+package redaction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ConfigKey is the key of the redaction rules in the config of a source or a destination
+const ConfigKey = "redactionRules"
+
+// Actions of the rules
+const (
+	// ActionDrop removes the field
+	ActionDrop = "drop"
+	// ActionMask replaces the characters of the field with *, except for the last Keep ones
+	ActionMask = "mask"
+	// ActionHash replaces the field with the hex encoded SHA-256 hash of the salt followed by the field
+	ActionHash = "hash"
+	// ActionTruncateIP zeroes the host part of an IP field, keeping its /24 network for IPv4 and /48 for IPv6
+	ActionTruncateIP = "truncateIP"
+)
+
+const (
+	pathWildcard = "#"
+	maskChar     = "*"
+)
+
+var (
+	ipv4Mask = net.CIDRMask(24, 8*net.IPv4len)
+	ipv6Mask = net.CIDRMask(48, 8*net.IPv6len)
+)
+
+// Rule redacts the field of the events at Path.
+// Paths are gjson-style, i.e. dot separated with \ escaping dots, numbers indexing arrays and # matching all their elements, e.g. context.traits.email or products.#.sku
+type Rule struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	// Salt is prepended to the field before hashing
+	Salt string `json:"salt"`
+	// Keep is the number of trailing characters kept by mask
+	Keep int `json:"keep"`
+
+	path []string
+}
+
+// Rules are applied in order
+type Rules []Rule
+
+// Parse returns the rules configured in the config of a source or a destination.
+// Invalid rules are skipped along with an error.
+func Parse(config map[string]interface{}) (Rules, error) {
+	raw, ok := config[ConfigKey]
+	if !ok {
+		return nil, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling redaction rules: %w", err)
+	}
+	var configured []Rule
+	if err := json.Unmarshal(data, &configured); err != nil {
+		return nil, fmt.Errorf("parsing redaction rules: %w", err)
+	}
+
+	var (
+		rules Rules
+		errs  []string
+	)
+	for _, rule := range configured {
+		if err := rule.validate(); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if len(errs) > 0 {
+		return rules, fmt.Errorf("invalid redaction rules: %s", strings.Join(errs, ", "))
+	}
+	return rules, nil
+}
+
+func (r *Rule) validate() error {
+	switch r.Action {
+	case ActionDrop, ActionMask, ActionHash, ActionTruncateIP:
+	default:
+		return fmt.Errorf("invalid action %q of rule for path %q", r.Action, r.Path)
+	}
+	if r.Keep < 0 {
+		return fmt.Errorf("keep of rule for path %q must not be negative", r.Path)
+	}
+	r.path = splitPath(r.Path)
+	for _, component := range r.path {
+		if component == "" {
+			return fmt.Errorf("invalid path %q", r.Path)
+		}
+	}
+	return nil
+}
+
+// splitPath splits the path on dots, except for the escaped ones
+func splitPath(path string) []string {
+	var (
+		components []string
+		component  strings.Builder
+		escaped    bool
+	)
+	for _, c := range path {
+		switch {
+		case escaped:
+			component.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '.':
+			components = append(components, component.String())
+			component.Reset()
+		default:
+			component.WriteRune(c)
+		}
+	}
+	return append(components, component.String())
+}
+
+// Apply returns the event with the rules applied, along with the number of fields redacted by action.
+// The event is never modified, the maps and arrays holding redacted fields are copied instead, since events are shared between destinations.
+func (rules Rules) Apply(event map[string]interface{}) (map[string]interface{}, map[string]int) {
+	var applied map[string]int
+	for i := range rules {
+		redacted, n := rules[i].redact(event, rules[i].path)
+		if n == 0 {
+			continue
+		}
+		if applied == nil {
+			applied = make(map[string]int)
+		}
+		applied[rules[i].Action] += n
+		event = redacted.(map[string]interface{})
+	}
+	return event, applied
+}
+
+// redact returns a copy of the container with the fields at path redacted, along with the number of fields redacted.
+// The container is returned as is if nothing is redacted.
+func (r *Rule) redact(container interface{}, path []string) (interface{}, int) {
+	switch container := container.(type) {
+	case map[string]interface{}:
+		value, ok := container[path[0]]
+		if !ok {
+			return container, 0
+		}
+		if len(path) == 1 && r.Action == ActionDrop {
+			redacted := make(map[string]interface{}, len(container))
+			for k, v := range container {
+				if k != path[0] {
+					redacted[k] = v
+				}
+			}
+			return redacted, 1
+		}
+		value, n := r.redactValue(value, path[1:])
+		if n == 0 {
+			return container, 0
+		}
+		redacted := make(map[string]interface{}, len(container))
+		for k, v := range container {
+			redacted[k] = v
+		}
+		redacted[path[0]] = value
+		return redacted, n
+
+	case []interface{}:
+		indexes, ok := arrayIndexes(container, path[0])
+		if !ok {
+			return container, 0
+		}
+		if len(path) == 1 && r.Action == ActionDrop {
+			drop := make(map[int]struct{}, len(indexes))
+			for _, i := range indexes {
+				drop[i] = struct{}{}
+			}
+			redacted := make([]interface{}, 0, len(container)-len(drop))
+			for i, v := range container {
+				if _, ok := drop[i]; !ok {
+					redacted = append(redacted, v)
+				}
+			}
+			return redacted, len(drop)
+		}
+		var (
+			redacted []interface{}
+			total    int
+		)
+		for _, i := range indexes {
+			value, n := r.redactValue(container[i], path[1:])
+			if n == 0 {
+				continue
+			}
+			if redacted == nil {
+				redacted = append([]interface{}{}, container...)
+			}
+			redacted[i] = value
+			total += n
+		}
+		if total == 0 {
+			return container, 0
+		}
+		return redacted, total
+	}
+	return container, 0
+}
+
+// redactValue redacts the value if the path is fully resolved, or the fields of the value at the rest of the path otherwise
+func (r *Rule) redactValue(value interface{}, path []string) (interface{}, int) {
+	if len(path) > 0 {
+		return r.redact(value, path)
+	}
+	redacted, ok := r.transform(value)
+	if !ok {
+		return value, 0
+	}
+	return redacted, 1
+}
+
+func arrayIndexes(array []interface{}, component string) ([]int, bool) {
+	if component == pathWildcard {
+		indexes := make([]int, len(array))
+		for i := range array {
+			indexes[i] = i
+		}
+		return indexes, len(indexes) > 0
+	}
+	i, err := strconv.Atoi(component)
+	if err != nil || i < 0 || i >= len(array) {
+		return nil, false
+	}
+	return []int{i}, true
+}
+
+// transform returns the redacted value, or false if the value can't be redacted by the rule
+func (r *Rule) transform(value interface{}) (interface{}, bool) {
+	s, ok := stringValue(value)
+	if !ok {
+		return nil, false
+	}
+	switch r.Action {
+	case ActionMask:
+		runes := []rune(s)
+		keep := r.Keep
+		if keep > len(runes) {
+			keep = len(runes)
+		}
+		return strings.Repeat(maskChar, len(runes)-keep) + string(runes[len(runes)-keep:]), true
+	case ActionHash:
+		sum := sha256.Sum256([]byte(r.Salt + s))
+		return hex.EncodeToString(sum[:]), true
+	case ActionTruncateIP:
+		ip := net.ParseIP(strings.TrimSpace(s))
+		if ip == nil {
+			return nil, false
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.Mask(ipv4Mask).String(), true
+		}
+		return ip.Mask(ipv6Mask).String(), true
+	}
+	return nil, false
+}
+
+// stringValue returns the value as a string, for strings, numbers and booleans
+func stringValue(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, value != ""
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case json.Number:
+		return value.String(), true
+	case int:
+		return strconv.Itoa(value), true
+	case bool:
+		return strconv.FormatBool(value), true
+	}
+	return "", false
+}
@@ -0,0 +1,112 @@
+package redaction_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/processor/redaction"
+)
+
+func parse(t *testing.T, rules ...map[string]interface{}) redaction.Rules {
+	t.Helper()
+	raw := make([]interface{}, len(rules))
+	for i := range rules {
+		raw[i] = rules[i]
+	}
+	parsed, err := redaction.Parse(map[string]interface{}{redaction.ConfigKey: raw})
+	require.NoError(t, err)
+	return parsed
+}
+
+func TestParse(t *testing.T) {
+	t.Run("no rules", func(t *testing.T) {
+		rules, err := redaction.Parse(map[string]interface{}{})
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+
+	t.Run("invalid rules are skipped", func(t *testing.T) {
+		rules, err := redaction.Parse(map[string]interface{}{redaction.ConfigKey: []interface{}{
+			map[string]interface{}{"path": "context.ip", "action": redaction.ActionTruncateIP},
+			map[string]interface{}{"path": "context.ip", "action": "encrypt"},
+			map[string]interface{}{"path": "context..ip", "action": redaction.ActionDrop},
+			map[string]interface{}{"path": "userId", "action": redaction.ActionMask, "keep": -1},
+		}})
+		require.Error(t, err)
+		require.Len(t, rules, 1)
+	})
+}
+
+func TestApply(t *testing.T) {
+	newEvent := func() map[string]interface{} {
+		return map[string]interface{}{
+			"userId": "user-1",
+			"context": map[string]interface{}{
+				"ip":     "192.168.10.23",
+				"traits": map[string]interface{}{"email": "jane@example.com", "phone": "+15551234567"},
+			},
+			"properties": map[string]interface{}{
+				"products": []interface{}{
+					map[string]interface{}{"sku": "sku-1", "coupon": "c-1"},
+					map[string]interface{}{"sku": "sku-2"},
+				},
+				"a.b": "dotted",
+			},
+		}
+	}
+
+	t.Run("all actions", func(t *testing.T) {
+		rules := parse(t,
+			map[string]interface{}{"path": "context.traits.email", "action": redaction.ActionHash, "salt": "pepper"},
+			map[string]interface{}{"path": "context.traits.phone", "action": redaction.ActionMask, "keep": 4},
+			map[string]interface{}{"path": "context.ip", "action": redaction.ActionTruncateIP},
+			map[string]interface{}{"path": "properties.products.#.coupon", "action": redaction.ActionDrop},
+			map[string]interface{}{"path": `properties.a\.b`, "action": redaction.ActionDrop},
+			map[string]interface{}{"path": "properties.products.1.sku", "action": redaction.ActionMask},
+			map[string]interface{}{"path": "context.traits.missing", "action": redaction.ActionDrop},
+		)
+
+		event := newEvent()
+		redacted, applied := rules.Apply(event)
+
+		sum := sha256.Sum256([]byte("pepperjane@example.com"))
+		require.Equal(t, map[string]interface{}{
+			"userId": "user-1",
+			"context": map[string]interface{}{
+				"ip":     "192.168.10.0",
+				"traits": map[string]interface{}{"email": hex.EncodeToString(sum[:]), "phone": "********4567"},
+			},
+			"properties": map[string]interface{}{
+				"products": []interface{}{
+					map[string]interface{}{"sku": "sku-1"},
+					map[string]interface{}{"sku": "*****"},
+				},
+			},
+		}, redacted)
+		require.Equal(t, map[string]int{
+			redaction.ActionHash:       1,
+			redaction.ActionMask:       2,
+			redaction.ActionTruncateIP: 1,
+			redaction.ActionDrop:       2,
+		}, applied)
+		require.Equal(t, newEvent(), event, "the event is not modified")
+	})
+
+	t.Run("ipv6 and invalid ips", func(t *testing.T) {
+		rules := parse(t, map[string]interface{}{"path": "ips.#", "action": redaction.ActionTruncateIP})
+		redacted, applied := rules.Apply(map[string]interface{}{"ips": []interface{}{"2001:db8:1234:5678::1", "not an ip"}})
+		require.Equal(t, map[string]interface{}{"ips": []interface{}{"2001:db8:1234::", "not an ip"}}, redacted)
+		require.Equal(t, map[string]int{redaction.ActionTruncateIP: 1}, applied)
+	})
+
+	t.Run("nothing to redact", func(t *testing.T) {
+		rules := parse(t, map[string]interface{}{"path": "context.traits.email", "action": redaction.ActionHash})
+		event := map[string]interface{}{"context": "not an object"}
+		redacted, applied := rules.Apply(event)
+		require.Equal(t, event, redacted)
+		require.Empty(t, applied)
+	})
+}
@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	mocksTypes "github.com/rudderlabs/rudder-server/mocks/utils/types"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+func TestIsSuppressedUserEvent(t *testing.T) {
+	initProcessor()
+	ctrl := gomock.NewController(t)
+	suppressUser := mocksTypes.NewMockUserSuppression(ctrl)
+	suppressUser.EXPECT().IsSuppressedUserEvent("workspace-1", "source-1", "user-1", "", "").Return(true).AnyTimes()
+	suppressUser.EXPECT().IsSuppressedUserEvent("workspace-1", "source-1", "", "anonymous-1", "").Return(true).AnyTimes()
+	suppressUser.EXPECT().IsSuppressedUserEvent("workspace-1", "source-1", "user-2", "", "jane@example.com").Return(true).AnyTimes()
+	suppressUser.EXPECT().IsSuppressedUserEvent(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false).AnyTimes()
+
+	proc := &HandleT{suppressUserHandler: suppressUser}
+	job := &jobsdb.JobT{WorkspaceId: "workspace-1", Parameters: []byte(`{"source_id":"source-1"}`)}
+
+	require.True(t, proc.isSuppressedUserEvent(job, types.SingularEventT{"userId": "user-1"}))
+	require.True(t, proc.isSuppressedUserEvent(job, types.SingularEventT{"anonymousId": "anonymous-1"}))
+	require.False(t, proc.isSuppressedUserEvent(job, types.SingularEventT{"userId": "user-3"}))
+	require.True(t, proc.isSuppressedUserEvent(job, types.SingularEventT{"userId": "user-2", "context": map[string]interface{}{"traits": map[string]interface{}{"email": "jane@example.com"}}}))
+	require.True(t, proc.isSuppressedUserEvent(job, types.SingularEventT{"userId": "user-2", "traits": map[string]interface{}{"email": "jane@example.com"}}), "the email of identify events is in their traits")
+	require.False(t, proc.isSuppressedUserEvent(&jobsdb.JobT{WorkspaceId: "workspace-1", Parameters: []byte(`{"source_id":"source-2"}`)}, types.SingularEventT{"userId": "user-1"}))
+
+	t.Run("feature disabled", func(t *testing.T) {
+		enableSuppressUserFeature = false
+		defer func() { enableSuppressUserFeature = true }()
+		require.False(t, proc.isSuppressedUserEvent(job, types.SingularEventT{"userId": "user-1"}))
+	})
+
+	t.Run("no suppress user handler", func(t *testing.T) {
+		require.False(t, (&HandleT{}).isSuppressedUserEvent(job, types.SingularEventT{"userId": "user-1"}))
+	})
+}
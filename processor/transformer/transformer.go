@@ -166,6 +166,8 @@ func (trans *HandleT) Setup() {
 type ResponseT struct {
 	Events       []TransformerResponseT
 	FailedEvents []TransformerResponseT
+	// FilteredEvents are the events filtered out by the processor, along with the reason in their Error
+	FilteredEvents []TransformerResponseT
 }
 
 // GetVersion gets the transformer version by asking it on /transfomerBuildVersion. if there is any error it returns empty string
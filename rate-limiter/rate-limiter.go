@@ -55,3 +55,8 @@ func (rateLimiter *HandleT) SetUp() {
 func (rateLimiter *HandleT) LimitReached(key string) bool {
 	return rateLimiter.restrictor.LimitReached(key)
 }
+
+// RetryAfter returns the time to wait before retrying once the limit is reached, i.e. until the oldest bucket leaves the rolling window
+func RetryAfter() time.Duration {
+	return rateLimitWindowInMins / time.Duration(noOfBucketsInWindow)
+}
@@ -59,6 +59,7 @@ var json = jsoniter.ConfigCompatibleWithStandardLibrary
 var (
 	mainLoopSleep, diagnosisTickerTime time.Duration
 	uploadFreqInS                      int64
+	streamingUploadFreqInS             int64
 	objectStorageDestinations          []string
 	warehouseServiceFailedTime         time.Time
 	warehouseServiceFailedTimeLock     sync.RWMutex
@@ -1145,6 +1146,58 @@ func (brt *HandleT) postToWarehouse(batchJobs *BatchJobsT, output StorageUploadO
 	return
 }
 
+// errInvalidStreamingEvent is the error of jobs whose payload isn't an event the warehouse can stream, which are aborted.
+var errInvalidStreamingEvent = errors.New("invalid event payload for streaming")
+
+// splitStreamingJobs returns the events of the jobs to stream along with their jobs, and the jobs with invalid payloads.
+func (brt *HandleT) splitStreamingJobs(batchJobs *BatchJobsT) (events []warehouseutils.StreamingEventT, streamJobs, invalidJobs *BatchJobsT) {
+	streamJobs = &BatchJobsT{BatchDestination: batchJobs.BatchDestination, TimeWindow: batchJobs.TimeWindow}
+	invalidJobs = &BatchJobsT{BatchDestination: batchJobs.BatchDestination, TimeWindow: batchJobs.TimeWindow}
+	for _, job := range batchJobs.Jobs {
+		var event warehouseutils.StreamingEventT
+		if err := json.Unmarshal(job.EventPayload, &event); err != nil {
+			brt.logger.Errorf("BRT: Failed to unmarshal event payload of job %d for streaming: %v", job.JobID, err)
+			invalidJobs.Jobs = append(invalidJobs.Jobs, job)
+			continue
+		}
+		events = append(events, event)
+		streamJobs.Jobs = append(streamJobs.Jobs, job)
+	}
+	return events, streamJobs, invalidJobs
+}
+
+// postToWarehouseStream sends the events of the batch to the warehouse service, which loads them straight into the warehouse
+// for destinations with streaming enabled, instead of routing them through staging files.
+func (brt *HandleT) postToWarehouseStream(batchJobs *BatchJobsT, events []warehouseutils.StreamingEventT) (err error) {
+	payload := warehouseutils.StreamingBatch{
+		WorkspaceID: batchJobs.Jobs[0].WorkspaceId,
+		BatchDestination: warehouseutils.DestinationT{
+			Source:      batchJobs.BatchDestination.Source,
+			Destination: batchJobs.BatchDestination.Destination,
+		},
+		Events: events,
+	}
+
+	jsonPayload, err := json.Marshal(&payload)
+	if err != nil {
+		return fmt.Errorf("BRT: Failed to marshal WH streaming batch payload: %w", err)
+	}
+	uri := fmt.Sprintf(`%s/v1/warehouse/stream`, brt.warehouseURL)
+	resp, err := brt.netHandle.Post(uri, "application/json; charset=utf-8", bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		brt.logger.Errorf("BRT: Failed to stream events to warehouse service@%v, error:%v", uri, err)
+		return
+	}
+	defer func() { httputil.CloseResponse(resp) }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		err = fmt.Errorf("BRT: Failed to stream events to warehouse service@%v, status: %v, body: %v", uri, resp.Status, string(body))
+		brt.logger.Error(err)
+	}
+	return
+}
+
 func (brt *HandleT) setJobStatus(batchJobs *BatchJobsT, isWarehouse bool, errOccurred error, postToWarehouseErr bool) {
 	var (
 		batchJobState string
@@ -1161,6 +1214,9 @@ func (brt *HandleT) setJobStatus(batchJobs *BatchJobsT, isWarehouse bool, errOcc
 				time.Now().Format("01-02-2006"))
 			batchJobState = jobsdb.Succeeded.State
 			errorResp = []byte(fmt.Sprintf(`{"success":"%s"}`, errOccurred.Error()))
+		case errors.Is(errOccurred, errInvalidStreamingEvent):
+			batchJobState = jobsdb.Aborted.State
+			errorResp, _ = json.Marshal(ErrorResponseT{Error: errOccurred.Error()})
 		case errors.Is(errOccurred, rterror.InvalidServiceProvider):
 			brt.logger.Warnf("BRT: Destination %s : %s for destination ID : %v at %v",
 				batchJobs.BatchDestination.Destination.DestinationDefinition.DisplayName, errOccurred.Error(),
@@ -1824,6 +1880,26 @@ func (worker *workerT) workerProcess() {
 						brt.recordUploadStats(*batchJobs.BatchDestination, output)
 					}
 
+					destUploadStat.End()
+				case warehouseutils.IsStreamingEnabled(brt.destType, batchJobs.BatchDestination.Destination.Config):
+					destUploadStat := stats.Default.NewStat(fmt.Sprintf(`batch_router.%s_stream_upload_time`, brt.destType), stats.TimerType)
+					destUploadStat.Start()
+					events, streamJobs, invalidJobs := brt.splitStreamingJobs(&batchJobs)
+					if len(invalidJobs.Jobs) > 0 {
+						brt.setJobStatus(invalidJobs, false, errInvalidStreamingEvent, false)
+					}
+					if len(streamJobs.Jobs) > 0 {
+						output := StorageUploadOutput{TotalEvents: len(streamJobs.Jobs)}
+						output.Error = brt.postToWarehouseStream(streamJobs, events)
+						// no staging files are generated for streamed events, which are loaded by now. Failures are retried
+						// like the ones of object storage destinations, as they are load failures rather than the warehouse
+						// service being unavailable.
+						brt.recordDeliveryStatus(*streamJobs.BatchDestination, output, false)
+						brt.setJobStatus(streamJobs, false, output.Error, false)
+						if output.Error == nil {
+							warehouseutils.DestStat(stats.CountType, "streaming_batch_size", streamJobs.BatchDestination.Destination.ID).Count(len(streamJobs.Jobs))
+						}
+					}
 					destUploadStat.End()
 				case misc.Contains(warehouseutils.WarehouseDestinations, brt.destType):
 					useRudderStorage := misc.IsConfiguredToUseRudderObjectStorage(batchJobs.BatchDestination.Destination.Config)
@@ -1943,7 +2019,7 @@ func (brt *HandleT) setDestInProgress(destID string, starting bool) {
 	brt.inProgressMapLock.Unlock()
 }
 
-func (brt *HandleT) uploadFrequencyExceeded(destID string) bool {
+func (brt *HandleT) uploadFrequencyExceeded(destID string, uploadFreqInS int64) bool {
 	brt.lastExecMapLock.Lock()
 	defer brt.lastExecMapLock.Unlock()
 	if lastExecTime, ok := brt.lastExecMap[destID]; ok && time.Now().Unix()-lastExecTime < uploadFreqInS {
@@ -1965,7 +2041,12 @@ func (brt *HandleT) readAndProcess() {
 				brt.logger.Debugf("BRT: Skipping batch router upload loop since destination %s:%s is in progress", batchDest.Destination.DestinationDefinition.Name, destID)
 				continue
 			}
-			if brt.uploadFrequencyExceeded(destID) {
+			destUploadFreqInS := uploadFreqInS
+			// streaming destinations are read more often to load events within seconds
+			if warehouseutils.IsStreamingEnabled(brt.destType, batchDest.Destination.Config) {
+				destUploadFreqInS = streamingUploadFreqInS
+			}
+			if brt.uploadFrequencyExceeded(destID, destUploadFreqInS) {
 				brt.logger.Debugf("BRT: Skipping batch router upload loop since %s:%s upload freq not exceeded", batchDest.Destination.DestinationDefinition.Name, destID)
 				continue
 			}
@@ -1974,7 +2055,7 @@ func (brt *HandleT) readAndProcess() {
 			brt.processQ <- &BatchDestinationDataT{batchDestination: *batchDest, jobs: jobs, parentWG: nil}
 		}
 	} else {
-		if brt.uploadFrequencyExceeded(brt.destType) {
+		if brt.uploadFrequencyExceeded(brt.destType, uploadFreqInS) {
 			brt.logger.Debugf("BRT: %s: Skipping batch router read since upload freq not exceeded", brt.destType)
 			return
 		}
@@ -2259,6 +2340,7 @@ func (brt *HandleT) collectMetrics(ctx context.Context) {
 func loadConfig() {
 	config.RegisterDurationConfigVariable(2, &mainLoopSleep, true, time.Second, []string{"BatchRouter.mainLoopSleep", "BatchRouter.mainLoopSleepInS"}...)
 	config.RegisterInt64ConfigVariable(30, &uploadFreqInS, true, 1, "BatchRouter.uploadFreqInS")
+	config.RegisterInt64ConfigVariable(1, &streamingUploadFreqInS, true, 1, "BatchRouter.streamingUploadFreqInS")
 	objectStorageDestinations = []string{"S3", "GCS", "AZURE_BLOB", "MINIO", "DIGITAL_OCEAN_SPACES"}
 	asyncDestinations = []string{"MARKETO_BULK_UPLOAD"}
 	// Time period for diagnosis ticker
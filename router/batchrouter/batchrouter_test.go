@@ -370,3 +370,23 @@ func TestPostToWarehouse(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitStreamingJobs(t *testing.T) {
+	brt := HandleT{logger: logger.NOP}
+	batchJobs := BatchJobsT{
+		Jobs: []*jobsdb.JobT{
+			{JobID: 1, EventPayload: jsonb.RawMessage(`{"metadata": {"table": "tracks", "columns": {"id": "string"}}, "data": {"id": "1"}}`)},
+			{JobID: 2, EventPayload: jsonb.RawMessage(`{"metadata": "tracks"}`)},
+			{JobID: 3, EventPayload: jsonb.RawMessage(`{"metadata": {"table": "pages", "columns": {"id": "string"}}, "data": {"id": "3"}}`)},
+		},
+		BatchDestination: &DestinationT{},
+	}
+
+	events, streamJobs, invalidJobs := brt.splitStreamingJobs(&batchJobs)
+	require.Len(t, events, 2)
+	require.Equal(t, "tracks", events[0].Metadata.Table)
+	require.Equal(t, "pages", events[1].Metadata.Table)
+	require.Equal(t, []*jobsdb.JobT{batchJobs.Jobs[0], batchJobs.Jobs[2]}, streamJobs.Jobs)
+	require.Equal(t, []*jobsdb.JobT{batchJobs.Jobs[1]}, invalidJobs.Jobs, "jobs with invalid payloads are left out of the batch")
+	require.Same(t, batchJobs.BatchDestination, invalidJobs.BatchDestination)
+}
@@ -28,6 +28,12 @@ var (
 	dedupWindow  time.Duration
 	memOptimized bool
 	pkgLogger    logger.Logger
+
+	store           string
+	redisKeyPrefix  string
+	redisBatchSize  int
+	redisTimeout    time.Duration
+	redisRetryAfter time.Duration
 )
 
 func Init() {
@@ -39,6 +45,12 @@ func loadConfig() {
 	// Dedup time window in hours
 	config.RegisterDurationConfigVariable(3600, &dedupWindow, true, time.Second, []string{"Dedup.dedupWindow", "Dedup.dedupWindowInS"}...)
 	config.RegisterBoolConfigVariable(true, &memOptimized, false, "Dedup.memOptimized")
+	// Store shared by all the replicas, either badger for a local store only or redis
+	config.RegisterStringConfigVariable(storeBadger, &store, false, "Dedup.store")
+	config.RegisterStringConfigVariable("dedup:", &redisKeyPrefix, false, "Dedup.redis.keyPrefix")
+	config.RegisterIntConfigVariable(1000, &redisBatchSize, true, 1, "Dedup.redis.batchSize")
+	config.RegisterDurationConfigVariable(1, &redisTimeout, true, time.Second, "Dedup.redis.timeout")
+	config.RegisterDurationConfigVariable(30, &redisRetryAfter, true, time.Second, "Dedup.redis.retryAfter")
 }
 
 type loggerForBadger struct {
@@ -197,6 +209,27 @@ func (d *DedupHandleT) MarkProcessed(messageIDs []string) error {
 }
 
 func (d *DedupHandleT) FindDuplicates(messageIDs []string, allMessageIDsSet map[string]struct{}) (duplicateIndexes []int) {
+	toRemoveMessageIndexesSet := findBatchDuplicates(messageIDs, allMessageIDsSet)
+
+	// Dedup with badgerDB
+	err := d.badgerDB.View(func(txn *badger.Txn) error {
+		for idx, messageID := range messageIDs {
+			_, err := txn.Get([]byte(messageID))
+			if err != badger.ErrKeyNotFound {
+				toRemoveMessageIndexesSet[idx] = struct{}{}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return sortedIndexes(toRemoveMessageIndexesSet)
+}
+
+// findBatchDuplicates returns the indexes of the messageIDs duplicated within the batch of a web request,
+// or within the batch of batch jobs
+func findBatchDuplicates(messageIDs []string, allMessageIDsSet map[string]struct{}) map[int]struct{} {
 	toRemoveMessageIndexesSet := make(map[int]struct{})
 	// Dedup within events batch in a web request
 	messageIDSet := make(map[string]struct{})
@@ -221,27 +254,16 @@ func (d *DedupHandleT) FindDuplicates(messageIDs []string, allMessageIDsSet map[
 			toRemoveMessageIndexesSet[idx] = struct{}{}
 		}
 	}
+	return toRemoveMessageIndexesSet
+}
 
-	// Dedup with badgerDB
-	err := d.badgerDB.View(func(txn *badger.Txn) error {
-		for idx, messageID := range messageIDs {
-			_, err := txn.Get([]byte(messageID))
-			if err != badger.ErrKeyNotFound {
-				toRemoveMessageIndexesSet[idx] = struct{}{}
-			}
-		}
-		return nil
-	})
-	if err != nil {
-		panic(err)
+func sortedIndexes(indexesSet map[int]struct{}) []int {
+	indexes := make([]int, 0, len(indexesSet))
+	for k := range indexesSet {
+		indexes = append(indexes, k)
 	}
-	toRemoveMessageIndexes := make([]int, 0, len(toRemoveMessageIndexesSet))
-	for k := range toRemoveMessageIndexesSet {
-		toRemoveMessageIndexes = append(toRemoveMessageIndexes, k)
-	}
-
-	sort.Ints(toRemoveMessageIndexes)
-	return toRemoveMessageIndexes
+	sort.Ints(indexes)
+	return indexes
 }
 
 func (d *DedupHandleT) Close() {
@@ -0,0 +1,107 @@
+package dedup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// Source config keys of the dedup key strategy
+const (
+	KeyStrategyConfig = "dedupKeyStrategy"
+	KeyFieldsConfig   = "dedupKeyFields"
+)
+
+const (
+	// KeyStrategyMessageID dedups events by their messageId only
+	KeyStrategyMessageID = "messageId"
+	// KeyStrategyContentHash dedups events by a hash over the configured fields, in addition to their messageId.
+	// It catches the retries of sources regenerating messageIds.
+	KeyStrategyContentHash = "contentHash"
+)
+
+const contentHashKeyPrefix = "hash:"
+
+// defaultKeyFields are the fields hashed if the source doesn't configure any
+var defaultKeyFields = []string{"type", "event", "userId", "anonymousId", "timestamp", "originalTimestamp"}
+
+// KeyConfig is how the dedup keys of the events of a source are computed
+type KeyConfig struct {
+	Strategy string
+	// Fields are the paths of the hashed fields, e.g. properties.order_id for nested ones
+	Fields []string
+}
+
+// GetKeyConfig returns the dedup key strategy configured for the source, defaulting to messageId.
+// Fields are configured either as a list or as a comma separated string.
+func GetKeyConfig(sourceConfig map[string]interface{}) KeyConfig {
+	strategy, _ := sourceConfig[KeyStrategyConfig].(string)
+	if strategy != KeyStrategyContentHash {
+		return KeyConfig{Strategy: KeyStrategyMessageID}
+	}
+
+	var fields []string
+	switch value := sourceConfig[KeyFieldsConfig].(type) {
+	case string:
+		fields = strings.Split(value, ",")
+	case []interface{}:
+		for _, field := range value {
+			if field, ok := field.(string); ok {
+				fields = append(fields, field)
+			}
+		}
+	case []string:
+		fields = value
+	}
+
+	keyConfig := KeyConfig{Strategy: KeyStrategyContentHash}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			keyConfig.Fields = append(keyConfig.Fields, field)
+		}
+	}
+	if len(keyConfig.Fields) == 0 {
+		keyConfig.Fields = defaultKeyFields
+	}
+	return keyConfig
+}
+
+// ContentHash returns the dedup key of the event hashed over the configured fields of the source.
+// The source is part of the hash, so that identical events of different sources are not duplicates.
+// It returns an empty key if none of the fields is set, since all such events of the source would be duplicates otherwise.
+func (kc KeyConfig) ContentHash(sourceID string, event map[string]interface{}) string {
+	values := make([]interface{}, len(kc.Fields))
+	var hasValue bool
+	for i, field := range kc.Fields {
+		values[i] = fieldValue(event, field)
+		hasValue = hasValue || values[i] != nil
+	}
+	if !hasValue {
+		return ""
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(sourceID))
+	for i, field := range kc.Fields {
+		value, _ := json.Marshal(values[i])
+		hash.Write([]byte{0})
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+		hash.Write(value)
+	}
+	return contentHashKeyPrefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+// fieldValue returns the value at the dot separated path of the event, or nil if it is missing
+func fieldValue(event map[string]interface{}, path string) interface{} {
+	var value interface{} = event
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
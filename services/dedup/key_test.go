@@ -0,0 +1,92 @@
+package dedup_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/services/dedup"
+)
+
+func Test_GetKeyConfig(t *testing.T) {
+	t.Run("messageId by default", func(t *testing.T) {
+		require.Equal(t, dedup.KeyConfig{Strategy: dedup.KeyStrategyMessageID}, dedup.GetKeyConfig(nil))
+		require.Equal(t, dedup.KeyConfig{Strategy: dedup.KeyStrategyMessageID}, dedup.GetKeyConfig(map[string]interface{}{
+			dedup.KeyStrategyConfig: "unknown",
+			dedup.KeyFieldsConfig:   "event",
+		}))
+	})
+
+	t.Run("content hash with list of fields", func(t *testing.T) {
+		keyConfig := dedup.GetKeyConfig(map[string]interface{}{
+			dedup.KeyStrategyConfig: dedup.KeyStrategyContentHash,
+			dedup.KeyFieldsConfig:   []interface{}{"event", "properties.order_id", ""},
+		})
+		require.Equal(t, dedup.KeyStrategyContentHash, keyConfig.Strategy)
+		require.Equal(t, []string{"event", "properties.order_id"}, keyConfig.Fields)
+	})
+
+	t.Run("content hash with comma separated fields", func(t *testing.T) {
+		keyConfig := dedup.GetKeyConfig(map[string]interface{}{
+			dedup.KeyStrategyConfig: dedup.KeyStrategyContentHash,
+			dedup.KeyFieldsConfig:   "event, userId",
+		})
+		require.Equal(t, []string{"event", "userId"}, keyConfig.Fields)
+	})
+
+	t.Run("content hash with default fields", func(t *testing.T) {
+		keyConfig := dedup.GetKeyConfig(map[string]interface{}{
+			dedup.KeyStrategyConfig: dedup.KeyStrategyContentHash,
+		})
+		require.NotEmpty(t, keyConfig.Fields)
+		require.Contains(t, keyConfig.Fields, "event")
+	})
+}
+
+func Test_ContentHash(t *testing.T) {
+	keyConfig := dedup.KeyConfig{
+		Strategy: dedup.KeyStrategyContentHash,
+		Fields:   []string{"event", "userId", "properties.order_id"},
+	}
+	event := func(messageID, orderID string) map[string]interface{} {
+		return map[string]interface{}{
+			"messageId": messageID,
+			"event":     "Order Completed",
+			"userId":    "user-1",
+			"properties": map[string]interface{}{
+				"order_id": orderID,
+				"revenue":  10,
+			},
+		}
+	}
+
+	hash := keyConfig.ContentHash("source-1", event("message-1", "order-1"))
+	require.True(t, strings.HasPrefix(hash, "hash:"))
+
+	t.Run("same hash for events differing by other fields", func(t *testing.T) {
+		other := event("message-2", "order-1")
+		other["properties"].(map[string]interface{})["revenue"] = 20
+		require.Equal(t, hash, keyConfig.ContentHash("source-1", other))
+	})
+
+	t.Run("different hash for events differing by hashed fields", func(t *testing.T) {
+		require.NotEqual(t, hash, keyConfig.ContentHash("source-1", event("message-1", "order-2")))
+	})
+
+	t.Run("different hash for events of different sources", func(t *testing.T) {
+		require.NotEqual(t, hash, keyConfig.ContentHash("source-2", event("message-1", "order-1")))
+	})
+
+	t.Run("missing fields are hashed as null", func(t *testing.T) {
+		missing := event("message-1", "order-1")
+		delete(missing, "properties")
+		require.NotEqual(t, hash, keyConfig.ContentHash("source-1", missing))
+		require.Equal(t, keyConfig.ContentHash("source-1", missing), keyConfig.ContentHash("source-1", missing))
+	})
+
+	t.Run("no hash for events missing all fields", func(t *testing.T) {
+		require.Empty(t, keyConfig.ContentHash("source-1", map[string]interface{}{"messageId": "message-1", "properties": map[string]interface{}{"revenue": 10}}))
+		require.Empty(t, keyConfig.ContentHash("source-1", map[string]interface{}{"event": nil}))
+	})
+}
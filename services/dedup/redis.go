@@ -0,0 +1,168 @@
+package dedup
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+)
+
+const (
+	storeBadger = "badger"
+	storeRedis  = "redis"
+)
+
+// RedisDedupHandleT dedups messageIDs against a redis store shared by all the replicas, so that retried requests
+// are deduplicated regardless of the replica they hit.
+// MessageIDs are only looked up in redis while finding the duplicates, and set once they are marked as processed,
+// i.e. after the jobs are stored, so that the events of a failed store aren't dropped as duplicates when they are read again.
+// They are marked in the local badger store too, which is used whenever redis is unavailable.
+type RedisDedupHandleT struct {
+	client *redis.Client
+	local  *DedupHandleT
+	stats  stats.Stats
+	// unavailableUntil is the unix nano time until which redis is skipped after a failure
+	unavailableUntil atomic.Int64
+}
+
+// NewRedis returns a dedup handle backed by redis, falling back to the local badger store.
+// The window of the local store is used as the TTL of the messageIDs in redis.
+func NewRedis(client *redis.Client, local *DedupHandleT) *RedisDedupHandleT {
+	return &RedisDedupHandleT{
+		client: client,
+		local:  local,
+		stats:  stats.Default,
+	}
+}
+
+func newRedisClient() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     config.GetString("Dedup.redis.addr", "localhost:6379"),
+		Username: config.GetString("Dedup.redis.username", ""),
+		Password: config.GetString("Dedup.redis.password", ""),
+		DB:       config.GetInt("Dedup.redis.db", 0),
+	})
+}
+
+func (d *RedisDedupHandleT) available() bool {
+	return timeutil.Now().UnixNano() >= d.unavailableUntil.Load()
+}
+
+// markUnavailable skips redis for a while, so that requests aren't delayed by the timeouts of an unavailable store
+func (d *RedisDedupHandleT) markUnavailable(op string, err error) {
+	pkgLogger.Warnf("Dedup: redis unavailable on %s, falling back to local store for %s: %v", op, redisRetryAfter, err)
+	d.unavailableUntil.Store(timeutil.Now().Add(redisRetryAfter).UnixNano())
+	d.stats.NewTaggedStat("dedup_redis_unavailable", stats.CountType, stats.Tags{"op": op}).Increment()
+}
+
+// FindDuplicates returns the indexes of the messageIDs found in the batches, in the local store or in redis.
+func (d *RedisDedupHandleT) FindDuplicates(messageIDs []string, allMessageIDsSet map[string]struct{}) (duplicateIndexes []int) {
+	toRemoveMessageIndexesSet := make(map[int]struct{})
+	for _, idx := range d.local.FindDuplicates(messageIDs, allMessageIDsSet) {
+		toRemoveMessageIndexesSet[idx] = struct{}{}
+	}
+	if !d.available() {
+		return sortedIndexes(toRemoveMessageIndexesSet)
+	}
+
+	var (
+		lookupIDs     []string
+		lookupIndexes []int
+	)
+	for idx, messageID := range messageIDs {
+		if _, ok := toRemoveMessageIndexesSet[idx]; !ok {
+			lookupIDs = append(lookupIDs, messageID)
+			lookupIndexes = append(lookupIndexes, idx)
+		}
+	}
+
+	for start := 0; start < len(lookupIDs); start += redisBatchSize {
+		end := start + redisBatchSize
+		if end > len(lookupIDs) {
+			end = len(lookupIDs)
+		}
+		found, err := d.exist(lookupIDs[start:end])
+		if err != nil {
+			d.markUnavailable("find", err)
+			break
+		}
+		for i, ok := range found {
+			if ok {
+				toRemoveMessageIndexesSet[lookupIndexes[start+i]] = struct{}{}
+			}
+		}
+	}
+	return sortedIndexes(toRemoveMessageIndexesSet)
+}
+
+// exist looks up the messageIDs in redis with a single command, returning whether each of them was found.
+func (d *RedisDedupHandleT) exist(messageIDs []string) ([]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	keys := make([]string, len(messageIDs))
+	for i, messageID := range messageIDs {
+		keys[i] = redisKeyPrefix + messageID
+	}
+	values, err := d.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]bool, len(messageIDs))
+	for i, value := range values {
+		found[i] = value != nil
+	}
+	return found, nil
+}
+
+// MarkProcessed persists messageIDs in the local store and in redis, with expiry time of dedupWindow.
+// It is called once the jobs of the messageIDs are stored. Failing to set them in redis doesn't fail the call,
+// since they are deduplicated by the local store of this replica anyway.
+func (d *RedisDedupHandleT) MarkProcessed(messageIDs []string) error {
+	if err := d.local.MarkProcessed(messageIDs); err != nil {
+		return err
+	}
+	if !d.available() {
+		return nil
+	}
+
+	for start := 0; start < len(messageIDs); start += redisBatchSize {
+		end := start + redisBatchSize
+		if end > len(messageIDs) {
+			end = len(messageIDs)
+		}
+		if err := d.set(messageIDs[start:end]); err != nil {
+			d.markUnavailable("mark", err)
+			break
+		}
+	}
+	return nil
+}
+
+// set sets the messageIDs in redis in a single pipeline
+func (d *RedisDedupHandleT) set(messageIDs []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	_, err := d.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, messageID := range messageIDs {
+			pipe.Set(ctx, redisKeyPrefix+messageID, 1, *d.local.window)
+		}
+		return nil
+	})
+	return err
+}
+
+func (d *RedisDedupHandleT) PrintHistogram() {
+	d.local.PrintHistogram()
+}
+
+func (d *RedisDedupHandleT) Close() {
+	d.local.Close()
+	_ = d.client.Close()
+}
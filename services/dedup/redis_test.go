@@ -0,0 +1,92 @@
+package dedup_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/dedup"
+	"github.com/rudderlabs/rudder-server/testhelper/destination"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+func newLocalDedup(t *testing.T, name string, window time.Duration) *dedup.DedupHandleT {
+	t.Helper()
+
+	dbPath := os.TempDir() + "/" + name
+	t.Cleanup(func() { _ = os.RemoveAll(dbPath) })
+	_ = os.RemoveAll(dbPath)
+	return dedup.New(dbPath, dedup.WithClearDB(), dedup.WithWindow(window))
+}
+
+func Test_RedisDedup(t *testing.T) {
+	config.Reset()
+	logger.Reset()
+	dedup.Init()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	redisResource, err := destination.SetupRedis(context.Background(), pool, t)
+	require.NoError(t, err)
+
+	t.Run("duplicates across replicas", func(t *testing.T) {
+		replica1 := dedup.NewRedis(redis.NewClient(&redis.Options{Addr: redisResource.Addr}), newLocalDedup(t, "dedup_test_replica_1", time.Hour))
+		defer replica1.Close()
+		replica2 := dedup.NewRedis(redis.NewClient(&redis.Options{Addr: redisResource.Addr}), newLocalDedup(t, "dedup_test_replica_2", time.Hour))
+		defer replica2.Close()
+
+		require.Equal(t, []int{}, replica1.FindDuplicates([]string{"a", "b", "c"}, nil))
+		require.Equal(t, []int{}, replica2.FindDuplicates([]string{"a", "b", "c"}, nil), "messageIDs aren't set before being marked as processed")
+		require.NoError(t, replica1.MarkProcessed([]string{"a", "b", "c"}))
+
+		require.Equal(t, []int{0, 1, 2}, replica2.FindDuplicates([]string{"a", "b", "c"}, nil))
+		require.Equal(t, []int{1, 2}, replica2.FindDuplicates([]string{"d", "a", "d"}, map[string]struct{}{"d": {}}))
+	})
+
+	t.Run("failed store", func(t *testing.T) {
+		replica1 := dedup.NewRedis(redis.NewClient(&redis.Options{Addr: redisResource.Addr}), newLocalDedup(t, "dedup_test_failed_store_1", time.Hour))
+		defer replica1.Close()
+		replica2 := dedup.NewRedis(redis.NewClient(&redis.Options{Addr: redisResource.Addr}), newLocalDedup(t, "dedup_test_failed_store_2", time.Hour))
+		defer replica2.Close()
+
+		// the jobs of the messageIDs fail to be stored, so they are never marked as processed
+		require.Equal(t, []int{}, replica1.FindDuplicates([]string{"e", "f"}, nil))
+
+		require.Equal(t, []int{}, replica1.FindDuplicates([]string{"e", "f"}, nil), "the jobs are read again by the same replica")
+		require.Equal(t, []int{}, replica2.FindDuplicates([]string{"e", "f"}, nil), "the jobs are read again by another replica")
+		require.NoError(t, replica2.MarkProcessed([]string{"e", "f"}))
+		require.Equal(t, []int{0, 1}, replica1.FindDuplicates([]string{"e", "f"}, nil))
+	})
+
+	t.Run("duplicates within window", func(t *testing.T) {
+		d := dedup.NewRedis(redis.NewClient(&redis.Options{Addr: redisResource.Addr}), newLocalDedup(t, "dedup_test_window", time.Second))
+		defer d.Close()
+
+		require.Equal(t, []int{}, d.FindDuplicates([]string{"to be deleted"}, nil))
+		require.NoError(t, d.MarkProcessed([]string{"to be deleted"}))
+		require.Equal(t, []int{0}, d.FindDuplicates([]string{"to be deleted"}, nil))
+
+		time.Sleep(2 * time.Second)
+		require.Equal(t, []int{}, d.FindDuplicates([]string{"to be deleted"}, nil), "messageIDs expire with the window")
+	})
+}
+
+func Test_RedisDedup_Unavailable(t *testing.T) {
+	config.Reset()
+	logger.Reset()
+	dedup.Init()
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:1", MaxRetries: -1})
+	d := dedup.NewRedis(client, newLocalDedup(t, "dedup_test_unavailable", time.Hour))
+	defer d.Close()
+
+	require.Equal(t, []int{}, d.FindDuplicates([]string{"a", "b", "c"}, nil))
+	require.NoError(t, d.MarkProcessed([]string{"a", "b"}))
+	require.Equal(t, []int{0, 1}, d.FindDuplicates([]string{"a", "b", "c"}, nil))
+}
@@ -2,6 +2,8 @@ package dedup
 
 import (
 	"sync"
+
+	"github.com/rudderlabs/rudder-server/config"
 )
 
 var (
@@ -18,7 +20,19 @@ func GetInstance(clearDB *bool) DedupI {
 			opts = append(opts, WithClearDB())
 		}
 
-		dedupManager = New(DefaultRudderPath(), opts...)
+		local := New(DefaultRudderPath(), opts...)
+		switch store {
+		case storeRedis:
+			if !config.IsSet("Dedup.redis.addr") {
+				pkgLogger.Errorf("[[ Dedup ]] Dedup.redis.addr is not set for store %s, using the local store only", store)
+				dedupManager = local
+				return
+			}
+			pkgLogger.Infof("[[ Dedup ]] Using redis store at %s", config.GetString("Dedup.redis.addr", ""))
+			dedupManager = NewRedis(newRedisClient(), local)
+		default:
+			dedupManager = local
+		}
 	})
 
 	return dedupManager
@@ -0,0 +1,111 @@
+package filemanager
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// LifecycleRule is an expiration rule of the lifecycle policy of a bucket.
+// An empty prefix applies the rule to all the objects of the bucket.
+type LifecycleRule struct {
+	ID             string `json:"id"`
+	Prefix         string `json:"prefix"`
+	Enabled        bool   `json:"enabled"`
+	ExpirationDays int64  `json:"expirationDays"`
+}
+
+// LifecycleManager is implemented by the file managers which can read the lifecycle policy of their bucket
+type LifecycleManager interface {
+	GetLifecycleRules(ctx context.Context) ([]LifecycleRule, error)
+}
+
+// GetLifecycleRules returns the expiration rules of the lifecycle configuration of the bucket.
+// Rules without an expiration in days, e.g. transitions only, are skipped.
+func (manager *S3Manager) GetLifecycleRules(ctx context.Context) ([]LifecycleRule, error) {
+	sess, err := manager.getSession(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting S3 session: %w", err)
+	}
+	svc := s3.New(sess)
+
+	ctx, cancel := context.WithTimeout(ctx, manager.getTimeout())
+	defer cancel()
+
+	output, err := svc.GetBucketLifecycleConfigurationWithContext(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(manager.Config.Bucket),
+	})
+	if err != nil {
+		if awsError, ok := err.(awserr.Error); ok && awsError.Code() == "NoSuchLifecycleConfiguration" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var rules []LifecycleRule
+	for _, rule := range output.Rules {
+		if rule.Expiration == nil || aws.Int64Value(rule.Expiration.Days) == 0 {
+			continue
+		}
+		prefix := aws.StringValue(rule.Prefix)
+		if rule.Filter != nil {
+			switch {
+			case rule.Filter.Prefix != nil:
+				prefix = aws.StringValue(rule.Filter.Prefix)
+			case rule.Filter.And != nil:
+				prefix = aws.StringValue(rule.Filter.And.Prefix)
+			}
+		}
+		rules = append(rules, LifecycleRule{
+			ID:             aws.StringValue(rule.ID),
+			Prefix:         prefix,
+			Enabled:        aws.StringValue(rule.Status) == s3.ExpirationStatusEnabled,
+			ExpirationDays: aws.Int64Value(rule.Expiration.Days),
+		})
+	}
+	return rules, nil
+}
+
+// GetLifecycleRules returns the delete rules of the lifecycle configuration of the bucket, one for each of their prefixes.
+// Rules conditioned on anything but the age of the objects are skipped.
+func (manager *GCSManager) GetLifecycleRules(ctx context.Context) ([]LifecycleRule, error) {
+	client, err := manager.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, manager.getTimeout())
+	defer cancel()
+
+	attrs, err := client.Bucket(manager.Config.Bucket).Attrs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []LifecycleRule
+	for i, rule := range attrs.Lifecycle.Rules {
+		if rule.Action.Type != storage.DeleteAction || rule.Condition.AgeInDays == 0 {
+			continue
+		}
+		if !rule.Condition.CreatedBefore.IsZero() || rule.Condition.NumNewerVersions > 0 || rule.Condition.Liveness == storage.Archived {
+			continue
+		}
+		prefixes := rule.Condition.MatchesPrefix
+		if len(prefixes) == 0 {
+			prefixes = []string{""}
+		}
+		for _, prefix := range prefixes {
+			rules = append(rules, LifecycleRule{
+				ID:             fmt.Sprintf("rule-%d", i),
+				Prefix:         prefix,
+				Enabled:        true,
+				ExpirationDays: rule.Condition.AgeInDays,
+			})
+		}
+	}
+	return rules, nil
+}
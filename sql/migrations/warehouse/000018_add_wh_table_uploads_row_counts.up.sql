@@ -0,0 +1,7 @@
+--
+-- wh_table_uploads
+--
+
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS rows_before_load BIGINT;
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS rows_after_load BIGINT;
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS rows_mismatch BIGINT;
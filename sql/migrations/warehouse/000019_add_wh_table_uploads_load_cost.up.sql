@@ -0,0 +1,7 @@
+--
+-- wh_table_uploads
+--
+
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS load_duration_ms BIGINT;
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS bytes_loaded BIGINT;
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS load_cost JSONB;
@@ -0,0 +1,7 @@
+--
+-- wh_table_uploads
+--
+
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS first_failed_at TIMESTAMP;
+ALTER TABLE wh_table_uploads ADD COLUMN IF NOT EXISTS next_retry_time TIMESTAMP;
@@ -0,0 +1,67 @@
+package destination
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/denisenkom/go-mssqldb"
+	"github.com/ory/dockertest/v3"
+)
+
+const (
+	mssqlDefaultDB       = "master"
+	mssqlDefaultUser     = "SA"
+	mssqlDefaultPassword = "reallyStrongPwd123"
+)
+
+type MSSQLResource struct {
+	DB       *sql.DB
+	DBDsn    string
+	Database string
+	Password string
+	User     string
+	Host     string
+	Port     string
+}
+
+func SetupMSSQL(pool *dockertest.Pool, d cleaner) (*MSSQLResource, error) {
+	// pulls an image, creates a container based on it and runs it
+	mssqlContainer, err := pool.Run("mcr.microsoft.com/mssql/server", "2019-latest", []string{
+		"ACCEPT_EULA=Y",
+		"SA_PASSWORD=" + mssqlDefaultPassword,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d.Cleanup(func() {
+		if err := pool.Purge(mssqlContainer); err != nil {
+			d.Log("Could not purge resource:", err)
+		}
+	})
+
+	dbDSN := fmt.Sprintf(
+		"sqlserver://%s:%s@localhost:%s?database=%s&encrypt=disable",
+		mssqlDefaultUser, mssqlDefaultPassword, mssqlContainer.GetPort("1433/tcp"), mssqlDefaultDB,
+	)
+	var db *sql.DB
+	// exponential backoff-retry, because the application in the container might not be ready to accept connections yet
+	err = pool.Retry(func() (err error) {
+		if db, err = sql.Open("sqlserver", dbDSN); err != nil {
+			return err
+		}
+		return db.Ping()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &MSSQLResource{
+		DB:       db,
+		DBDsn:    dbDSN,
+		Database: mssqlDefaultDB,
+		User:     mssqlDefaultUser,
+		Password: mssqlDefaultPassword,
+		Host:     "localhost",
+		Port:     mssqlContainer.GetPort("1433/tcp"),
+	}, nil
+}
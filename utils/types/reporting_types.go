@@ -18,14 +18,19 @@ const (
 
 	DEFAULT_REPORTING_ENABLED = true
 	DEFAULT_REPLAY_ENABLED    = false
+
+	// FilterEventCode is the status code of the events filtered out by the processor
+	FilterEventCode = 298
 )
 
 var (
-	DiffStatus = "diff"
+	DiffStatus   = "diff"
+	FilterStatus = "filtered"
 
 	// Module names
 	GATEWAY                = "gateway"
 	DESTINATION_FILTER     = "destination_filter"
+	CONSENT_FILTER         = "consent_filter"
 	TRACKINGPLAN_VALIDATOR = "tracking_plan_validator"
 	USER_TRANSFORMER       = "user_transformer"
 	EVENT_FILTER           = "event_filter"
@@ -25,6 +25,8 @@ type GatewayBatchRequestT struct {
 // UserSuppression is interface to access Suppress user feature
 type UserSuppression interface {
 	IsSuppressedUser(workspaceID, userID, sourceID string) bool
+	// IsSuppressedUserEvent returns true if the user of an event is suppressed by its userId, anonymousId or email
+	IsSuppressedUserEvent(workspaceID, sourceID, userID, anonymousID, email string) bool
 }
 
 // EventSchemasI is interface to access EventSchemas feature
@@ -37,6 +39,7 @@ type EventSchemasI interface {
 	GetKeyCounts(w http.ResponseWriter, r *http.Request)
 	GetEventModelMetadata(w http.ResponseWriter, r *http.Request)
 	GetJsonSchemas(w http.ResponseWriter, r *http.Request)
+	GetTrackingPlan(w http.ResponseWriter, r *http.Request)
 }
 
 // ConfigEnvI is interface to inject env variables into config
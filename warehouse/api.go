@@ -175,6 +175,7 @@ func (uploadsReq *UploadsReqT) validateReq() error {
 
 var statusMap = map[string]string{
 	"success": model.ExportedData,
+	"partial": model.PartiallyExportedData,
 	"waiting": model.Waiting,
 	"aborted": model.Aborted,
 	"failed":  "%failed%",
@@ -423,7 +424,7 @@ func (tableUploadReq TableUploadReqT) GetWhTableUploads() ([]*proto.WHTable, err
 	if err != nil {
 		return []*proto.WHTable{}, err
 	}
-	query := tableUploadReq.generateQuery(`id, wh_upload_id, table_name, total_events, status, error, last_exec_time, updated_at`)
+	query := tableUploadReq.generateQuery(`id, wh_upload_id, table_name, total_events, status, error, last_exec_time, updated_at, rows_after_load - rows_before_load, rows_mismatch`)
 	tableUploadReq.API.log.Debug(query)
 	rows, err := tableUploadReq.API.dbHandle.Query(query)
 	if err != nil {
@@ -435,6 +436,7 @@ func (tableUploadReq TableUploadReqT) GetWhTableUploads() ([]*proto.WHTable, err
 		var tableUpload proto.WHTable
 		var count sql.NullInt32
 		var lastExecTime, updatedAt sql.NullTime
+		var rowsLoaded, rowsMismatch sql.NullInt64
 		err = rows.Scan(
 			&tableUpload.Id,
 			&tableUpload.UploadId,
@@ -444,6 +446,8 @@ func (tableUploadReq TableUploadReqT) GetWhTableUploads() ([]*proto.WHTable, err
 			&tableUpload.Error,
 			&lastExecTime,
 			&updatedAt,
+			&rowsLoaded,
+			&rowsMismatch,
 		)
 		if err != nil {
 			tableUploadReq.API.log.Errorf(err.Error())
@@ -452,6 +456,8 @@ func (tableUploadReq TableUploadReqT) GetWhTableUploads() ([]*proto.WHTable, err
 		if count.Valid {
 			tableUpload.Count = count.Int32
 		}
+		tableUpload.RowsLoaded = rowsLoaded.Int64
+		tableUpload.RowsMismatch = rowsMismatch.Int64
 		// do not include rudder_discards table if no events are present
 		if strings.ToLower(tableUpload.Name) == warehouseutils.DiscardsTable && !count.Valid {
 			continue
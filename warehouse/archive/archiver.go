@@ -89,12 +89,8 @@ func (a *Archiver) backupRecords(args backupRecordsArgs) (backupLocation string,
 	)
 	defer misc.RemoveFilePaths(path)
 
-	fManager, err := a.FileManager.New(&filemanager.SettingsT{
-		Provider: config.GetString("JOBS_BACKUP_STORAGE_PROVIDER", "S3"),
-		Config:   filemanager.GetProviderConfigForBackupsFromEnv(context.TODO()),
-	})
+	fManager, err := a.backupFileManager()
 	if err != nil {
-		err = fmt.Errorf("error in creating a file manager for:%s. Error: %w", config.GetString("JOBS_BACKUP_STORAGE_PROVIDER", "S3"), err)
 		return
 	}
 
@@ -133,6 +129,18 @@ func (a *Archiver) backupRecords(args backupRecordsArgs) (backupLocation string,
 	return
 }
 
+func (a *Archiver) backupFileManager() (filemanager.FileManager, error) {
+	provider := config.GetString("JOBS_BACKUP_STORAGE_PROVIDER", "S3")
+	fManager, err := a.FileManager.New(&filemanager.SettingsT{
+		Provider: provider,
+		Config:   filemanager.GetProviderConfigForBackupsFromEnv(context.TODO()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in creating a file manager for:%s. Error: %w", provider, err)
+	}
+	return fManager, nil
+}
+
 func (a *Archiver) deleteFilesInStorage(locations []string) error {
 	fManager, err := a.FileManager.New(&filemanager.SettingsT{
 		Provider: warehouseutils.S3,
@@ -248,6 +256,7 @@ func (a *Archiver) Do(ctx context.Context) error {
 		hasUsedRudderStorage := a.usedRudderStorage(u.uploadMetdata)
 
 		// archive staging files
+		// staging files restored for backfills get ids in the range of regular uploads, hence only the ones of the upload's backfill are picked
 		stmt := fmt.Sprintf(`
 			SELECT
 			  id,
@@ -258,13 +267,15 @@ func (a *Archiver) Do(ctx context.Context) error {
 			  source_id = '%s'
 			  AND destination_id = '%s'
 			  AND id >= %d
-			  and id <= %d;
+			  and id <= %d
+			  AND COALESCE(metadata ->> 'backfill_id', '') = '%s';
 `,
 			warehouseutils.WarehouseStagingFilesTable,
 			u.sourceID,
 			u.destID,
 			u.startStagingFileId,
 			u.endStagingFileId,
+			gjson.GetBytes(u.uploadMetdata, "backfill_id").String(),
 		)
 
 		stagingFileRows, err := txn.Query(stmt)
@@ -392,6 +403,9 @@ func (a *Archiver) Do(ctx context.Context) error {
 
 		// update upload metadata
 		u.uploadMetdata, _ = sjson.SetBytes(u.uploadMetdata, "archivedStagingAndLoadFiles", true)
+		if storedStagingFilesLocation != "" {
+			u.uploadMetdata, _ = sjson.SetBytes(u.uploadMetdata, ArchivedStagingFilesLocationKey, storedStagingFilesLocation)
+		}
 		stmt = fmt.Sprintf(`
 			UPDATE
 			  %s
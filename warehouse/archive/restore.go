@@ -0,0 +1,219 @@
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// ArchivedStagingFilesLocationKey is the upload metadata key holding the location of the archived staging file records.
+const ArchivedStagingFilesLocationKey = "archivedStagingFilesLocation"
+
+// archivedTimestampLayout is how postgres serialises timestamp columns without time zone into json.
+const archivedTimestampLayout = "2006-01-02T15:04:05.999999999"
+
+// archivedStagingFile is a wh_staging_files row as written by backupRecords.
+type archivedStagingFile struct {
+	ID            int64           `json:"id"`
+	Location      string          `json:"location"`
+	SourceID      string          `json:"source_id"`
+	DestinationID string          `json:"destination_id"`
+	Schema        json.RawMessage `json:"schema"`
+	TotalEvents   int             `json:"total_events"`
+	FirstEventAt  *string         `json:"first_event_at"`
+	LastEventAt   *string         `json:"last_event_at"`
+	WorkspaceID   string          `json:"workspace_id"`
+	Metadata      struct {
+		UseRudderStorage      bool   `json:"use_rudder_storage"`
+		SourceBatchID         string `json:"source_batch_id"`
+		SourceTaskID          string `json:"source_task_id"`
+		SourceTaskRunID       string `json:"source_task_run_id"`
+		SourceJobID           string `json:"source_job_id"`
+		SourceJobRunID        string `json:"source_job_run_id"`
+		TimeWindowYear        int    `json:"time_window_year"`
+		TimeWindowMonth       int    `json:"time_window_month"`
+		TimeWindowDay         int    `json:"time_window_day"`
+		TimeWindowHour        int    `json:"time_window_hour"`
+		DestinationRevisionID string `json:"destination_revision_id"`
+		BackfillID            string `json:"backfill_id"`
+	} `json:"metadata"`
+}
+
+// ArchivedStagingFiles returns the archived staging files of a source and destination
+// having events in the [start, end] time range, ordered as they were originally received.
+//
+// Staging files uploaded to rudder storage are not part of the archive, since they are deleted along with their records.
+func (a *Archiver) ArchivedStagingFiles(ctx context.Context, sourceID, destinationID string, start, end time.Time) ([]model.StagingFileWithSchema, error) {
+	locations, err := a.archivedStagingFilesLocations(ctx, sourceID, destinationID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	fManager, err := a.backupFileManager()
+	if err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "archived-staging-files")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	var stagingFiles []model.StagingFileWithSchema
+	for _, location := range locations {
+		objectName, err := fManager.GetObjectNameFromLocation(location)
+		if err != nil {
+			return nil, fmt.Errorf("getting object name for %s: %w", location, err)
+		}
+
+		file, err := os.CreateTemp(tmpDir, "staging-files.*.json.gz")
+		if err != nil {
+			return nil, fmt.Errorf("creating temp file: %w", err)
+		}
+		if err := fManager.Download(ctx, file, objectName); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("downloading archived staging files from %s: %w", location, err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("seeking archived staging files: %w", err)
+		}
+
+		archived, err := parseArchivedStagingFiles(file, start, end)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("parsing archived staging files from %s: %w", location, err)
+		}
+		stagingFiles = append(stagingFiles, archived...)
+	}
+
+	sort.SliceStable(stagingFiles, func(i, j int) bool {
+		return stagingFiles[i].ID < stagingFiles[j].ID
+	})
+	return stagingFiles, nil
+}
+
+func (a *Archiver) archivedStagingFilesLocations(ctx context.Context, sourceID, destinationID string, start, end time.Time) ([]string, error) {
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  metadata ->> '%[2]s'
+		FROM
+		  %[1]s
+		WHERE
+		  source_id = $1
+		  AND destination_id = $2
+		  AND (metadata ->> 'archivedStagingAndLoadFiles'):: bool
+		  AND metadata ->> '%[2]s' IS NOT NULL
+		  AND metadata ->> 'backfill_id' IS NULL
+		  AND first_event_at <= $4
+		  AND last_event_at >= $3
+		ORDER BY
+		  id ASC;
+`,
+		pq.QuoteIdentifier(warehouseutils.WarehouseUploadsTable),
+		ArchivedStagingFilesLocationKey,
+	)
+
+	rows, err := a.DB.QueryContext(ctx, sqlStatement, sourceID, destinationID, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("querying archived uploads: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var locations []string
+	for rows.Next() {
+		var location string
+		if err := rows.Scan(&location); err != nil {
+			return nil, fmt.Errorf("scanning archived upload: %w", err)
+		}
+		locations = append(locations, location)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating archived uploads: %w", err)
+	}
+	return locations, nil
+}
+
+// parseArchivedStagingFiles reads the gzipped staging file records, keeping the ones with events in the [start, end] time range.
+// Records restored by earlier backfills are skipped, as the original records of their staging files are archived as well.
+func parseArchivedStagingFiles(r io.Reader, start, end time.Time) ([]model.StagingFileWithSchema, error) {
+	gzReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("creating gzip reader: %w", err)
+	}
+	defer func() { _ = gzReader.Close() }()
+
+	var stagingFiles []model.StagingFileWithSchema
+
+	decoder := json.NewDecoder(gzReader)
+	for {
+		var archived archivedStagingFile
+		err := decoder.Decode(&archived)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoding staging file: %w", err)
+		}
+		if archived.Metadata.UseRudderStorage || archived.Metadata.BackfillID != "" {
+			continue
+		}
+
+		firstEventAt, err := parseArchivedTimestamp(archived.FirstEventAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing first_event_at of staging file %d: %w", archived.ID, err)
+		}
+		lastEventAt, err := parseArchivedTimestamp(archived.LastEventAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing last_event_at of staging file %d: %w", archived.ID, err)
+		}
+		if firstEventAt.After(end) || lastEventAt.Before(start) {
+			continue
+		}
+
+		m := archived.Metadata
+		stagingFiles = append(stagingFiles, model.StagingFile{
+			ID:                    archived.ID,
+			WorkspaceID:           archived.WorkspaceID,
+			Location:              archived.Location,
+			SourceID:              archived.SourceID,
+			DestinationID:         archived.DestinationID,
+			FirstEventAt:          firstEventAt,
+			LastEventAt:           lastEventAt,
+			TotalEvents:           archived.TotalEvents,
+			DestinationRevisionID: m.DestinationRevisionID,
+			SourceBatchID:         m.SourceBatchID,
+			SourceTaskID:          m.SourceTaskID,
+			SourceTaskRunID:       m.SourceTaskRunID,
+			SourceJobID:           m.SourceJobID,
+			SourceJobRunID:        m.SourceJobRunID,
+			TimeWindow:            time.Date(m.TimeWindowYear, time.Month(m.TimeWindowMonth), m.TimeWindowDay, m.TimeWindowHour, 0, 0, 0, time.UTC),
+		}.WithSchema(archived.Schema))
+	}
+	return stagingFiles, nil
+}
+
+func parseArchivedTimestamp(value *string) (time.Time, error) {
+	if value == nil {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, *value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.ParseInLocation(archivedTimestampLayout, *value, time.UTC)
+}
@@ -0,0 +1,60 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArchivedStagingFiles(t *testing.T) {
+	records := []string{
+		`{"id":1,"location":"s3://bucket/1.json.gz","source_id":"source","destination_id":"destination","schema":{"tracks":{"id":"string"}},"error":null,"status":"succeeded","first_event_at":"2022-09-20T10:00:00.123456","last_event_at":"2022-09-20T10:30:00","total_events":10,"metadata":{"source_job_run_id":"job-run","time_window_year":2022,"time_window_month":9,"time_window_day":20,"time_window_hour":10},"workspace_id":"workspace"}`,
+		`{"id":2,"location":"s3://bucket/2.json.gz","source_id":"source","destination_id":"destination","schema":{},"error":null,"status":"succeeded","first_event_at":"2022-09-21T10:00:00","last_event_at":"2022-09-21T10:30:00","total_events":5,"metadata":{},"workspace_id":"workspace"}`,
+		`{"id":3,"location":"rudder/3.json.gz","source_id":"source","destination_id":"destination","schema":{},"error":null,"status":"succeeded","first_event_at":"2022-09-20T11:00:00","last_event_at":"2022-09-20T11:30:00","total_events":5,"metadata":{"use_rudder_storage":true},"workspace_id":"workspace"}`,
+		`{"id":4,"location":"s3://bucket/1.json.gz","source_id":"source","destination_id":"destination","schema":{},"error":null,"status":"succeeded","first_event_at":"2022-09-20T10:00:00.123456","last_event_at":"2022-09-20T10:30:00","total_events":10,"metadata":{"backfill_id":"backfill"},"workspace_id":"workspace"}`,
+	}
+
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	for _, record := range records {
+		_, err := gzWriter.Write([]byte(record + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, gzWriter.Close())
+
+	start := time.Date(2022, 9, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 9, 20, 23, 59, 59, 0, time.UTC)
+
+	stagingFiles, err := parseArchivedStagingFiles(&buf, start, end)
+	require.NoError(t, err)
+	require.Len(t, stagingFiles, 1)
+
+	stagingFile := stagingFiles[0]
+	require.EqualValues(t, 1, stagingFile.ID)
+	require.Equal(t, "s3://bucket/1.json.gz", stagingFile.Location)
+	require.Equal(t, "workspace", stagingFile.WorkspaceID)
+	require.Equal(t, 10, stagingFile.TotalEvents)
+	require.Equal(t, "job-run", stagingFile.SourceJobRunID)
+	require.Equal(t, time.Date(2022, 9, 20, 10, 0, 0, 123456000, time.UTC), stagingFile.FirstEventAt)
+	require.Equal(t, time.Date(2022, 9, 20, 10, 30, 0, 0, time.UTC), stagingFile.LastEventAt)
+	require.Equal(t, time.Date(2022, 9, 20, 10, 0, 0, 0, time.UTC), stagingFile.TimeWindow)
+	require.JSONEq(t, `{"tracks":{"id":"string"}}`, string(stagingFile.Schema))
+}
+
+func TestParseArchivedStagingFilesInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	gzWriter := gzip.NewWriter(&buf)
+	_, err := gzWriter.Write([]byte(`{"id":1,"first_event_at":"yesterday"}`))
+	require.NoError(t, err)
+	require.NoError(t, gzWriter.Close())
+
+	_, err = parseArchivedStagingFiles(&buf, time.Time{}, time.Now())
+	require.Error(t, err)
+
+	_, err = parseArchivedStagingFiles(bytes.NewReader(json.RawMessage(`{}`)), time.Time{}, time.Now())
+	require.Error(t, err)
+}
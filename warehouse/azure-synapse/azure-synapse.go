@@ -689,7 +689,16 @@ func (as *HandleT) AddColumns(tableName string, columnsInfo []warehouseutils.Col
 	return
 }
 
-func (*HandleT) AlterColumn(_, _, _ string) (err error) {
+// AlterColumn widens the column to columnType, converting the existing values.
+func (as *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
+	dataType, ok := rudderDataTypesMapToMssql[columnType]
+	if !ok {
+		return
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %s.%s ALTER COLUMN %s %s;`, as.Namespace, tableName, columnName, dataType)
+	pkgLogger.Infof("AZ: Altering column for destinationID: %s, tableName: %s with query: %v", as.Warehouse.Destination.ID, tableName, query)
+	_, err = as.Db.Exec(query)
 	return
 }
 
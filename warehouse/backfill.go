@@ -0,0 +1,251 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/repo"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var errNoArchivedStagingFiles = errors.New("no archived staging files found")
+
+type backfillRequest struct {
+	SourceID      string    `json:"source_id"`
+	DestinationID string    `json:"destination_id"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	// Namespace to load into, defaults to the namespace of the destination
+	Namespace string `json:"namespace"`
+}
+
+type backfillResponse struct {
+	UploadID     int64  `json:"upload_id"`
+	BackfillID   string `json:"backfill_id"`
+	Namespace    string `json:"namespace"`
+	StagingFiles int    `json:"staging_files"`
+}
+
+func (req *backfillRequest) validate() error {
+	if req.SourceID == "" || req.DestinationID == "" {
+		return errors.New("source_id and destination_id are required")
+	}
+	if req.StartTime.IsZero() || req.EndTime.IsZero() {
+		return errors.New("start_time and end_time are required")
+	}
+	if !req.StartTime.Before(req.EndTime) {
+		return errors.New("start_time should be before end_time")
+	}
+	return nil
+}
+
+type archivedStagingFilesFetcher interface {
+	ArchivedStagingFiles(ctx context.Context, sourceID, destinationID string, start, end time.Time) ([]model.StagingFileWithSchema, error)
+}
+
+// backfiller creates uploads reloading archived staging files of a source and destination.
+//
+// Restored staging files are marked with the backfill id and are only picked up by the backfill upload,
+// which goes through the regular upload state machine, regenerating the load files.
+type backfiller struct {
+	db          *sql.DB
+	archive     archivedStagingFilesFetcher
+	stagingRepo *repo.StagingFiles
+}
+
+// backfillHandler creates a backfill upload from the archived staging files of a source and destination in a time range.
+func (b *backfiller) backfillHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	ctx := r.Context()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error reading body: %v", err)
+		http.Error(w, "can't read body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var req backfillRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		pkgLogger.Errorf("[WH]: Error unmarshalling body: %v", err)
+		http.Error(w, "can't unmarshall body", http.StatusBadRequest)
+		return
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	connectionsMapLock.Lock()
+	warehouse, err := getDestinationFromConnectionMap(req.DestinationID, req.SourceID)
+	connectionsMapLock.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if tenantManager.DegradedWorkspace(warehouse.WorkspaceID) {
+		pkgLogger.Infof("[WH]: Workspace (id: %q) is degraded", warehouse.WorkspaceID)
+		http.Error(w, "workspace is in degraded mode", http.StatusServiceUnavailable)
+		return
+	}
+
+	res, err := b.backfill(ctx, warehouse, req)
+	if errors.Is(err, errNoArchivedStagingFiles) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error creating backfill for %s: %v", warehouse.Identifier, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resBody, err := json.Marshal(res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBody)
+}
+
+func (b *backfiller) backfill(ctx context.Context, warehouse warehouseutils.Warehouse, req backfillRequest) (*backfillResponse, error) {
+	stagingFiles, err := b.archive.ArchivedStagingFiles(ctx, req.SourceID, req.DestinationID, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("fetching archived staging files: %w", err)
+	}
+	if len(stagingFiles) == 0 {
+		return nil, errNoArchivedStagingFiles
+	}
+
+	namespace := warehouse.Namespace
+	if req.Namespace != "" {
+		namespace = warehouseutils.ToProviderCase(warehouse.Type, warehouseutils.ToSafeNamespace(warehouse.Type, req.Namespace))
+	}
+	backfillID := misc.FastUUID().String()
+
+	// the restored staging files are only picked up by the backfill upload, hence they are created along with it
+	txn, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer func() { _ = txn.Rollback() }()
+
+	restored := make([]*model.StagingFile, 0, len(stagingFiles))
+	for i := range stagingFiles {
+		stagingFile := stagingFiles[i]
+		stagingFile.BackfillID = backfillID
+		stagingFile.Status = warehouseutils.StagingFileWaitingState
+
+		id, err := b.stagingRepo.InsertTx(ctx, txn, &stagingFile)
+		if err != nil {
+			return nil, fmt.Errorf("restoring staging file %d: %w", stagingFiles[i].ID, err)
+		}
+		stagingFile.ID = id
+		restored = append(restored, &stagingFile.StagingFile)
+	}
+
+	uploadID, err := b.createUpload(ctx, txn, warehouse, namespace, backfillID, restored)
+	if err != nil {
+		return nil, fmt.Errorf("creating backfill upload: %w", err)
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, fmt.Errorf("committing backfill upload: %w", err)
+	}
+	pkgLogger.Infof("[WH]: Created backfill upload %d for %s with %d staging files into namespace %s", uploadID, warehouse.Identifier, len(restored), namespace)
+
+	return &backfillResponse{
+		UploadID:     uploadID,
+		BackfillID:   backfillID,
+		Namespace:    namespace,
+		StagingFiles: len(restored),
+	}, nil
+}
+
+func (*backfiller) createUpload(ctx context.Context, txn *sql.Tx, warehouse warehouseutils.Warehouse, namespace, backfillID string, stagingFiles []*model.StagingFile) (int64, error) {
+	sqlStatement := fmt.Sprintf(`
+		INSERT INTO %s (
+		  source_id, namespace, workspace_id, destination_id,
+		  destination_type, start_staging_file_id,
+		  end_staging_file_id, start_load_file_id,
+		  end_load_file_id, status, schema,
+		  error, metadata, first_event_at,
+		  last_event_at, created_at, updated_at
+		)
+		VALUES
+		  (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15, $16, $17
+		  ) RETURNING id;
+`,
+		warehouseutils.WarehouseUploadsTable,
+	)
+
+	firstEventAt, lastEventAt := stagingFiles[0].FirstEventAt, stagingFiles[0].LastEventAt
+	for _, stagingFile := range stagingFiles {
+		if !stagingFile.FirstEventAt.IsZero() && (firstEventAt.IsZero() || stagingFile.FirstEventAt.Before(firstEventAt)) {
+			firstEventAt = stagingFile.FirstEventAt
+		}
+		if stagingFile.LastEventAt.After(lastEventAt) {
+			lastEventAt = stagingFile.LastEventAt
+		}
+	}
+
+	now := timeutil.Now()
+	metadata, err := json.Marshal(map[string]interface{}{
+		"use_rudder_storage": false,
+		"source_batch_id":    stagingFiles[0].SourceBatchID,
+		"source_task_id":     stagingFiles[0].SourceTaskID,
+		"source_task_run_id": stagingFiles[0].SourceTaskRunID,
+		"source_job_id":      stagingFiles[0].SourceJobID,
+		"source_job_run_id":  stagingFiles[0].SourceJobRunID,
+		"load_file_type":     warehouseutils.GetLoadFileType(warehouse.Type),
+		"nextRetryTime":      now.Format(time.RFC3339),
+		"backfill_id":        backfillID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshalling metadata: %w", err)
+	}
+
+	var uploadID int64
+	err = txn.QueryRowContext(ctx, sqlStatement,
+		warehouse.Source.ID,
+		namespace,
+		warehouse.WorkspaceID,
+		warehouse.Destination.ID,
+		warehouse.Type,
+		stagingFiles[0].ID,
+		stagingFiles[len(stagingFiles)-1].ID,
+		0,
+		0,
+		model.Waiting,
+		"{}",
+		"{}",
+		metadata,
+		firstEventAt,
+		lastEventAt,
+		now,
+		now,
+	).Scan(&uploadID)
+	if err != nil {
+		return 0, err
+	}
+	return uploadID, nil
+}
@@ -0,0 +1,73 @@
+package warehouse
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestBackfillRequestValidate(t *testing.T) {
+	start := time.Date(2022, 9, 20, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	testCases := []struct {
+		name    string
+		req     backfillRequest
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			req:  backfillRequest{SourceID: "source", DestinationID: "destination", StartTime: start, EndTime: end},
+		},
+		{
+			name:    "missing source",
+			req:     backfillRequest{DestinationID: "destination", StartTime: start, EndTime: end},
+			wantErr: true,
+		},
+		{
+			name:    "missing time range",
+			req:     backfillRequest{SourceID: "source", DestinationID: "destination"},
+			wantErr: true,
+		},
+		{
+			name:    "inverted time range",
+			req:     backfillRequest{SourceID: "source", DestinationID: "destination", StartTime: end, EndTime: start},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.req.validate()
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+type mockArchivedStagingFiles []model.StagingFileWithSchema
+
+func (m mockArchivedStagingFiles) ArchivedStagingFiles(context.Context, string, string, time.Time, time.Time) ([]model.StagingFileWithSchema, error) {
+	return m, nil
+}
+
+func TestBackfillWithoutArchivedStagingFiles(t *testing.T) {
+	b := &backfiller{archive: mockArchivedStagingFiles(nil)}
+
+	_, err := b.backfill(context.Background(), warehouseutils.Warehouse{Type: warehouseutils.POSTGRES}, backfillRequest{
+		SourceID:      "source",
+		DestinationID: "destination",
+		StartTime:     time.Now().Add(-time.Hour),
+		EndTime:       time.Now(),
+	})
+	require.ErrorIs(t, err, errNoArchivedStagingFiles)
+}
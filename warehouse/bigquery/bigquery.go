@@ -38,6 +38,7 @@ type HandleT struct {
 	warehouse         warehouseutils.Warehouse
 	projectID         string
 	uploader          warehouseutils.UploaderI
+	loadCosts         warehouseutils.LoadCosts
 }
 
 type StagingLoadTableT struct {
@@ -120,6 +121,18 @@ func (bq *HandleT) CreateTable(tableName string, columnMap map[string]string) (e
 		Schema:           sampleSchema,
 		TimePartitioning: &bigquery.TimePartitioning{},
 	}
+	partitioning := bq.tablePartitioning(tableName, columnMap)
+	if partitioning.PartitionColumn != "" {
+		metaData.TimePartitioning = &bigquery.TimePartitioning{
+			Type:  timePartitioningType(partitioning.PartitionGranularity),
+			Field: partitioning.PartitionColumn,
+		}
+	}
+	if len(partitioning.ClusteringColumns) > 0 {
+		metaData.Clustering = &bigquery.Clustering{
+			Fields: partitioning.ClusteringColumns,
+		}
+	}
 	tableRef := bq.db.Dataset(bq.namespace).Table(tableName)
 	err = tableRef.Create(bq.backgroundContext, metaData)
 	if !checkAndIgnoreAlreadyExistError(err) {
@@ -132,6 +145,22 @@ func (bq *HandleT) CreateTable(tableName string, columnMap map[string]string) (e
 	return
 }
 
+func (bq *HandleT) tablePartitioning(tableName string, columnMap map[string]string) warehouseutils.TablePartitioningT {
+	return warehouseutils.GetTablePartitioning(bq.warehouse, tableName, columnMap)
+}
+
+func timePartitioningType(granularity string) bigquery.TimePartitioningType {
+	switch granularity {
+	case warehouseutils.PartitionGranularityHour:
+		return bigquery.HourPartitioningType
+	case warehouseutils.PartitionGranularityMonth:
+		return bigquery.MonthPartitioningType
+	case warehouseutils.PartitionGranularityYear:
+		return bigquery.YearPartitioningType
+	}
+	return bigquery.DayPartitioningType
+}
+
 func (bq *HandleT) DropTable(tableName string) (err error) {
 	err = bq.DeleteTable(tableName)
 	if err != nil {
@@ -154,9 +183,14 @@ func (bq *HandleT) createTableView(tableName string, columnMap map[string]string
 		viewOrderByStmt = " ORDER BY loaded_at DESC "
 	}
 
+	partitionFilterColumn := "_PARTITIONTIME"
+	if partitioning := bq.tablePartitioning(tableName, columnMap); partitioning.PartitionColumn != "" {
+		partitionFilterColumn = partitioning.PartitionColumn
+	}
+
 	// assuming it has field named id upon which dedup is done in view
 	viewQuery := `SELECT * EXCEPT (__row_number) FROM (
-			SELECT *, ROW_NUMBER() OVER (PARTITION BY ` + partitionKey + viewOrderByStmt + `) AS __row_number FROM ` + "`" + bq.projectID + "." + bq.namespace + "." + tableName + "`" + ` WHERE _PARTITIONTIME BETWEEN TIMESTAMP_TRUNC(TIMESTAMP_MICROS(UNIX_MICROS(CURRENT_TIMESTAMP()) - 60 * 60 * 60 * 24 * 1000000), DAY, 'UTC')
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY ` + partitionKey + viewOrderByStmt + `) AS __row_number FROM ` + "`" + bq.projectID + "." + bq.namespace + "." + tableName + "`" + ` WHERE ` + partitionFilterColumn + ` BETWEEN TIMESTAMP_TRUNC(TIMESTAMP_MICROS(UNIX_MICROS(CURRENT_TIMESTAMP()) - 60 * 60 * 60 * 24 * 1000000), DAY, 'UTC')
 					AND TIMESTAMP_TRUNC(CURRENT_TIMESTAMP(), DAY, 'UTC')
 			)
 		WHERE __row_number = 1`
@@ -314,6 +348,10 @@ func (bq *HandleT) loadTable(tableName string, _, getLoadFileLocFromTableUploads
 		if customPartitionsEnabled || slices.Contains(customPartitionsEnabledWorkspaceIDs, bq.warehouse.WorkspaceID) {
 			outputTable = tableName
 		}
+		// rows of tables partitioned by a column are assigned to partitions by the column value, so they are not loaded into a partition decorator
+		if bq.tablePartitioning(tableName, bq.uploader.GetTableSchemaInWarehouse(tableName)).PartitionColumn != "" {
+			outputTable = tableName
+		}
 
 		loader := bq.db.Dataset(bq.namespace).Table(outputTable).LoaderFrom(gcsRef)
 
@@ -331,6 +369,7 @@ func (bq *HandleT) loadTable(tableName string, _, getLoadFileLocFromTableUploads
 		if status.Err() != nil {
 			return status.Err()
 		}
+		bq.loadCosts.Add(tableName, jobCost(job, status))
 		return
 	}
 
@@ -366,6 +405,7 @@ func (bq *HandleT) loadTable(tableName string, _, getLoadFileLocFromTableUploads
 		if status.Err() != nil {
 			return status.Err()
 		}
+		cost := jobCost(job, status)
 
 		if !skipTempTableDelete {
 			defer bq.dropStagingTable(stagingTableName)
@@ -445,6 +485,8 @@ func (bq *HandleT) loadTable(tableName string, _, getLoadFileLocFromTableUploads
 		if status.Err() != nil {
 			return status.Err()
 		}
+		cost.Add(jobCost(job, status))
+		bq.loadCosts.Add(tableName, cost)
 		return
 	}
 
@@ -457,6 +499,26 @@ func (bq *HandleT) loadTable(tableName string, _, getLoadFileLocFromTableUploads
 	return
 }
 
+// jobCost returns the id, bytes billed and run time of a completed job. Load jobs are not billed.
+func jobCost(job *bigquery.Job, status *bigquery.JobStatus) warehouseutils.LoadCostT {
+	cost := warehouseutils.LoadCostT{QueryIDs: []string{job.ID()}}
+	if status.Statistics == nil {
+		return cost
+	}
+	if queryStats, ok := status.Statistics.Details.(*bigquery.QueryStatistics); ok {
+		cost.BytesBilled = queryStats.TotalBytesBilled
+	}
+	if !status.Statistics.StartTime.IsZero() && !status.Statistics.EndTime.IsZero() {
+		cost.QueryTimeMs = status.Statistics.EndTime.Sub(status.Statistics.StartTime).Milliseconds()
+	}
+	return cost
+}
+
+// LoadTableCost returns the job ids, bytes billed and job run time of the last load of the table.
+func (bq *HandleT) LoadTableCost(tableName string) (warehouseutils.LoadCostT, bool) {
+	return bq.loadCosts.Pop(tableName)
+}
+
 func (bq *HandleT) LoadUserTables() (errorMap map[string]error) {
 	errorMap = map[string]error{warehouseutils.IdentifiesTable: nil}
 	pkgLogger.Infof("BQ: Starting load for identifies and users tables\n")
@@ -562,6 +624,7 @@ func (bq *HandleT) LoadUserTables() (errorMap map[string]error) {
 			errorMap[warehouseutils.UsersTable] = status.Err()
 			return
 		}
+		bq.loadCosts.Add(warehouseutils.UsersTable, jobCost(job, status))
 	}
 
 	loadUserTableByMerge := func() {
@@ -588,6 +651,7 @@ func (bq *HandleT) LoadUserTables() (errorMap map[string]error) {
 			errorMap[warehouseutils.UsersTable] = status.Err()
 			return
 		}
+		cost := jobCost(job, status)
 		defer bq.dropStagingTable(identifyLoadTable.stagingTableName)
 		defer bq.dropStagingTable(stagingTableName)
 
@@ -635,6 +699,8 @@ func (bq *HandleT) LoadUserTables() (errorMap map[string]error) {
 			errorMap[warehouseutils.UsersTable] = status.Err()
 			return
 		}
+		cost.Add(jobCost(job, status))
+		bq.loadCosts.Add(warehouseutils.UsersTable, cost)
 	}
 
 	if !dedupEnabled() {
@@ -863,8 +929,23 @@ func (bq *HandleT) AddColumns(tableName string, columnsInfo []warehouseutils.Col
 	return
 }
 
-func (*HandleT) AlterColumn(_, _, _ string) (err error) {
-	return
+// AlterColumn widens integer columns to float. BigQuery doesn't support any other in place type change.
+func (bq *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
+	if columnType != "float" {
+		return
+	}
+
+	query := fmt.Sprintf("ALTER TABLE `%s`.`%s` ALTER COLUMN `%s` SET DATA TYPE FLOAT64", bq.namespace, tableName, columnName)
+	pkgLogger.Infof("BQ: Altering column for destinationID: %s, tableName: %s with query: %v", bq.warehouse.Destination.ID, tableName, query)
+	job, err := bq.db.Query(query).Run(bq.backgroundContext)
+	if err != nil {
+		return
+	}
+	status, err := job.Wait(bq.backgroundContext)
+	if err != nil {
+		return
+	}
+	return status.Err()
 }
 
 // FetchSchema queries bigquery and returns the schema associated with provided namespace
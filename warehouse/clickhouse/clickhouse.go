@@ -792,8 +792,23 @@ func getSortKeyTuple(sortKeyFields []string) string {
 	return tuple
 }
 
+// partitionExpression returns the partition key of a table partitioned by the column with the granularity
+func partitionExpression(column, granularity string) string {
+	switch granularity {
+	case warehouseutils.PartitionGranularityHour:
+		return fmt.Sprintf(`toStartOfHour(%q)`, column)
+	case warehouseutils.PartitionGranularityMonth:
+		return fmt.Sprintf(`toYYYYMM(%q)`, column)
+	case warehouseutils.PartitionGranularityYear:
+		return fmt.Sprintf(`toYear(%q)`, column)
+	}
+	return fmt.Sprintf(`toDate(%q)`, column)
+}
+
 // CreateTable creates table with engine ReplacingMergeTree(), this is used for dedupe event data and replace it will the latest data if duplicate data found. This logic is handled by clickhouse
 // The engine differs from MergeTree in that it removes duplicate entries with the same sorting key value.
+// Configured clustering columns never change the sorting key, so that the dedupe is kept. They are used as primary key
+// if they are a prefix of the sorting key, see warehouseutils.GetTablePartitioning.
 func (ch *HandleT) CreateTable(tableName string, columns map[string]string) (err error) {
 	sortKeyFields := []string{"received_at", "id"}
 	if tableName == warehouseutils.DiscardsTable {
@@ -815,17 +830,30 @@ func (ch *HandleT) CreateTable(tableName string, columns map[string]string) (err
 		engine = fmt.Sprintf(`%s%s`, "Replicated", engine)
 		engineOptions = `'/clickhouse/{cluster}/tables/{database}/{table}', '{replica}'`
 	}
+	partitioning := warehouseutils.GetTablePartitioning(ch.Warehouse, tableName, columns)
+
 	var orderByClause string
 	if len(sortKeyFields) > 0 {
 		orderByClause = fmt.Sprintf(`ORDER BY %s`, getSortKeyTuple(sortKeyFields))
 	}
+	if len(partitioning.ClusteringColumns) > 0 {
+		orderByClause = fmt.Sprintf(`%s PRIMARY KEY %s`, orderByClause, getSortKeyTuple(partitioning.ClusteringColumns))
+	}
 
 	var partitionByClause string
-	if _, ok := columns[partitionField]; ok {
+	if partitioning.PartitionColumn != "" {
+		partitionByClause = fmt.Sprintf(`PARTITION BY %s`, partitionExpression(partitioning.PartitionColumn, partitioning.PartitionGranularity))
+	} else if _, ok := columns[partitionField]; ok {
 		partitionByClause = fmt.Sprintf(`PARTITION BY toDate(%s)`, partitionField)
 	}
 
-	sqlStatement = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q.%q %s ( %v ) ENGINE = %s(%s) %s %s`, ch.Namespace, tableName, clusterClause, ColumnsWithDataTypes(tableName, columns, sortKeyFields), engine, engineOptions, orderByClause, partitionByClause)
+	// the configured partition column is nullable unlike the sort key columns
+	var settingsClause string
+	if partitioning.PartitionColumn != "" {
+		settingsClause = `SETTINGS allow_nullable_key = 1`
+	}
+
+	sqlStatement = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %q.%q %s ( %v ) ENGINE = %s(%s) %s %s %s`, ch.Namespace, tableName, clusterClause, ColumnsWithDataTypes(tableName, columns, sortKeyFields), engine, engineOptions, orderByClause, partitionByClause, settingsClause)
 
 	pkgLogger.Infof("CH: Creating table in clickhouse for ch:%s : %v", ch.Warehouse.Destination.ID, sqlStatement)
 	_, err = ch.Db.Exec(sqlStatement)
@@ -890,7 +918,21 @@ func (ch *HandleT) CreateSchema() (err error) {
 	return err
 }
 
-func (*HandleT) AlterColumn(_, _, _ string) (err error) {
+// AlterColumn widens the column to columnType. Columns which are part of the sorting key can't be altered.
+func (ch *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
+	dataType, ok := rudderDataTypesMapToClickHouse[columnType]
+	if !ok {
+		return
+	}
+
+	var clusterClause string
+	if cluster := warehouseutils.GetConfigValue(Cluster, ch.Warehouse); len(strings.TrimSpace(cluster)) > 0 {
+		clusterClause = fmt.Sprintf(`ON CLUSTER %q`, cluster)
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %q.%q %s MODIFY COLUMN %q %s;`, ch.Namespace, tableName, clusterClause, columnName, getClickHouseColumnTypeForSpecificTable(tableName, columnName, dataType, false))
+	pkgLogger.Infof("CH: Altering column for destinationID: %s, tableName: %s with query: %v", ch.Warehouse.Destination.ID, tableName, query)
+	_, err = ch.Db.Exec(query)
 	return
 }
 
@@ -1012,6 +1054,42 @@ func (ch *HandleT) LoadTable(tableName string) error {
 	return err
 }
 
+// LoadStreamingBatch inserts the rows of a streamed batch into the table in a single transaction.
+// Retried rows are deduplicated and users are merged on their id by the merge tree engines of the tables, like for uploads.
+func (ch *HandleT) LoadStreamingBatch(tableName string, tableSchema warehouseutils.TableSchemaT, rows []map[string]interface{}) error {
+	sortedColumnKeys := warehouseutils.SortColumnKeysFromColumnMap(tableSchema)
+	sortedColumnString := warehouseutils.DoubleQuoteAndJoinByComma(sortedColumnKeys)
+
+	txn, err := ch.Db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s beginning transaction: %w", ch.GetLogIdentifier(tableName), err)
+	}
+
+	sqlStatement := fmt.Sprintf(`INSERT INTO %q.%q (%v) VALUES (%s)`, ch.Namespace, tableName, sortedColumnString, generateArgumentString(len(sortedColumnKeys)))
+	stmt, err := txn.Prepare(sqlStatement)
+	if err != nil {
+		_ = txn.Rollback()
+		return fmt.Errorf("%s preparing statement: %w", ch.GetLogIdentifier(tableName), err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, row := range rows {
+		recordInterface := make([]interface{}, 0, len(sortedColumnKeys))
+		for _, columnName := range sortedColumnKeys {
+			value := warehouseutils.StreamingValueToString(row[columnName])
+			recordInterface = append(recordInterface, typecastDataFromType(value, tableSchema[columnName]))
+		}
+		if _, err = stmt.Exec(recordInterface...); err != nil {
+			_ = txn.Rollback()
+			return fmt.Errorf("%s inserting row: %w", ch.GetLogIdentifier(tableName), err)
+		}
+	}
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("%s committing transaction: %w", ch.GetLogIdentifier(tableName), err)
+	}
+	return nil
+}
+
 func (ch *HandleT) Cleanup() {
 	if ch.Db != nil {
 		_ = ch.Db.Close()
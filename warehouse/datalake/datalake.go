@@ -8,6 +8,7 @@ import (
 	"github.com/rudderlabs/rudder-server/utils/logger"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/warehouse/client"
+	"github.com/rudderlabs/rudder-server/warehouse/datalake/iceberg"
 	schemarepository "github.com/rudderlabs/rudder-server/warehouse/datalake/schema-repository"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 )
@@ -25,6 +26,8 @@ type HandleT struct {
 	SchemaRepository schemarepository.SchemaRepository
 	Warehouse        warehouseutils.Warehouse
 	Uploader         warehouseutils.UploaderI
+	// Iceberg is set when the destination commits its load files to iceberg tables
+	Iceberg *iceberg.Client
 }
 
 func (wh *HandleT) Setup(warehouse warehouseutils.Warehouse, uploader warehouseutils.UploaderI) (err error) {
@@ -32,6 +35,13 @@ func (wh *HandleT) Setup(warehouse warehouseutils.Warehouse, uploader warehouseu
 	wh.Uploader = uploader
 
 	wh.SchemaRepository, err = schemarepository.NewSchemaRepository(wh.Warehouse, wh.Uploader)
+	if err != nil {
+		return err
+	}
+
+	if iceberg.Enabled(wh.Warehouse) {
+		wh.Iceberg, err = iceberg.NewClient(wh.Warehouse, wh.Uploader.UseRudderStorage())
+	}
 
 	return err
 }
@@ -49,7 +59,10 @@ func (wh *HandleT) CreateSchema() (err error) {
 }
 
 func (wh *HandleT) CreateTable(tableName string, columnMap map[string]string) (err error) {
-	return wh.SchemaRepository.CreateTable(tableName, columnMap)
+	if err = wh.SchemaRepository.CreateTable(tableName, columnMap); err != nil {
+		return err
+	}
+	return wh.evolveIcebergTable(tableName, columnMap)
 }
 
 func (*HandleT) DropTable(_ string) (err error) {
@@ -57,16 +70,49 @@ func (*HandleT) DropTable(_ string) (err error) {
 }
 
 func (wh *HandleT) AddColumns(tableName string, columnsInfo []warehouseutils.ColumnInfo) (err error) {
-	return wh.SchemaRepository.AddColumns(tableName, columnsInfo)
+	if err = wh.SchemaRepository.AddColumns(tableName, columnsInfo); err != nil {
+		return err
+	}
+
+	columnMap := make(map[string]string, len(columnsInfo))
+	for _, columnInfo := range columnsInfo {
+		columnMap[columnInfo.Name] = columnInfo.Type
+	}
+	return wh.evolveIcebergTable(tableName, columnMap)
 }
 
 func (wh *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
-	return wh.SchemaRepository.AlterColumn(tableName, columnName, columnType)
+	if err = wh.SchemaRepository.AlterColumn(tableName, columnName, columnType); err != nil {
+		return err
+	}
+	return wh.evolveIcebergTable(tableName, map[string]string{columnName: columnType})
+}
+
+// evolveIcebergTable creates the iceberg table or adds the columns to it, if the destination uses iceberg.
+func (wh *HandleT) evolveIcebergTable(tableName string, columnMap map[string]string) error {
+	if wh.Iceberg == nil {
+		return nil
+	}
+	_, err := wh.Iceberg.EnsureTable(context.TODO(), tableName, columnMap)
+	return err
 }
 
 func (wh *HandleT) LoadTable(tableName string) error {
-	pkgLogger.Infof("Skipping load for table %s : %s is a datalake destination", tableName, wh.Warehouse.Destination.ID)
-	return nil
+	if wh.Iceberg == nil {
+		pkgLogger.Infof("Skipping load for table %s : %s is a datalake destination", tableName, wh.Warehouse.Destination.ID)
+		return nil
+	}
+	return wh.commitIcebergSnapshot(tableName)
+}
+
+// commitIcebergSnapshot commits the load files of the table in the current upload as a new iceberg snapshot.
+func (wh *HandleT) commitIcebergSnapshot(tableName string) error {
+	return wh.Iceberg.AppendLoadFiles(
+		context.TODO(),
+		tableName,
+		wh.Uploader.GetTableSchemaInUpload(tableName),
+		wh.Uploader.GetLoadFilesMetadata(warehouseutils.GetLoadFilesOptionsT{Table: tableName}),
+	)
 }
 
 func (*HandleT) DeleteBy([]string, warehouseutils.DeleteByParams) (err error) {
@@ -74,13 +120,19 @@ func (*HandleT) DeleteBy([]string, warehouseutils.DeleteByParams) (err error) {
 }
 
 func (wh *HandleT) LoadUserTables() map[string]error {
-	pkgLogger.Infof("Skipping load for user tables : %s is a datalake destination", wh.Warehouse.Destination.ID)
 	// return map with nil error entries for identifies and users(if any) tables
 	// this is so that they are marked as succeeded
 	errorMap := map[string]error{warehouseutils.IdentifiesTable: nil}
 	if len(wh.Uploader.GetTableSchemaInUpload(warehouseutils.UsersTable)) > 0 {
 		errorMap[warehouseutils.UsersTable] = nil
 	}
+	if wh.Iceberg == nil {
+		pkgLogger.Infof("Skipping load for user tables : %s is a datalake destination", wh.Warehouse.Destination.ID)
+		return errorMap
+	}
+	for tableName := range errorMap {
+		errorMap[tableName] = wh.commitIcebergSnapshot(tableName)
+	}
 	return errorMap
 }
 
@@ -0,0 +1,152 @@
+package iceberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var (
+	ErrNoSuchTable        = errors.New("iceberg table does not exist")
+	ErrTableAlreadyExists = errors.New("iceberg table already exists")
+	ErrCommitConflict     = errors.New("iceberg table was modified concurrently")
+)
+
+const (
+	FileCatalog = "file"
+	RESTCatalog = "rest"
+)
+
+// Catalog tracks the current metadata of iceberg tables.
+type Catalog interface {
+	// LoadTable returns the current metadata of the table or ErrNoSuchTable.
+	LoadTable(ctx context.Context, namespace, table string) (*TableMetadata, error)
+	// CreateTable creates an empty table at location or returns ErrTableAlreadyExists.
+	CreateTable(ctx context.Context, namespace, table, location string, schema Schema) (*TableMetadata, error)
+	// CommitTable atomically applies the changes on top of base or returns ErrCommitConflict if base is no longer current.
+	CommitTable(ctx context.Context, namespace, table string, base *TableMetadata, changes Changes) (*TableMetadata, error)
+}
+
+// fileCatalog keeps versioned metadata files and a version hint in the table's metadata folder,
+// following the layout of the hadoop catalog, so that tables can be read without any catalog service.
+//
+// Object storages don't offer conditional writes, hence commits are only safe as long as a table
+// has a single writer, which holds true since uploads of a destination are never processed concurrently.
+type fileCatalog struct {
+	store ObjectStore
+}
+
+// NewFileCatalog returns a catalog storing the table metadata next to the table data.
+func NewFileCatalog(store ObjectStore) Catalog {
+	return &fileCatalog{store: store}
+}
+
+func metadataFolder(namespace, table string) string {
+	return path.Join(warehouseutils.GetTablePathInObjectStorage(namespace, table), "metadata")
+}
+
+func versionHintKey(namespace, table string) string {
+	return path.Join(metadataFolder(namespace, table), "version-hint.text")
+}
+
+func metadataKey(namespace, table string, version int) string {
+	return path.Join(metadataFolder(namespace, table), fmt.Sprintf("v%d.metadata.json", version))
+}
+
+func (fc *fileCatalog) currentVersion(ctx context.Context, namespace, table string) (int, error) {
+	exists, err := fc.store.Exists(ctx, versionHintKey(namespace, table))
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, ErrNoSuchTable
+	}
+
+	hint, err := fc.store.Get(ctx, versionHintKey(namespace, table))
+	if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(strings.TrimSpace(string(hint)))
+	if err != nil {
+		return 0, fmt.Errorf("parsing version hint for table %s.%s: %w", namespace, table, err)
+	}
+	return version, nil
+}
+
+func (fc *fileCatalog) load(ctx context.Context, namespace, table string) (*TableMetadata, int, error) {
+	version, err := fc.currentVersion(ctx, namespace, table)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err := fc.store.Get(ctx, metadataKey(namespace, table, version))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var metadata TableMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, 0, fmt.Errorf("unmarshalling metadata for table %s.%s: %w", namespace, table, err)
+	}
+	return &metadata, version, nil
+}
+
+func (fc *fileCatalog) write(ctx context.Context, namespace, table string, version int, metadata *TableMetadata) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("marshalling metadata for table %s.%s: %w", namespace, table, err)
+	}
+	if err := fc.store.Put(ctx, metadataKey(namespace, table, version), data); err != nil {
+		return err
+	}
+	return fc.store.Put(ctx, versionHintKey(namespace, table), []byte(strconv.Itoa(version)))
+}
+
+func (fc *fileCatalog) LoadTable(ctx context.Context, namespace, table string) (*TableMetadata, error) {
+	metadata, _, err := fc.load(ctx, namespace, table)
+	return metadata, err
+}
+
+func (fc *fileCatalog) CreateTable(ctx context.Context, namespace, table, location string, schema Schema) (*TableMetadata, error) {
+	_, err := fc.currentVersion(ctx, namespace, table)
+	if err == nil {
+		return nil, ErrTableAlreadyExists
+	}
+	if !errors.Is(err, ErrNoSuchTable) {
+		return nil, err
+	}
+
+	metadata := NewTableMetadata(location, schema, timeNowMs())
+	if err := fc.write(ctx, namespace, table, 1, metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+func (fc *fileCatalog) CommitTable(ctx context.Context, namespace, table string, base *TableMetadata, changes Changes) (*TableMetadata, error) {
+	current, version, err := fc.load(ctx, namespace, table)
+	if err != nil {
+		return nil, err
+	}
+	// every commit appends the previous metadata file to the metadata log
+	if current.TableUUID != base.TableUUID || len(current.MetadataLog) != len(base.MetadataLog) {
+		return nil, ErrCommitConflict
+	}
+
+	updated := current.Apply(changes)
+	updated.MetadataLog = append(updated.MetadataLog, MetadataLogEntry{
+		MetadataFile: fc.store.URI(metadataKey(namespace, table, version)),
+		TimestampMs:  current.LastUpdatedMs,
+	})
+
+	if err := fc.write(ctx, namespace, table, version+1, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
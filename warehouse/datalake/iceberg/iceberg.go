@@ -0,0 +1,315 @@
+// Package iceberg commits the parquet load files of datalake destinations as snapshots of Apache Iceberg tables.
+//
+// Load files are never rewritten: every upload appends a snapshot referencing the load files of a table,
+// while schema changes of the upload are mapped to iceberg schema evolution.
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// destination config keys
+const (
+	UseIcebergConfig     = "useIceberg"
+	CatalogTypeConfig    = "icebergCatalogType"
+	CatalogURIConfig     = "icebergCatalogURI"
+	CatalogPrefixConfig  = "icebergCatalogPrefix"
+	CatalogTokenConfig   = "icebergCatalogToken"
+	commitRetriesDefault = 3
+)
+
+var pkgLogger logger.Logger
+
+func init() {
+	pkgLogger = logger.NewLogger().Child("warehouse").Child("datalake").Child("iceberg")
+}
+
+// Enabled reports whether the destination is configured to write iceberg tables.
+func Enabled(warehouse warehouseutils.Warehouse) bool {
+	return warehouseutils.GetConfigValueBoolString(UseIcebergConfig, warehouse) == "true"
+}
+
+// Client manages the iceberg tables of a datalake destination namespace.
+type Client struct {
+	Catalog       Catalog
+	Store         ObjectStore
+	Namespace     string
+	CommitRetries int
+}
+
+// NewClient returns a client for the destination, using the catalog type configured on it.
+func NewClient(warehouse warehouseutils.Warehouse, useRudderStorage bool) (*Client, error) {
+	store, err := NewObjectStore(warehouse, useRudderStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog Catalog
+	switch catalogType := warehouseutils.GetConfigValue(CatalogTypeConfig, warehouse); catalogType {
+	case "", FileCatalog:
+		catalog = NewFileCatalog(store)
+	case RESTCatalog:
+		catalogURI := warehouseutils.GetConfigValue(CatalogURIConfig, warehouse)
+		if catalogURI == "" {
+			return nil, fmt.Errorf("%s is required for the %s iceberg catalog", CatalogURIConfig, RESTCatalog)
+		}
+		catalog = NewRESTCatalog(
+			catalogURI,
+			warehouseutils.GetConfigValue(CatalogPrefixConfig, warehouse),
+			warehouseutils.GetConfigValue(CatalogTokenConfig, warehouse),
+			&http.Client{Timeout: config.GetDuration("Warehouse.datalake.icebergCatalogTimeout", 30, time.Second)},
+		)
+	default:
+		return nil, fmt.Errorf("unsupported iceberg catalog type: %s", catalogType)
+	}
+
+	return &Client{
+		Catalog:       catalog,
+		Store:         store,
+		Namespace:     warehouse.Namespace,
+		CommitRetries: config.GetInt("Warehouse.datalake.icebergCommitRetries", commitRetriesDefault),
+	}, nil
+}
+
+func (c *Client) tableLocation(tableName string) string {
+	return c.Store.URI(warehouseutils.GetTablePathInObjectStorage(c.Namespace, tableName))
+}
+
+// FetchSchema returns the current schema of the provided tables, skipping the ones which don't exist yet.
+func (c *Client) FetchSchema(ctx context.Context, tableNames []string) (warehouseutils.SchemaT, error) {
+	schema := warehouseutils.SchemaT{}
+	for _, tableName := range tableNames {
+		metadata, err := c.Catalog.LoadTable(ctx, c.Namespace, tableName)
+		if errors.Is(err, ErrNoSuchTable) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		schema[tableName] = metadata.RudderSchema()
+	}
+	return schema, nil
+}
+
+// EnsureTable creates the table if it doesn't exist and evolves its schema to contain all the provided columns.
+func (c *Client) EnsureTable(ctx context.Context, tableName string, columnMap map[string]string) (*TableMetadata, error) {
+	var metadata *TableMetadata
+	err := c.withRetries(func() (err error) {
+		metadata, err = c.ensureTable(ctx, tableName, columnMap)
+		return err
+	})
+	return metadata, err
+}
+
+func (c *Client) ensureTable(ctx context.Context, tableName string, columnMap map[string]string) (*TableMetadata, error) {
+	metadata, err := c.Catalog.LoadTable(ctx, c.Namespace, tableName)
+	if errors.Is(err, ErrNoSuchTable) {
+		pkgLogger.Infof("Creating iceberg table %s.%s at %s", c.Namespace, tableName, c.tableLocation(tableName))
+		metadata, err = c.Catalog.CreateTable(ctx, c.Namespace, tableName, c.tableLocation(tableName), NewSchema(columnMap))
+		if errors.Is(err, ErrTableAlreadyExists) {
+			return nil, ErrCommitConflict
+		}
+		return metadata, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	evolvedSchema := metadata.EvolveSchema(columnMap)
+	if evolvedSchema == nil {
+		return metadata, nil
+	}
+
+	pkgLogger.Infof("Evolving iceberg table %s.%s to schema %d", c.Namespace, tableName, evolvedSchema.SchemaID)
+	return c.Catalog.CommitTable(ctx, c.Namespace, tableName, metadata, Changes{Schema: evolvedSchema})
+}
+
+// AppendLoadFiles commits the load files as a new snapshot of the table.
+// Committing the same set of load files more than once, e.g. on upload retries, is a no-op.
+func (c *Client) AppendLoadFiles(ctx context.Context, tableName string, columnMap map[string]string, loadFiles []warehouseutils.LoadFileT) error {
+	if len(loadFiles) == 0 {
+		return nil
+	}
+
+	dataFiles := make([]DataFile, 0, len(loadFiles))
+	locations := make([]string, 0, len(loadFiles))
+	for _, loadFile := range loadFiles {
+		filePath, err := c.Store.URIFromLocation(loadFile.Location)
+		if err != nil {
+			return err
+		}
+		dataFiles = append(dataFiles, DataFile{
+			Path:          filePath,
+			RecordCount:   gjson.GetBytes(loadFile.Metadata, "total_rows").Int(),
+			FileSizeBytes: gjson.GetBytes(loadFile.Metadata, "content_length").Int(),
+		})
+		locations = append(locations, loadFile.Location)
+	}
+	loadFilesHash := hashLocations(locations)
+
+	return c.withRetries(func() error {
+		metadata, err := c.ensureTable(ctx, tableName, columnMap)
+		if err != nil {
+			return err
+		}
+		if metadata.HasLoadFiles(loadFilesHash) {
+			pkgLogger.Infof("Skipping iceberg commit for table %s.%s: load files already committed", c.Namespace, tableName)
+			return nil
+		}
+
+		snapshot, err := c.writeSnapshot(ctx, tableName, metadata, dataFiles)
+		if err != nil {
+			return err
+		}
+		snapshot.Summary[summaryLoadFilesHash] = loadFilesHash
+
+		_, err = c.Catalog.CommitTable(ctx, c.Namespace, tableName, metadata, Changes{Snapshot: snapshot})
+		if err == nil {
+			pkgLogger.Infof("Committed snapshot %d with %d data files to iceberg table %s.%s", snapshot.SnapshotID, len(dataFiles), c.Namespace, tableName)
+		}
+		return err
+	})
+}
+
+// writeSnapshot writes the manifest for the data files and a manifest list carrying over the manifests of the current snapshot.
+func (c *Client) writeSnapshot(ctx context.Context, tableName string, metadata *TableMetadata, dataFiles []DataFile) (*Snapshot, error) {
+	folder := metadataFolder(c.Namespace, tableName)
+	snapshotID := newSnapshotID()
+	sequenceNumber := metadata.LastSequenceNumber + 1
+
+	var manifestBuf bytes.Buffer
+	if err := writeManifest(&manifestBuf, metadata.CurrentSchema(), snapshotID, dataFiles); err != nil {
+		return nil, err
+	}
+	manifestKey := path.Join(folder, fmt.Sprintf("%s-m0.avro", misc.FastUUID().String()))
+	if err := c.Store.Put(ctx, manifestKey, manifestBuf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	var addedRows, addedSize int64
+	for _, dataFile := range dataFiles {
+		addedRows += dataFile.RecordCount
+		addedSize += dataFile.FileSizeBytes
+	}
+
+	manifests := []ManifestFile{{
+		Path:              c.Store.URI(manifestKey),
+		Length:            int64(manifestBuf.Len()),
+		Content:           manifestContentData,
+		SequenceNumber:    sequenceNumber,
+		MinSequenceNumber: sequenceNumber,
+		AddedSnapshotID:   snapshotID,
+		AddedFilesCount:   int32(len(dataFiles)),
+		AddedRowsCount:    addedRows,
+	}}
+
+	snapshot := &Snapshot{
+		SnapshotID:     snapshotID,
+		SequenceNumber: sequenceNumber,
+		TimestampMs:    timeNowMs(),
+		SchemaID:       metadata.CurrentSchemaID,
+		Summary: map[string]string{
+			summaryOperation:    operationAppend,
+			summaryAddedFiles:   strconv.Itoa(len(dataFiles)),
+			summaryAddedRecords: strconv.FormatInt(addedRows, 10),
+			summaryAddedSize:    strconv.FormatInt(addedSize, 10),
+		},
+	}
+
+	totalFiles, totalRecords := int64(len(dataFiles)), addedRows
+	if parent := metadata.CurrentSnapshot(); parent != nil {
+		snapshot.ParentSnapshotID = &parent.SnapshotID
+
+		manifestListKey, err := c.keyFromURI(parent.ManifestList)
+		if err != nil {
+			return nil, err
+		}
+		data, err := c.Store.Get(ctx, manifestListKey)
+		if err != nil {
+			return nil, err
+		}
+		parentManifests, err := readManifestList(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, parentManifests...)
+
+		totalFiles += summaryValue(parent.Summary, summaryTotalFiles)
+		totalRecords += summaryValue(parent.Summary, summaryTotalRecords)
+	}
+	snapshot.Summary[summaryTotalFiles] = strconv.FormatInt(totalFiles, 10)
+	snapshot.Summary[summaryTotalRecords] = strconv.FormatInt(totalRecords, 10)
+
+	var manifestListBuf bytes.Buffer
+	if err := writeManifestList(&manifestListBuf, *snapshot, manifests); err != nil {
+		return nil, err
+	}
+	manifestListKey := path.Join(folder, fmt.Sprintf("snap-%d-1-%s.avro", snapshotID, misc.FastUUID().String()))
+	if err := c.Store.Put(ctx, manifestListKey, manifestListBuf.Bytes()); err != nil {
+		return nil, err
+	}
+	snapshot.ManifestList = c.Store.URI(manifestListKey)
+
+	return snapshot, nil
+}
+
+// keyFromURI returns the object key, relative to the configured prefix, of a file inside the destination's bucket.
+func (c *Client) keyFromURI(uri string) (string, error) {
+	root := strings.TrimSuffix(c.Store.URI(""), "/") + "/"
+	if !strings.HasPrefix(uri, root) {
+		return "", fmt.Errorf("iceberg file %s is outside of %s", uri, root)
+	}
+	return strings.TrimPrefix(uri, root), nil
+}
+
+func (c *Client) withRetries(fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= c.CommitRetries; attempt++ {
+		if err = fn(); !errors.Is(err, ErrCommitConflict) {
+			return err
+		}
+		pkgLogger.Warnf("Retrying iceberg commit for namespace %s after conflict, attempt: %d", c.Namespace, attempt+1)
+	}
+	return err
+}
+
+func hashLocations(locations []string) string {
+	sort.Strings(locations)
+	hash := sha256.Sum256([]byte(strings.Join(locations, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+func summaryValue(summary map[string]string, key string) int64 {
+	value, _ := strconv.ParseInt(summary[key], 10, 64)
+	return value
+}
+
+// newSnapshotID returns a random positive snapshot id.
+func newSnapshotID() int64 {
+	id := misc.FastUUID()
+	return int64(binary.BigEndian.Uint64(id[:8]) >> 1)
+}
+
+func timeNowMs() int64 {
+	return timeutil.Now().UnixMilli()
+}
@@ -0,0 +1,167 @@
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{objects: map[string][]byte{}}
+}
+
+func (m *memoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[strings.TrimPrefix(key, "/")]
+	if !ok {
+		return nil, fmt.Errorf("key not found: %s", key)
+	}
+	return data, nil
+}
+
+func (m *memoryStore) Put(_ context.Context, key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[strings.TrimPrefix(key, "/")] = data
+	return nil
+}
+
+func (m *memoryStore) Exists(_ context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.objects[strings.TrimPrefix(key, "/")]
+	return ok, nil
+}
+
+func (*memoryStore) URI(key string) string {
+	return "s3://bucket/" + key
+}
+
+func (*memoryStore) URIFromLocation(location string) (string, error) {
+	return "s3://bucket/" + strings.TrimPrefix(location, "https://bucket.s3.amazonaws.com/"), nil
+}
+
+func loadFile(location string, rows, size int) warehouseutils.LoadFileT {
+	return warehouseutils.LoadFileT{
+		Location: "https://bucket.s3.amazonaws.com/" + location,
+		Metadata: json.RawMessage(fmt.Sprintf(`{"content_length": %d, "total_rows": %d}`, size, rows)),
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	client := &Client{
+		Catalog:       NewFileCatalog(store),
+		Store:         store,
+		Namespace:     "namespace",
+		CommitRetries: commitRetriesDefault,
+	}
+
+	columns := map[string]string{"id": "string", "received_at": "datetime"}
+
+	t.Run("create table", func(t *testing.T) {
+		metadata, err := client.EnsureTable(ctx, "tracks", columns)
+		require.NoError(t, err)
+		require.Equal(t, "s3://bucket/rudder-datalake/namespace/tracks", metadata.Location)
+		require.Equal(t, "1", string(store.objects["rudder-datalake/namespace/tracks/metadata/version-hint.text"]))
+
+		schema, err := client.FetchSchema(ctx, []string{"tracks", "pages"})
+		require.NoError(t, err)
+		require.Equal(t, warehouseutils.SchemaT{"tracks": columns}, schema)
+	})
+
+	t.Run("existing columns don't create a new version", func(t *testing.T) {
+		_, err := client.EnsureTable(ctx, "tracks", map[string]string{"id": "string"})
+		require.NoError(t, err)
+		require.Equal(t, "1", string(store.objects["rudder-datalake/namespace/tracks/metadata/version-hint.text"]))
+	})
+
+	t.Run("append load files", func(t *testing.T) {
+		err := client.AppendLoadFiles(ctx, "tracks", columns, []warehouseutils.LoadFileT{
+			loadFile("rudder-datalake/namespace/tracks/2022/11/01/00/a.parquet", 10, 100),
+			loadFile("rudder-datalake/namespace/tracks/2022/11/01/00/b.parquet", 5, 50),
+		})
+		require.NoError(t, err)
+
+		metadata, err := client.Catalog.LoadTable(ctx, "namespace", "tracks")
+		require.NoError(t, err)
+		snapshot := metadata.CurrentSnapshot()
+		require.NotNil(t, snapshot)
+		require.Nil(t, snapshot.ParentSnapshotID)
+		require.Equal(t, "15", snapshot.Summary[summaryTotalRecords])
+		require.Equal(t, "2", snapshot.Summary[summaryTotalFiles])
+		require.Len(t, metadata.MetadataLog, 1)
+
+		manifestListKey, err := client.keyFromURI(snapshot.ManifestList)
+		require.NoError(t, err)
+		manifests, err := readManifestList(bytes.NewReader(store.objects[manifestListKey]))
+		require.NoError(t, err)
+		require.Len(t, manifests, 1)
+		require.EqualValues(t, 2, manifests[0].AddedFilesCount)
+		require.EqualValues(t, 15, manifests[0].AddedRowsCount)
+	})
+
+	t.Run("committing the same load files again is a no-op", func(t *testing.T) {
+		err := client.AppendLoadFiles(ctx, "tracks", columns, []warehouseutils.LoadFileT{
+			loadFile("rudder-datalake/namespace/tracks/2022/11/01/00/b.parquet", 5, 50),
+			loadFile("rudder-datalake/namespace/tracks/2022/11/01/00/a.parquet", 10, 100),
+		})
+		require.NoError(t, err)
+
+		metadata, err := client.Catalog.LoadTable(ctx, "namespace", "tracks")
+		require.NoError(t, err)
+		require.Len(t, metadata.Snapshots, 1)
+	})
+
+	t.Run("append with schema evolution", func(t *testing.T) {
+		err := client.AppendLoadFiles(ctx, "tracks", map[string]string{"id": "string", "revenue": "float"}, []warehouseutils.LoadFileT{
+			loadFile("rudder-datalake/namespace/tracks/2022/11/01/01/c.parquet", 7, 70),
+		})
+		require.NoError(t, err)
+
+		metadata, err := client.Catalog.LoadTable(ctx, "namespace", "tracks")
+		require.NoError(t, err)
+		require.Len(t, metadata.Snapshots, 2)
+		require.Equal(t, 1, metadata.CurrentSchemaID)
+
+		snapshot := metadata.CurrentSnapshot()
+		require.Equal(t, metadata.Snapshots[0].SnapshotID, *snapshot.ParentSnapshotID)
+		require.EqualValues(t, 2, snapshot.SequenceNumber)
+		require.Equal(t, 1, snapshot.SchemaID)
+		require.Equal(t, "22", snapshot.Summary[summaryTotalRecords])
+
+		manifestListKey, err := client.keyFromURI(snapshot.ManifestList)
+		require.NoError(t, err)
+		manifests, err := readManifestList(bytes.NewReader(store.objects[manifestListKey]))
+		require.NoError(t, err)
+		require.Len(t, manifests, 2)
+		require.EqualValues(t, 2, manifests[0].SequenceNumber)
+		require.EqualValues(t, 1, manifests[1].SequenceNumber)
+	})
+
+	t.Run("commit conflict", func(t *testing.T) {
+		base, err := client.Catalog.LoadTable(ctx, "namespace", "tracks")
+		require.NoError(t, err)
+
+		_, err = client.EnsureTable(ctx, "tracks", map[string]string{"name": "string"})
+		require.NoError(t, err)
+
+		_, err = client.Catalog.CommitTable(ctx, "namespace", "tracks", base, Changes{Schema: base.EvolveSchema(map[string]string{"other": "string"})})
+		require.ErrorIs(t, err, ErrCommitConflict)
+	})
+}
@@ -0,0 +1,241 @@
+package iceberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/linkedin/goavro"
+)
+
+const (
+	manifestEntryStatusAdded = 1
+
+	manifestContentData = 0
+	dataFileContentData = 0
+
+	fileFormatParquet = "PARQUET"
+)
+
+// manifestEntrySchemaTemplate is the avro schema of a v2 manifest file for an unpartitioned table.
+// The field-id attributes are required by iceberg readers to project the manifest.
+const manifestEntrySchemaTemplate = `{
+	"type": "record",
+	"name": "manifest_entry",
+	"fields": [
+		{"name": "status", "type": "int", "field-id": 0},
+		{"name": "snapshot_id", "type": ["null", "long"], "default": null, "field-id": 1},
+		{"name": "sequence_number", "type": ["null", "long"], "default": null, "field-id": 3},
+		{"name": "file_sequence_number", "type": ["null", "long"], "default": null, "field-id": 4},
+		{"name": "data_file", "field-id": 2, "type": {
+			"type": "record",
+			"name": "r2",
+			"fields": [
+				{"name": "content", "type": "int", "field-id": 134},
+				{"name": "file_path", "type": "string", "field-id": 100},
+				{"name": "file_format", "type": "string", "field-id": 101},
+				%s
+				{"name": "record_count", "type": "long", "field-id": 103},
+				{"name": "file_size_in_bytes", "type": "long", "field-id": 104}
+			]
+		}}
+	]
+}`
+
+const partitionFieldSchema = `{"name": "partition", "field-id": 102, "type": {"type": "record", "name": "r102", "fields": []}},`
+
+var (
+	manifestEntrySchema = fmt.Sprintf(manifestEntrySchemaTemplate, partitionFieldSchema)
+	// manifestEntryCodec encodes manifest entries without the empty partition record, see writeOCF.
+	manifestEntryCodec = mustCodec(fmt.Sprintf(manifestEntrySchemaTemplate, ""))
+)
+
+// manifestFileSchema is the avro schema of a v2 manifest list.
+const manifestFileSchema = `{
+	"type": "record",
+	"name": "manifest_file",
+	"fields": [
+		{"name": "manifest_path", "type": "string", "field-id": 500},
+		{"name": "manifest_length", "type": "long", "field-id": 501},
+		{"name": "partition_spec_id", "type": "int", "field-id": 502},
+		{"name": "content", "type": "int", "field-id": 517},
+		{"name": "sequence_number", "type": "long", "field-id": 515},
+		{"name": "min_sequence_number", "type": "long", "field-id": 516},
+		{"name": "added_snapshot_id", "type": "long", "field-id": 503},
+		{"name": "added_files_count", "type": "int", "field-id": 504},
+		{"name": "existing_files_count", "type": "int", "field-id": 505},
+		{"name": "deleted_files_count", "type": "int", "field-id": 506},
+		{"name": "added_rows_count", "type": "long", "field-id": 512},
+		{"name": "existing_rows_count", "type": "long", "field-id": 513},
+		{"name": "deleted_rows_count", "type": "long", "field-id": 514}
+	]
+}`
+
+// DataFile is a parquet load file to be added to the table.
+type DataFile struct {
+	Path          string
+	RecordCount   int64
+	FileSizeBytes int64
+}
+
+// ManifestFile is an entry of a snapshot's manifest list.
+type ManifestFile struct {
+	Path               string
+	Length             int64
+	PartitionSpecID    int32
+	Content            int32
+	SequenceNumber     int64
+	MinSequenceNumber  int64
+	AddedSnapshotID    int64
+	AddedFilesCount    int32
+	ExistingFilesCount int32
+	DeletedFilesCount  int32
+	AddedRowsCount     int64
+	ExistingRowsCount  int64
+	DeletedRowsCount   int64
+}
+
+// writeManifest writes a manifest file adding the data files in the provided snapshot.
+// Sequence numbers are left null so that they are inherited from the manifest list entry.
+func writeManifest(w io.Writer, schema Schema, snapshotID int64, dataFiles []DataFile) error {
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("marshalling schema: %w", err)
+	}
+
+	entries := make([]map[string]interface{}, 0, len(dataFiles))
+	for _, dataFile := range dataFiles {
+		entries = append(entries, map[string]interface{}{
+			"status":               int32(manifestEntryStatusAdded),
+			"snapshot_id":          goavro.Union("long", snapshotID),
+			"sequence_number":      goavro.Union("null", nil),
+			"file_sequence_number": goavro.Union("null", nil),
+			"data_file": map[string]interface{}{
+				"content":            int32(dataFileContentData),
+				"file_path":          dataFile.Path,
+				"file_format":        fileFormatParquet,
+				"record_count":       dataFile.RecordCount,
+				"file_size_in_bytes": dataFile.FileSizeBytes,
+			},
+		})
+	}
+
+	err = writeOCF(w, manifestEntrySchema, manifestEntryCodec, map[string][]byte{
+		"schema":            schemaJSON,
+		"schema-id":         []byte(strconv.Itoa(schema.SchemaID)),
+		"partition-spec":    []byte("[]"),
+		"partition-spec-id": []byte("0"),
+		"format-version":    []byte(strconv.Itoa(formatVersion)),
+		"content":           []byte("data"),
+	}, entries)
+	if err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	return nil
+}
+
+// writeManifestList writes the manifest list of a snapshot.
+func writeManifestList(w io.Writer, snapshot Snapshot, manifests []ManifestFile) error {
+	parentSnapshotID := "null"
+	if snapshot.ParentSnapshotID != nil {
+		parentSnapshotID = strconv.FormatInt(*snapshot.ParentSnapshotID, 10)
+	}
+
+	ocfWriter, err := goavro.NewOCFWriter(goavro.OCFConfig{
+		W:      w,
+		Schema: manifestFileSchema,
+		MetaData: map[string][]byte{
+			"snapshot-id":        []byte(strconv.FormatInt(snapshot.SnapshotID, 10)),
+			"parent-snapshot-id": []byte(parentSnapshotID),
+			"sequence-number":    []byte(strconv.FormatInt(snapshot.SequenceNumber, 10)),
+			"format-version":     []byte(strconv.Itoa(formatVersion)),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("creating manifest list writer: %w", err)
+	}
+
+	entries := make([]interface{}, 0, len(manifests))
+	for _, manifest := range manifests {
+		entries = append(entries, map[string]interface{}{
+			"manifest_path":        manifest.Path,
+			"manifest_length":      manifest.Length,
+			"partition_spec_id":    manifest.PartitionSpecID,
+			"content":              manifest.Content,
+			"sequence_number":      manifest.SequenceNumber,
+			"min_sequence_number":  manifest.MinSequenceNumber,
+			"added_snapshot_id":    manifest.AddedSnapshotID,
+			"added_files_count":    manifest.AddedFilesCount,
+			"existing_files_count": manifest.ExistingFilesCount,
+			"deleted_files_count":  manifest.DeletedFilesCount,
+			"added_rows_count":     manifest.AddedRowsCount,
+			"existing_rows_count":  manifest.ExistingRowsCount,
+			"deleted_rows_count":   manifest.DeletedRowsCount,
+		})
+	}
+
+	if err := ocfWriter.Append(entries); err != nil {
+		return fmt.Errorf("appending manifest list entries: %w", err)
+	}
+	return nil
+}
+
+// readManifestList reads the manifests referenced by a snapshot's manifest list.
+func readManifestList(r io.Reader) ([]ManifestFile, error) {
+	ocfReader, err := goavro.NewOCFReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("creating manifest list reader: %w", err)
+	}
+
+	var manifests []ManifestFile
+	for ocfReader.Scan() {
+		datum, err := ocfReader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("reading manifest list entry: %w", err)
+		}
+		record, ok := datum.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected manifest list entry: %T", datum)
+		}
+		manifests = append(manifests, ManifestFile{
+			Path:               stringValue(record["manifest_path"]),
+			Length:             longValue(record["manifest_length"]),
+			PartitionSpecID:    int32(longValue(record["partition_spec_id"])),
+			Content:            int32(longValue(record["content"])),
+			SequenceNumber:     longValue(record["sequence_number"]),
+			MinSequenceNumber:  longValue(record["min_sequence_number"]),
+			AddedSnapshotID:    longValue(record["added_snapshot_id"]),
+			AddedFilesCount:    int32(longValue(record["added_files_count"])),
+			ExistingFilesCount: int32(longValue(record["existing_files_count"])),
+			DeletedFilesCount:  int32(longValue(record["deleted_files_count"])),
+			AddedRowsCount:     longValue(record["added_rows_count"]),
+			ExistingRowsCount:  longValue(record["existing_rows_count"]),
+			DeletedRowsCount:   longValue(record["deleted_rows_count"]),
+		})
+	}
+	if err := ocfReader.Err(); err != nil {
+		return nil, fmt.Errorf("scanning manifest list: %w", err)
+	}
+	return manifests, nil
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+// longValue returns the numeric value of an avro int or long, also unwrapping optional unions.
+func longValue(v interface{}) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case map[string]interface{}:
+		for _, inner := range n {
+			return longValue(inner)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+package iceberg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/linkedin/goavro"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteManifest(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeManifest(&buf, NewSchema(map[string]string{"id": "string"}), 42, []DataFile{
+		{Path: "s3://bucket/a.parquet", RecordCount: 10, FileSizeBytes: 1024},
+		{Path: "s3://bucket/b.parquet", RecordCount: 20, FileSizeBytes: 2048},
+	})
+	require.NoError(t, err)
+
+	metadata, entries := readOCF(t, buf.Bytes(), manifestEntryCodec)
+	require.Equal(t, manifestEntrySchema, string(metadata["avro.schema"]))
+	require.Equal(t, "data", string(metadata["content"]))
+	require.Equal(t, "2", string(metadata["format-version"]))
+	require.Contains(t, string(metadata["schema"]), `"name":"id"`)
+
+	var paths []string
+	for _, entry := range entries {
+		require.EqualValues(t, manifestEntryStatusAdded, entry["status"])
+		require.EqualValues(t, 42, longValue(entry["snapshot_id"]))
+		require.Nil(t, entry["sequence_number"])
+
+		dataFile := entry["data_file"].(map[string]interface{})
+		require.Equal(t, fileFormatParquet, dataFile["file_format"])
+		paths = append(paths, dataFile["file_path"].(string))
+	}
+	require.Equal(t, []string{"s3://bucket/a.parquet", "s3://bucket/b.parquet"}, paths)
+}
+
+// readOCF decodes a single block object container file written by writeOCF.
+func readOCF(t *testing.T, data []byte, codec *goavro.Codec) (map[string][]byte, []map[string]interface{}) {
+	t.Helper()
+
+	require.True(t, bytes.HasPrefix(data, ocfMagic))
+	data = data[len(ocfMagic):]
+
+	header, data, err := ocfMetadataCodec.NativeFromBinary(data)
+	require.NoError(t, err)
+	metadata := make(map[string][]byte)
+	for key, value := range header.(map[string]interface{}) {
+		metadata[key] = value.([]byte)
+	}
+	syncMarker, data := data[:16], data[16:]
+
+	count, data, err := ocfLongCodec.NativeFromBinary(data)
+	require.NoError(t, err)
+	_, data, err = ocfLongCodec.NativeFromBinary(data)
+	require.NoError(t, err)
+
+	var records []map[string]interface{}
+	for i := int64(0); i < count.(int64); i++ {
+		var record interface{}
+		record, data, err = codec.NativeFromBinary(data)
+		require.NoError(t, err)
+		records = append(records, record.(map[string]interface{}))
+	}
+	require.Equal(t, syncMarker, data)
+	return metadata, records
+}
+
+func TestManifestList(t *testing.T) {
+	parentSnapshotID := int64(1)
+	manifests := []ManifestFile{
+		{
+			Path:              "s3://bucket/table/metadata/2-m0.avro",
+			Length:            100,
+			SequenceNumber:    2,
+			MinSequenceNumber: 2,
+			AddedSnapshotID:   2,
+			AddedFilesCount:   3,
+			AddedRowsCount:    30,
+		},
+		{
+			Path:              "s3://bucket/table/metadata/1-m0.avro",
+			Length:            200,
+			SequenceNumber:    1,
+			MinSequenceNumber: 1,
+			AddedSnapshotID:   1,
+			AddedFilesCount:   1,
+			AddedRowsCount:    10,
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeManifestList(&buf, Snapshot{SnapshotID: 2, ParentSnapshotID: &parentSnapshotID, SequenceNumber: 2}, manifests)
+	require.NoError(t, err)
+
+	read, err := readManifestList(&buf)
+	require.NoError(t, err)
+	require.Equal(t, manifests, read)
+}
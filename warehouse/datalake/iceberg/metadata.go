@@ -0,0 +1,355 @@
+package iceberg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+const (
+	formatVersion = 2
+
+	mainBranch = "main"
+
+	// nameMappingProperty lets readers resolve parquet columns by name, since load files are written without field ids.
+	nameMappingProperty = "schema.name-mapping.default"
+
+	summaryOperation     = "operation"
+	summaryLoadFilesHash = "rudder.load-files-hash"
+	summaryAddedFiles    = "added-data-files"
+	summaryAddedRecords  = "added-records"
+	summaryAddedSize     = "added-files-size"
+	summaryTotalFiles    = "total-data-files"
+	summaryTotalRecords  = "total-records"
+
+	operationAppend = "append"
+)
+
+var rudderDataTypesMapToIceberg = map[string]string{
+	"boolean":  "boolean",
+	"int":      "long",
+	"bigint":   "long",
+	"float":    "double",
+	"string":   "string",
+	"text":     "string",
+	"json":     "string",
+	"datetime": "timestamptz",
+}
+
+var icebergDataTypesMapToRudder = map[string]string{
+	"boolean":     "boolean",
+	"int":         "int",
+	"long":        "int",
+	"float":       "float",
+	"double":      "float",
+	"string":      "string",
+	"timestamptz": "datetime",
+	"timestamp":   "datetime",
+}
+
+// NestedField is a column of an iceberg schema.
+type NestedField struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Type     string `json:"type"`
+}
+
+// Schema is an iceberg struct schema identified by its schema-id.
+type Schema struct {
+	Type     string        `json:"type"`
+	SchemaID int           `json:"schema-id"`
+	Fields   []NestedField `json:"fields"`
+}
+
+type PartitionSpec struct {
+	SpecID int               `json:"spec-id"`
+	Fields []json.RawMessage `json:"fields"`
+}
+
+type SortOrder struct {
+	OrderID int               `json:"order-id"`
+	Fields  []json.RawMessage `json:"fields"`
+}
+
+type Snapshot struct {
+	SnapshotID       int64             `json:"snapshot-id"`
+	ParentSnapshotID *int64            `json:"parent-snapshot-id,omitempty"`
+	SequenceNumber   int64             `json:"sequence-number"`
+	TimestampMs      int64             `json:"timestamp-ms"`
+	ManifestList     string            `json:"manifest-list"`
+	Summary          map[string]string `json:"summary"`
+	SchemaID         int               `json:"schema-id"`
+}
+
+type SnapshotRef struct {
+	SnapshotID int64  `json:"snapshot-id"`
+	Type       string `json:"type"`
+}
+
+type SnapshotLogEntry struct {
+	SnapshotID  int64 `json:"snapshot-id"`
+	TimestampMs int64 `json:"timestamp-ms"`
+}
+
+type MetadataLogEntry struct {
+	MetadataFile string `json:"metadata-file"`
+	TimestampMs  int64  `json:"timestamp-ms"`
+}
+
+// TableMetadata is the iceberg table metadata file as defined by the table spec (format version 2).
+type TableMetadata struct {
+	FormatVersion      int                    `json:"format-version"`
+	TableUUID          string                 `json:"table-uuid"`
+	Location           string                 `json:"location"`
+	LastSequenceNumber int64                  `json:"last-sequence-number"`
+	LastUpdatedMs      int64                  `json:"last-updated-ms"`
+	LastColumnID       int                    `json:"last-column-id"`
+	CurrentSchemaID    int                    `json:"current-schema-id"`
+	Schemas            []Schema               `json:"schemas"`
+	DefaultSpecID      int                    `json:"default-spec-id"`
+	PartitionSpecs     []PartitionSpec        `json:"partition-specs"`
+	LastPartitionID    int                    `json:"last-partition-id"`
+	DefaultSortOrderID int                    `json:"default-sort-order-id"`
+	SortOrders         []SortOrder            `json:"sort-orders"`
+	Properties         map[string]string      `json:"properties"`
+	CurrentSnapshotID  *int64                 `json:"current-snapshot-id,omitempty"`
+	Snapshots          []Snapshot             `json:"snapshots"`
+	Refs               map[string]SnapshotRef `json:"refs"`
+	SnapshotLog        []SnapshotLogEntry     `json:"snapshot-log"`
+	MetadataLog        []MetadataLogEntry     `json:"metadata-log"`
+}
+
+// Changes are the modifications to be committed on top of a base table metadata.
+type Changes struct {
+	Schema   *Schema
+	Snapshot *Snapshot
+}
+
+// NewSchema returns the initial schema for the provided rudder column map.
+// Field ids are assigned in column name order so that the schema is deterministic.
+func NewSchema(columnMap map[string]string) Schema {
+	schema := Schema{Type: "struct", Fields: []NestedField{}}
+	for id, columnName := range sortedColumns(columnMap) {
+		schema.Fields = append(schema.Fields, NestedField{
+			ID:   id + 1,
+			Name: columnName,
+			Type: icebergType(columnMap[columnName]),
+		})
+	}
+	return schema
+}
+
+// NewTableMetadata returns the metadata for an empty, unpartitioned table at location.
+func NewTableMetadata(location string, schema Schema, timestampMs int64) *TableMetadata {
+	return &TableMetadata{
+		FormatVersion:   formatVersion,
+		TableUUID:       misc.FastUUID().String(),
+		Location:        location,
+		LastUpdatedMs:   timestampMs,
+		LastColumnID:    schema.lastColumnID(),
+		CurrentSchemaID: schema.SchemaID,
+		Schemas:         []Schema{schema},
+		PartitionSpecs:  []PartitionSpec{{SpecID: 0, Fields: []json.RawMessage{}}},
+		LastPartitionID: 999,
+		SortOrders:      []SortOrder{{OrderID: 0, Fields: []json.RawMessage{}}},
+		Properties:      map[string]string{nameMappingProperty: schema.nameMapping()},
+		Snapshots:       []Snapshot{},
+		Refs:            map[string]SnapshotRef{},
+		SnapshotLog:     []SnapshotLogEntry{},
+		MetadataLog:     []MetadataLogEntry{},
+	}
+}
+
+// CurrentSchema returns the schema the table is currently using.
+func (m *TableMetadata) CurrentSchema() Schema {
+	for _, schema := range m.Schemas {
+		if schema.SchemaID == m.CurrentSchemaID {
+			return schema
+		}
+	}
+	return Schema{Type: "struct", Fields: []NestedField{}}
+}
+
+// CurrentSnapshot returns the snapshot the main branch points to, or nil for an empty table.
+func (m *TableMetadata) CurrentSnapshot() *Snapshot {
+	if m.CurrentSnapshotID == nil {
+		return nil
+	}
+	for i := range m.Snapshots {
+		if m.Snapshots[i].SnapshotID == *m.CurrentSnapshotID {
+			return &m.Snapshots[i]
+		}
+	}
+	return nil
+}
+
+// RudderSchema returns the current schema of the table as a rudder column map.
+func (m *TableMetadata) RudderSchema() map[string]string {
+	columns := make(map[string]string)
+	for _, field := range m.CurrentSchema().Fields {
+		if rudderType, ok := icebergDataTypesMapToRudder[field.Type]; ok {
+			columns[field.Name] = rudderType
+		}
+	}
+	return columns
+}
+
+// EvolveSchema returns a new schema containing the current columns plus the columns from columnMap
+// which are not yet part of the table. It returns nil if the table already contains all of them.
+//
+// Type changes are only applied when iceberg allows promoting the existing type, otherwise the existing type is kept.
+func (m *TableMetadata) EvolveSchema(columnMap map[string]string) *Schema {
+	current := m.CurrentSchema()
+
+	existing := make(map[string]int, len(current.Fields))
+	for idx, field := range current.Fields {
+		existing[field.Name] = idx
+	}
+
+	evolved := Schema{
+		Type:     "struct",
+		SchemaID: m.nextSchemaID(),
+		Fields:   append([]NestedField{}, current.Fields...),
+	}
+	lastColumnID := m.LastColumnID
+
+	var changed bool
+	for _, columnName := range sortedColumns(columnMap) {
+		newType := icebergType(columnMap[columnName])
+		if idx, ok := existing[columnName]; ok {
+			if canPromote(evolved.Fields[idx].Type, newType) {
+				evolved.Fields[idx].Type = newType
+				changed = true
+			}
+			continue
+		}
+		lastColumnID++
+		evolved.Fields = append(evolved.Fields, NestedField{ID: lastColumnID, Name: columnName, Type: newType})
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return &evolved
+}
+
+// Apply returns a copy of the metadata with the changes applied.
+func (m *TableMetadata) Apply(changes Changes) *TableMetadata {
+	updated := m.clone()
+
+	if changes.Schema != nil {
+		updated.Schemas = append(updated.Schemas, *changes.Schema)
+		updated.CurrentSchemaID = changes.Schema.SchemaID
+		if lastColumnID := changes.Schema.lastColumnID(); lastColumnID > updated.LastColumnID {
+			updated.LastColumnID = lastColumnID
+		}
+		updated.Properties[nameMappingProperty] = changes.Schema.nameMapping()
+		updated.LastUpdatedMs = timeNowMs()
+	}
+
+	if changes.Snapshot != nil {
+		snapshot := *changes.Snapshot
+		updated.Snapshots = append(updated.Snapshots, snapshot)
+		updated.CurrentSnapshotID = &snapshot.SnapshotID
+		updated.LastSequenceNumber = snapshot.SequenceNumber
+		updated.Refs[mainBranch] = SnapshotRef{SnapshotID: snapshot.SnapshotID, Type: "branch"}
+		updated.SnapshotLog = append(updated.SnapshotLog, SnapshotLogEntry{SnapshotID: snapshot.SnapshotID, TimestampMs: snapshot.TimestampMs})
+		updated.LastUpdatedMs = snapshot.TimestampMs
+	}
+
+	return updated
+}
+
+// HasLoadFiles reports whether a snapshot with the provided load files hash was already committed.
+func (m *TableMetadata) HasLoadFiles(hash string) bool {
+	for _, snapshot := range m.Snapshots {
+		if snapshot.Summary[summaryLoadFilesHash] == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *TableMetadata) nextSchemaID() int {
+	var maxSchemaID int
+	for _, schema := range m.Schemas {
+		if schema.SchemaID > maxSchemaID {
+			maxSchemaID = schema.SchemaID
+		}
+	}
+	return maxSchemaID + 1
+}
+
+func (m *TableMetadata) clone() *TableMetadata {
+	var cloned TableMetadata
+	// the metadata only contains json serializable fields, so a round trip is a cheap deep copy
+	data, err := json.Marshal(m)
+	if err != nil {
+		panic(fmt.Errorf("marshalling iceberg table metadata: %w", err))
+	}
+	if err := json.Unmarshal(data, &cloned); err != nil {
+		panic(fmt.Errorf("unmarshalling iceberg table metadata: %w", err))
+	}
+	if cloned.Properties == nil {
+		cloned.Properties = map[string]string{}
+	}
+	if cloned.Refs == nil {
+		cloned.Refs = map[string]SnapshotRef{}
+	}
+	return &cloned
+}
+
+func (s Schema) lastColumnID() int {
+	var lastColumnID int
+	for _, field := range s.Fields {
+		if field.ID > lastColumnID {
+			lastColumnID = field.ID
+		}
+	}
+	return lastColumnID
+}
+
+// nameMapping returns the default name mapping for the schema as described in the iceberg spec.
+func (s Schema) nameMapping() string {
+	type mappedField struct {
+		FieldID int      `json:"field-id"`
+		Names   []string `json:"names"`
+	}
+	mapping := make([]mappedField, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		mapping = append(mapping, mappedField{FieldID: field.ID, Names: []string{field.Name}})
+	}
+	data, _ := json.Marshal(mapping)
+	return string(data)
+}
+
+func icebergType(rudderType string) string {
+	if icebergType, ok := rudderDataTypesMapToIceberg[rudderType]; ok {
+		return icebergType
+	}
+	return "string"
+}
+
+// canPromote reports whether iceberg allows widening a column from one type to another.
+func canPromote(from, to string) bool {
+	switch {
+	case from == "int" && to == "long":
+		return true
+	case from == "float" && to == "double":
+		return true
+	}
+	return false
+}
+
+func sortedColumns(columnMap map[string]string) []string {
+	columns := make([]string, 0, len(columnMap))
+	for columnName := range columnMap {
+		columns = append(columns, columnName)
+	}
+	sort.Strings(columns)
+	return columns
+}
@@ -0,0 +1,117 @@
+package iceberg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchema(t *testing.T) {
+	schema := NewSchema(map[string]string{
+		"received_at": "datetime",
+		"id":          "string",
+		"revenue":     "float",
+		"count":       "int",
+		"is_active":   "boolean",
+		"context":     "json",
+	})
+
+	require.Equal(t, []NestedField{
+		{ID: 1, Name: "context", Type: "string"},
+		{ID: 2, Name: "count", Type: "long"},
+		{ID: 3, Name: "id", Type: "string"},
+		{ID: 4, Name: "is_active", Type: "boolean"},
+		{ID: 5, Name: "received_at", Type: "timestamptz"},
+		{ID: 6, Name: "revenue", Type: "double"},
+	}, schema.Fields)
+	require.Equal(t, 6, schema.lastColumnID())
+}
+
+func TestTableMetadata_EvolveSchema(t *testing.T) {
+	metadata := NewTableMetadata("s3://bucket/table", NewSchema(map[string]string{
+		"id":          "string",
+		"received_at": "datetime",
+	}), 1)
+
+	t.Run("no new columns", func(t *testing.T) {
+		require.Nil(t, metadata.EvolveSchema(map[string]string{"id": "string"}))
+	})
+
+	t.Run("incompatible type change is ignored", func(t *testing.T) {
+		require.Nil(t, metadata.EvolveSchema(map[string]string{"received_at": "string"}))
+	})
+
+	t.Run("new columns", func(t *testing.T) {
+		evolved := metadata.EvolveSchema(map[string]string{
+			"id":      "string",
+			"revenue": "float",
+			"name":    "string",
+		})
+		require.NotNil(t, evolved)
+		require.Equal(t, 1, evolved.SchemaID)
+		require.Equal(t, []NestedField{
+			{ID: 1, Name: "id", Type: "string"},
+			{ID: 2, Name: "received_at", Type: "timestamptz"},
+			{ID: 3, Name: "name", Type: "string"},
+			{ID: 4, Name: "revenue", Type: "double"},
+		}, evolved.Fields)
+
+		updated := metadata.Apply(Changes{Schema: evolved})
+		require.Equal(t, 1, updated.CurrentSchemaID)
+		require.Equal(t, 4, updated.LastColumnID)
+		require.Len(t, updated.Schemas, 2)
+		require.Equal(t, map[string]string{
+			"id":          "string",
+			"received_at": "datetime",
+			"name":        "string",
+			"revenue":     "float",
+		}, updated.RudderSchema())
+
+		var nameMapping []struct {
+			FieldID int      `json:"field-id"`
+			Names   []string `json:"names"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(updated.Properties[nameMappingProperty]), &nameMapping))
+		require.Len(t, nameMapping, 4)
+		require.Equal(t, 4, nameMapping[3].FieldID)
+		require.Equal(t, []string{"revenue"}, nameMapping[3].Names)
+
+		// the base metadata is left untouched
+		require.Equal(t, 0, metadata.CurrentSchemaID)
+		require.Len(t, metadata.Schemas, 1)
+	})
+
+	t.Run("promotion", func(t *testing.T) {
+		metadata := NewTableMetadata("s3://bucket/table", Schema{
+			Type:   "struct",
+			Fields: []NestedField{{ID: 1, Name: "count", Type: "int"}},
+		}, 1)
+
+		evolved := metadata.EvolveSchema(map[string]string{"count": "int"})
+		require.NotNil(t, evolved)
+		require.Equal(t, []NestedField{{ID: 1, Name: "count", Type: "long"}}, evolved.Fields)
+	})
+}
+
+func TestTableMetadata_ApplySnapshot(t *testing.T) {
+	metadata := NewTableMetadata("s3://bucket/table", NewSchema(map[string]string{"id": "string"}), 1)
+	require.Nil(t, metadata.CurrentSnapshot())
+
+	updated := metadata.Apply(Changes{Snapshot: &Snapshot{
+		SnapshotID:     42,
+		SequenceNumber: 1,
+		TimestampMs:    100,
+		ManifestList:   "s3://bucket/table/metadata/snap-42.avro",
+		Summary:        map[string]string{summaryLoadFilesHash: "hash"},
+	}})
+
+	require.NotNil(t, updated.CurrentSnapshot())
+	require.EqualValues(t, 42, updated.CurrentSnapshot().SnapshotID)
+	require.EqualValues(t, 1, updated.LastSequenceNumber)
+	require.EqualValues(t, 100, updated.LastUpdatedMs)
+	require.Equal(t, SnapshotRef{SnapshotID: 42, Type: "branch"}, updated.Refs[mainBranch])
+	require.Equal(t, []SnapshotLogEntry{{SnapshotID: 42, TimestampMs: 100}}, updated.SnapshotLog)
+	require.True(t, updated.HasLoadFiles("hash"))
+	require.False(t, updated.HasLoadFiles("other"))
+}
@@ -0,0 +1,68 @@
+package iceberg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/linkedin/goavro"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+var (
+	ocfMagic         = []byte("Obj\x01")
+	ocfMetadataCodec = mustCodec(`{"type": "map", "values": "bytes"}`)
+	ocfLongCodec     = mustCodec(`"long"`)
+)
+
+func mustCodec(schema string) *goavro.Codec {
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		panic(fmt.Errorf("creating avro codec: %w", err))
+	}
+	return codec
+}
+
+// writeOCF writes records as a single block avro object container file.
+//
+// The schema advertised in the header is written as is, while records are encoded using codec. This allows
+// writing schemas with empty records, which iceberg requires for unpartitioned tables but goavro can't handle:
+// empty records are encoded as zero bytes, hence a codec omitting them produces the same binary encoding.
+func writeOCF(w io.Writer, schema string, codec *goavro.Codec, metadata map[string][]byte, records []map[string]interface{}) error {
+	headerMetadata := map[string]interface{}{
+		"avro.schema": []byte(schema),
+		"avro.codec":  []byte("null"),
+	}
+	for key, value := range metadata {
+		headerMetadata[key] = value
+	}
+
+	syncMarker := misc.FastUUID()
+
+	buf := bytes.NewBuffer(append([]byte{}, ocfMagic...))
+	header, err := ocfMetadataCodec.BinaryFromNative(nil, headerMetadata)
+	if err != nil {
+		return fmt.Errorf("encoding ocf header: %w", err)
+	}
+	buf.Write(header)
+	buf.Write(syncMarker[:])
+
+	var block []byte
+	for _, record := range records {
+		if block, err = codec.BinaryFromNative(block, record); err != nil {
+			return fmt.Errorf("encoding record: %w", err)
+		}
+	}
+
+	if len(records) > 0 {
+		blockHeader, _ := ocfLongCodec.BinaryFromNative(nil, int64(len(records)))
+		blockHeader, _ = ocfLongCodec.BinaryFromNative(blockHeader, int64(len(block)))
+		buf.Write(blockHeader)
+		buf.Write(block)
+		buf.Write(syncMarker[:])
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,198 @@
+package iceberg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/utils/httputil"
+)
+
+// restCatalog talks to a catalog implementing the iceberg REST catalog open api specification.
+type restCatalog struct {
+	baseURI    string
+	prefix     string
+	token      string
+	httpClient *http.Client
+}
+
+type loadTableResult struct {
+	MetadataLocation string        `json:"metadata-location"`
+	Metadata         TableMetadata `json:"metadata"`
+}
+
+type createNamespaceRequest struct {
+	Namespace  []string          `json:"namespace"`
+	Properties map[string]string `json:"properties"`
+}
+
+type createTableRequest struct {
+	Name       string            `json:"name"`
+	Location   string            `json:"location"`
+	Schema     Schema            `json:"schema"`
+	Properties map[string]string `json:"properties"`
+}
+
+type commitTableRequest struct {
+	Requirements []map[string]interface{} `json:"requirements"`
+	Updates      []map[string]interface{} `json:"updates"`
+}
+
+// NewRESTCatalog returns a catalog backed by the REST catalog at baseURI.
+// The prefix, if any, is the one returned by the catalog's config endpoint for the configured warehouse.
+func NewRESTCatalog(baseURI, prefix, token string, httpClient *http.Client) Catalog {
+	return &restCatalog{
+		baseURI:    strings.TrimSuffix(baseURI, "/"),
+		prefix:     strings.Trim(prefix, "/"),
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+func (rc *restCatalog) url(segments ...string) string {
+	escaped := []string{rc.baseURI, "v1"}
+	if rc.prefix != "" {
+		escaped = append(escaped, rc.prefix)
+	}
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+	return strings.Join(escaped, "/")
+}
+
+func (rc *restCatalog) do(ctx context.Context, method, url string, body, response interface{}) (int, error) {
+	reqBody := io.Reader(http.NoBody)
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("marshalling request body: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return 0, fmt.Errorf("creating new request with ctx: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if rc.token != "" {
+		req.Header.Set("Authorization", "Bearer "+rc.token)
+	}
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("fetching response from http client: %w", err)
+	}
+	defer func() { httputil.CloseResponse(resp) }()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, fmt.Errorf("invalid status code: %d, body: %s", resp.StatusCode, respBody)
+	}
+
+	if response != nil {
+		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+			return resp.StatusCode, fmt.Errorf("decoding upstream response body: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func (rc *restCatalog) LoadTable(ctx context.Context, namespace, table string) (*TableMetadata, error) {
+	var result loadTableResult
+	statusCode, err := rc.do(ctx, http.MethodGet, rc.url("namespaces", namespace, "tables", table), nil, &result)
+	if statusCode == http.StatusNotFound {
+		return nil, ErrNoSuchTable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading table %s.%s: %w", namespace, table, err)
+	}
+	return &result.Metadata, nil
+}
+
+func (rc *restCatalog) createNamespace(ctx context.Context, namespace string) error {
+	statusCode, err := rc.do(ctx, http.MethodPost, rc.url("namespaces"), createNamespaceRequest{
+		Namespace:  []string{namespace},
+		Properties: map[string]string{},
+	}, nil)
+	if statusCode == http.StatusConflict {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("creating namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+func (rc *restCatalog) CreateTable(ctx context.Context, namespace, table, location string, schema Schema) (*TableMetadata, error) {
+	if err := rc.createNamespace(ctx, namespace); err != nil {
+		return nil, err
+	}
+
+	var result loadTableResult
+	statusCode, err := rc.do(ctx, http.MethodPost, rc.url("namespaces", namespace, "tables"), createTableRequest{
+		Name:       table,
+		Location:   location,
+		Schema:     schema,
+		Properties: map[string]string{nameMappingProperty: schema.nameMapping()},
+	}, &result)
+	if statusCode == http.StatusConflict {
+		return nil, ErrTableAlreadyExists
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating table %s.%s: %w", namespace, table, err)
+	}
+	return &result.Metadata, nil
+}
+
+func (rc *restCatalog) CommitTable(ctx context.Context, namespace, table string, base *TableMetadata, changes Changes) (*TableMetadata, error) {
+	request := commitTableRequest{
+		Requirements: []map[string]interface{}{
+			{"type": "assert-table-uuid", "uuid": base.TableUUID},
+		},
+		Updates: []map[string]interface{}{},
+	}
+
+	if changes.Schema != nil {
+		request.Requirements = append(request.Requirements, map[string]interface{}{
+			"type":              "assert-current-schema-id",
+			"current-schema-id": base.CurrentSchemaID,
+		})
+		request.Updates = append(request.Updates,
+			map[string]interface{}{"action": "add-schema", "schema": changes.Schema, "last-column-id": changes.Schema.lastColumnID()},
+			// -1 refers to the schema added by this commit
+			map[string]interface{}{"action": "set-current-schema", "schema-id": -1},
+			map[string]interface{}{"action": "set-properties", "updates": map[string]string{nameMappingProperty: changes.Schema.nameMapping()}},
+		)
+	}
+
+	if changes.Snapshot != nil {
+		request.Requirements = append(request.Requirements, map[string]interface{}{
+			"type":        "assert-ref-snapshot-id",
+			"ref":         mainBranch,
+			"snapshot-id": base.CurrentSnapshotID,
+		})
+		request.Updates = append(request.Updates,
+			map[string]interface{}{"action": "add-snapshot", "snapshot": changes.Snapshot},
+			map[string]interface{}{"action": "set-snapshot-ref", "ref-name": mainBranch, "type": "branch", "snapshot-id": changes.Snapshot.SnapshotID},
+		)
+	}
+
+	var result loadTableResult
+	statusCode, err := rc.do(ctx, http.MethodPost, rc.url("namespaces", namespace, "tables", table), request, &result)
+	if statusCode == http.StatusConflict {
+		return nil, ErrCommitConflict
+	}
+	if statusCode == http.StatusNotFound {
+		return nil, ErrNoSuchTable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("committing table %s.%s: %w", namespace, table, err)
+	}
+	return &result.Metadata, nil
+}
@@ -0,0 +1,136 @@
+package iceberg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// restCatalogServer is a minimal in memory implementation of the REST catalog endpoints used by the client.
+type restCatalogServer struct {
+	mu         sync.Mutex
+	namespaces map[string]bool
+	tables     map[string]*TableMetadata
+	requests   []commitTableRequest
+}
+
+func (s *restCatalogServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/prefix/namespaces":
+		var req createNamespaceRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if s.namespaces[req.Namespace[0]] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		s.namespaces[req.Namespace[0]] = true
+		_ = json.NewEncoder(w).Encode(req)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/prefix/namespaces/namespace/tables":
+		var req createTableRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if _, ok := s.tables[req.Name]; ok {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		s.tables[req.Name] = NewTableMetadata(req.Location, req.Schema, 1)
+		_ = json.NewEncoder(w).Encode(loadTableResult{Metadata: *s.tables[req.Name]})
+	case r.URL.Path == "/v1/prefix/namespaces/namespace/tables/tracks":
+		metadata, ok := s.tables["tracks"]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(loadTableResult{Metadata: *metadata})
+			return
+		}
+
+		var req commitTableRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		s.requests = append(s.requests, req)
+		for _, requirement := range req.Requirements {
+			if requirement["type"] == "assert-current-schema-id" && int(requirement["current-schema-id"].(float64)) != metadata.CurrentSchemaID {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+		}
+		var changes Changes
+		for _, update := range req.Updates {
+			data, _ := json.Marshal(update)
+			switch update["action"] {
+			case "add-schema":
+				var u struct{ Schema Schema }
+				_ = json.Unmarshal(data, &u)
+				changes.Schema = &u.Schema
+			case "add-snapshot":
+				var u struct{ Snapshot Snapshot }
+				_ = json.Unmarshal(data, &u)
+				changes.Snapshot = &u.Snapshot
+			}
+		}
+		s.tables["tracks"] = metadata.Apply(changes)
+		_ = json.NewEncoder(w).Encode(loadTableResult{Metadata: *s.tables["tracks"]})
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestRESTCatalog(t *testing.T) {
+	ctx := context.Background()
+	server := &restCatalogServer{
+		namespaces: map[string]bool{},
+		tables:     map[string]*TableMetadata{},
+	}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	catalog := NewRESTCatalog(ts.URL+"/", "prefix", "token", ts.Client())
+
+	_, err := catalog.LoadTable(ctx, "namespace", "tracks")
+	require.ErrorIs(t, err, ErrNoSuchTable)
+
+	metadata, err := catalog.CreateTable(ctx, "namespace", "tracks", "s3://bucket/tracks", NewSchema(map[string]string{"id": "string"}))
+	require.NoError(t, err)
+	require.Equal(t, "s3://bucket/tracks", metadata.Location)
+	require.True(t, server.namespaces["namespace"])
+
+	_, err = catalog.CreateTable(ctx, "namespace", "tracks", "s3://bucket/tracks", NewSchema(map[string]string{"id": "string"}))
+	require.ErrorIs(t, err, ErrTableAlreadyExists)
+
+	evolved := metadata.EvolveSchema(map[string]string{"revenue": "float"})
+	updated, err := catalog.CommitTable(ctx, "namespace", "tracks", metadata, Changes{
+		Schema:   evolved,
+		Snapshot: &Snapshot{SnapshotID: 7, SequenceNumber: 1, TimestampMs: 10, Summary: map[string]string{}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, updated.CurrentSchemaID)
+	require.EqualValues(t, 7, *updated.CurrentSnapshotID)
+
+	require.Len(t, server.requests, 1)
+	var actions []interface{}
+	for _, update := range server.requests[0].Updates {
+		actions = append(actions, update["action"])
+	}
+	require.Equal(t, []interface{}{"add-schema", "set-current-schema", "set-properties", "add-snapshot", "set-snapshot-ref"}, actions)
+
+	// committing on top of a stale base is rejected
+	_, err = catalog.CommitTable(ctx, "namespace", "tracks", metadata, Changes{Schema: evolved})
+	require.ErrorIs(t, err, ErrCommitConflict)
+
+	loaded, err := catalog.LoadTable(ctx, "namespace", "tracks")
+	require.NoError(t, err)
+	require.Equal(t, updated.RudderSchema(), loaded.RudderSchema())
+}
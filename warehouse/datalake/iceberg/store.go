@@ -0,0 +1,159 @@
+package iceberg
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// ObjectStore reads and writes the iceberg metadata files next to the datalake load files.
+type ObjectStore interface {
+	// Get returns the contents of the object with key relative to the configured prefix.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put writes data to the object with key relative to the configured prefix.
+	Put(ctx context.Context, key string, data []byte) error
+	// Exists reports whether an object with key relative to the configured prefix exists.
+	Exists(ctx context.Context, key string) (bool, error)
+	// URI returns the fully qualified location of key, e.g. s3://bucket/prefix/key.
+	URI(key string) string
+	// URIFromLocation converts a load file location into the fully qualified location iceberg readers expect.
+	URIFromLocation(location string) (string, error)
+}
+
+type objectStore struct {
+	fm        filemanager.FileManager
+	settings  *filemanager.SettingsT
+	bucketURI string
+}
+
+// NewObjectStore returns an object store backed by the destination's bucket.
+func NewObjectStore(warehouse warehouseutils.Warehouse, useRudderStorage bool) (ObjectStore, error) {
+	provider := warehouseutils.ObjectStorageType(warehouse.Type, warehouse.Destination.Config, useRudderStorage)
+	settings := &filemanager.SettingsT{
+		Provider: provider,
+		Config: misc.GetObjectStorageConfig(misc.ObjectStorageOptsT{
+			Provider:         provider,
+			Config:           warehouse.Destination.Config,
+			UseRudderStorage: useRudderStorage,
+			WorkspaceID:      warehouse.Destination.WorkspaceID,
+		}),
+	}
+	fm, err := filemanager.DefaultFileManagerFactory.New(settings)
+	if err != nil {
+		return nil, fmt.Errorf("creating file manager: %w", err)
+	}
+
+	bucketURI, err := bucketURI(provider, warehouse)
+	if err != nil {
+		return nil, err
+	}
+
+	return &objectStore{
+		fm:        fm,
+		settings:  settings,
+		bucketURI: bucketURI,
+	}, nil
+}
+
+func bucketURI(provider string, warehouse warehouseutils.Warehouse) (string, error) {
+	switch provider {
+	case warehouseutils.S3, warehouseutils.MINIO:
+		return fmt.Sprintf("s3://%s", warehouseutils.GetConfigValue(warehouseutils.AWSBucketNameConfig, warehouse)), nil
+	case warehouseutils.GCS:
+		return fmt.Sprintf("gs://%s", warehouseutils.GetConfigValue("bucketName", warehouse)), nil
+	case warehouseutils.AZURE_BLOB:
+		return fmt.Sprintf(
+			"abfss://%s@%s.dfs.core.windows.net",
+			warehouseutils.GetConfigValue("containerName", warehouse),
+			warehouseutils.GetConfigValue("accountName", warehouse),
+		), nil
+	}
+	return "", fmt.Errorf("iceberg is not supported for object storage: %s", provider)
+}
+
+func (s *objectStore) objectKey(key string) string {
+	return path.Join(s.fm.GetConfiguredPrefix(), key)
+}
+
+func (s *objectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	tmpDir, err := os.MkdirTemp("", "iceberg")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	file, err := os.Create(filepath.Join(tmpDir, path.Base(key)))
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := s.fm.Download(ctx, file, s.objectKey(key)); err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", key, err)
+	}
+	return os.ReadFile(file.Name())
+}
+
+func (s *objectStore) Put(ctx context.Context, key string, data []byte) error {
+	tmpDir, err := os.MkdirTemp("", "iceberg")
+	if err != nil {
+		return fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// file managers name the uploaded object after the local file, so it has to carry the object's base name
+	filePath := filepath.Join(tmpDir, path.Base(key))
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("opening temp file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err := s.fm.Upload(ctx, file, path.Dir(key)); err != nil {
+		return fmt.Errorf("uploading %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *objectStore) Exists(ctx context.Context, key string) (bool, error) {
+	// listing keeps pagination state in the file manager, so every lookup uses a fresh one
+	fm, err := filemanager.DefaultFileManagerFactory.New(s.settings)
+	if err != nil {
+		return false, fmt.Errorf("creating file manager: %w", err)
+	}
+
+	objectKey := s.objectKey(key)
+	objects, err := fm.ListFilesWithPrefix(ctx, "", objectKey, 1)
+	if err != nil {
+		return false, fmt.Errorf("listing %s: %w", key, err)
+	}
+	for _, object := range objects {
+		if object.Key == objectKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *objectStore) URI(key string) string {
+	return fmt.Sprintf("%s/%s", s.bucketURI, s.objectKey(key))
+}
+
+func (s *objectStore) URIFromLocation(location string) (string, error) {
+	objectKey, err := s.fm.GetObjectNameFromLocation(location)
+	if err != nil {
+		return "", fmt.Errorf("getting object name from location %s: %w", location, err)
+	}
+	return fmt.Sprintf("%s/%s", s.bucketURI, strings.TrimPrefix(objectKey, "/")), nil
+}
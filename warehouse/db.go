@@ -38,6 +38,7 @@ func (db *DB) GetLatestUploadStatus(ctx context.Context, destType, sourceID, des
 		  UT.destination_type = '%[2]s' 
 		  AND UT.source_id = '%[3]s' 
 		  AND UT.destination_id = '%[4]s' 
+		  AND UT.metadata ->> 'backfill_id' IS NULL
 		ORDER BY 
 		  id DESC 
 		LIMIT 
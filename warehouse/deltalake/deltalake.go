@@ -146,15 +146,35 @@ func getDeltaLakeDataType(columnType string) string {
 	return dataTypesMap[columnType]
 }
 
+// defaultPartitioning partitions tables by the day of received_at
+var defaultPartitioning = warehouseutils.TablePartitioningT{
+	PartitionColumn:      "received_at",
+	PartitionGranularity: warehouseutils.PartitionGranularityDay,
+}
+
 // ColumnsWithDataTypes returns columns with specified prefix and data type
 func ColumnsWithDataTypes(columns map[string]string, prefix string) string {
+	return columnsWithDataTypes(columns, prefix, defaultPartitioning)
+}
+
+// eventDateExpression returns the expression of the generated event_date column, truncating the partition column to the partition granularity
+func eventDateExpression(partitioning warehouseutils.TablePartitioningT) string {
+	switch partitioning.PartitionGranularity {
+	case warehouseutils.PartitionGranularityMonth, warehouseutils.PartitionGranularityYear:
+		return fmt.Sprintf(`CAST(DATE_TRUNC('%s', %s) AS DATE)`, strings.ToUpper(partitioning.PartitionGranularity), partitioning.PartitionColumn)
+	}
+	return fmt.Sprintf(`CAST(%s AS DATE)`, partitioning.PartitionColumn)
+}
+
+// columnsWithDataTypes returns columns with specified prefix and data type, along with the event_date column generated from the partition column
+func columnsWithDataTypes(columns map[string]string, prefix string, partitioning warehouseutils.TablePartitioningT) string {
 	keys := warehouseutils.SortColumnKeysFromColumnMap(columns)
 	format := func(idx int, name string) string {
 		if _, ok := excludeColumnsMap[name]; ok {
 			return ""
 		}
-		if name == "received_at" {
-			generatedColumnSQL := "DATE GENERATED ALWAYS AS ( CAST(received_at AS DATE) )"
+		if name == partitioning.PartitionColumn {
+			generatedColumnSQL := fmt.Sprintf("DATE GENERATED ALWAYS AS ( %s )", eventDateExpression(partitioning))
 			return fmt.Sprintf(`%s%s %s, %s%s %s`, prefix, name, getDeltaLakeDataType(columns[name]), prefix, "event_date", generatedColumnSQL)
 		}
 
@@ -340,6 +360,12 @@ func (dl *HandleT) partitionQuery(tableName string) (string, error) {
 		return "", nil
 	}
 
+	// the date range of the upload only applies to event_date generated from the day of received_at
+	partitioning := dl.tablePartitioning(tableName, dl.Uploader.GetTableSchemaInWarehouse(tableName))
+	if partitioning.PartitionColumn != defaultPartitioning.PartitionColumn || partitioning.PartitionGranularity != defaultPartitioning.PartitionGranularity {
+		return "", nil
+	}
+
 	firstEvent, lastEvent := dl.Uploader.GetFirstLastEvent()
 
 	dateRange := warehouseutils.GetDateRangeList(firstEvent, lastEvent, "2006-01-02")
@@ -840,8 +866,9 @@ func (dl *HandleT) CreateTable(tableName string, columns map[string]string) (err
 	name := fmt.Sprintf(`%s.%s`, dl.Namespace, tableName)
 
 	tableLocationSql := dl.getTableLocationSql(tableName)
+	partitioning := dl.tablePartitioning(tableName, columns)
 	var partitionedSql string
-	if _, ok := columns["received_at"]; ok {
+	if _, ok := columns[partitioning.PartitionColumn]; ok {
 		partitionedSql = `PARTITIONED BY(event_date)`
 	}
 
@@ -850,12 +877,20 @@ func (dl *HandleT) CreateTable(tableName string, columns map[string]string) (err
 		createTableClauseSql = "CREATE OR REPLACE TABLE"
 	}
 
-	sqlStatement := fmt.Sprintf(`%s %s ( %v ) USING DELTA %s %s;`, createTableClauseSql, name, ColumnsWithDataTypes(columns, ""), tableLocationSql, partitionedSql)
+	sqlStatement := fmt.Sprintf(`%s %s ( %v ) USING DELTA %s %s;`, createTableClauseSql, name, columnsWithDataTypes(columns, "", partitioning), tableLocationSql, partitionedSql)
 	pkgLogger.Infof("%s Creating table in delta lake with SQL: %v", dl.GetLogIdentifier(tableName), sqlStatement)
 	err = dl.ExecuteSQL(sqlStatement, "CreateTable")
 	return
 }
 
+// tablePartitioning returns the partitioning configured for the table, defaulting to the day of received_at
+func (dl *HandleT) tablePartitioning(tableName string, columns map[string]string) warehouseutils.TablePartitioningT {
+	if partitioning := warehouseutils.GetTablePartitioning(dl.Warehouse, tableName, columns); partitioning.PartitionColumn != "" {
+		return partitioning
+	}
+	return defaultPartitioning
+}
+
 func (dl *HandleT) DropTable(tableName string) (err error) {
 	pkgLogger.Infof("%s Dropping table %s", dl.GetLogIdentifier(), tableName)
 	sqlStatement := fmt.Sprintf(`DROP TABLE %[1]s.%[2]s;`, dl.Namespace, tableName)
@@ -0,0 +1,443 @@
+package warehouse
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/jobs"
+	"github.com/rudderlabs/rudder-server/warehouse/manager"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var (
+	enableSchemaDriftDetection bool
+	schemaDriftCheckInterval   time.Duration
+	schemaDriftAutoRepair      bool
+)
+
+func loadSchemaDriftConfig() {
+	config.RegisterBoolConfigVariable(false, &enableSchemaDriftDetection, true, "Warehouse.enableSchemaDriftDetection")
+	config.RegisterDurationConfigVariable(360, &schemaDriftCheckInterval, true, time.Minute, "Warehouse.schemaDriftCheckInterval")
+	config.RegisterBoolConfigVariable(false, &schemaDriftAutoRepair, true, "Warehouse.schemaDriftAutoRepair")
+}
+
+// schema drift kinds
+const (
+	driftMissingTable  = "missing_table"
+	driftMissingColumn = "missing_column"
+	driftTypeMismatch  = "type_mismatch"
+)
+
+// schemas the warehouse is compared against
+const (
+	driftOriginLocalSchema  = "local_schema"
+	driftOriginUploadSchema = "upload_schema"
+)
+
+// schemaDrift is a difference between the schema we expect in the warehouse and the actual one.
+type schemaDrift struct {
+	Kind         string `json:"kind"`
+	Origin       string `json:"origin"`
+	Table        string `json:"table"`
+	Column       string `json:"column,omitempty"`
+	ExpectedType string `json:"expected_type,omitempty"`
+	ActualType   string `json:"actual_type,omitempty"`
+	Repaired     bool   `json:"repaired"`
+}
+
+type schemaDriftReport struct {
+	SourceID        string        `json:"source_id"`
+	DestinationID   string        `json:"destination_id"`
+	DestinationType string        `json:"destination_type"`
+	Namespace       string        `json:"namespace"`
+	CheckedAt       time.Time     `json:"checked_at"`
+	Drifts          []schemaDrift `json:"drifts"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// roundTripDataTypes are the data types which are fetched back from the warehouse as another data type,
+// since the warehouse stores them with the same type as the latter.
+var roundTripDataTypes = map[string]map[string]string{
+	warehouseutils.SNOWFLAKE:     {"bigint": "int"},
+	warehouseutils.RS:            {"bigint": "int", "text": "string"},
+	warehouseutils.POSTGRES:      {"text": "string"},
+	warehouseutils.MSSQL:         {"text": "string"},
+	warehouseutils.AZURE_SYNAPSE: {"text": "string"},
+}
+
+// roundTripDataType returns the data type the column would be fetched back with from the warehouse.
+func roundTripDataType(warehouseType, columnType string) string {
+	if dataType, ok := roundTripDataTypes[warehouseType][columnType]; ok {
+		return dataType
+	}
+	return columnType
+}
+
+// detectSchemaDrift returns the tables and columns of the expected schema which are missing or of another type in the warehouse.
+// Both types are compared as fetched back from the warehouse, so that types stored alike aren't reported as mismatching.
+func detectSchemaDrift(warehouseType, origin string, expectedSchema, schemaInWarehouse warehouseutils.SchemaT) []schemaDrift {
+	var drifts []schemaDrift
+	for tableName, columns := range expectedSchema {
+		columnsInWarehouse, ok := schemaInWarehouse[tableName]
+		if !ok {
+			drifts = append(drifts, schemaDrift{Kind: driftMissingTable, Origin: origin, Table: tableName})
+			continue
+		}
+		for columnName, columnType := range columns {
+			columnTypeInWarehouse, ok := columnsInWarehouse[columnName]
+			if !ok {
+				drifts = append(drifts, schemaDrift{Kind: driftMissingColumn, Origin: origin, Table: tableName, Column: columnName, ExpectedType: columnType})
+				continue
+			}
+			if roundTripDataType(warehouseType, columnTypeInWarehouse) != roundTripDataType(warehouseType, columnType) {
+				drifts = append(drifts, schemaDrift{Kind: driftTypeMismatch, Origin: origin, Table: tableName, Column: columnName, ExpectedType: columnType, ActualType: columnTypeInWarehouse})
+			}
+		}
+	}
+	sortSchemaDrifts(drifts)
+	return drifts
+}
+
+// mergeSchemaDrifts appends the drifts of other not already reported.
+func mergeSchemaDrifts(drifts, other []schemaDrift) []schemaDrift {
+	reported := make(map[string]bool, len(drifts))
+	for _, drift := range drifts {
+		reported[drift.Table+"."+drift.Column] = true
+	}
+	for _, drift := range other {
+		if reported[drift.Table+"."+drift.Column] || reported[drift.Table+"."] {
+			continue
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts
+}
+
+func sortSchemaDrifts(drifts []schemaDrift) {
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Table != drifts[j].Table {
+			return drifts[i].Table < drifts[j].Table
+		}
+		return drifts[i].Column < drifts[j].Column
+	})
+}
+
+// schemaDriftDetector periodically compares the schema of the warehouses with the schemas stored in wh_schemas and wh_uploads.
+type schemaDriftDetector struct {
+	dbHandle *sql.DB
+	stats    stats.Stats
+
+	reportsLock sync.RWMutex
+	reports     map[string]*schemaDriftReport
+}
+
+func newSchemaDriftDetector(dbHandle *sql.DB, stats stats.Stats) *schemaDriftDetector {
+	return &schemaDriftDetector{
+		dbHandle: dbHandle,
+		stats:    stats,
+		reports:  map[string]*schemaDriftReport{},
+	}
+}
+
+func schemaDriftReportKey(sourceID, destinationID, namespace string) string {
+	return fmt.Sprintf(`%s_%s_%s`, sourceID, destinationID, namespace)
+}
+
+func (d *schemaDriftDetector) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(schemaDriftCheckInterval):
+			if !enableSchemaDriftDetection {
+				continue
+			}
+			for _, warehouse := range connectedWarehouses() {
+				if ctx.Err() != nil {
+					return
+				}
+				d.check(ctx, warehouse, schemaDriftAutoRepair)
+			}
+		}
+	}
+}
+
+func connectedWarehouses() []warehouseutils.Warehouse {
+	connectionsMapLock.RLock()
+	defer connectionsMapLock.RUnlock()
+
+	var warehouses []warehouseutils.Warehouse
+	for _, sourceMap := range connectionsMap {
+		for _, warehouse := range sourceMap {
+			if !warehouse.Source.Enabled || !warehouse.Destination.Enabled {
+				continue
+			}
+			warehouses = append(warehouses, warehouse)
+		}
+	}
+	return warehouses
+}
+
+// check compares the schema of the warehouse with the expected one and stores the report, repairing missing tables and columns if asked to.
+func (d *schemaDriftDetector) check(ctx context.Context, warehouse warehouseutils.Warehouse, repair bool) *schemaDriftReport {
+	report := &schemaDriftReport{
+		SourceID:        warehouse.Source.ID,
+		DestinationID:   warehouse.Destination.ID,
+		DestinationType: warehouse.Type,
+		Namespace:       warehouse.Namespace,
+		CheckedAt:       timeutil.Now(),
+	}
+	if err := d.detect(ctx, warehouse, report, repair); err != nil {
+		pkgLogger.Errorf("[WH]: Schema drift check failed for %s: %v", warehouse.Identifier, err)
+		report.Error = err.Error()
+	}
+	d.recordStats(warehouse, report)
+
+	d.reportsLock.Lock()
+	d.reports[schemaDriftReportKey(report.SourceID, report.DestinationID, report.Namespace)] = report
+	d.reportsLock.Unlock()
+	return report
+}
+
+func (d *schemaDriftDetector) detect(ctx context.Context, warehouse warehouseutils.Warehouse, report *schemaDriftReport, repair bool) error {
+	sh := SchemaHandleT{warehouse: warehouse}
+	localSchema := sh.getLocalSchema()
+	uploadSchema, err := d.latestUploadSchema(ctx, warehouse)
+	if err != nil {
+		return err
+	}
+	if len(localSchema) == 0 && len(uploadSchema) == 0 {
+		return nil
+	}
+
+	whManager, err := manager.New(warehouse.Type)
+	if err != nil {
+		return err
+	}
+	if err := whManager.Setup(warehouse, &jobs.WhAsyncJob{}); err != nil {
+		return fmt.Errorf("setting up warehouse manager: %w", err)
+	}
+	defer whManager.Cleanup()
+
+	schemaInWarehouse, _, err := whManager.FetchSchema(warehouse)
+	if err != nil {
+		return fmt.Errorf("fetching schema from warehouse: %w", err)
+	}
+
+	report.Drifts = mergeSchemaDrifts(
+		detectSchemaDrift(warehouse.Type, driftOriginLocalSchema, localSchema, schemaInWarehouse),
+		detectSchemaDrift(warehouse.Type, driftOriginUploadSchema, uploadSchema, schemaInWarehouse),
+	)
+	if !repair || len(report.Drifts) == 0 {
+		return nil
+	}
+	return repairSchemaDrifts(whManager, report.Drifts, localSchema, uploadSchema)
+}
+
+// latestUploadSchema returns the schema of the latest exported upload of the warehouse.
+func (d *schemaDriftDetector) latestUploadSchema(ctx context.Context, warehouse warehouseutils.Warehouse) (warehouseutils.SchemaT, error) {
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  schema
+		FROM
+		  %[1]s
+		WHERE
+		  source_id = $1
+		  AND destination_id = $2
+		  AND namespace = $3
+		  AND status = $4
+		ORDER BY
+		  id DESC
+		LIMIT
+		  1;
+`,
+		warehouseutils.WarehouseUploadsTable,
+	)
+
+	var rawSchema json.RawMessage
+	err := d.dbHandle.QueryRowContext(ctx, sqlStatement,
+		warehouse.Source.ID,
+		warehouse.Destination.ID,
+		warehouse.Namespace,
+		model.ExportedData,
+	).Scan(&rawSchema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return warehouseutils.SchemaT{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("querying latest upload schema: %w", err)
+	}
+	return warehouseutils.JSONSchemaToMap(rawSchema), nil
+}
+
+// repairSchemaDrifts creates the missing tables and columns. Type mismatches are left as they are, since they require converting data.
+func repairSchemaDrifts(whManager manager.ManagerI, drifts []schemaDrift, schemas ...warehouseutils.SchemaT) error {
+	expectedColumnType := func(tableName, columnName string) string {
+		for _, schema := range schemas {
+			if columnType, ok := schema[tableName][columnName]; ok {
+				return columnType
+			}
+		}
+		return ""
+	}
+
+	var schemaCreated bool
+	missingColumns := make(map[string][]warehouseutils.ColumnInfo)
+	for i, drift := range drifts {
+		switch drift.Kind {
+		case driftMissingTable:
+			if !schemaCreated {
+				if err := whManager.CreateSchema(); err != nil {
+					return fmt.Errorf("creating schema: %w", err)
+				}
+				schemaCreated = true
+			}
+
+			columns := make(map[string]string)
+			for _, schema := range schemas {
+				for columnName := range schema[drift.Table] {
+					columns[columnName] = expectedColumnType(drift.Table, columnName)
+				}
+			}
+			if err := whManager.CreateTable(drift.Table, columns); err != nil {
+				return fmt.Errorf("creating table %s: %w", drift.Table, err)
+			}
+			drifts[i].Repaired = true
+		case driftMissingColumn:
+			missingColumns[drift.Table] = append(missingColumns[drift.Table], warehouseutils.ColumnInfo{Name: drift.Column, Type: drift.ExpectedType})
+		}
+	}
+
+	tableNames := make([]string, 0, len(missingColumns))
+	for tableName := range missingColumns {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	for _, tableName := range tableNames {
+		if err := whManager.AddColumns(tableName, missingColumns[tableName]); err != nil {
+			return fmt.Errorf("adding columns to table %s: %w", tableName, err)
+		}
+		for i, drift := range drifts {
+			if drift.Table == tableName && drift.Kind == driftMissingColumn {
+				drifts[i].Repaired = true
+			}
+		}
+	}
+	return nil
+}
+
+func (d *schemaDriftDetector) recordStats(warehouse warehouseutils.Warehouse, report *schemaDriftReport) {
+	counts := map[string]int{
+		driftMissingTable:  0,
+		driftMissingColumn: 0,
+		driftTypeMismatch:  0,
+	}
+	for _, drift := range report.Drifts {
+		if !drift.Repaired {
+			counts[drift.Kind]++
+		}
+	}
+	for kind, count := range counts {
+		d.stats.NewTaggedStat("warehouse_schema_drift", stats.GaugeType, stats.Tags{
+			"workspaceId":   warehouse.WorkspaceID,
+			"sourceID":      warehouse.Source.ID,
+			"destinationID": warehouse.Destination.ID,
+			"destType":      warehouse.Type,
+			"kind":          kind,
+		}).Gauge(count)
+	}
+	if report.Error != "" {
+		d.stats.NewTaggedStat("warehouse_schema_drift_check_failed", stats.CountType, stats.Tags{
+			"workspaceId":   warehouse.WorkspaceID,
+			"destinationID": warehouse.Destination.ID,
+			"destType":      warehouse.Type,
+		}).Count(1)
+	}
+}
+
+func (d *schemaDriftDetector) getReports(sourceID, destinationID string) []*schemaDriftReport {
+	d.reportsLock.RLock()
+	defer d.reportsLock.RUnlock()
+
+	reports := make([]*schemaDriftReport, 0, len(d.reports))
+	for _, report := range d.reports {
+		if sourceID != "" && report.SourceID != sourceID {
+			continue
+		}
+		if destinationID != "" && report.DestinationID != destinationID {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return schemaDriftReportKey(reports[i].SourceID, reports[i].DestinationID, reports[i].Namespace) <
+			schemaDriftReportKey(reports[j].SourceID, reports[j].DestinationID, reports[j].Namespace)
+	})
+	return reports
+}
+
+type schemaDriftCheckRequest struct {
+	SourceID      string `json:"source_id"`
+	DestinationID string `json:"destination_id"`
+	Repair        bool   `json:"repair"`
+}
+
+// schemaDriftHandler returns the latest schema drift reports on GET, optionally filtered by sourceId and destinationId,
+// and checks a source and destination right away on POST.
+func (d *schemaDriftDetector) schemaDriftHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	var reports []*schemaDriftReport
+	switch r.Method {
+	case http.MethodGet:
+		reports = d.getReports(r.URL.Query().Get("sourceId"), r.URL.Query().Get("destinationId"))
+	case http.MethodPost:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			pkgLogger.Errorf("[WH]: Error reading body: %v", err)
+			http.Error(w, "can't read body", http.StatusBadRequest)
+			return
+		}
+		defer r.Body.Close()
+
+		var req schemaDriftCheckRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			pkgLogger.Errorf("[WH]: Error unmarshalling body: %v", err)
+			http.Error(w, "can't unmarshall body", http.StatusBadRequest)
+			return
+		}
+
+		connectionsMapLock.RLock()
+		warehouse, err := getDestinationFromConnectionMap(req.DestinationID, req.SourceID)
+		connectionsMapLock.RUnlock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		reports = append(reports, d.check(r.Context(), warehouse, req.Repair))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resBody, err := json.Marshal(reports)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBody)
+}
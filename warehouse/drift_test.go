@@ -0,0 +1,113 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/warehouse/manager"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestDetectSchemaDrift(t *testing.T) {
+	expectedSchema := warehouseutils.SchemaT{
+		"tracks": {"id": "string", "received_at": "datetime", "revenue": "float"},
+		"pages":  {"id": "string"},
+	}
+	schemaInWarehouse := warehouseutils.SchemaT{
+		"tracks": {"id": "string", "revenue": "int", "extra": "string"},
+	}
+
+	drifts := detectSchemaDrift(warehouseutils.POSTGRES, driftOriginLocalSchema, expectedSchema, schemaInWarehouse)
+	require.Equal(t, []schemaDrift{
+		{Kind: driftMissingTable, Origin: driftOriginLocalSchema, Table: "pages"},
+		{Kind: driftMissingColumn, Origin: driftOriginLocalSchema, Table: "tracks", Column: "received_at", ExpectedType: "datetime"},
+		{Kind: driftTypeMismatch, Origin: driftOriginLocalSchema, Table: "tracks", Column: "revenue", ExpectedType: "float", ActualType: "int"},
+	}, drifts)
+
+	require.Empty(t, detectSchemaDrift(warehouseutils.POSTGRES, driftOriginLocalSchema, schemaInWarehouse, schemaInWarehouse))
+
+	t.Run("snowflake bigint", func(t *testing.T) {
+		expectedSchema := warehouseutils.SchemaT{"tracks": {"id": "string", "sent_at_ms": "bigint"}}
+		schemaInWarehouse := warehouseutils.SchemaT{"tracks": {"id": "string", "sent_at_ms": "int"}}
+		require.Empty(t, detectSchemaDrift(warehouseutils.SNOWFLAKE, driftOriginLocalSchema, expectedSchema, schemaInWarehouse), "bigint columns are fetched back as int")
+
+		schemaInWarehouse = warehouseutils.SchemaT{"tracks": {"id": "string", "sent_at_ms": "float"}}
+		require.Equal(t, []schemaDrift{
+			{Kind: driftTypeMismatch, Origin: driftOriginLocalSchema, Table: "tracks", Column: "sent_at_ms", ExpectedType: "bigint", ActualType: "float"},
+		}, detectSchemaDrift(warehouseutils.SNOWFLAKE, driftOriginLocalSchema, expectedSchema, schemaInWarehouse))
+	})
+
+	t.Run("text columns", func(t *testing.T) {
+		expectedSchema := warehouseutils.SchemaT{"tracks": {"id": "string", "properties": "text"}}
+		schemaInWarehouse := warehouseutils.SchemaT{"tracks": {"id": "string", "properties": "string"}}
+		require.Empty(t, detectSchemaDrift(warehouseutils.RS, driftOriginLocalSchema, expectedSchema, schemaInWarehouse))
+		require.Empty(t, detectSchemaDrift(warehouseutils.POSTGRES, driftOriginLocalSchema, expectedSchema, schemaInWarehouse))
+	})
+}
+
+func TestMergeSchemaDrifts(t *testing.T) {
+	local := []schemaDrift{
+		{Kind: driftMissingTable, Origin: driftOriginLocalSchema, Table: "pages"},
+		{Kind: driftMissingColumn, Origin: driftOriginLocalSchema, Table: "tracks", Column: "received_at", ExpectedType: "datetime"},
+	}
+	upload := []schemaDrift{
+		{Kind: driftMissingColumn, Origin: driftOriginUploadSchema, Table: "pages", Column: "id", ExpectedType: "string"},
+		{Kind: driftMissingColumn, Origin: driftOriginUploadSchema, Table: "tracks", Column: "received_at", ExpectedType: "datetime"},
+		{Kind: driftMissingColumn, Origin: driftOriginUploadSchema, Table: "tracks", Column: "context_ip", ExpectedType: "string"},
+	}
+
+	require.Equal(t, append(local, upload[2]), mergeSchemaDrifts(local, upload))
+}
+
+type driftRepairManager struct {
+	manager.ManagerI
+
+	schemaCreated bool
+	createdTables map[string]map[string]string
+	addedColumns  map[string][]warehouseutils.ColumnInfo
+}
+
+func (m *driftRepairManager) CreateSchema() error {
+	m.schemaCreated = true
+	return nil
+}
+
+func (m *driftRepairManager) CreateTable(tableName string, columnMap map[string]string) error {
+	m.createdTables[tableName] = columnMap
+	return nil
+}
+
+func (m *driftRepairManager) AddColumns(tableName string, columnsInfo []warehouseutils.ColumnInfo) error {
+	m.addedColumns[tableName] = columnsInfo
+	return nil
+}
+
+func TestRepairSchemaDrifts(t *testing.T) {
+	whManager := &driftRepairManager{
+		createdTables: map[string]map[string]string{},
+		addedColumns:  map[string][]warehouseutils.ColumnInfo{},
+	}
+	localSchema := warehouseutils.SchemaT{
+		"tracks": {"id": "string", "received_at": "datetime", "revenue": "float"},
+		"pages":  {"id": "string"},
+	}
+	uploadSchema := warehouseutils.SchemaT{
+		"pages": {"id": "string", "url": "string"},
+	}
+	drifts := []schemaDrift{
+		{Kind: driftMissingTable, Origin: driftOriginLocalSchema, Table: "pages"},
+		{Kind: driftMissingColumn, Origin: driftOriginLocalSchema, Table: "tracks", Column: "received_at", ExpectedType: "datetime"},
+		{Kind: driftTypeMismatch, Origin: driftOriginLocalSchema, Table: "tracks", Column: "revenue", ExpectedType: "float", ActualType: "int"},
+	}
+
+	err := repairSchemaDrifts(whManager, drifts, localSchema, uploadSchema)
+	require.NoError(t, err)
+
+	require.True(t, whManager.schemaCreated)
+	require.Equal(t, map[string]map[string]string{"pages": {"id": "string", "url": "string"}}, whManager.createdTables)
+	require.Equal(t, map[string][]warehouseutils.ColumnInfo{"tracks": {{Name: "received_at", Type: "datetime"}}}, whManager.addedColumns)
+	require.True(t, drifts[0].Repaired)
+	require.True(t, drifts[1].Repaired)
+	require.False(t, drifts[2].Repaired)
+}
@@ -0,0 +1,255 @@
+package identity
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+	"github.com/xitongsys/parquet-go/types"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const mappingsExportBatchSize = 10000
+
+var ErrIdentityNotFound = errors.New("identity not found")
+
+// mappingsParquetSchema is the schema of the parquet files the identity mappings are exported to
+var mappingsParquetSchema = []string{
+	fmt.Sprintf("name=merge_property_type, %s", warehouseutils.PARQUET_STRING),
+	fmt.Sprintf("name=merge_property_value, %s", warehouseutils.PARQUET_STRING),
+	fmt.Sprintf("name=rudder_id, %s", warehouseutils.PARQUET_STRING),
+	fmt.Sprintf("name=updated_at, %s", warehouseutils.PARQUET_TIMESTAMP_MICROS),
+}
+
+// MappingT maps a merge property, e.g. an anonymous_id, user_id or email, to the rudder_id it is resolved to.
+type MappingT struct {
+	MergePropertyType  string    `json:"mergePropertyType"`
+	MergePropertyValue string    `json:"mergePropertyValue"`
+	RudderID           string    `json:"rudderId"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// ClusterT is the connected identity cluster of a rudder_id, i.e. all the merge properties resolved to it.
+type ClusterT struct {
+	RudderID   string     `json:"rudderId"`
+	Identities []MappingT `json:"identities"`
+}
+
+// ResolveIdentity returns the identity clusters of the merge property value.
+// If mergePropertyType is empty, the value is looked up across all merge property types,
+// so that more than one cluster may be returned.
+func (idr *HandleT) ResolveIdentity(ctx context.Context, mergePropertyType, mergePropertyValue string) ([]ClusterT, error) {
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  merge_property_type,
+		  merge_property_value,
+		  rudder_id,
+		  updated_at
+		FROM
+		  %[1]s
+		WHERE
+		  rudder_id IN (
+			SELECT
+			  rudder_id
+			FROM
+			  %[1]s
+			WHERE
+			  merge_property_value = $1
+			  AND ($2 = '' OR merge_property_type = $2)
+		  )
+		ORDER BY
+		  rudder_id,
+		  updated_at,
+		  merge_property_type,
+		  merge_property_value;
+`,
+		idr.mappingsTable(),
+	)
+
+	rows, err := idr.DbHandle.QueryContext(ctx, sqlStatement, mergePropertyValue, mergePropertyType)
+	if err != nil {
+		return nil, fmt.Errorf("querying identity cluster: %w", err)
+	}
+	defer rows.Close()
+
+	var clusters []ClusterT
+	for rows.Next() {
+		var mapping MappingT
+		if err := rows.Scan(&mapping.MergePropertyType, &mapping.MergePropertyValue, &mapping.RudderID, &mapping.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scanning identity cluster: %w", err)
+		}
+		if len(clusters) == 0 || clusters[len(clusters)-1].RudderID != mapping.RudderID {
+			clusters = append(clusters, ClusterT{RudderID: mapping.RudderID})
+		}
+		clusters[len(clusters)-1].Identities = append(clusters[len(clusters)-1].Identities, mapping)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating identity cluster: %w", err)
+	}
+	if len(clusters) == 0 {
+		return nil, ErrIdentityNotFound
+	}
+	return clusters, nil
+}
+
+// ExportMappings writes the identity mappings table into a parquet file and uploads it to the object storage of the destination.
+// It returns the location of the uploaded file.
+func (idr *HandleT) ExportMappings(ctx context.Context) (location string, err error) {
+	tmpDirPath, err := misc.CreateTMPDIR()
+	if err != nil {
+		return "", fmt.Errorf("creating tmp dir: %w", err)
+	}
+	filePath := filepath.Join(
+		tmpDirPath,
+		misc.RudderIdentityMappingsTmp,
+		fmt.Sprintf(`%s_%s`, idr.Warehouse.Destination.DestinationDefinition.Name, idr.Warehouse.Destination.ID),
+		misc.FastUUID().String()+".parquet",
+	)
+	if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return "", fmt.Errorf("creating export dir: %w", err)
+	}
+	defer func() { _ = os.Remove(filePath) }()
+
+	totalRows, err := idr.writeMappingsToParquet(ctx, filePath)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("opening export file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	useRudderStorage := misc.IsConfiguredToUseRudderObjectStorage(idr.Warehouse.Destination.Config)
+	storageProvider := warehouseutils.ObjectStorageType(idr.Warehouse.Destination.DestinationDefinition.Name, idr.Warehouse.Destination.Config, useRudderStorage)
+	uploader, err := filemanager.DefaultFileManagerFactory.New(&filemanager.SettingsT{
+		Provider: storageProvider,
+		Config: misc.GetObjectStorageConfig(misc.ObjectStorageOptsT{
+			Provider:         storageProvider,
+			Config:           idr.Warehouse.Destination.Config,
+			UseRudderStorage: useRudderStorage,
+			WorkspaceID:      idr.Warehouse.Destination.WorkspaceID,
+		}),
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating file manager: %w", err)
+	}
+
+	output, err := uploader.Upload(
+		ctx,
+		file,
+		config.GetString("Warehouse.identityMappingsExportFolderName", "rudder-identity-mappings-exports"),
+		idr.Warehouse.Namespace,
+		idr.Warehouse.Destination.ID,
+		time.Now().UTC().Format("2006-01-02-15-04-05"),
+	)
+	if err != nil {
+		return "", fmt.Errorf("uploading export file: %w", err)
+	}
+	pkgLogger.Infof("IDR: Exported %d identity mappings of %s to %s", totalRows, idr.Warehouse.Identifier, output.Location)
+	return output.Location, nil
+}
+
+// writeMappingsToParquet writes the identity mappings table into a parquet file, paginating over the table by id.
+func (idr *HandleT) writeMappingsToParquet(ctx context.Context, filePath string) (totalRows int, err error) {
+	bufWriter, err := misc.CreateBufferedWriter(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("creating export file: %w", err)
+	}
+	pw, err := writer.NewCSVWriterFromWriter(mappingsParquetSchema, bufWriter, 1)
+	if err != nil {
+		_ = bufWriter.Close()
+		return 0, fmt.Errorf("creating parquet writer: %w", err)
+	}
+
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  id,
+		  merge_property_type,
+		  merge_property_value,
+		  rudder_id,
+		  updated_at
+		FROM
+		  %s
+		WHERE
+		  id > $1
+		ORDER BY
+		  id
+		LIMIT
+		  $2;
+`,
+		idr.mappingsTable(),
+	)
+
+	var lastID int64
+	for {
+		var batchRows int
+		batchRows, lastID, err = idr.writeMappingsBatch(ctx, pw, sqlStatement, lastID)
+		if err != nil {
+			_ = bufWriter.Close()
+			return 0, err
+		}
+		totalRows += batchRows
+		if batchRows < mappingsExportBatchSize {
+			break
+		}
+	}
+
+	if err = pw.WriteStop(); err != nil {
+		_ = bufWriter.Close()
+		return 0, fmt.Errorf("writing parquet footer: %w", err)
+	}
+	if err = bufWriter.Close(); err != nil {
+		return 0, fmt.Errorf("closing export file: %w", err)
+	}
+	return totalRows, nil
+}
+
+func (idr *HandleT) writeMappingsBatch(ctx context.Context, pw *writer.CSVWriter, sqlStatement string, afterID int64) (batchRows int, lastID int64, err error) {
+	rows, err := idr.DbHandle.QueryContext(ctx, sqlStatement, afterID, mappingsExportBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("querying identity mappings: %w", err)
+	}
+	defer rows.Close()
+
+	lastID = afterID
+	for rows.Next() {
+		var mapping MappingT
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&lastID, &mapping.MergePropertyType, &mapping.MergePropertyValue, &mapping.RudderID, &updatedAt); err != nil {
+			return 0, 0, fmt.Errorf("scanning identity mappings: %w", err)
+		}
+		mapping.UpdatedAt = updatedAt.Time
+		if err := pw.Write(mappingParquetRow(mapping)); err != nil {
+			return 0, 0, fmt.Errorf("writing identity mapping: %w", err)
+		}
+		batchRows++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("iterating identity mappings: %w", err)
+	}
+	return batchRows, lastID, nil
+}
+
+// mappingParquetRow returns the values of the mapping in the order of mappingsParquetSchema
+func mappingParquetRow(mapping MappingT) []interface{} {
+	var updatedAt interface{}
+	if !mapping.UpdatedAt.IsZero() {
+		updatedAt = types.TimeToTIMESTAMP_MICROS(mapping.UpdatedAt, false)
+	}
+	return []interface{}{
+		mapping.MergePropertyType,
+		mapping.MergePropertyValue,
+		mapping.RudderID,
+		updatedAt,
+	}
+}
@@ -0,0 +1,129 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	"github.com/rudderlabs/rudder-server/testhelper/destination"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestIdentityGraph(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	pgResource, err := destination.SetupPostgres(pool, t)
+	require.NoError(t, err)
+	minioResource, err := destination.SetupMINIO(pool, t)
+	require.NoError(t, err)
+
+	storageConfig := map[string]interface{}{
+		"bucketProvider":  warehouseutils.MINIO,
+		"bucketName":      minioResource.BucketName,
+		"endPoint":        minioResource.Endpoint,
+		"accessKeyID":     minioResource.AccessKey,
+		"secretAccessKey": minioResource.SecretKey,
+		"useSSL":          false,
+	}
+	idr := &HandleT{
+		Warehouse: warehouseutils.Warehouse{
+			Namespace: "namespace",
+			Destination: backendconfig.DestinationT{
+				ID:                    "destination_id",
+				Config:                storageConfig,
+				DestinationDefinition: backendconfig.DestinationDefinitionT{Name: warehouseutils.POSTGRES},
+			},
+		},
+		DbHandle: pgResource.DB,
+	}
+
+	_, err = pgResource.DB.Exec(fmt.Sprintf(`
+		CREATE TABLE %s (
+		  id BIGSERIAL PRIMARY KEY,
+		  merge_property_type VARCHAR(64) NOT NULL,
+		  merge_property_value TEXT NOT NULL,
+		  rudder_id VARCHAR(64) NOT NULL,
+		  updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+		);`, idr.mappingsTable()))
+	require.NoError(t, err)
+
+	updatedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	mappings := []MappingT{
+		{MergePropertyType: "anonymous_id", MergePropertyValue: "anon-1", RudderID: "rudder-1", UpdatedAt: updatedAt},
+		{MergePropertyType: "user_id", MergePropertyValue: "user-1", RudderID: "rudder-1", UpdatedAt: updatedAt.Add(time.Hour)},
+		{MergePropertyType: "email", MergePropertyValue: "user-1", RudderID: "rudder-2", UpdatedAt: updatedAt},
+	}
+	for _, mapping := range mappings {
+		_, err := pgResource.DB.Exec(
+			fmt.Sprintf(`INSERT INTO %s (merge_property_type, merge_property_value, rudder_id, updated_at) VALUES ($1, $2, $3, $4)`, idr.mappingsTable()),
+			mapping.MergePropertyType, mapping.MergePropertyValue, mapping.RudderID, mapping.UpdatedAt,
+		)
+		require.NoError(t, err)
+	}
+
+	// resolve returns the clusters of the merge property with their times in UTC, to be comparable with the mappings
+	resolve := func(t *testing.T, mergePropertyType, mergePropertyValue string) ([]ClusterT, error) {
+		t.Helper()
+		clusters, err := idr.ResolveIdentity(context.Background(), mergePropertyType, mergePropertyValue)
+		for _, cluster := range clusters {
+			for i := range cluster.Identities {
+				cluster.Identities[i].UpdatedAt = cluster.Identities[i].UpdatedAt.UTC()
+			}
+		}
+		return clusters, err
+	}
+
+	t.Run("resolve cluster", func(t *testing.T) {
+		clusters, err := resolve(t, "anonymous_id", "anon-1")
+		require.NoError(t, err)
+		require.Equal(t, []ClusterT{{RudderID: "rudder-1", Identities: mappings[:2]}}, clusters)
+	})
+
+	t.Run("resolve across merge property types", func(t *testing.T) {
+		clusters, err := resolve(t, "", "user-1")
+		require.NoError(t, err)
+		require.Equal(t, []ClusterT{
+			{RudderID: "rudder-1", Identities: mappings[:2]},
+			{RudderID: "rudder-2", Identities: mappings[2:]},
+		}, clusters)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := resolve(t, "user_id", "anon-1")
+		require.ErrorIs(t, err, ErrIdentityNotFound)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		location, err := idr.ExportMappings(context.Background())
+		require.NoError(t, err)
+
+		fm, err := filemanager.DefaultFileManagerFactory.New(&filemanager.SettingsT{Provider: warehouseutils.MINIO, Config: storageConfig})
+		require.NoError(t, err)
+		objectName, err := fm.GetObjectNameFromLocation(location)
+		require.NoError(t, err)
+
+		filePath := filepath.Join(t.TempDir(), "mappings.parquet")
+		file, err := os.Create(filePath)
+		require.NoError(t, err)
+		require.NoError(t, fm.Download(context.Background(), file, objectName))
+		require.NoError(t, file.Close())
+
+		fr, err := local.NewLocalFileReader(filePath)
+		require.NoError(t, err)
+		defer func() { _ = fr.Close() }()
+		pr, err := reader.NewParquetReader(fr, nil, 1)
+		require.NoError(t, err)
+		defer pr.ReadStop()
+		require.EqualValues(t, len(mappings), pr.GetNumRows())
+	})
+}
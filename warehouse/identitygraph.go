@@ -0,0 +1,173 @@
+package warehouse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/warehouse/identity"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var errIdentityResolutionNotEnabled = errors.New("identity resolution is not enabled for destination")
+
+// identityResolveRequest looks up the identity clusters of a merge property value, e.g. an anonymous_id, user_id or email
+type identityResolveRequest struct {
+	SourceID           string
+	DestinationID      string
+	MergePropertyType  string
+	MergePropertyValue string
+}
+
+type identityResolveResponse struct {
+	Clusters []identity.ClusterT `json:"clusters"`
+}
+
+type identityExportRequest struct {
+	SourceID      string `json:"source_id"`
+	DestinationID string `json:"destination_id"`
+}
+
+type identityExportResponse struct {
+	Location string `json:"location"`
+}
+
+// parseIdentityResolveRequest parses the query params of an identity resolve request.
+// The merge property type is optional, in which case the value is looked up across all types.
+func parseIdentityResolveRequest(query url.Values) (identityResolveRequest, error) {
+	req := identityResolveRequest{
+		SourceID:           query.Get("sourceId"),
+		DestinationID:      query.Get("destinationId"),
+		MergePropertyType:  query.Get("type"),
+		MergePropertyValue: query.Get("value"),
+	}
+	if req.SourceID == "" || req.DestinationID == "" {
+		return identityResolveRequest{}, errors.New("sourceId and destinationId are required")
+	}
+	if req.MergePropertyValue == "" {
+		return identityResolveRequest{}, errors.New("value is required")
+	}
+	return req, nil
+}
+
+// identityWarehouse returns the warehouse of the source and destination, if identity resolution is enabled for it
+func identityWarehouse(sourceID, destinationID string) (warehouseutils.Warehouse, error) {
+	connectionsMapLock.RLock()
+	warehouse, err := getDestinationFromConnectionMap(destinationID, sourceID)
+	connectionsMapLock.RUnlock()
+	if err != nil {
+		return warehouseutils.Warehouse{}, err
+	}
+	if !warehouseutils.IDResolutionEnabled() || !misc.Contains(warehouseutils.IdentityEnabledWarehouses, warehouse.Type) {
+		return warehouseutils.Warehouse{}, errIdentityResolutionNotEnabled
+	}
+	return warehouse, nil
+}
+
+// identityResolveHandler returns the resolved rudder_id and the connected identity cluster of a merge property value
+func identityResolveHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := parseIdentityResolveRequest(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	warehouse, err := identityWarehouse(req.SourceID, req.DestinationID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	idr := identity.HandleT{
+		Warehouse: warehouse,
+		DbHandle:  dbHandle,
+	}
+	clusters, err := idr.ResolveIdentity(r.Context(), req.MergePropertyType, req.MergePropertyValue)
+	if errors.Is(err, identity.ErrIdentityNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error resolving identity for %s: %v", warehouse.Identifier, err)
+		http.Error(w, "can't resolve identity", http.StatusInternalServerError)
+		return
+	}
+
+	resBody, err := json.Marshal(identityResolveResponse{Clusters: clusters})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBody)
+}
+
+// identityExportHandler exports the identity mappings of a destination as parquet to its object storage.
+// The export is cancelled after identityMappingsExportTimeout, so that large tables don't hold the request forever.
+func identityExportHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error reading body: %v", err)
+		http.Error(w, "can't read body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var req identityExportRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		pkgLogger.Errorf("[WH]: Error unmarshalling body: %v", err)
+		http.Error(w, "can't unmarshall body", http.StatusBadRequest)
+		return
+	}
+
+	warehouse, err := identityWarehouse(req.SourceID, req.DestinationID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	idr := identity.HandleT{
+		Warehouse: warehouse,
+		DbHandle:  dbHandle,
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), identityMappingsExportTimeout)
+	defer cancel()
+	location, err := idr.ExportMappings(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		pkgLogger.Errorf("[WH]: Timed out exporting identity mappings for %s: %v", warehouse.Identifier, err)
+		http.Error(w, "timed out exporting identity mappings", http.StatusGatewayTimeout)
+		return
+	}
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error exporting identity mappings for %s: %v", warehouse.Identifier, err)
+		http.Error(w, fmt.Sprintf("can't export identity mappings: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	resBody, err := json.Marshal(identityExportResponse{Location: location})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBody)
+}
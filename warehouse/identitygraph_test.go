@@ -0,0 +1,76 @@
+package warehouse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/utils/logger"
+)
+
+func TestParseIdentityResolveRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   url.Values
+		want    identityResolveRequest
+		wantErr bool
+	}{
+		{
+			name:  "with merge property type",
+			query: url.Values{"sourceId": {"source"}, "destinationId": {"destination"}, "type": {"email"}, "value": {"user@example.com"}},
+			want: identityResolveRequest{
+				SourceID:           "source",
+				DestinationID:      "destination",
+				MergePropertyType:  "email",
+				MergePropertyValue: "user@example.com",
+			},
+		},
+		{
+			name:  "without merge property type",
+			query: url.Values{"sourceId": {"source"}, "destinationId": {"destination"}, "value": {"anon-1"}},
+			want: identityResolveRequest{
+				SourceID:           "source",
+				DestinationID:      "destination",
+				MergePropertyValue: "anon-1",
+			},
+		},
+		{
+			name:    "missing destination",
+			query:   url.Values{"sourceId": {"source"}, "value": {"anon-1"}},
+			wantErr: true,
+		},
+		{
+			name:    "missing value",
+			query:   url.Values{"sourceId": {"source"}, "destinationId": {"destination"}, "type": {"user_id"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := parseIdentityResolveRequest(tc.query)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, req)
+		})
+	}
+}
+
+func TestIdentityHandlersMethodNotAllowed(t *testing.T) {
+	pkgLogger = logger.NOP
+
+	resp := httptest.NewRecorder()
+	identityExportHandler(resp, httptest.NewRequest(http.MethodGet, "/v1/warehouse/identity/export", http.NoBody))
+	require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+
+	resp = httptest.NewRecorder()
+	identityResolveHandler(resp, httptest.NewRequest(http.MethodPost, "/v1/warehouse/identity", http.NoBody))
+	require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
@@ -0,0 +1,104 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/cron"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "every minute", expr: "* * * * *"},
+		{name: "steps and ranges", expr: "*/15 9-17 * * 1-5"},
+		{name: "lists and names", expr: "0 0,12 1 jan,jul MON-FRI"},
+		{name: "sunday as 7", expr: "0 0 * * 7"},
+		{name: "missing field", expr: "* * * *", wantErr: true},
+		{name: "out of range", expr: "60 * * * *", wantErr: true},
+		{name: "invalid step", expr: "*/0 * * * *", wantErr: true},
+		{name: "inverted range", expr: "* 17-9 * * *", wantErr: true},
+		{name: "invalid name", expr: "* * * * funday", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := cron.Parse(tc.expr)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expr     string
+		now      time.Time
+		wantNext time.Time
+		wantPrev time.Time
+	}{
+		{
+			name:     "every 15 minutes",
+			expr:     "*/15 * * * *",
+			now:      time.Date(2022, 12, 6, 10, 7, 30, 0, time.UTC),
+			wantNext: time.Date(2022, 12, 6, 10, 15, 0, 0, time.UTC),
+			wantPrev: time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "activation time is included in prev but not in next",
+			expr:     "0 * * * *",
+			now:      time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2022, 12, 6, 11, 0, 0, 0, time.UTC),
+			wantPrev: time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekdays at night",
+			expr:     "30 22 * * mon-fri",
+			now:      time.Date(2022, 12, 10, 12, 0, 0, 0, time.UTC), // saturday
+			wantNext: time.Date(2022, 12, 12, 22, 30, 0, 0, time.UTC),
+			wantPrev: time.Date(2022, 12, 9, 22, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "across years",
+			expr:     "0 0 1 1 *",
+			now:      time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC),
+			wantNext: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantPrev: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day of month or day of week",
+			expr:     "0 0 15 * sun",
+			now:      time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC), // tuesday
+			wantNext: time.Date(2022, 12, 11, 0, 0, 0, 0, time.UTC),
+			wantPrev: time.Date(2022, 12, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "never",
+			expr: "0 0 30 feb *",
+			now:  time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := cron.Parse(tc.expr)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantNext, s.Next(tc.now))
+			require.Equal(t, tc.wantPrev, s.Prev(tc.now))
+			if !tc.wantNext.IsZero() {
+				require.True(t, s.Matches(tc.wantNext))
+			}
+		})
+	}
+}
@@ -30,6 +30,8 @@ type StagingFile struct {
 	SourceJobID     string
 	SourceJobRunID  string
 	TimeWindow      time.Time
+	// BackfillID is set for staging files restored from the archive for a backfill upload
+	BackfillID string
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
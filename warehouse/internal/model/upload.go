@@ -9,6 +9,7 @@ const (
 	CreatedRemoteSchema       = "created_remote_schema"
 	ExportedUserTables        = "exported_user_tables"
 	ExportedData              = "exported_data"
+	PartiallyExportedData     = "partially_exported_data"
 	ExportedIdentities        = "exported_identities"
 	Aborted                   = "aborted"
 )
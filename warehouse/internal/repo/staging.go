@@ -52,6 +52,7 @@ type metadataSchema struct {
 	TimeWindowDay         int    `json:"time_window_day"`
 	TimeWindowHour        int    `json:"time_window_hour"`
 	DestinationRevisionID string `json:"destination_revision_id"`
+	BackfillID            string `json:"backfill_id,omitempty"`
 }
 
 func metadataFromStagingFile(stagingFile *model.StagingFile) metadataSchema {
@@ -67,6 +68,7 @@ func metadataFromStagingFile(stagingFile *model.StagingFile) metadataSchema {
 		TimeWindowDay:         stagingFile.TimeWindow.Day(),
 		TimeWindowHour:        stagingFile.TimeWindow.Hour(),
 		DestinationRevisionID: stagingFile.DestinationRevisionID,
+		BackfillID:            stagingFile.BackfillID,
 	}
 }
 
@@ -79,6 +81,7 @@ func (m *metadataSchema) SetStagingFile(stagingFile *model.StagingFile) {
 	stagingFile.SourceJobRunID = m.SourceJobRunID
 	stagingFile.TimeWindow = time.Date(m.TimeWindowYear, time.Month(m.TimeWindowMonth), m.TimeWindowDay, m.TimeWindowHour, 0, 0, 0, time.UTC)
 	stagingFile.DestinationRevisionID = m.DestinationRevisionID
+	stagingFile.BackfillID = m.BackfillID
 }
 
 func (repo *StagingFiles) init() {
@@ -97,6 +100,19 @@ func (repo *StagingFiles) init() {
 // - CreatedAt
 // - UpdatedAt
 func (repo *StagingFiles) Insert(ctx context.Context, stagingFile *model.StagingFileWithSchema) (int64, error) {
+	return repo.insert(ctx, repo.DB, stagingFile)
+}
+
+// InsertTx inserts a staging file as part of the transaction, see Insert.
+func (repo *StagingFiles) InsertTx(ctx context.Context, tx *sql.Tx, stagingFile *model.StagingFileWithSchema) (int64, error) {
+	return repo.insert(ctx, tx, stagingFile)
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (repo *StagingFiles) insert(ctx context.Context, db queryRower, stagingFile *model.StagingFileWithSchema) (int64, error) {
 	repo.init()
 
 	var (
@@ -126,7 +142,7 @@ func (repo *StagingFiles) Insert(ctx context.Context, stagingFile *model.Staging
 		return id, fmt.Errorf("marshaling schema: %w", err)
 	}
 
-	err = repo.DB.QueryRowContext(ctx,
+	err = db.QueryRowContext(ctx,
 		`INSERT INTO `+stagingTableName+` (
 			location,
 			schema,
@@ -273,6 +289,7 @@ func (repo *StagingFiles) GetSchemaByID(ctx context.Context, ID int64) (json.Raw
 }
 
 // GetInRange returns staging files in [startID, endID] range inclusive.
+// Staging files restored for backfills are excluded, see GetForBackfill.
 func (repo *StagingFiles) GetInRange(ctx context.Context, sourceID, destinationID string, startID, endID int64) ([]model.StagingFile, error) {
 	repo.init()
 
@@ -281,6 +298,7 @@ func (repo *StagingFiles) GetInRange(ctx context.Context, sourceID, destinationI
 		id >= $1 AND id <= $2
 		AND source_id = $3
 		AND destination_id = $4
+		AND metadata ->> 'backfill_id' IS NULL
 	ORDER BY
 		id ASC;`
 
@@ -293,6 +311,7 @@ func (repo *StagingFiles) GetInRange(ctx context.Context, sourceID, destinationI
 }
 
 // GetAfterID returns staging files in (startID, +Inf) range.
+// Staging files restored for backfills are excluded, see GetForBackfill.
 func (repo *StagingFiles) GetAfterID(ctx context.Context, sourceID, destinationID string, startID int64) ([]model.StagingFile, error) {
 	repo.init()
 
@@ -301,6 +320,7 @@ func (repo *StagingFiles) GetAfterID(ctx context.Context, sourceID, destinationI
 		id > $1
 		AND source_id = $2
 		AND destination_id = $3
+		AND metadata ->> 'backfill_id' IS NULL
 	ORDER BY
 		id ASC;`
 
@@ -311,3 +331,23 @@ func (repo *StagingFiles) GetAfterID(ctx context.Context, sourceID, destinationI
 
 	return repo.parseRows(rows)
 }
+
+// GetForBackfill returns staging files restored from the archive for the given backfill.
+func (repo *StagingFiles) GetForBackfill(ctx context.Context, sourceID, destinationID, backfillID string) ([]model.StagingFile, error) {
+	repo.init()
+
+	query := `SELECT ` + stagingTableColumns + ` FROM ` + stagingTableName + `
+	WHERE
+		source_id = $1
+		AND destination_id = $2
+		AND metadata ->> 'backfill_id' = $3
+	ORDER BY
+		id ASC;`
+
+	rows, err := repo.DB.QueryContext(ctx, query, sourceID, destinationID, backfillID)
+	if err != nil {
+		return nil, fmt.Errorf("querying staging files: %w", err)
+	}
+
+	return repo.parseRows(rows)
+}
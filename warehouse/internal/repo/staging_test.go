@@ -144,6 +144,28 @@ func TestStagingFileRepo(t *testing.T) {
 		_, err := r.GetByID(ctx, -1)
 		require.EqualError(t, err, "no staging file found with id: -1")
 	})
+
+	t.Run("insert in transaction", func(t *testing.T) {
+		stagingFile := testcases[0].stagingFile
+
+		txn, err := r.DB.Begin()
+		require.NoError(t, err)
+		id, err := r.InsertTx(ctx, txn, &stagingFile)
+		require.NoError(t, err)
+		require.NoError(t, txn.Rollback())
+
+		_, err = r.GetByID(ctx, id)
+		require.EqualError(t, err, fmt.Sprintf("no staging file found with id: %d", id))
+
+		txn, err = r.DB.Begin()
+		require.NoError(t, err)
+		id, err = r.InsertTx(ctx, txn, &stagingFile)
+		require.NoError(t, err)
+		require.NoError(t, txn.Commit())
+
+		_, err = r.GetByID(ctx, id)
+		require.NoError(t, err)
+	})
 }
 
 func TestStagingFileRepo_Many(t *testing.T) {
@@ -192,6 +214,41 @@ func TestStagingFileRepo_Many(t *testing.T) {
 		stagingFiles = append(stagingFiles, file.StagingFile)
 	}
 
+	var backfillStagingFiles []model.StagingFile
+	for i := 0; i < 2; i++ {
+		file := model.StagingFile{
+			WorkspaceID:   "workspace_id",
+			Location:      fmt.Sprintf("s3://bucket/path/to/backfill-file-%d", i),
+			SourceID:      "source_id",
+			DestinationID: "destination_id",
+			Status:        warehouseutils.StagingFileWaitingState,
+			TotalEvents:   100,
+			BackfillID:    "backfill_id",
+		}.WithSchema([]byte(`{"type": "object"}`))
+
+		id, err := r.Insert(ctx, &file)
+		require.NoError(t, err)
+
+		file.ID = id
+		file.CreatedAt = now
+		file.UpdatedAt = now
+		file.TimeWindow = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		backfillStagingFiles = append(backfillStagingFiles, file.StagingFile)
+	}
+
+	t.Run("GetForBackfill", func(t *testing.T) {
+		t.Parallel()
+
+		retrieved, err := r.GetForBackfill(ctx, "source_id", "destination_id", "backfill_id")
+		require.NoError(t, err)
+		require.Equal(t, backfillStagingFiles, retrieved)
+
+		retrieved, err = r.GetForBackfill(ctx, "source_id", "destination_id", "bad_backfill_id")
+		require.NoError(t, err)
+		require.Empty(t, retrieved)
+	})
+
 	t.Run("GetInRange", func(t *testing.T) {
 		t.Parallel()
 
@@ -209,7 +266,7 @@ func TestStagingFileRepo_Many(t *testing.T) {
 				sourceID:      "source_id",
 				destinationID: "destination_id",
 				startID:       0,
-				endID:         10,
+				endID:         12,
 
 				expected: stagingFiles,
 			},
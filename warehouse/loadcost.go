@@ -0,0 +1,180 @@
+package warehouse
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+const defaultLoadCostsWindow = 30 * 24 * time.Hour
+
+// loadCostsRequest filters the load costs aggregated per source, destination and day
+type loadCostsRequest struct {
+	SourceID      string
+	DestinationID string
+	Start         time.Time
+	End           time.Time
+}
+
+// loadCostT is the cost of loading the tables of a source into a destination on a day
+type loadCostT struct {
+	SourceID        string    `json:"sourceId"`
+	DestinationID   string    `json:"destinationId"`
+	DestinationType string    `json:"destinationType"`
+	Day             time.Time `json:"day"`
+	TablesLoaded    int64     `json:"tablesLoaded"`
+	LoadDurationMs  int64     `json:"loadDurationMs"`
+	BytesLoaded     int64     `json:"bytesLoaded"`
+	Credits         float64   `json:"credits"`
+	BytesBilled     int64     `json:"bytesBilled"`
+	QueryTimeMs     int64     `json:"queryTimeMs"`
+}
+
+// parseLoadCostsRequest parses the query params of a load costs request, with start and end days formatted as 2006-01-02.
+// By default, the costs of the last 30 days are returned.
+func parseLoadCostsRequest(query url.Values, now time.Time) (loadCostsRequest, error) {
+	req := loadCostsRequest{
+		SourceID:      query.Get("sourceId"),
+		DestinationID: query.Get("destinationId"),
+		End:           timeutil.StartOfDay(now).AddDate(0, 0, 1),
+	}
+	req.Start = req.End.Add(-defaultLoadCostsWindow)
+
+	var err error
+	if start := query.Get("start"); start != "" {
+		if req.Start, err = time.Parse("2006-01-02", start); err != nil {
+			return loadCostsRequest{}, fmt.Errorf("invalid start: %w", err)
+		}
+	}
+	if end := query.Get("end"); end != "" {
+		if req.End, err = time.Parse("2006-01-02", end); err != nil {
+			return loadCostsRequest{}, fmt.Errorf("invalid end: %w", err)
+		}
+		// end day is inclusive
+		req.End = req.End.AddDate(0, 0, 1)
+	}
+	if !req.Start.Before(req.End) {
+		return loadCostsRequest{}, errors.New("start must be before end")
+	}
+	return req, nil
+}
+
+// getLoadCosts aggregates the load durations, bytes and warehouse cost signals of table uploads per source, destination and day
+func getLoadCosts(db *sql.DB, req loadCostsRequest) ([]loadCostT, error) {
+	filters := []string{
+		"t.load_duration_ms IS NOT NULL",
+		"t.last_exec_time >= $1",
+		"t.last_exec_time < $2",
+	}
+	args := []interface{}{req.Start, req.End}
+	if req.SourceID != "" {
+		args = append(args, req.SourceID)
+		filters = append(filters, fmt.Sprintf("u.source_id = $%d", len(args)))
+	}
+	if req.DestinationID != "" {
+		args = append(args, req.DestinationID)
+		filters = append(filters, fmt.Sprintf("u.destination_id = $%d", len(args)))
+	}
+
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  u.source_id,
+		  u.destination_id,
+		  u.destination_type,
+		  date_trunc('day', t.last_exec_time) AS day,
+		  COUNT(*),
+		  COALESCE(SUM(t.load_duration_ms), 0),
+		  COALESCE(SUM(t.bytes_loaded), 0),
+		  COALESCE(SUM((t.load_cost ->> 'credits')::double precision), 0),
+		  COALESCE(SUM((t.load_cost ->> 'bytesBilled')::bigint), 0),
+		  COALESCE(SUM((t.load_cost ->> 'queryTimeMs')::bigint), 0)
+		FROM
+		  %[1]s t
+		  JOIN %[2]s u ON u.id = t.wh_upload_id
+		WHERE
+		  %[3]s
+		GROUP BY
+		  u.source_id,
+		  u.destination_id,
+		  u.destination_type,
+		  day
+		ORDER BY
+		  day DESC,
+		  u.source_id,
+		  u.destination_id;
+`,
+		warehouseutils.WarehouseTableUploadsTable,
+		warehouseutils.WarehouseUploadsTable,
+		strings.Join(filters, " AND "),
+	)
+
+	rows, err := db.Query(sqlStatement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying load costs: %w", err)
+	}
+	defer rows.Close()
+
+	var loadCosts []loadCostT
+	for rows.Next() {
+		var loadCost loadCostT
+		err := rows.Scan(
+			&loadCost.SourceID,
+			&loadCost.DestinationID,
+			&loadCost.DestinationType,
+			&loadCost.Day,
+			&loadCost.TablesLoaded,
+			&loadCost.LoadDurationMs,
+			&loadCost.BytesLoaded,
+			&loadCost.Credits,
+			&loadCost.BytesBilled,
+			&loadCost.QueryTimeMs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning load costs: %w", err)
+		}
+		loadCosts = append(loadCosts, loadCost)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating load costs: %w", err)
+	}
+	return loadCosts, nil
+}
+
+// loadCostsHandler returns the load costs aggregated per source, destination and day
+func loadCostsHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := parseLoadCostsRequest(r.URL.Query(), timeutil.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	loadCosts, err := getLoadCosts(dbHandle, req)
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error getting load costs: %v", err)
+		http.Error(w, "can't get load costs", http.StatusInternalServerError)
+		return
+	}
+
+	resBody, err := json.Marshal(loadCosts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBody)
+}
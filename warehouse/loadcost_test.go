@@ -0,0 +1,62 @@
+package warehouse
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLoadCostsRequest(t *testing.T) {
+	now := time.Date(2022, 12, 6, 10, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		query   url.Values
+		want    loadCostsRequest
+		wantErr bool
+	}{
+		{
+			name:  "defaults to last 30 days",
+			query: url.Values{"sourceId": {"source"}},
+			want: loadCostsRequest{
+				SourceID: "source",
+				Start:    time.Date(2022, 11, 7, 0, 0, 0, 0, time.UTC),
+				End:      time.Date(2022, 12, 7, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "inclusive end day",
+			query: url.Values{"destinationId": {"destination"}, "start": {"2022-12-01"}, "end": {"2022-12-01"}},
+			want: loadCostsRequest{
+				DestinationID: "destination",
+				Start:         time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC),
+				End:           time.Date(2022, 12, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:    "invalid start",
+			query:   url.Values{"start": {"yesterday"}},
+			wantErr: true,
+		},
+		{
+			name:    "start after end",
+			query:   url.Values{"start": {"2022-12-02"}, "end": {"2022-12-01"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := parseLoadCostsRequest(tc.query, now)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, req)
+		})
+	}
+}
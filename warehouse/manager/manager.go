@@ -48,6 +48,19 @@ type WarehouseDelete interface {
 	DeleteBy(tableName []string, params warehouseutils.DeleteByParams) error
 }
 
+// LoadCostReporter is implemented by warehouses reporting the cost signals of the tables they load.
+type LoadCostReporter interface {
+	// LoadTableCost returns the cost of the last load of the table, if any.
+	LoadTableCost(tableName string) (warehouseutils.LoadCostT, bool)
+}
+
+// StreamLoader is implemented by warehouses supporting the streaming micro-batch load path.
+type StreamLoader interface {
+	// LoadStreamingBatch loads the rows into the table in a single transaction. The table schema lists the columns of the rows.
+	// Rows of retried batches must not be duplicated and users must be merged on their id.
+	LoadStreamingBatch(tableName string, tableSchema warehouseutils.TableSchemaT, rows []map[string]interface{}) error
+}
+
 type WarehouseOperations interface {
 	ManagerI
 	WarehouseDelete
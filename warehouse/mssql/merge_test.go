@@ -0,0 +1,91 @@
+package mssql
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/testhelper/destination"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestMergeStatement(t *testing.T) {
+	statement := mergeStatement("namespace", "orders", "SELECT * FROM staging", []string{"id", "received_at", "store_id", "total"}, []string{"id", "store_id"})
+	require.Contains(t, statement, `MERGE INTO "namespace"."orders" AS _target`)
+	require.Contains(t, statement, `USING (SELECT * FROM staging) AS _source`)
+	require.Contains(t, statement, `ON ((_target."id" = _source."id" OR (_target."id" IS NULL AND _source."id" IS NULL)) AND (_target."store_id" = _source."store_id" OR (_target."store_id" IS NULL AND _source."store_id" IS NULL)))`)
+	require.Contains(t, statement, `WHEN MATCHED THEN UPDATE SET "received_at" = _source."received_at", "total" = _source."total"`)
+	require.Contains(t, statement, `WHEN NOT MATCHED THEN INSERT ("id","received_at","store_id","total") VALUES (_source."id", _source."received_at", _source."store_id", _source."total");`)
+
+	statement = mergeStatement("namespace", "orders", "SELECT * FROM staging", []string{"id"}, []string{"id"})
+	require.NotContains(t, statement, "WHEN MATCHED")
+}
+
+func TestMergeFromStagingTable(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	mssqlResource, err := destination.SetupMSSQL(pool, t)
+	require.NoError(t, err)
+	db := mssqlResource.DB
+
+	Init()
+	const namespace = "merge_test"
+	_, err = db.Exec(fmt.Sprintf(`CREATE SCHEMA %q`, namespace))
+	require.NoError(t, err)
+
+	ms := &HandleT{
+		Db:        db,
+		Namespace: namespace,
+		Warehouse: warehouseutils.Warehouse{
+			Destination: backendconfig.DestinationT{
+				Config: map[string]interface{}{
+					warehouseutils.LoadModeConfig:         warehouseutils.MergeLoadMode,
+					warehouseutils.MergePrimaryKeysConfig: map[string]interface{}{"payments": "store_id,order_id"},
+				},
+			},
+		},
+	}
+	columns := []string{"id", "order_id", "received_at", "store_id", "total"}
+
+	for _, name := range []string{"payments", "payments_staging"} {
+		_, err := db.Exec(fmt.Sprintf(`CREATE TABLE %q.%q (id NVARCHAR(64), order_id NVARCHAR(64), received_at DATETIMEOFFSET, store_id NVARCHAR(64), total BIGINT)`, namespace, name))
+		require.NoError(t, err)
+	}
+	_, err = db.Exec(fmt.Sprintf(`INSERT INTO %q."payments" VALUES
+		('1', NULL, '2022-01-01', 's1', 1),
+		('2', 'o1', '2022-01-01', 's1', 2),
+		('3', NULL, '2022-01-01', NULL, 3)`, namespace))
+	require.NoError(t, err)
+	_, err = db.Exec(fmt.Sprintf(`INSERT INTO %q."payments_staging" VALUES
+		('4', NULL, '2022-01-02', 's1', 4),
+		('5', 'o1', '2022-01-02', 's1', 5),
+		('6', NULL, '2022-01-02', NULL, 6),
+		('7', NULL, '2022-01-03', NULL, 7)`, namespace))
+	require.NoError(t, err)
+
+	txn, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, ms.mergeFromStagingTable(txn, "payments", "payments_staging", columns))
+	require.NoError(t, txn.Commit())
+
+	rows, err := db.Query(fmt.Sprintf(`SELECT store_id, order_id, total FROM %q."payments" ORDER BY total`, namespace))
+	require.NoError(t, err)
+	defer func() { _ = rows.Close() }()
+	var result [][]interface{}
+	for rows.Next() {
+		var storeID, orderID sql.NullString
+		var total int
+		require.NoError(t, rows.Scan(&storeID, &orderID, &total))
+		result = append(result, []interface{}{storeID.String, orderID.String, total})
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, [][]interface{}{
+		{"s1", "", 4},
+		{"s1", "o1", 5},
+		{"", "", 7},
+	}, result, "rows with NULL keys are merged as well")
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/rudderlabs/rudder-server/warehouse/client"
 	"github.com/rudderlabs/rudder-server/warehouse/tunnelling"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+	"golang.org/x/exp/slices"
 )
 
 var (
@@ -461,7 +462,29 @@ func (ms *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 		return
 
 	}
-	// deduplication process
+	if ms.Warehouse.GetLoadMode() == warehouseutils.MergeLoadMode && tableName != warehouseutils.DiscardsTable {
+		err = ms.mergeFromStagingTable(txn, tableName, stagingTableName, sortedColumnKeys)
+	} else {
+		err = ms.appendFromStagingTable(txn, tableName, stagingTableName, sortedColumnKeys)
+	}
+	if err != nil {
+		txn.Rollback()
+		return
+	}
+
+	if err = txn.Commit(); err != nil {
+		pkgLogger.Errorf("MS: Error while committing transaction as there was error while loading staging table:%s: %v", stagingTableName, err)
+		txn.Rollback()
+		return
+	}
+
+	pkgLogger.Infof("MS: Complete load for table:%s", tableName)
+	return
+}
+
+// appendFromStagingTable deletes the rows of the table which are present in the staging table
+// and inserts the latest staging row of every partition key afterwards.
+func (ms *HandleT) appendFromStagingTable(txn *sql.Tx, tableName, stagingTableName string, sortedColumnKeys []string) error {
 	primaryKey := "id"
 	if column, ok := primaryKeyMap[tableName]; ok {
 		primaryKey = column
@@ -474,13 +497,12 @@ func (ms *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 	if tableName == warehouseutils.DiscardsTable {
 		additionalJoinClause = fmt.Sprintf(`AND _source.%[3]s = "%[1]s"."%[2]s"."%[3]s" AND _source.%[4]s = "%[1]s"."%[2]s"."%[4]s"`, ms.Namespace, tableName, "table_name", "column_name")
 	}
-	sqlStatement = fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" FROM "%[1]s"."%[3]s" as  _source where (_source.%[4]s = "%[1]s"."%[2]s"."%[4]s" %[5]s)`, ms.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
+	sqlStatement := fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" FROM "%[1]s"."%[3]s" as  _source where (_source.%[4]s = "%[1]s"."%[2]s"."%[4]s" %[5]s)`, ms.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
 	pkgLogger.Infof("MS: Deduplicate records for table:%s using staging table: %s\n", tableName, sqlStatement)
-	_, err = txn.Exec(sqlStatement)
+	_, err := txn.Exec(sqlStatement)
 	if err != nil {
 		pkgLogger.Errorf("MS: Error deleting from original table for dedup: %v\n", err)
-		txn.Rollback()
-		return
+		return err
 	}
 
 	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(sortedColumnKeys)
@@ -494,18 +516,70 @@ func (ms *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 
 	if err != nil {
 		pkgLogger.Errorf("MS: Error inserting into original table: %v\n", err)
-		txn.Rollback()
-		return
+		return err
 	}
+	return nil
+}
 
-	if err = txn.Commit(); err != nil {
-		pkgLogger.Errorf("MS: Error while committing transaction as there was error while loading staging table:%s: %v", stagingTableName, err)
-		txn.Rollback()
-		return
+// mergeFromStagingTable merges the latest staging row of every merge key into the table using MERGE.
+func (ms *HandleT) mergeFromStagingTable(txn *sql.Tx, tableName, stagingTableName string, sortedColumnKeys []string) error {
+	mergeKeys := ms.mergeKeys(tableName)
+	for _, key := range mergeKeys {
+		if !slices.Contains(sortedColumnKeys, key) {
+			return fmt.Errorf("merge key %s not found in upload schema of table %s", key, tableName)
+		}
 	}
 
-	pkgLogger.Infof("MS: Complete load for table:%s", tableName)
-	return
+	source := fmt.Sprintf(`SELECT * FROM (
+									SELECT *, row_number() OVER (PARTITION BY %[3]s ORDER BY received_at DESC) AS _rudder_staging_row_number FROM "%[1]s"."%[2]s"
+								) AS _ where _rudder_staging_row_number = 1`, ms.Namespace, stagingTableName, warehouseutils.DoubleQuoteAndJoinByComma(mergeKeys))
+	sqlStatement := mergeStatement(ms.Namespace, tableName, source, sortedColumnKeys, mergeKeys)
+	pkgLogger.Infof("MS: Merging records for table:%s using staging table: %s\n", tableName, sqlStatement)
+	if _, err := txn.Exec(sqlStatement); err != nil {
+		pkgLogger.Errorf("MS: Error merging into original table: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// mergeKeys returns the configured merge keys of the table, falling back to its primary key.
+// Users are always merged on id, since their latest traits are computed per id.
+func (ms *HandleT) mergeKeys(tableName string) []string {
+	primaryKey := "id"
+	if column, ok := primaryKeyMap[tableName]; ok {
+		primaryKey = column
+	}
+	if tableName == warehouseutils.UsersTable {
+		return []string{primaryKey}
+	}
+	return ms.Warehouse.GetMergePrimaryKeys(tableName, []string{primaryKey})
+}
+
+// mergeStatement merges the rows selected by source into the table, updating all non key columns of matched rows.
+// NULL merge keys match each other, the same way they are partitioned together while deduplicating the staging rows.
+func mergeStatement(namespace, tableName, source string, columns, mergeKeys []string) string {
+	var conditions, updates, values []string
+	for _, key := range mergeKeys {
+		conditions = append(conditions, fmt.Sprintf(`(_target.%[1]q = _source.%[1]q OR (_target.%[1]q IS NULL AND _source.%[1]q IS NULL))`, key))
+	}
+	for _, column := range columns {
+		values = append(values, fmt.Sprintf(`_source.%q`, column))
+		if slices.Contains(mergeKeys, column) {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf(`%[1]q = _source.%[1]q`, column))
+	}
+	var matchedClause string
+	if len(updates) > 0 {
+		matchedClause = "WHEN MATCHED THEN UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	return fmt.Sprintf(`MERGE INTO "%[1]s"."%[2]s" AS _target
+									USING (%[3]s) AS _source
+									ON (%[4]s)
+									%[5]s
+									WHEN NOT MATCHED THEN INSERT (%[6]s) VALUES (%[7]s);`,
+		namespace, tableName, source, strings.Join(conditions, " AND "), matchedClause, warehouseutils.DoubleQuoteAndJoinByComma(columns), strings.Join(values, ", "))
 }
 
 // Taken from https://github.com/denisenkom/go-mssqldb/blob/master/tds.go
@@ -615,23 +689,12 @@ func (ms *HandleT) loadUserTables() (errorMap map[string]error) {
 		return
 	}
 
-	primaryKey := "id"
-	sqlStatement = fmt.Sprintf(`DELETE FROM %[1]s."%[2]s" FROM %[3]s _source where (_source.%[4]s = %[1]s.%[2]s.%[4]s)`, ms.Namespace, warehouseutils.UsersTable, ms.Namespace+"."+stagingTableName, primaryKey)
-	pkgLogger.Infof("MS: Dedup records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
-	_, err = tx.Exec(sqlStatement)
-	if err != nil {
-		pkgLogger.Errorf("MS: Error deleting from original table for dedup: %v\n", err)
-		tx.Rollback()
-		errorMap[warehouseutils.UsersTable] = err
-		return
+	if ms.Warehouse.GetLoadMode() == warehouseutils.MergeLoadMode {
+		err = ms.mergeFromStagingTable(tx, warehouseutils.UsersTable, stagingTableName, warehouseutils.SortColumnKeysFromColumnMap(userColMap))
+	} else {
+		err = ms.appendUsersFromStagingTable(tx, stagingTableName, userColNames)
 	}
-
-	sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[4]s) SELECT %[4]s FROM  %[3]s`, ms.Namespace, warehouseutils.UsersTable, ms.Namespace+"."+stagingTableName, strings.Join(append([]string{"id"}, userColNames...), ","))
-	pkgLogger.Infof("MS: Inserting records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
-	_, err = tx.Exec(sqlStatement)
-
 	if err != nil {
-		pkgLogger.Errorf("MS: Error inserting into users table from staging table: %v\n", err)
 		tx.Rollback()
 		errorMap[warehouseutils.UsersTable] = err
 		return
@@ -647,6 +710,28 @@ func (ms *HandleT) loadUserTables() (errorMap map[string]error) {
 	return
 }
 
+// appendUsersFromStagingTable replaces the users present in the staging table with their latest traits.
+func (ms *HandleT) appendUsersFromStagingTable(tx *sql.Tx, stagingTableName string, userColNames []string) error {
+	primaryKey := "id"
+	sqlStatement := fmt.Sprintf(`DELETE FROM %[1]s."%[2]s" FROM %[3]s _source where (_source.%[4]s = %[1]s.%[2]s.%[4]s)`, ms.Namespace, warehouseutils.UsersTable, ms.Namespace+"."+stagingTableName, primaryKey)
+	pkgLogger.Infof("MS: Dedup records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
+	_, err := tx.Exec(sqlStatement)
+	if err != nil {
+		pkgLogger.Errorf("MS: Error deleting from original table for dedup: %v\n", err)
+		return err
+	}
+
+	sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[4]s) SELECT %[4]s FROM  %[3]s`, ms.Namespace, warehouseutils.UsersTable, ms.Namespace+"."+stagingTableName, strings.Join(append([]string{"id"}, userColNames...), ","))
+	pkgLogger.Infof("MS: Inserting records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
+	_, err = tx.Exec(sqlStatement)
+
+	if err != nil {
+		pkgLogger.Errorf("MS: Error inserting into users table from staging table: %v\n", err)
+		return err
+	}
+	return nil
+}
+
 func (ms *HandleT) CreateSchema() (err error) {
 	sqlStatement := fmt.Sprintf(`IF NOT EXISTS ( SELECT  * FROM  sys.schemas WHERE   name = N'%s' )
     EXEC('CREATE SCHEMA [%s]');
@@ -733,7 +818,16 @@ func (ms *HandleT) AddColumns(tableName string, columnsInfo []warehouseutils.Col
 	return
 }
 
-func (*HandleT) AlterColumn(_, _, _ string) (err error) {
+// AlterColumn widens the column to columnType, converting the existing values.
+func (ms *HandleT) AlterColumn(tableName, columnName, columnType string) (err error) {
+	dataType, ok := rudderDataTypesMapToMssql[columnType]
+	if !ok {
+		return
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %s.%s ALTER COLUMN %q %s;`, ms.Namespace, tableName, columnName, dataType)
+	pkgLogger.Infof("MS: Altering column for destinationID: %s, tableName: %s with query: %v", ms.Warehouse.Destination.ID, tableName, query)
+	_, err = ms.Db.Exec(query)
 	return
 }
 
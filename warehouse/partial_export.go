@@ -0,0 +1,175 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/config"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+)
+
+var (
+	enablePartialExports  bool
+	tableMinRetryAttempts int
+	tableRetryTimeWindow  time.Duration
+)
+
+func loadPartialExportConfig() {
+	config.RegisterBoolConfigVariable(true, &enablePartialExports, true, "Warehouse.enablePartialExports")
+	config.RegisterIntConfigVariable(3, &tableMinRetryAttempts, true, 1, "Warehouse.tableMinRetryAttempts")
+	config.RegisterDurationConfigVariable(180, &tableRetryTimeWindow, true, time.Minute, "Warehouse.tableRetryTimeWindow")
+}
+
+// partialExportPlanT is what happens to the failing tables of an upload once all other tables are exported
+type partialExportPlanT struct {
+	// tablesToAbort exceeded their retry attempts and time window
+	tablesToAbort []string
+	// tablesToRetry are retried at their next retry time, backing off with every attempt
+	tablesToRetry map[string]time.Time
+	// pendingTables are neither exported nor aborted yet
+	pendingTables []string
+	// nextRetryTime is the earliest retry time of the pending tables
+	nextRetryTime time.Time
+}
+
+func isTableUploadFailed(status string) bool {
+	return strings.HasSuffix(status, "_failed")
+}
+
+// planPartialExport decides which failing tables of an upload are aborted or retried on their own schedule.
+// Only regular tables are exported partially, so it returns false if any of the special tables failed.
+// Failed tables with a next retry time set were not attempted in this run, and tables skipped since they failed
+// in a previous upload are retried after the minimum backoff.
+func planPartialExport(tableRetries []tableUploadRetryT, specialTables, skippedTables []string, now time.Time) (partialExportPlanT, bool) {
+	plan := partialExportPlanT{
+		tablesToRetry: make(map[string]time.Time),
+	}
+	addPending := func(tableName string, retryTime time.Time) {
+		plan.pendingTables = append(plan.pendingTables, tableName)
+		if plan.nextRetryTime.IsZero() || retryTime.Before(plan.nextRetryTime) {
+			plan.nextRetryTime = retryTime
+		}
+	}
+
+	for _, tableRetry := range tableRetries {
+		if !isTableUploadFailed(tableRetry.status) {
+			continue
+		}
+		if misc.Contains(specialTables, tableRetry.tableName) {
+			return partialExportPlanT{}, false
+		}
+		if !tableRetry.nextRetryTime.IsZero() {
+			addPending(tableRetry.tableName, tableRetry.nextRetryTime)
+			continue
+		}
+		if tableRetry.attempts > tableMinRetryAttempts && now.Sub(tableRetry.firstFailedAt) > tableRetryTimeWindow {
+			plan.tablesToAbort = append(plan.tablesToAbort, tableRetry.tableName)
+			continue
+		}
+		retryTime := now.Add(DurationBeforeNextAttempt(int64(tableRetry.attempts)))
+		plan.tablesToRetry[tableRetry.tableName] = retryTime
+		addPending(tableRetry.tableName, retryTime)
+	}
+	for _, tableName := range skippedTables {
+		addPending(tableName, now.Add(DurationBeforeNextAttempt(1)))
+	}
+
+	sort.Strings(plan.tablesToAbort)
+	sort.Strings(plan.pendingTables)
+	return plan, true
+}
+
+// loadErrorTable returns the table a load error belongs to, if any
+func loadErrorTable(loadErr error) (string, bool) {
+	var skipErr *TableSkipError
+	if errors.As(loadErr, &skipErr) {
+		return skipErr.tableName, true
+	}
+	var tableLoadErr *TableLoadError
+	if errors.As(loadErr, &tableLoadErr) {
+		return tableLoadErr.tableName, true
+	}
+	return "", false
+}
+
+// remainingLoadErrors returns the load errors which are not handled by the plan, i.e. the ones which don't belong
+// to a table either aborted or pending.
+func remainingLoadErrors(loadErrors []error, plan partialExportPlanT) []error {
+	var remaining []error
+	for _, loadErr := range loadErrors {
+		tableName, ok := loadErrorTable(loadErr)
+		if ok && (misc.Contains(plan.tablesToAbort, tableName) || misc.Contains(plan.pendingTables, tableName)) {
+			continue
+		}
+		remaining = append(remaining, loadErr)
+	}
+	return remaining
+}
+
+// exportPartially finalizes the tables exported so far, aborting or scheduling the retries of the failing regular tables.
+// It returns the load errors which can't be handled on a per table basis, which fail the upload.
+// Otherwise, the upload is partially exported as long as any of its tables is pending.
+func (job *UploadJobT) exportPartially(loadErrors []error, specialTables []string) (remainingErrors []error, partiallyExported bool, err error) {
+	var skippedTables []string
+	for _, loadErr := range loadErrors {
+		var skipErr *TableSkipError
+		if errors.As(loadErr, &skipErr) {
+			skippedTables = append(skippedTables, skipErr.tableName)
+		}
+	}
+
+	tableRetries, err := getTableUploadRetries(job.upload.ID)
+	if err != nil {
+		return loadErrors, false, err
+	}
+
+	now := timeutil.Now()
+	plan, ok := planPartialExport(tableRetries, specialTables, skippedTables, now)
+	if !ok {
+		return loadErrors, false, nil
+	}
+
+	attempts := make(map[string]int, len(tableRetries))
+	for _, tableRetry := range tableRetries {
+		attempts[tableRetry.tableName] = tableRetry.attempts
+	}
+	for _, tableName := range plan.tablesToAbort {
+		if err := NewTableUpload(job.upload.ID, tableName).setStatus(TableUploadAborted); err != nil {
+			return loadErrors, false, fmt.Errorf("aborting table upload %s: %w", tableName, err)
+		}
+		pkgLogger.Warnf("[WH]: Aborted table %s of upload %d for %s after %d attempts, exceeding the minimum of %d attempts", tableName, job.upload.ID, job.warehouse.Identifier, attempts[tableName], tableMinRetryAttempts)
+		job.counterStat("table_upload_aborted", tag{name: "tableName", value: tableName}).Count(1)
+	}
+	for tableName, retryTime := range plan.tablesToRetry {
+		if err := NewTableUpload(job.upload.ID, tableName).setNextRetryTime(retryTime); err != nil {
+			return loadErrors, false, fmt.Errorf("scheduling retry of table upload %s: %w", tableName, err)
+		}
+	}
+	// errors not attributed to an aborted or pending table fail the upload
+	if remainingErrors = remainingLoadErrors(loadErrors, plan); len(remainingErrors) > 0 || len(plan.pendingTables) == 0 {
+		return remainingErrors, false, nil
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(job.upload.Metadata, &metadata); err != nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["nextRetryTime"] = plan.nextRetryTime.Format(time.RFC3339)
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return loadErrors, false, fmt.Errorf("marshalling upload metadata: %w", err)
+	}
+	if err := job.setUploadColumns(UploadColumnsOpts{Fields: []UploadColumnT{{Column: UploadMetadataField, Value: metadataJSON}}}); err != nil {
+		return loadErrors, false, fmt.Errorf("setting next retry time of upload: %w", err)
+	}
+	job.upload.Metadata = metadataJSON
+
+	pkgLogger.Infof("[WH]: Partially exported upload %d for %s, pending tables: %v", job.upload.ID, job.warehouse.Identifier, plan.pendingTables)
+	job.counterStat("partially_exported_uploads").Count(1)
+	return nil, true, nil
+}
@@ -0,0 +1,120 @@
+package warehouse
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanPartialExport(t *testing.T) {
+	tableMinRetryAttempts = 3
+	tableRetryTimeWindow = 3 * time.Hour
+	minUploadBackoff = time.Minute
+	maxUploadBackoff = time.Hour
+
+	now := time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC)
+	specialTables := []string{"identifies", "users"}
+
+	testCases := []struct {
+		name          string
+		tableRetries  []tableUploadRetryT
+		skippedTables []string
+		wantOK        bool
+		want          partialExportPlanT
+	}{
+		{
+			name: "all tables exported",
+			tableRetries: []tableUploadRetryT{
+				{tableName: "tracks", status: TableUploadExported},
+				{tableName: "users", status: TableUploadExported},
+			},
+			wantOK: true,
+			want:   partialExportPlanT{tablesToRetry: map[string]time.Time{}},
+		},
+		{
+			name: "failing special table",
+			tableRetries: []tableUploadRetryT{
+				{tableName: "tracks", status: TableUploadExported},
+				{tableName: "users", status: UserTableUploadExportingFailed, attempts: 1, firstFailedAt: now},
+			},
+		},
+		{
+			name: "failing regular table is retried with backoff",
+			tableRetries: []tableUploadRetryT{
+				{tableName: "tracks", status: TableUploadExported},
+				{tableName: "product_viewed", status: TableUploadExportingFailed, attempts: 2, firstFailedAt: now.Add(-time.Hour)},
+			},
+			wantOK: true,
+			want: partialExportPlanT{
+				tablesToRetry: map[string]time.Time{"product_viewed": now.Add(2 * time.Minute)},
+				pendingTables: []string{"product_viewed"},
+				nextRetryTime: now.Add(2 * time.Minute),
+			},
+		},
+		{
+			name: "failing regular table is aborted after its attempts and time window",
+			tableRetries: []tableUploadRetryT{
+				{tableName: "tracks", status: TableUploadExported},
+				{tableName: "product_viewed", status: TableUploadUpdatingSchemaFailed, attempts: 4, firstFailedAt: now.Add(-4 * time.Hour)},
+			},
+			wantOK: true,
+			want: partialExportPlanT{
+				tablesToAbort: []string{"product_viewed"},
+				tablesToRetry: map[string]time.Time{},
+			},
+		},
+		{
+			name: "deferred and skipped tables stay pending",
+			tableRetries: []tableUploadRetryT{
+				{tableName: "tracks", status: TableUploadExported},
+				{tableName: "product_viewed", status: TableUploadExportingFailed, attempts: 1, firstFailedAt: now, nextRetryTime: now.Add(30 * time.Second)},
+			},
+			skippedTables: []string{"order_completed"},
+			wantOK:        true,
+			want: partialExportPlanT{
+				tablesToRetry: map[string]time.Time{},
+				pendingTables: []string{"order_completed", "product_viewed"},
+				nextRetryTime: now.Add(30 * time.Second),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			plan, ok := planPartialExport(tc.tableRetries, specialTables, tc.skippedTables, now)
+			require.Equal(t, tc.wantOK, ok)
+			if !tc.wantOK {
+				return
+			}
+			require.Equal(t, tc.want, plan)
+		})
+	}
+}
+
+func TestRemainingLoadErrors(t *testing.T) {
+	plan := partialExportPlanT{
+		tablesToAbort: []string{"product_viewed"},
+		pendingTables: []string{"order_completed", "tracks"},
+	}
+	schemaErr := errors.New("schema error")
+	loadErrors := []error{
+		&TableLoadError{tableName: "product_viewed", err: errors.New("load error")},
+		&TableSkipError{tableName: "order_completed"},
+		&TableLoadError{tableName: "tracks", err: errors.New("load error")},
+		&TableLoadError{tableName: "pages", err: errors.New("load error")},
+		schemaErr,
+	}
+
+	require.Equal(t, []error{loadErrors[3], schemaErr}, remainingLoadErrors(loadErrors, plan), "only errors of aborted and pending tables are handled")
+	require.Empty(t, remainingLoadErrors(loadErrors[:3], plan))
+
+	tableName, ok := loadErrorTable(fmt.Errorf("wrapped: %w", loadErrors[0]))
+	require.True(t, ok)
+	require.Equal(t, "product_viewed", tableName)
+	_, ok = loadErrorTable(schemaErr)
+	require.False(t, ok)
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/testhelper/destination"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestUpsertStatement(t *testing.T) {
+	statement := upsertStatement("namespace", "orders", "SELECT * FROM staging", []string{"id", "received_at", "store_id", "total"}, []string{"id", "store_id"})
+	require.Contains(t, statement, `INSERT INTO "namespace"."orders" ("id","received_at","store_id","total")`)
+	require.Contains(t, statement, `FROM (SELECT * FROM staging) AS _source WHERE NOT (_source."id" IS NULL OR _source."store_id" IS NULL)`)
+	require.Contains(t, statement, `ON CONFLICT ("id","store_id") DO UPDATE SET "received_at" = EXCLUDED."received_at", "total" = EXCLUDED."total"`)
+
+	statement = upsertStatement("namespace", "orders", "SELECT * FROM staging", []string{"id"}, []string{"id"})
+	require.Contains(t, statement, `ON CONFLICT ("id") DO NOTHING`)
+}
+
+func TestMergeKeyIndexStatement(t *testing.T) {
+	statement := mergeKeyIndexStatement("namespace", "orders", []string{"id", "store_id"})
+	require.Regexp(t, `^CREATE UNIQUE INDEX IF NOT EXISTS "rudder_merge_[0-9a-f]{32}" ON "namespace"."orders" \("id","store_id"\)$`, statement)
+
+	require.NotEqual(t, statement, mergeKeyIndexStatement("namespace", "orders", []string{"id"}))
+	require.NotEqual(t, statement, mergeKeyIndexStatement("namespace", "payments", []string{"id", "store_id"}))
+}
+
+func TestStreamingMergeStatements(t *testing.T) {
+	statements := streamingMergeStatements("namespace", "tracks", "staging", []string{"event", "id"})
+	require.Equal(t, []string{
+		`INSERT INTO "namespace"."tracks" ("event","id") SELECT _source."event", _source."id" FROM "staging" AS _source WHERE NOT EXISTS (SELECT 1 FROM "namespace"."tracks" AS _target WHERE _target."id" = _source."id")`,
+	}, statements, "retried rows are skipped")
+
+	statements = streamingMergeStatements("namespace", "users", "staging", []string{"email", "id"})
+	require.Equal(t, []string{
+		`UPDATE "namespace"."users" AS _target SET "email" = COALESCE(_source."email", _target."email") FROM "staging" AS _source WHERE _target."id" = _source."id"`,
+		`INSERT INTO "namespace"."users" ("email","id") SELECT _source."email", _source."id" FROM "staging" AS _source WHERE NOT EXISTS (SELECT 1 FROM "namespace"."users" AS _target WHERE _target."id" = _source."id")`,
+	}, statements, "users are merged on id")
+
+	statements = streamingMergeStatements("namespace", "rudder_discards", "staging", []string{"column_name", "row_id", "table_name"})
+	require.Len(t, statements, 1)
+	require.Contains(t, statements[0], `WHERE _target."row_id" = _source."row_id" AND _target."table_name" = _source."table_name" AND _target."column_name" = _source."column_name")`)
+
+	statements = streamingMergeStatements("namespace", "tracks", "staging", []string{"event"})
+	require.Equal(t, []string{`INSERT INTO "namespace"."tracks" ("event") SELECT "event" FROM "staging"`}, statements)
+}
+
+func TestMergeFromStagingTable(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+	pgResource, err := destination.SetupPostgres(pool, t)
+	require.NoError(t, err)
+	db := pgResource.DB
+
+	const namespace = "merge_test"
+	_, err = db.Exec(fmt.Sprintf(`CREATE SCHEMA %q`, namespace))
+	require.NoError(t, err)
+
+	pg := &Handle{
+		DB:        db,
+		Namespace: namespace,
+		logger:    logger.NOP,
+		Warehouse: warehouseutils.Warehouse{
+			Destination: backendconfig.DestinationT{
+				Config: map[string]interface{}{
+					warehouseutils.LoadModeConfig:         warehouseutils.MergeLoadMode,
+					warehouseutils.MergePrimaryKeysConfig: map[string]interface{}{"orders": "store_id,order_id"},
+				},
+			},
+		},
+	}
+	columns := []string{"id", "order_id", "received_at", "store_id", "total"}
+
+	// merge creates the tables, loads the staging rows and merges them, returning the rows of the table by order_id and total
+	merge := func(t *testing.T, table string, existing, staged []string) [][]interface{} {
+		t.Helper()
+		for _, name := range []string{table, table + "_staging"} {
+			_, err := db.Exec(fmt.Sprintf(`CREATE TABLE %q.%q (id TEXT, order_id TEXT, received_at TIMESTAMP, store_id TEXT, total INT)`, namespace, name))
+			require.NoError(t, err)
+		}
+		for _, row := range existing {
+			_, err := db.Exec(fmt.Sprintf(`INSERT INTO %q.%q VALUES %s`, namespace, table, row))
+			require.NoError(t, err)
+		}
+		for _, row := range staged {
+			_, err := db.Exec(fmt.Sprintf(`INSERT INTO %q.%q VALUES %s`, namespace, table+"_staging", row))
+			require.NoError(t, err)
+		}
+
+		txn, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, pg.mergeFromStagingTable(txn, table, table+"_staging", columns, stats.Tags{}))
+		require.NoError(t, txn.Commit())
+
+		rows, err := db.Query(fmt.Sprintf(`SELECT store_id, order_id, total FROM %q.%q ORDER BY store_id NULLS FIRST, order_id NULLS FIRST, total`, namespace, table))
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+		var result [][]interface{}
+		for rows.Next() {
+			var storeID, orderID sql.NullString
+			var total int
+			require.NoError(t, rows.Scan(&storeID, &orderID, &total))
+			result = append(result, []interface{}{storeID.String, orderID.String, total})
+		}
+		require.NoError(t, rows.Err())
+		return result
+	}
+
+	t.Run("existing duplicate keys", func(t *testing.T) {
+		result := merge(t, "orders",
+			[]string{
+				`('1', 'o1', '2022-01-01', 's1', 1)`,
+				`('2', 'o1', '2022-01-02', 's1', 2)`,
+				`('3', 'o2', '2022-01-01', 's1', 3)`,
+			},
+			[]string{
+				`('4', 'o1', '2022-01-03', 's1', 4)`,
+				`('5', 'o1', '2022-01-04', 's1', 5)`,
+			},
+		)
+		require.Equal(t, [][]interface{}{
+			{"s1", "o1", 5},
+			{"s1", "o2", 3},
+		}, result, "the duplicates of the merged keys are replaced by the latest staging row")
+
+		var indexes int
+		require.NoError(t, db.QueryRow(`SELECT count(*) FROM pg_indexes WHERE schemaname = $1 AND tablename = 'orders'`, namespace).Scan(&indexes))
+		require.Zero(t, indexes, "the unique index can't be created with duplicate keys")
+	})
+
+	pg.Warehouse.Destination.Config[warehouseutils.MergePrimaryKeysConfig] = map[string]interface{}{"payments": "store_id,order_id"}
+	t.Run("null keys", func(t *testing.T) {
+		result := merge(t, "payments",
+			[]string{
+				`('1', NULL, '2022-01-01', 's1', 1)`,
+				`('2', 'o1', '2022-01-01', 's1', 2)`,
+				`('3', NULL, '2022-01-01', NULL, 3)`,
+			},
+			[]string{
+				`('4', NULL, '2022-01-02', 's1', 4)`,
+				`('5', 'o1', '2022-01-02', 's1', 5)`,
+				`('6', NULL, '2022-01-02', NULL, 6)`,
+				`('7', NULL, '2022-01-03', NULL, 7)`,
+			},
+		)
+		require.Equal(t, [][]interface{}{
+			{"", "", 7},
+			{"s1", "", 4},
+			{"s1", "o1", 5},
+		}, result, "rows with NULL keys are merged as well")
+
+		var indexes int
+		require.NoError(t, db.QueryRow(`SELECT count(*) FROM pg_indexes WHERE schemaname = $1 AND tablename = 'payments'`, namespace).Scan(&indexes))
+		require.Equal(t, 1, indexes, "the unique index is created without duplicate keys")
+	})
+}
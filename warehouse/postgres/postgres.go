@@ -56,6 +56,7 @@ const (
 	deleteDedup              = "dedup_deletion"
 	insertDedup              = "dedup_insertion"
 	dedupStage               = "dedup_stage"
+	mergeUpsert              = "merge_upsert"
 )
 
 var rudderDataTypesMapToPostgres = map[string]string{
@@ -417,7 +418,30 @@ func (pg *Handle) loadTable(tableName string, tableSchemaInUpload warehouseutils
 		return
 
 	}
-	// deduplication process
+	if pg.Warehouse.GetLoadMode() == warehouseutils.MergeLoadMode && tableName != warehouseutils.DiscardsTable {
+		err = pg.mergeFromStagingTable(txn, tableName, stagingTableName, sortedColumnKeys, tags)
+	} else {
+		err = pg.appendFromStagingTable(txn, tableName, stagingTableName, sortedColumnKeys, tags)
+	}
+	if err != nil {
+		pg.runRollbackWithTimeout(txn.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
+		return
+	}
+
+	if err = txn.Commit(); err != nil {
+		pg.logger.Errorf("PG: Error while committing transaction as there was error while loading staging table:%s: %v", stagingTableName, err)
+		tags["stage"] = dedupStage
+		pg.runRollbackWithTimeout(txn.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
+		return
+	}
+
+	pg.logger.Infof("PG: Complete load for table:%s", tableName)
+	return
+}
+
+// appendFromStagingTable deletes the rows of the table which are present in the staging table
+// and inserts the latest staging row of every partition key afterwards.
+func (pg *Handle) appendFromStagingTable(txn *sql.Tx, tableName, stagingTableName string, sortedColumnKeys []string, tags stats.Tags) error {
 	primaryKey := "id"
 	if column, ok := primaryKeyMap[tableName]; ok {
 		primaryKey = column
@@ -430,9 +454,9 @@ func (pg *Handle) loadTable(tableName string, tableSchemaInUpload warehouseutils
 	if tableName == warehouseutils.DiscardsTable {
 		additionalJoinClause = fmt.Sprintf(`AND _source.%[3]s = "%[1]s"."%[2]s"."%[3]s" AND _source.%[4]s = "%[1]s"."%[2]s"."%[4]s"`, pg.Namespace, tableName, "table_name", "column_name")
 	}
-	sqlStatement = fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" USING "%[1]s"."%[3]s" as  _source where (_source.%[4]s = "%[1]s"."%[2]s"."%[4]s" %[5]s)`, pg.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
+	sqlStatement := fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" USING "%[1]s"."%[3]s" as  _source where (_source.%[4]s = "%[1]s"."%[2]s"."%[4]s" %[5]s)`, pg.Namespace, tableName, stagingTableName, primaryKey, additionalJoinClause)
 	pg.logger.Infof("PG: Deduplicate records for table:%s using staging table: %s\n", tableName, sqlStatement)
-	err = pg.handleExec(&QueryParams{
+	err := pg.handleExec(&QueryParams{
 		txn:                 txn,
 		query:               sqlStatement,
 		enableWithQueryPlan: pg.EnableSQLStatementExecutionPlan || slices.Contains(pg.EnableSQLStatementExecutionPlanWorkspaceIDs, pg.Warehouse.WorkspaceID),
@@ -440,8 +464,7 @@ func (pg *Handle) loadTable(tableName string, tableSchemaInUpload warehouseutils
 	if err != nil {
 		pg.logger.Errorf("PG: Error deleting from original table for dedup: %v\n", err)
 		tags["stage"] = deleteDedup
-		pg.runRollbackWithTimeout(txn.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
-		return
+		return err
 	}
 
 	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(sortedColumnKeys)
@@ -460,19 +483,132 @@ func (pg *Handle) loadTable(tableName string, tableSchemaInUpload warehouseutils
 	if err != nil {
 		pg.logger.Errorf("PG: Error inserting into original table: %v\n", err)
 		tags["stage"] = insertDedup
-		pg.runRollbackWithTimeout(txn.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
-		return
+		return err
 	}
+	return nil
+}
 
-	if err = txn.Commit(); err != nil {
-		pg.logger.Errorf("PG: Error while committing transaction as there was error while loading staging table:%s: %v", stagingTableName, err)
-		tags["stage"] = dedupStage
-		pg.runRollbackWithTimeout(txn.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
-		return
+// mergeFromStagingTable merges the latest staging row of every merge key into the table.
+// Rows are upserted using INSERT ... ON CONFLICT when the unique index on the merge keys, which ON CONFLICT requires
+// as arbiter, exists or can be created. Otherwise, e.g. when the table already has duplicate merge keys from
+// earlier append loads, the matching rows are deleted and inserted again, which also removes those duplicates.
+// Rows with NULL merge keys never conflict, so they are always deleted and inserted matching NULLs as equal.
+func (pg *Handle) mergeFromStagingTable(txn *sql.Tx, tableName, stagingTableName string, sortedColumnKeys []string, tags stats.Tags) error {
+	mergeKeys := pg.mergeKeys(tableName)
+	for _, key := range mergeKeys {
+		if !slices.Contains(sortedColumnKeys, key) {
+			tags["stage"] = mergeUpsert
+			return fmt.Errorf("merge key %s not found in upload schema of table %s", key, tableName)
+		}
 	}
 
-	pg.logger.Infof("PG: Complete load for table:%s", tableName)
-	return
+	source := fmt.Sprintf(`SELECT * FROM (
+									SELECT *, row_number() OVER (PARTITION BY %[3]s ORDER BY received_at DESC) AS _rudder_staging_row_number FROM "%[1]s"."%[2]s"
+								) AS _ where _rudder_staging_row_number = 1`, pg.Namespace, stagingTableName, warehouseutils.DoubleQuoteAndJoinByComma(mergeKeys))
+
+	var sqlStatements []string
+	if pg.createMergeKeyIndex(tableName, mergeKeys) {
+		sqlStatements = append(sqlStatements, upsertStatement(pg.Namespace, tableName, source, sortedColumnKeys, mergeKeys))
+		sqlStatements = append(sqlStatements, deleteInsertStatements(pg.Namespace, tableName, source, sortedColumnKeys, mergeKeys, nullMergeKeysFilter(mergeKeys))...)
+	} else {
+		sqlStatements = deleteInsertStatements(pg.Namespace, tableName, source, sortedColumnKeys, mergeKeys, "")
+	}
+	for _, sqlStatement := range sqlStatements {
+		pg.logger.Infof("PG: Merging records for table:%s using staging table: %s\n", tableName, sqlStatement)
+		err := pg.handleExec(&QueryParams{
+			txn:                 txn,
+			query:               sqlStatement,
+			enableWithQueryPlan: pg.EnableSQLStatementExecutionPlan || slices.Contains(pg.EnableSQLStatementExecutionPlanWorkspaceIDs, pg.Warehouse.WorkspaceID),
+		})
+		if err != nil {
+			pg.logger.Errorf("PG: Error merging into original table: %v\n", err)
+			tags["stage"] = mergeUpsert
+			return err
+		}
+	}
+	return nil
+}
+
+// createMergeKeyIndex creates the unique index on the merge keys of the table if it doesn't exist yet, returning whether it exists.
+// It runs outside the load transaction, so that failing to create it, e.g. because of duplicate keys, doesn't fail the load.
+func (pg *Handle) createMergeKeyIndex(tableName string, mergeKeys []string) bool {
+	sqlStatement := mergeKeyIndexStatement(pg.Namespace, tableName, mergeKeys)
+	pg.logger.Infof("PG: Creating merge key index for table:%s: %s\n", tableName, sqlStatement)
+	if _, err := pg.DB.Exec(sqlStatement); err != nil {
+		pg.logger.Warnf("PG: Could not create merge key index for table:%s, merging with delete and insert: %v\n", tableName, err)
+		return false
+	}
+	return true
+}
+
+// mergeKeys returns the configured merge keys of the table, falling back to its primary key.
+// Users are always merged on id, since their latest traits are computed per id.
+func (pg *Handle) mergeKeys(tableName string) []string {
+	primaryKey := "id"
+	if column, ok := primaryKeyMap[tableName]; ok {
+		primaryKey = column
+	}
+	if tableName == warehouseutils.UsersTable {
+		return []string{primaryKey}
+	}
+	return pg.Warehouse.GetMergePrimaryKeys(tableName, []string{primaryKey})
+}
+
+// mergeKeyIndexStatement creates a unique index on the merge keys. The index name is derived from the table and
+// its keys, so that changing the configured keys results in a new index instead of silently reusing the old one.
+func mergeKeyIndexStatement(namespace, tableName string, mergeKeys []string) string {
+	indexName := "rudder_merge_" + misc.GetMD5Hash(tableName+":"+strings.Join(mergeKeys, ","))
+	return fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %[1]q ON "%[2]s"."%[3]s" (%[4]s)`, indexName, namespace, tableName, warehouseutils.DoubleQuoteAndJoinByComma(mergeKeys))
+}
+
+// nullMergeKeysFilter matches the source rows having any NULL merge key
+func nullMergeKeysFilter(mergeKeys []string) string {
+	conditions := make([]string, len(mergeKeys))
+	for i, key := range mergeKeys {
+		conditions[i] = fmt.Sprintf(`_source.%q IS NULL`, key)
+	}
+	return strings.Join(conditions, " OR ")
+}
+
+// deleteInsertStatements delete the rows of the table having the merge keys of the rows selected by source, NULLs included,
+// and insert the source rows afterwards. Only the source rows matching filter are merged, all of them if it is empty.
+func deleteInsertStatements(namespace, tableName, source string, columns, mergeKeys []string, filter string) []string {
+	conditions := make([]string, len(mergeKeys))
+	for i, key := range mergeKeys {
+		conditions[i] = fmt.Sprintf(`"%[1]s"."%[2]s".%[3]q IS NOT DISTINCT FROM _source.%[3]q`, namespace, tableName, key)
+	}
+	where := strings.Join(conditions, " AND ")
+	insertWhere := ""
+	if filter != "" {
+		where = fmt.Sprintf("(%s) AND (%s)", where, filter)
+		insertWhere = fmt.Sprintf(" WHERE %s", filter)
+	}
+
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(columns)
+	return []string{
+		fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" USING (%[3]s) AS _source WHERE %[4]s`, namespace, tableName, source, where),
+		fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s) SELECT %[3]s FROM (%[4]s) AS _source%[5]s`, namespace, tableName, quotedColumnNames, source, insertWhere),
+	}
+}
+
+// upsertStatement inserts the rows selected by source having all their merge keys into the table, updating all non key columns on conflicts.
+func upsertStatement(namespace, tableName, source string, columns, mergeKeys []string) string {
+	var updates []string
+	for _, column := range columns {
+		if slices.Contains(mergeKeys, column) {
+			continue
+		}
+		updates = append(updates, fmt.Sprintf(`%[1]q = EXCLUDED.%[1]q`, column))
+	}
+	conflictAction := "DO NOTHING"
+	if len(updates) > 0 {
+		conflictAction = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(columns)
+	return fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s)
+									SELECT %[3]s FROM (%[4]s) AS _source WHERE NOT (%[5]s)
+									ON CONFLICT (%[6]s) %[7]s`, namespace, tableName, quotedColumnNames, source, nullMergeKeysFilter(mergeKeys), warehouseutils.DoubleQuoteAndJoinByComma(mergeKeys), conflictAction)
 }
 
 // DeleteBy Need to create a structure with delete parameters instead of simply adding a long list of params
@@ -601,9 +737,6 @@ func (pg *Handle) loadUserTables() (errorMap map[string]error) {
 		return
 	}
 
-	primaryKey := "id"
-	sqlStatement = fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" using "%[1]s"."%[3]s" _source where (_source.%[4]s = %[1]s.%[2]s.%[4]s)`, pg.Namespace, warehouseutils.UsersTable, stagingTableName, primaryKey)
-	pg.logger.Infof("PG: Dedup records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
 	// tags
 	tags := stats.Tags{
 		"workspaceId": pg.Warehouse.WorkspaceID,
@@ -611,7 +744,35 @@ func (pg *Handle) loadUserTables() (errorMap map[string]error) {
 		"destId":      pg.Warehouse.Destination.ID,
 		"tableName":   warehouseutils.UsersTable,
 	}
-	err = pg.handleExec(&QueryParams{
+
+	if pg.Warehouse.GetLoadMode() == warehouseutils.MergeLoadMode {
+		err = pg.mergeFromStagingTable(tx, warehouseutils.UsersTable, stagingTableName, warehouseutils.SortColumnKeysFromColumnMap(userColMap), tags)
+	} else {
+		err = pg.appendUsersFromStagingTable(tx, stagingTableName, userColNames, tags)
+	}
+	if err != nil {
+		pg.runRollbackWithTimeout(tx.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
+		errorMap[warehouseutils.UsersTable] = err
+		return
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		pg.logger.Errorf("PG: Error in transaction commit for users table: %v\n", err)
+		tags["stage"] = dedupStage
+		pg.runRollbackWithTimeout(tx.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
+		errorMap[warehouseutils.UsersTable] = err
+		return
+	}
+	return
+}
+
+// appendUsersFromStagingTable replaces the users present in the staging table with their latest traits.
+func (pg *Handle) appendUsersFromStagingTable(tx *sql.Tx, stagingTableName string, userColNames []string, tags stats.Tags) error {
+	primaryKey := "id"
+	sqlStatement := fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" using "%[1]s"."%[3]s" _source where (_source.%[4]s = %[1]s.%[2]s.%[4]s)`, pg.Namespace, warehouseutils.UsersTable, stagingTableName, primaryKey)
+	pg.logger.Infof("PG: Dedup records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
+	err := pg.handleExec(&QueryParams{
 		txn:                 tx,
 		query:               sqlStatement,
 		enableWithQueryPlan: pg.EnableSQLStatementExecutionPlan || slices.Contains(pg.EnableSQLStatementExecutionPlanWorkspaceIDs, pg.Warehouse.WorkspaceID),
@@ -619,9 +780,7 @@ func (pg *Handle) loadUserTables() (errorMap map[string]error) {
 	if err != nil {
 		pg.logger.Errorf("PG: Error deleting from original table for dedup: %v\n", err)
 		tags["stage"] = deleteDedup
-		pg.runRollbackWithTimeout(tx.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
-		errorMap[warehouseutils.UsersTable] = err
-		return
+		return err
 	}
 
 	sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[4]s) SELECT %[4]s FROM  "%[1]s"."%[3]s"`, pg.Namespace, warehouseutils.UsersTable, stagingTableName, strings.Join(append([]string{"id"}, userColNames...), ","))
@@ -635,20 +794,9 @@ func (pg *Handle) loadUserTables() (errorMap map[string]error) {
 	if err != nil {
 		pg.logger.Errorf("PG: Error inserting into users table from staging table: %v\n", err)
 		tags["stage"] = insertDedup
-		pg.runRollbackWithTimeout(tx.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
-		errorMap[warehouseutils.UsersTable] = err
-		return
-	}
-
-	err = tx.Commit()
-	if err != nil {
-		pg.logger.Errorf("PG: Error in transaction commit for users table: %v\n", err)
-		tags["stage"] = dedupStage
-		pg.runRollbackWithTimeout(tx.Rollback, handleRollbackTimeout, pg.TxnRollbackTimeout, tags)
-		errorMap[warehouseutils.UsersTable] = err
-		return
+		return err
 	}
-	return
+	return nil
 }
 
 func (pg *Handle) schemaExists(_ string) (exists bool, err error) {
@@ -740,7 +888,20 @@ func (pg *Handle) AddColumns(tableName string, columnsInfo []warehouseutils.Colu
 	return
 }
 
-func (*Handle) AlterColumn(_, _, _ string) (err error) {
+// AlterColumn widens the column to columnType, casting the existing values.
+// Altering string columns to text is a no-op, since both are stored as text.
+func (pg *Handle) AlterColumn(tableName, columnName, columnType string) (err error) {
+	if columnType == "text" {
+		return
+	}
+	dataType, ok := rudderDataTypesMapToPostgres[columnType]
+	if !ok {
+		return
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %[1]q.%[2]q ALTER COLUMN %[3]q TYPE %[4]s USING %[3]q::%[4]s;`, pg.Namespace, tableName, columnName, dataType)
+	pg.logger.Infof("PG: Altering column for destinationID: %s, tableName: %s with query: %v", pg.Warehouse.Destination.ID, tableName, query)
+	_, err = pg.DB.Exec(query)
 	return
 }
 
@@ -913,6 +1074,111 @@ func (pg *Handle) LoadTable(tableName string) error {
 	return err
 }
 
+// LoadStreamingBatch loads the rows of a streamed batch into the table in a single transaction.
+// The rows are copied into a temporary staging table first, so that retried batches are deduplicated on the
+// primary key of the table (the message id for event tables) and users are merged on their id like uploads do.
+func (pg *Handle) LoadStreamingBatch(tableName string, tableSchema warehouseutils.TableSchemaT, rows []map[string]interface{}) error {
+	sortedColumnKeys := warehouseutils.SortColumnKeysFromColumnMap(tableSchema)
+	stagingTableName := warehouseutils.StagingTableName(provider, tableName, tableNameLimit)
+
+	txn, err := pg.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+
+	sqlStatement := fmt.Sprintf(`CREATE TEMPORARY TABLE "%[1]s" (LIKE "%[2]s"."%[3]s") ON COMMIT DROP`, stagingTableName, pg.Namespace, tableName)
+	if _, err = txn.Exec(sqlStatement); err != nil {
+		_ = txn.Rollback()
+		return fmt.Errorf("creating staging table for table %s: %w", tableName, err)
+	}
+
+	stmt, err := txn.Prepare(pq.CopyIn(stagingTableName, sortedColumnKeys...))
+	if err != nil {
+		_ = txn.Rollback()
+		return fmt.Errorf("preparing copy into staging table %s: %w", stagingTableName, err)
+	}
+	for _, row := range rows {
+		recordInterface := make([]interface{}, 0, len(sortedColumnKeys))
+		for _, columnName := range sortedColumnKeys {
+			value := warehouseutils.StreamingValueToString(row[columnName])
+			if strings.TrimSpace(value) == "" {
+				recordInterface = append(recordInterface, nil)
+			} else {
+				recordInterface = append(recordInterface, value)
+			}
+		}
+		if _, err = stmt.Exec(recordInterface...); err != nil {
+			_ = stmt.Close()
+			_ = txn.Rollback()
+			return fmt.Errorf("copying row into staging table %s: %w", stagingTableName, err)
+		}
+	}
+	if _, err = stmt.Exec(); err != nil {
+		_ = stmt.Close()
+		_ = txn.Rollback()
+		return fmt.Errorf("flushing copy into staging table %s: %w", stagingTableName, err)
+	}
+	if err = stmt.Close(); err != nil {
+		_ = txn.Rollback()
+		return fmt.Errorf("closing copy into staging table %s: %w", stagingTableName, err)
+	}
+
+	for _, sqlStatement := range streamingMergeStatements(pg.Namespace, tableName, stagingTableName, sortedColumnKeys) {
+		pg.logger.Debugf("PG: Merging streaming batch into table %s: %s", tableName, sqlStatement)
+		if _, err = txn.Exec(sqlStatement); err != nil {
+			_ = txn.Rollback()
+			return fmt.Errorf("merging streaming batch into table %s: %w", tableName, err)
+		}
+	}
+	if err = txn.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// streamingMergeStatements returns the statements moving the rows of the staging table into the table.
+// Users update the traits of the existing ones, keeping the current values of the traits missing in the batch,
+// and insert the new ones. Rows of other tables are inserted only if their primary key isn't loaded yet.
+// Tables whose primary key isn't part of the batch are appended to.
+func streamingMergeStatements(namespace, tableName, stagingTableName string, sortedColumnKeys []string) []string {
+	primaryKey := "id"
+	if column, ok := primaryKeyMap[tableName]; ok {
+		primaryKey = column
+	}
+	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(sortedColumnKeys)
+	if !slices.Contains(sortedColumnKeys, primaryKey) {
+		return []string{fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s) SELECT %[3]s FROM "%[4]s"`, namespace, tableName, quotedColumnNames, stagingTableName)}
+	}
+
+	joinClause := fmt.Sprintf(`_target."%[1]s" = _source."%[1]s"`, primaryKey)
+	if tableName == warehouseutils.DiscardsTable {
+		joinClause += ` AND _target."table_name" = _source."table_name" AND _target."column_name" = _source."column_name"`
+	}
+
+	var statements []string
+	if tableName == warehouseutils.UsersTable {
+		var updates []string
+		for _, columnName := range sortedColumnKeys {
+			if columnName == primaryKey {
+				continue
+			}
+			updates = append(updates, fmt.Sprintf(`"%[1]s" = COALESCE(_source."%[1]s", _target."%[1]s")`, columnName))
+		}
+		if len(updates) > 0 {
+			statements = append(statements, fmt.Sprintf(`UPDATE "%[1]s"."%[2]s" AS _target SET %[3]s FROM "%[4]s" AS _source WHERE %[5]s`,
+				namespace, tableName, strings.Join(updates, ", "), stagingTableName, joinClause))
+		}
+	}
+
+	sourceColumnNames := make([]string, 0, len(sortedColumnKeys))
+	for _, columnName := range sortedColumnKeys {
+		sourceColumnNames = append(sourceColumnNames, fmt.Sprintf(`_source."%s"`, columnName))
+	}
+	statements = append(statements, fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[3]s) SELECT %[4]s FROM "%[5]s" AS _source WHERE NOT EXISTS (SELECT 1 FROM "%[1]s"."%[2]s" AS _target WHERE %[6]s)`,
+		namespace, tableName, quotedColumnNames, strings.Join(sourceColumnNames, ", "), stagingTableName, joinClause))
+	return statements
+}
+
 func (pg *Handle) Cleanup() {
 	if pg.DB != nil {
 		pg.dropDanglingStagingTables()
@@ -13,6 +13,7 @@ func distinctDestinationRevisionIdsFromStagingFiles(ctx context.Context, d struc
 	destinationID      string
 	startStagingFileID int64
 	endStagingFileID   int64
+	backfillID         string
 },
 ) (revisionIDs []string, err error) {
 	sqlStatement := fmt.Sprintf(`
@@ -25,6 +26,7 @@ func distinctDestinationRevisionIdsFromStagingFiles(ctx context.Context, d struc
 		  AND id <= $2 
 		  AND source_id = $3 
 		  AND destination_id = $4 
+		  AND COALESCE(metadata ->> 'backfill_id', '') = $5 
 		  AND metadata ->> 'destination_revision_id' <> '';
 	`,
 		warehouseutils.WarehouseStagingFilesTable,
@@ -34,6 +36,7 @@ func distinctDestinationRevisionIdsFromStagingFiles(ctx context.Context, d struc
 		d.endStagingFileID,
 		d.sourceID,
 		d.destinationID,
+		d.backfillID,
 	}...)
 	if err == sql.ErrNoRows {
 		err = nil
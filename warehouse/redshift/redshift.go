@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -51,6 +52,7 @@ type HandleT struct {
 	Warehouse      warehouseutils.Warehouse
 	Uploader       warehouseutils.UploaderI
 	ConnectTimeout time.Duration
+	loadCosts      warehouseutils.LoadCosts
 }
 
 // String constants for redshift destination config
@@ -150,12 +152,33 @@ func (rs *HandleT) CreateTable(tableName string, columns map[string]string) (err
 	if _, ok := columns["id"]; ok {
 		distKeySql = `DISTSTYLE KEY DISTKEY("id")`
 	}
-	sqlStatement := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s ( %v ) %s SORTKEY(%q) `, name, ColumnsWithDataTypes(columns, ""), distKeySql, sortKeyField)
+	partitioning := warehouseutils.GetTablePartitioning(rs.Warehouse, tableName, columns)
+	sqlStatement := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s ( %v ) %s %s `, name, ColumnsWithDataTypes(columns, ""), distKeySql, sortKeySql(sortKeyField, partitioning))
 	pkgLogger.Infof("Creating table in redshift for RS:%s : %v", rs.Warehouse.Destination.ID, sqlStatement)
 	_, err = rs.Db.Exec(sqlStatement)
 	return
 }
 
+// sortKeySql returns the sort keys of a table. They are led by the partition column since redshift has no user defined partitions,
+// or by the default sort key if only clustering columns are configured, followed by the clustering columns.
+func sortKeySql(defaultSortKey string, partitioning warehouseutils.TablePartitioningT) string {
+	if partitioning.IsEmpty() {
+		return fmt.Sprintf(`SORTKEY(%q)`, defaultSortKey)
+	}
+	leadingSortKey := partitioning.PartitionColumn
+	if leadingSortKey == "" {
+		leadingSortKey = defaultSortKey
+	}
+	sortKeys := []string{fmt.Sprintf(`%q`, leadingSortKey)}
+	for _, column := range partitioning.ClusteringColumns {
+		if column == leadingSortKey {
+			continue
+		}
+		sortKeys = append(sortKeys, fmt.Sprintf(`%q`, column))
+	}
+	return fmt.Sprintf(`COMPOUND SORTKEY(%s)`, strings.Join(sortKeys, ", "))
+}
+
 func (rs *HandleT) DropTable(tableName string) (err error) {
 	sqlStatement := `DROP TABLE "%[1]s"."%[2]s"`
 	pkgLogger.Infof("RS: Dropping table in redshift for RS:%s : %v", rs.Warehouse.Destination.ID, sqlStatement)
@@ -378,12 +401,14 @@ func (rs *HandleT) loadTable(tableName string, tableSchemaInUpload, tableSchemaA
 		pkgLogger.Infof("RS: Running COPY command for table:%s at %s\n", tableName, sanitisedSQLStmt)
 	}
 
+	queryStartTime := time.Now()
 	_, err = tx.Exec(sqlStatement)
 	if err != nil {
 		pkgLogger.Errorf("RS: Error running COPY command: %v\n", err)
 		tx.Rollback()
 		return
 	}
+	queryIDs := lastQueryIDs(tx, "pg_last_copy_id()")
 
 	var (
 		primaryKey   = "id"
@@ -454,6 +479,8 @@ func (rs *HandleT) loadTable(tableName string, tableSchemaInUpload, tableSchemaA
 		tx.Rollback()
 		return
 	}
+	queryIDs = append(queryIDs, lastQueryIDs(tx, "pg_last_query_id()")...)
+	queryTime := time.Since(queryStartTime)
 
 	err = tx.Commit()
 	if err != nil {
@@ -461,10 +488,33 @@ func (rs *HandleT) loadTable(tableName string, tableSchemaInUpload, tableSchemaA
 		tx.Rollback()
 		return
 	}
+	rs.loadCosts.Add(tableName, warehouseutils.LoadCostT{
+		QueryIDs:    queryIDs,
+		QueryTimeMs: queryTime.Milliseconds(),
+	})
 	pkgLogger.Infof("RS: Complete load for table:%s\n", tableName)
 	return
 }
 
+// lastQueryIDs returns the id of the last query run in the transaction using the given redshift function,
+// e.g. pg_last_copy_id() for the last COPY command.
+func lastQueryIDs(tx *sql.Tx, lastQueryIDFunc string) []string {
+	var queryID sql.NullInt64
+	if err := tx.QueryRow(fmt.Sprintf(`SELECT %s;`, lastQueryIDFunc)).Scan(&queryID); err != nil {
+		pkgLogger.Warnf("RS: Error fetching %s: %v", lastQueryIDFunc, err)
+		return nil
+	}
+	if !queryID.Valid || queryID.Int64 <= 0 {
+		return nil
+	}
+	return []string{strconv.FormatInt(queryID.Int64, 10)}
+}
+
+// LoadTableCost returns the query ids and query time of the last load of the table.
+func (rs *HandleT) LoadTableCost(tableName string) (warehouseutils.LoadCostT, bool) {
+	return rs.loadCosts.Pop(tableName)
+}
+
 func (rs *HandleT) loadUserTables() (errorMap map[string]error) {
 	errorMap = map[string]error{warehouseutils.IdentifiesTable: nil}
 	pkgLogger.Infof("RS: Starting load for identifies and users tables\n")
@@ -532,6 +582,7 @@ func (rs *HandleT) loadUserTables() (errorMap map[string]error) {
 		return
 	}
 
+	queryStartTime := time.Now()
 	_, err = tx.Exec(sqlStatement)
 	if err != nil {
 		pkgLogger.Errorf("RS: Creating staging table for users failed: %s\n", sqlStatement)
@@ -565,6 +616,8 @@ func (rs *HandleT) loadUserTables() (errorMap map[string]error) {
 		errorMap[warehouseutils.UsersTable] = err
 		return
 	}
+	queryIDs := lastQueryIDs(tx, "pg_last_query_id()")
+	queryTime := time.Since(queryStartTime)
 
 	err = tx.Commit()
 	if err != nil {
@@ -573,6 +626,10 @@ func (rs *HandleT) loadUserTables() (errorMap map[string]error) {
 		errorMap[warehouseutils.UsersTable] = err
 		return
 	}
+	rs.loadCosts.Add(warehouseutils.UsersTable, warehouseutils.LoadCostT{
+		QueryIDs:    queryIDs,
+		QueryTimeMs: queryTime.Milliseconds(),
+	})
 	return
 }
 
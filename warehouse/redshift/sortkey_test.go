@@ -0,0 +1,44 @@
+package redshift
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestSortKeySql(t *testing.T) {
+	testCases := []struct {
+		name         string
+		partitioning warehouseutils.TablePartitioningT
+		want         string
+	}{
+		{
+			name: "default",
+			want: `SORTKEY("received_at")`,
+		},
+		{
+			name:         "partition column",
+			partitioning: warehouseutils.TablePartitioningT{PartitionColumn: "sent_at", PartitionGranularity: "day", ClusteringColumns: []string{"event"}},
+			want:         `COMPOUND SORTKEY("sent_at", "event")`,
+		},
+		{
+			name:         "clustering columns only",
+			partitioning: warehouseutils.TablePartitioningT{ClusteringColumns: []string{"event", "user_id"}},
+			want:         `COMPOUND SORTKEY("received_at", "event", "user_id")`,
+		},
+		{
+			name:         "clustering by the default sort key",
+			partitioning: warehouseutils.TablePartitioningT{ClusteringColumns: []string{"received_at", "event"}},
+			want:         `COMPOUND SORTKEY("received_at", "event")`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, sortKeySql("received_at", tc.partitioning))
+		})
+	}
+}
@@ -2,8 +2,11 @@ package warehouse
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 	"github.com/rudderlabs/rudder-server/config"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/cron"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 )
 
@@ -104,7 +108,7 @@ func GetPrevScheduledTime(syncFrequency, syncStartAt string, currTime time.Time)
 	return timeutil.StartOfDay(now).Add(time.Minute * time.Duration(allStartTimes[pos]))
 }
 
-// getLastUploadCreatedAt returns the start time of the last upload
+// getLastUploadCreatedAt returns the start time of the last upload, backfills aside as they are not scheduled
 func (wh *HandleT) getLastUploadCreatedAt(warehouse warehouseutils.Warehouse) time.Time {
 	var t sql.NullTime
 	sqlStatement := fmt.Sprintf(`
@@ -115,6 +119,7 @@ func (wh *HandleT) getLastUploadCreatedAt(warehouse warehouseutils.Warehouse) ti
 		WHERE
 		  source_id = '%s'
 		  AND destination_id = '%s'
+		  AND metadata ->> 'backfill_id' IS NULL
 		ORDER BY
 		  id DESC
 		LIMIT
@@ -186,6 +191,16 @@ func (wh *HandleT) canCreateUpload(warehouse warehouseutils.Warehouse) bool {
 	if CheckCurrentTimeExistsInExcludeWindow(timeutil.Now(), excludeWindowStartTime, excludeWindowEndTime) {
 		return false
 	}
+	if _, ok := getBlackoutWindows(warehouse).activeAt(timeutil.Now()); ok {
+		return false
+	}
+	if schedule, ok := getCronSchedule(warehouse); ok {
+		prevScheduledTime := schedule.Prev(timeutil.Now())
+		if prevScheduledTime.IsZero() {
+			return false
+		}
+		return wh.getLastUploadCreatedAt(warehouse).Before(prevScheduledTime)
+	}
 	syncFrequency := warehouseutils.GetConfigValue(warehouseutils.SyncFrequency, warehouse)
 	syncStartAt := warehouseutils.GetConfigValue(warehouseutils.SyncStartAt, warehouse)
 	if syncFrequency == "" || syncStartAt == "" {
@@ -198,6 +213,220 @@ func (wh *HandleT) canCreateUpload(warehouse warehouseutils.Warehouse) bool {
 	return lastUploadCreatedAt.Before(prevScheduledTime)
 }
 
+// GetNextScheduledTime returns the closest next scheduled time
+// e.g. Syncing every 3hrs starting at 13:00 (scheduled times: 13:00, 16:00, 19:00, 22:00, 01:00, 04:00, 07:00, 10:00)
+// next scheduled time for current time (e.g. 18:00 -> 19:00 same day, 23:30 -> 01:00 next day)
+func GetNextScheduledTime(syncFrequency, syncStartAt string, currTime time.Time) time.Time {
+	allStartTimes := ScheduledTimes(syncFrequency, syncStartAt)
+
+	now := currTime.UTC()
+	currMins := now.Hour()*60 + now.Minute()
+	for _, t := range allStartTimes {
+		if currMins < t {
+			return timeutil.StartOfDay(now).Add(time.Minute * time.Duration(t))
+		}
+	}
+	// if current time is past all start times in a day, take first start time in next day
+	return timeutil.StartOfDay(now).Add(time.Hour * 24).Add(time.Minute * time.Duration(allStartTimes[0]))
+}
+
+// getCronSchedule returns the parsed cron expression configured for the warehouse, if any.
+// Invalid expressions are logged and ignored, falling back to the sync frequency.
+func getCronSchedule(warehouse warehouseutils.Warehouse) (*cron.Schedule, bool) {
+	expr := warehouseutils.GetConfigValue(warehouseutils.SyncCronExpression, warehouse)
+	if expr == "" {
+		return nil, false
+	}
+	schedule, err := cron.Parse(expr)
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Invalid cron expression for %s: %v", warehouse.Identifier, err)
+		return nil, false
+	}
+	return schedule, true
+}
+
+// blackoutWindow is a time window (in UTC) during which no uploads are started,
+// e.g. 09:00 to 17:00 on weekdays. Windows ending before they start span midnight.
+type blackoutWindow struct {
+	startMins, endMins int
+	// days the window starts on, every day if empty
+	days map[time.Weekday]bool
+}
+
+type blackoutWindows []blackoutWindow
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// getBlackoutWindows returns the blackout windows configured for the warehouse.
+// Each window is configured as {"startTime": "09:00", "endTime": "17:00", "days": ["mon", "tue"]}.
+func getBlackoutWindows(warehouse warehouseutils.Warehouse) blackoutWindows {
+	configured, ok := warehouse.Destination.Config[warehouseutils.BlackoutWindows].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var windows blackoutWindows
+	for _, c := range configured {
+		windowConfig, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		startTime, _ := windowConfig[warehouseutils.BlackoutWindowStartTime].(string)
+		endTime, _ := windowConfig[warehouseutils.BlackoutWindowEndTime].(string)
+		if startTime == "" || endTime == "" || startTime == endTime {
+			continue
+		}
+
+		window := blackoutWindow{
+			startMins: timeutil.MinsOfDay(startTime),
+			endMins:   timeutil.MinsOfDay(endTime),
+		}
+		days, _ := windowConfig[warehouseutils.BlackoutWindowDays].([]interface{})
+		for _, d := range days {
+			day, _ := d.(string)
+			if len(day) < 3 {
+				continue
+			}
+			if weekday, ok := weekdays[strings.ToLower(day[:3])]; ok {
+				if window.days == nil {
+					window.days = map[time.Weekday]bool{}
+				}
+				window.days[weekday] = true
+			}
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// start returns the start of the window occurrence containing t, or false if t is not in the window.
+func (bw blackoutWindow) start(t time.Time) (time.Time, bool) {
+	t = t.UTC()
+	mins := t.Hour()*60 + t.Minute()
+
+	var startDay time.Time
+	switch {
+	case bw.startMins < bw.endMins && bw.startMins <= mins && mins < bw.endMins:
+		startDay = timeutil.StartOfDay(t)
+	case bw.startMins > bw.endMins && mins >= bw.startMins:
+		startDay = timeutil.StartOfDay(t)
+	case bw.startMins > bw.endMins && mins < bw.endMins:
+		// window started the day before and spans midnight
+		startDay = timeutil.StartOfDay(t).AddDate(0, 0, -1)
+	default:
+		return time.Time{}, false
+	}
+	if len(bw.days) > 0 && !bw.days[startDay.Weekday()] {
+		return time.Time{}, false
+	}
+	return startDay.Add(time.Duration(bw.startMins) * time.Minute), true
+}
+
+// activeAt returns the end of the blackout window containing t, or false if t is not in any window.
+func (windows blackoutWindows) activeAt(t time.Time) (time.Time, bool) {
+	var (
+		end    time.Time
+		active bool
+	)
+	for _, bw := range windows {
+		start, ok := bw.start(t)
+		if !ok {
+			continue
+		}
+		windowEnd := timeutil.StartOfDay(start).Add(time.Duration(bw.endMins) * time.Minute)
+		if bw.startMins > bw.endMins {
+			windowEnd = windowEnd.AddDate(0, 0, 1)
+		}
+		if windowEnd.After(end) {
+			end = windowEnd
+		}
+		active = true
+	}
+	return end, active
+}
+
+// nextUploadTime returns the time at which the next scheduled upload for the warehouse can start,
+// or false if it isn't scheduled. Scheduled times falling in blackout windows are postponed to their end.
+func nextUploadTime(warehouse warehouseutils.Warehouse, now time.Time) (time.Time, bool) {
+	var next time.Time
+	if schedule, ok := getCronSchedule(warehouse); ok {
+		next = schedule.Next(now)
+	} else {
+		syncFrequency := warehouseutils.GetConfigValue(warehouseutils.SyncFrequency, warehouse)
+		syncStartAt := warehouseutils.GetConfigValue(warehouseutils.SyncStartAt, warehouse)
+		if syncFrequency == "" || syncStartAt == "" {
+			return time.Time{}, false
+		}
+		next = GetNextScheduledTime(syncFrequency, syncStartAt, now)
+	}
+	if next.IsZero() {
+		return time.Time{}, false
+	}
+
+	// windows can be adjacent or overlapping, so keep postponing until out of all of them
+	windows := getBlackoutWindows(warehouse)
+	for i := 0; i <= len(windows); i++ {
+		end, ok := windows.activeAt(next)
+		if !ok {
+			break
+		}
+		next = end
+	}
+	return next.UTC(), true
+}
+
+type uploadScheduleResponse struct {
+	SourceID          string     `json:"sourceId"`
+	DestinationID     string     `json:"destinationId"`
+	CronExpression    string     `json:"cronExpression,omitempty"`
+	SyncFrequency     string     `json:"syncFrequency,omitempty"`
+	SyncStartAt       string     `json:"syncStartAt,omitempty"`
+	NextScheduledTime *time.Time `json:"nextScheduledTime,omitempty"`
+}
+
+// uploadScheduleHandler returns the next scheduled upload time for a source and destination
+func uploadScheduleHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	sourceID := r.URL.Query().Get("sourceId")
+	destinationID := r.URL.Query().Get("destinationId")
+
+	connectionsMapLock.RLock()
+	warehouse, err := getDestinationFromConnectionMap(destinationID, sourceID)
+	connectionsMapLock.RUnlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res := uploadScheduleResponse{
+		SourceID:       sourceID,
+		DestinationID:  destinationID,
+		CronExpression: warehouseutils.GetConfigValue(warehouseutils.SyncCronExpression, warehouse),
+		SyncFrequency:  warehouseutils.GetConfigValue(warehouseutils.SyncFrequency, warehouse),
+		SyncStartAt:    warehouseutils.GetConfigValue(warehouseutils.SyncStartAt, warehouse),
+	}
+	if next, ok := nextUploadTime(warehouse, timeutil.Now()); ok {
+		res.NextScheduledTime = &next
+	}
+
+	resBody, err := json.Marshal(res)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBody)
+}
+
 func DurationBeforeNextAttempt(attempt int64) time.Duration { // Add state(retryable/non-retryable) as an argument to decide backoff etc.)
 	var d time.Duration
 	b := backoff.NewExponentialBackOff()
@@ -0,0 +1,129 @@
+package warehouse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	"github.com/rudderlabs/rudder-server/utils/logger"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestBlackoutWindows(t *testing.T) {
+	warehouse := warehouseutils.Warehouse{
+		Destination: backendconfig.DestinationT{
+			Config: map[string]interface{}{
+				warehouseutils.BlackoutWindows: []interface{}{
+					map[string]interface{}{"startTime": "09:00", "endTime": "17:00", "days": []interface{}{"mon", "tue", "wed", "thu", "friday"}},
+					map[string]interface{}{"startTime": "22:00", "endTime": "02:00", "days": []interface{}{"sat"}},
+					map[string]interface{}{"startTime": "10:00"},
+				},
+			},
+		},
+	}
+	windows := getBlackoutWindows(warehouse)
+	require.Len(t, windows, 2)
+
+	testCases := []struct {
+		name    string
+		now     time.Time
+		wantEnd time.Time
+		active  bool
+	}{
+		{
+			name:    "weekday within window",
+			now:     time.Date(2022, 12, 9, 12, 30, 0, 0, time.UTC), // friday
+			wantEnd: time.Date(2022, 12, 9, 17, 0, 0, 0, time.UTC),
+			active:  true,
+		},
+		{
+			name: "weekday at window end",
+			now:  time.Date(2022, 12, 9, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "weekend during weekday window",
+			now:  time.Date(2022, 12, 10, 12, 30, 0, 0, time.UTC), // saturday
+		},
+		{
+			name:    "overnight window after midnight",
+			now:     time.Date(2022, 12, 11, 1, 0, 0, 0, time.UTC), // sunday, window started on saturday
+			wantEnd: time.Date(2022, 12, 11, 2, 0, 0, 0, time.UTC),
+			active:  true,
+		},
+		{
+			name: "overnight window on another day",
+			now:  time.Date(2022, 12, 12, 1, 0, 0, 0, time.UTC), // monday, window started on sunday
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			end, active := windows.activeAt(tc.now)
+			require.Equal(t, tc.active, active)
+			require.Equal(t, tc.wantEnd, end)
+		})
+	}
+}
+
+func TestNextUploadTime(t *testing.T) {
+	Init3()
+	pkgLogger = logger.NOP
+
+	now := time.Date(2022, 12, 9, 8, 10, 0, 0, time.UTC) // friday
+
+	testCases := []struct {
+		name     string
+		config   map[string]interface{}
+		wantNext time.Time
+		wantOK   bool
+	}{
+		{
+			name:   "no schedule",
+			config: map[string]interface{}{},
+		},
+		{
+			name:     "sync frequency",
+			config:   map[string]interface{}{"syncFrequency": "180", "syncStartAt": "00:00"},
+			wantNext: time.Date(2022, 12, 9, 9, 0, 0, 0, time.UTC),
+			wantOK:   true,
+		},
+		{
+			name:     "cron expression takes precedence",
+			config:   map[string]interface{}{"syncFrequency": "180", "syncStartAt": "00:00", "syncCronExpression": "*/30 * * * *"},
+			wantNext: time.Date(2022, 12, 9, 8, 30, 0, 0, time.UTC),
+			wantOK:   true,
+		},
+		{
+			name:     "invalid cron expression falls back to sync frequency",
+			config:   map[string]interface{}{"syncFrequency": "180", "syncStartAt": "00:00", "syncCronExpression": "every hour"},
+			wantNext: time.Date(2022, 12, 9, 9, 0, 0, 0, time.UTC),
+			wantOK:   true,
+		},
+		{
+			name: "postponed to end of blackout windows",
+			config: map[string]interface{}{
+				"syncCronExpression": "0 * * * *",
+				"blackoutWindows": []interface{}{
+					map[string]interface{}{"startTime": "09:00", "endTime": "17:00"},
+					map[string]interface{}{"startTime": "16:00", "endTime": "18:00"},
+				},
+			},
+			wantNext: time.Date(2022, 12, 9, 18, 0, 0, 0, time.UTC),
+			wantOK:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			next, ok := nextUploadTime(warehouseutils.Warehouse{
+				Destination: backendconfig.DestinationT{Config: tc.config},
+			}, now)
+			require.Equal(t, tc.wantOK, ok)
+			require.Equal(t, tc.wantNext, next)
+		})
+	}
+}
@@ -71,6 +71,25 @@ var _ = Describe("Scheduling", func() {
 		})
 	})
 
+	Describe("GetNextScheduledTime", func() {
+		It("should return next scheduled time", func() {
+			now := time.Date(2020, 0o4, 27, 20, 23, 54, 3424534, time.UTC)
+			sTime := GetNextScheduledTime("30", "14:00", now)
+			Expect(sTime).To(Equal(time.Date(2020, 0o4, 27, 20, 30, 0, 0, time.UTC)))
+
+			// current time exactly equal to scheduled time
+			now = time.Date(2020, 0o4, 27, 20, 30, 0, 0, time.UTC)
+			sTime = GetNextScheduledTime("30", "14:00", now)
+			Expect(sTime).To(Equal(time.Date(2020, 0o4, 27, 21, 0, 0, 0, time.UTC)))
+		})
+
+		It("should return next day's first scheduled time if greater than all of today's scheduled time", func() {
+			now := time.Date(2020, 0o4, 27, 23, 23, 54, 3424534, time.UTC)
+			sTime := GetNextScheduledTime("360", "05:00", now)
+			Expect(sTime).To(Equal(time.Date(2020, 0o4, 28, 5, 0, 0, 0, time.UTC)))
+		})
+	})
+
 	Describe("CheckCurrentTimeExistsInExcludeWindow", func() {
 		It("should return true if current time falls in excludeWindow", func() {
 			startTime := "05:00"
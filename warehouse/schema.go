@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/utils/timeutil"
@@ -21,6 +22,95 @@ type SchemaHandleT struct {
 	schemaInWarehouse             warehouseutils.SchemaT
 	unrecognizedSchemaInWarehouse warehouseutils.SchemaT
 	uploadSchema                  warehouseutils.SchemaT
+	columnTypeConflicts           []columnTypeConflict
+}
+
+// column type conflict resolutions
+const (
+	columnTypeWidened   = "widened"
+	columnTypeShadowed  = "shadowed"
+	columnTypeDiscarded = "discarded"
+)
+
+// columnTypeConflict is a column of the warehouse receiving values of a type which can't be converted to the column's type.
+type columnTypeConflict struct {
+	TableName     string `json:"table_name"`
+	ColumnName    string `json:"column_name"`
+	WarehouseType string `json:"warehouse_type"`
+	StagingType   string `json:"staging_type"`
+	Resolution    string `json:"resolution"`
+	// ResolvedColumn and ResolvedType are the column and type the conflicting values are loaded into, unless discarded.
+	ResolvedColumn string `json:"resolved_column,omitempty"`
+	ResolvedType   string `json:"resolved_type,omitempty"`
+}
+
+func (c columnTypeConflict) String() string {
+	switch c.Resolution {
+	case columnTypeWidened:
+		return fmt.Sprintf("column %s.%s of type %s received %s values: widened to %s", c.TableName, c.ColumnName, c.WarehouseType, c.StagingType, c.ResolvedType)
+	case columnTypeShadowed:
+		return fmt.Sprintf("column %s.%s of type %s received %s values: loaded into shadow column %s", c.TableName, c.ColumnName, c.WarehouseType, c.StagingType, c.ResolvedColumn)
+	default:
+		return fmt.Sprintf("column %s.%s of type %s received %s values: discarded to %s", c.TableName, c.ColumnName, c.WarehouseType, c.StagingType, warehouseutils.DiscardsTable)
+	}
+}
+
+// columnTypeWideningOrder ranks the types values can be widened through, i.e. int -> float -> string.
+var columnTypeWideningOrder = map[string]int{
+	"int":    0,
+	"float":  1,
+	"string": 2,
+}
+
+// columnTypeWideningSupport lists the widenings each warehouse applies in place through AlterColumn.
+// Warehouses not listed here fall back to discarding conflicting values under the widen policy.
+var columnTypeWideningSupport = map[string]map[string][]string{
+	warehouseutils.POSTGRES:      {"int": {"float", "string"}, "float": {"string"}},
+	warehouseutils.MSSQL:         {"int": {"float", "string"}, "float": {"string"}},
+	warehouseutils.AZURE_SYNAPSE: {"int": {"float", "string"}, "float": {"string"}},
+	warehouseutils.CLICKHOUSE:    {"int": {"float", "string"}, "float": {"string"}},
+	warehouseutils.BQ:            {"int": {"float"}},
+}
+
+// isWiderDataType returns true if currentDataType is wider than existingDataType.
+func isWiderDataType(existingDataType, currentDataType string) bool {
+	existingOrder, ok := columnTypeWideningOrder[existingDataType]
+	if !ok {
+		return false
+	}
+	currentOrder, ok := columnTypeWideningOrder[currentDataType]
+	return ok && currentOrder > existingOrder
+}
+
+// isWideningConversion returns true if the warehouse can widen a column from existingDataType to currentDataType.
+func isWideningConversion(warehouseType, existingDataType, currentDataType string) bool {
+	if !isWiderDataType(existingDataType, currentDataType) {
+		return false
+	}
+	for _, dataType := range columnTypeWideningSupport[warehouseType][existingDataType] {
+		if dataType == currentDataType {
+			return true
+		}
+	}
+	return false
+}
+
+// isConvertibleDataType returns true if HandleSchemaChange converts values of currentDataType for a column of existingDataType.
+func isConvertibleDataType(existingDataType, currentDataType model.SchemaType) bool {
+	switch existingDataType {
+	case currentDataType, model.StringDataType, model.TextDataType, model.JSONDataType:
+		return true
+	case model.FloatDataType:
+		return currentDataType == model.IntDataType || currentDataType == model.BigIntDataType
+	case model.IntDataType, model.BigIntDataType:
+		return currentDataType == model.FloatDataType
+	}
+	return false
+}
+
+// shadowColumnName returns the column receiving the values of columnName which conflict with its type under the shadow policy.
+func shadowColumnName(warehouseType, columnName, dataType string) string {
+	return columnName + warehouseutils.ToProviderCase(warehouseType, "_"+dataType)
 }
 
 func HandleSchemaChange(existingDataType, currentDataType model.SchemaType, value any) (any, error) {
@@ -284,6 +374,7 @@ func (sh *SchemaHandleT) consolidateStagingFilesSchemaUsingWarehouseSchema() war
 	schemaInLocalDB := sh.localSchema
 
 	consolidatedSchema := warehouseutils.SchemaT{}
+	stagingColumnTypes := map[string]map[string]map[string]bool{}
 	count := 0
 	for {
 		lastIndex := count + stagingFilesSchemaPaginationSize
@@ -330,6 +421,7 @@ func (sh *SchemaHandleT) consolidateStagingFilesSchemaUsingWarehouseSchema() war
 		_ = rows.Close()
 
 		consolidatedSchema = mergeSchema(schemaInLocalDB, schemas, consolidatedSchema, sh.warehouse.Type)
+		collectStagingColumnTypes(schemaInLocalDB, schemas, stagingColumnTypes)
 
 		count += stagingFilesSchemaPaginationSize
 		if count >= len(sh.stagingFiles) {
@@ -337,6 +429,14 @@ func (sh *SchemaHandleT) consolidateStagingFilesSchemaUsingWarehouseSchema() war
 		}
 	}
 
+	sh.columnTypeConflicts = resolveColumnTypeConflicts(
+		warehouseutils.GetColumnTypeConflictPolicy(sh.warehouse.Destination.Config),
+		sh.warehouse.Type,
+		schemaInLocalDB,
+		consolidatedSchema,
+		stagingColumnTypes,
+	)
+
 	// add rudder_discards Schema
 	consolidatedSchema[sh.safeName(warehouseutils.DiscardsTable)] = sh.getDiscardsSchema()
 
@@ -351,6 +451,101 @@ func (sh *SchemaHandleT) consolidateStagingFilesSchemaUsingWarehouseSchema() war
 	return consolidatedSchema
 }
 
+// collectStagingColumnTypes collects the types of the staging files columns which differ from the column's type in the warehouse.
+func collectStagingColumnTypes(localSchema warehouseutils.SchemaT, schemaList []warehouseutils.SchemaT, stagingColumnTypes map[string]map[string]map[string]bool) {
+	for _, schema := range schemaList {
+		for tableName, columnMap := range schema {
+			for columnName, columnType := range columnMap {
+				existingType, ok := localSchema[tableName][columnName]
+				if !ok || existingType == columnType {
+					continue
+				}
+				if _, ok := stagingColumnTypes[tableName]; !ok {
+					stagingColumnTypes[tableName] = map[string]map[string]bool{}
+				}
+				if _, ok := stagingColumnTypes[tableName][columnName]; !ok {
+					stagingColumnTypes[tableName][columnName] = map[string]bool{}
+				}
+				stagingColumnTypes[tableName][columnName][columnType] = true
+			}
+		}
+	}
+}
+
+// resolveColumnTypeConflicts applies the column type conflict policy to the consolidated schema. Under the widen policy,
+// columns are widened to the widest type the warehouse supports, while the shadow policy adds a column suffixed by the
+// conflicting type. Values which can't be resolved are discarded, same as under the discard policy.
+func resolveColumnTypeConflicts(policy, warehouseType string, localSchema, consolidatedSchema warehouseutils.SchemaT, stagingColumnTypes map[string]map[string]map[string]bool) []columnTypeConflict {
+	var conflicts []columnTypeConflict
+
+	tableNames := make([]string, 0, len(stagingColumnTypes))
+	for tableName := range stagingColumnTypes {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	for _, tableName := range tableNames {
+		columnNames := make([]string, 0, len(stagingColumnTypes[tableName]))
+		for columnName := range stagingColumnTypes[tableName] {
+			columnNames = append(columnNames, columnName)
+		}
+		sort.Strings(columnNames)
+
+		for _, columnName := range columnNames {
+			existingType := localSchema[tableName][columnName]
+			// column types inferred from other tables, e.g. users from identifies, are left as is
+			if consolidatedSchema[tableName][columnName] != existingType {
+				continue
+			}
+
+			stagingTypes := make([]string, 0, len(stagingColumnTypes[tableName][columnName]))
+			for stagingType := range stagingColumnTypes[tableName][columnName] {
+				stagingTypes = append(stagingTypes, stagingType)
+			}
+			sort.Strings(stagingTypes)
+
+			resolvedType := existingType
+			if policy == warehouseutils.WidenConflictPolicy {
+				for _, stagingType := range stagingTypes {
+					if isWideningConversion(warehouseType, existingType, stagingType) && isWiderDataType(resolvedType, stagingType) {
+						resolvedType = stagingType
+					}
+				}
+				consolidatedSchema[tableName][columnName] = resolvedType
+			}
+
+			for _, stagingType := range stagingTypes {
+				conflict := columnTypeConflict{
+					TableName:     tableName,
+					ColumnName:    columnName,
+					WarehouseType: existingType,
+					StagingType:   stagingType,
+					Resolution:    columnTypeDiscarded,
+				}
+
+				switch {
+				case resolvedType != existingType && isConvertibleDataType(model.SchemaType(resolvedType), model.SchemaType(stagingType)):
+					conflict.Resolution = columnTypeWidened
+					conflict.ResolvedColumn = columnName
+					conflict.ResolvedType = resolvedType
+				case isConvertibleDataType(model.SchemaType(existingType), model.SchemaType(stagingType)):
+					continue
+				case policy == warehouseutils.ShadowColumnConflictPolicy:
+					shadowColumn := shadowColumnName(warehouseType, columnName, stagingType)
+					if shadowType, ok := consolidatedSchema[tableName][shadowColumn]; !ok || shadowType == stagingType {
+						consolidatedSchema[tableName][shadowColumn] = stagingType
+						conflict.Resolution = columnTypeShadowed
+						conflict.ResolvedColumn = shadowColumn
+						conflict.ResolvedType = stagingType
+					}
+				}
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+	return conflicts
+}
+
 // hasSchemaChanged Default behaviour is to do the deep equals.
 // If we are skipping deep equals, then we are validating local schemas against warehouse schemas only.
 // Not the other way around.
@@ -415,6 +610,14 @@ func getTableSchemaDiff(tableName string, currentSchema, uploadSchema warehouseu
 			diff.StringColumnsToBeAlteredToText = append(diff.StringColumnsToBeAlteredToText, columnName)
 			diff.UpdatedSchema[columnName] = columnType
 			diff.Exists = true
+		} else if isWiderDataType(currentTableSchema[columnName], columnType) {
+			// upload schema only differs in numerical types from the warehouse if the column has been widened
+			if diff.ColumnsToBeWidened == nil {
+				diff.ColumnsToBeWidened = make(map[string]string)
+			}
+			diff.ColumnsToBeWidened[columnName] = columnType
+			diff.UpdatedSchema[columnName] = columnType
+			diff.Exists = true
 		}
 	}
 	return diff
@@ -454,6 +657,10 @@ func mergeUploadAndLocalSchemas(uploadSchema, schemaInWarehousePreUpload warehou
 			if uploadColType == "text" && localColType == "string" {
 				mergedSchema[uploadTableName][uploadColName] = uploadColType
 			}
+			// change type of uploadCol if it has been widened
+			if isWiderDataType(localColType, uploadColType) {
+				mergedSchema[uploadTableName][uploadColName] = uploadColType
+			}
 		}
 	}
 	return mergedSchema
@@ -0,0 +1,76 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+type tableSchemaT struct {
+	Name         string                             `json:"name"`
+	Columns      warehouseutils.TableSchemaT        `json:"columns"`
+	Partitioning *warehouseutils.TablePartitioningT `json:"partitioning,omitempty"`
+}
+
+type schemaResponse struct {
+	SourceID        string         `json:"sourceId"`
+	DestinationID   string         `json:"destinationId"`
+	DestinationType string         `json:"destinationType"`
+	Namespace       string         `json:"namespace"`
+	Tables          []tableSchemaT `json:"tables"`
+}
+
+// tableSchemas returns the tables of the schema sorted by name, along with the partitioning and clustering they are created with,
+// including the default partitioning of the warehouse
+func tableSchemas(warehouse warehouseutils.Warehouse, schema warehouseutils.SchemaT) []tableSchemaT {
+	tables := make([]tableSchemaT, 0, len(schema))
+	for tableName, columns := range schema {
+		table := tableSchemaT{
+			Name:    tableName,
+			Columns: columns,
+		}
+		if partitioning := warehouseutils.GetTableLayout(warehouse, tableName, columns); !partitioning.IsEmpty() {
+			table.Partitioning = &partitioning
+		}
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables
+}
+
+// schemaHandler returns the schema of the tables of a source in a destination, along with their partitioning and clustering
+func schemaHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	connectionsMapLock.RLock()
+	warehouse, err := getDestinationFromConnectionMap(r.URL.Query().Get("destinationId"), r.URL.Query().Get("sourceId"))
+	connectionsMapLock.RUnlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sh := SchemaHandleT{warehouse: warehouse}
+	resBody, err := json.Marshal(schemaResponse{
+		SourceID:        warehouse.Source.ID,
+		DestinationID:   warehouse.Destination.ID,
+		DestinationType: warehouse.Type,
+		Namespace:       warehouse.Namespace,
+		Tables:          tableSchemas(warehouse, sh.getLocalSchema()),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(resBody)
+}
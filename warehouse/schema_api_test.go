@@ -0,0 +1,52 @@
+package warehouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestTableSchemas(t *testing.T) {
+	warehouse := warehouseutils.Warehouse{
+		Type: warehouseutils.BQ,
+		Destination: backendconfig.DestinationT{
+			Config: map[string]interface{}{
+				"partitionColumn":   "received_at",
+				"clusteringColumns": "event",
+			},
+		},
+	}
+	schema := warehouseutils.SchemaT{
+		"users":  {"id": "string", "received_at": "datetime"},
+		"tracks": {"id": "string", "event": "string", "received_at": "datetime"},
+	}
+
+	require.Equal(t, []tableSchemaT{
+		{
+			Name:    "tracks",
+			Columns: schema["tracks"],
+			Partitioning: &warehouseutils.TablePartitioningT{
+				PartitionColumn:      "received_at",
+				PartitionGranularity: "day",
+				ClusteringColumns:    []string{"event"},
+			},
+		},
+		{
+			Name:    "users",
+			Columns: schema["users"],
+			Partitioning: &warehouseutils.TablePartitioningT{
+				PartitionColumn:      "_PARTITIONTIME",
+				PartitionGranularity: "day",
+			},
+		},
+	}, tableSchemas(warehouse, schema), "users are reported as ingestion-time partitioned")
+
+	warehouse.Type = warehouseutils.POSTGRES
+	require.Equal(t, []tableSchemaT{
+		{Name: "tracks", Columns: schema["tracks"]},
+		{Name: "users", Columns: schema["users"]},
+	}, tableSchemas(warehouse, schema))
+}
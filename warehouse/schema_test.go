@@ -270,6 +270,126 @@ func TestHandleSchemaChange(t *testing.T) {
 	}
 }
 
+func TestResolveColumnTypeConflicts(t *testing.T) {
+	localSchema := warehouseutils.SchemaT{
+		"tracks": {
+			"id":       "string",
+			"revenue":  "int",
+			"price":    "float",
+			"is_admin": "boolean",
+		},
+	}
+	stagingColumnTypes := map[string]map[string]map[string]bool{
+		"tracks": {
+			"revenue":  {"float": true, "boolean": true},
+			"price":    {"int": true, "string": true},
+			"is_admin": {"string": true},
+		},
+	}
+	consolidatedSchema := func() warehouseutils.SchemaT {
+		schema := warehouseutils.SchemaT{"tracks": {}}
+		for columnName, columnType := range localSchema["tracks"] {
+			schema["tracks"][columnName] = columnType
+		}
+		return schema
+	}
+
+	testCases := []struct {
+		name           string
+		policy         string
+		warehouseType  string
+		expectedSchema map[string]string
+		expected       []columnTypeConflict
+	}{
+		{
+			name:           "discard",
+			policy:         warehouseutils.DiscardConflictPolicy,
+			warehouseType:  warehouseutils.POSTGRES,
+			expectedSchema: localSchema["tracks"],
+			expected: []columnTypeConflict{
+				{TableName: "tracks", ColumnName: "is_admin", WarehouseType: "boolean", StagingType: "string", Resolution: columnTypeDiscarded},
+				{TableName: "tracks", ColumnName: "price", WarehouseType: "float", StagingType: "string", Resolution: columnTypeDiscarded},
+				{TableName: "tracks", ColumnName: "revenue", WarehouseType: "int", StagingType: "boolean", Resolution: columnTypeDiscarded},
+			},
+		},
+		{
+			name:          "widen",
+			policy:        warehouseutils.WidenConflictPolicy,
+			warehouseType: warehouseutils.POSTGRES,
+			expectedSchema: map[string]string{
+				"id":       "string",
+				"revenue":  "float",
+				"price":    "string",
+				"is_admin": "boolean",
+			},
+			expected: []columnTypeConflict{
+				{TableName: "tracks", ColumnName: "is_admin", WarehouseType: "boolean", StagingType: "string", Resolution: columnTypeDiscarded},
+				{TableName: "tracks", ColumnName: "price", WarehouseType: "float", StagingType: "int", Resolution: columnTypeWidened, ResolvedColumn: "price", ResolvedType: "string"},
+				{TableName: "tracks", ColumnName: "price", WarehouseType: "float", StagingType: "string", Resolution: columnTypeWidened, ResolvedColumn: "price", ResolvedType: "string"},
+				{TableName: "tracks", ColumnName: "revenue", WarehouseType: "int", StagingType: "boolean", Resolution: columnTypeDiscarded},
+				{TableName: "tracks", ColumnName: "revenue", WarehouseType: "int", StagingType: "float", Resolution: columnTypeWidened, ResolvedColumn: "revenue", ResolvedType: "float"},
+			},
+		},
+		{
+			name:           "widen unsupported by warehouse",
+			policy:         warehouseutils.WidenConflictPolicy,
+			warehouseType:  warehouseutils.SNOWFLAKE,
+			expectedSchema: localSchema["tracks"],
+			expected: []columnTypeConflict{
+				{TableName: "tracks", ColumnName: "is_admin", WarehouseType: "boolean", StagingType: "string", Resolution: columnTypeDiscarded},
+				{TableName: "tracks", ColumnName: "price", WarehouseType: "float", StagingType: "string", Resolution: columnTypeDiscarded},
+				{TableName: "tracks", ColumnName: "revenue", WarehouseType: "int", StagingType: "boolean", Resolution: columnTypeDiscarded},
+			},
+		},
+		{
+			name:          "shadow",
+			policy:        warehouseutils.ShadowColumnConflictPolicy,
+			warehouseType: warehouseutils.POSTGRES,
+			expectedSchema: map[string]string{
+				"id":              "string",
+				"revenue":         "int",
+				"revenue_boolean": "boolean",
+				"price":           "float",
+				"price_string":    "string",
+				"is_admin":        "boolean",
+				"is_admin_string": "string",
+			},
+			expected: []columnTypeConflict{
+				{TableName: "tracks", ColumnName: "is_admin", WarehouseType: "boolean", StagingType: "string", Resolution: columnTypeShadowed, ResolvedColumn: "is_admin_string", ResolvedType: "string"},
+				{TableName: "tracks", ColumnName: "price", WarehouseType: "float", StagingType: "string", Resolution: columnTypeShadowed, ResolvedColumn: "price_string", ResolvedType: "string"},
+				{TableName: "tracks", ColumnName: "revenue", WarehouseType: "int", StagingType: "boolean", Resolution: columnTypeShadowed, ResolvedColumn: "revenue_boolean", ResolvedType: "boolean"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			schema := consolidatedSchema()
+			conflicts := resolveColumnTypeConflicts(tc.policy, tc.warehouseType, localSchema, schema, stagingColumnTypes)
+			require.Equal(t, tc.expected, conflicts)
+			require.Equal(t, tc.expectedSchema, schema["tracks"])
+		})
+	}
+}
+
+func TestCollectStagingColumnTypes(t *testing.T) {
+	localSchema := warehouseutils.SchemaT{
+		"tracks": {"id": "string", "revenue": "int"},
+	}
+	stagingColumnTypes := map[string]map[string]map[string]bool{}
+	collectStagingColumnTypes(localSchema, []warehouseutils.SchemaT{
+		{"tracks": {"id": "string", "revenue": "float"}, "pages": {"id": "int"}},
+		{"tracks": {"id": "int", "revenue": "float", "new_column": "string"}},
+	}, stagingColumnTypes)
+	require.Equal(t, map[string]map[string]map[string]bool{
+		"tracks": {
+			"id":      {"int": true},
+			"revenue": {"float": true},
+		},
+	}, stagingColumnTypes)
+}
+
 var _ = Describe("Schema", func() {
 	DescribeTable("Get table schema diff", func(tableName string, currentSchema, uploadSchema warehouseutils.SchemaT, expected warehouseutils.TableSchemaDiffT) {
 		Expect(getTableSchemaDiff(tableName, currentSchema, uploadSchema)).To(Equal(expected))
@@ -352,6 +472,33 @@ var _ = Describe("Schema", func() {
 			},
 			StringColumnsToBeAlteredToText: []string{"test-column"},
 		}),
+
+		Entry(nil, "test-table", warehouseutils.SchemaT{
+			"test-table": map[string]string{
+				"test-column":   "int",
+				"test-column-2": "float",
+				"test-column-3": "boolean",
+			},
+		}, warehouseutils.SchemaT{
+			"test-table": map[string]string{
+				"test-column":   "float",
+				"test-column-2": "string",
+				"test-column-3": "boolean",
+			},
+		}, warehouseutils.TableSchemaDiffT{
+			Exists:           true,
+			TableToBeCreated: false,
+			ColumnMap:        map[string]string{},
+			UpdatedSchema: map[string]string{
+				"test-column":   "float",
+				"test-column-2": "string",
+				"test-column-3": "boolean",
+			},
+			ColumnsToBeWidened: map[string]string{
+				"test-column":   "float",
+				"test-column-2": "string",
+			},
+		}),
 	)
 
 	DescribeTable("Merge Upload and Local Schema", func(uploadSchema, schemaInWarehousePreUpload, expected warehouseutils.SchemaT) {
@@ -189,6 +189,29 @@ func (job *Payload) getDiscardsTable() string {
 	return warehouseutils.ToProviderCase(job.DestinationType, warehouseutils.DiscardsTable)
 }
 
+// shadowConflictingColumns moves the values of the event conflicting with the type of their column in the upload schema
+// to the shadow column of their type, as long as the upload schema contains it, see resolveColumnTypeConflicts.
+func (job *Payload) shadowConflictingColumns(event *BatchRouterEventT) {
+	tableName := event.Metadata.Table
+	for columnName, columnType := range event.Metadata.Columns {
+		dataTypeInSchema, ok := job.UploadSchema[tableName][columnName]
+		if !ok || isConvertibleDataType(model.SchemaType(dataTypeInSchema), model.SchemaType(columnType)) {
+			continue
+		}
+		shadowColumn := shadowColumnName(job.DestinationType, columnName, columnType)
+		if job.UploadSchema[tableName][shadowColumn] != columnType {
+			continue
+		}
+
+		if value, ok := event.Data[columnName]; ok {
+			event.Data[shadowColumn] = value
+			delete(event.Data, columnName)
+		}
+		event.Metadata.Columns[shadowColumn] = columnType
+		delete(event.Metadata.Columns, columnName)
+	}
+}
+
 func (jobRun *JobRunT) getLoadFilePath(tableName string) string {
 	job := jobRun.job
 	randomness := misc.FastUUID().String()
@@ -437,6 +460,7 @@ func processStagingFile(job Payload, workerIndex int) (loadFileUploadOutputs []l
 
 	lineBytesCounter := 0
 	var interfaceSliceSample []interface{}
+	shadowConflictingColumns := warehouseutils.GetColumnTypeConflictPolicy(job.DestinationConfig) == warehouseutils.ShadowColumnConflictPolicy
 	for {
 		ok := scanner.Scan()
 		if !ok {
@@ -456,6 +480,10 @@ func processStagingFile(job Payload, workerIndex int) (loadFileUploadOutputs []l
 			continue
 		}
 
+		if shadowConflictingColumns {
+			job.shadowConflictingColumns(&batchRouterEvent)
+		}
+
 		tableName := batchRouterEvent.Metadata.Table
 		columnData := batchRouterEvent.Data
 
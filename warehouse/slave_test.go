@@ -43,3 +43,36 @@ func TestPickupStagingFileBucket(t *testing.T) {
 		require.Equal(t, got, input.expected)
 	}
 }
+
+func TestShadowConflictingColumns(t *testing.T) {
+	job := &Payload{
+		DestinationType: "SNOWFLAKE",
+		UploadSchema: map[string]map[string]string{
+			"TRACKS": {
+				"ID":             "string",
+				"REVENUE":        "int",
+				"REVENUE_STRING": "string",
+				"PRICE":          "float",
+			},
+		},
+	}
+	event := BatchRouterEventT{
+		Metadata: MetadataT{
+			Table: "TRACKS",
+			Columns: map[string]string{
+				"ID":      "string",
+				"REVENUE": "string",
+				"PRICE":   "boolean",
+			},
+		},
+		Data: DataT{
+			"ID":      "1",
+			"REVENUE": "a lot",
+			"PRICE":   true,
+		},
+	}
+
+	job.shadowConflictingColumns(&event)
+	require.Equal(t, map[string]string{"ID": "string", "REVENUE_STRING": "string", "PRICE": "boolean"}, event.Metadata.Columns)
+	require.Equal(t, DataT{"ID": "1", "REVENUE_STRING": "a lot", "PRICE": true}, event.Data)
+}
@@ -0,0 +1,16 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCredits(t *testing.T) {
+	require.Equal(t, 1.0, computeCredits("X-Small", time.Hour))
+	require.Equal(t, 2.0, computeCredits("Medium", 30*time.Minute))
+	require.Equal(t, 32.0, computeCredits("2X-Large", time.Hour))
+	require.Equal(t, 16.0, computeCredits("XLARGE", time.Hour))
+	require.Zero(t, computeCredits("", time.Hour), "queries not running on a warehouse use no compute credits")
+}
@@ -45,6 +45,7 @@ type HandleT struct {
 	Warehouse      warehouseutils.Warehouse
 	Uploader       warehouseutils.UploaderI
 	ConnectTimeout time.Duration
+	loadCosts      warehouseutils.LoadCosts
 }
 
 // String constants for snowflake destination config
@@ -132,6 +133,22 @@ func ColumnsWithDataTypes(columns map[string]string, prefix string) string {
 	return strings.Join(arr, ",")
 }
 
+// clusterByClause returns the clustering keys of a table, led by the partition column truncated to the partition granularity,
+// since snowflake has no user defined partitions.
+func clusterByClause(partitioning warehouseutils.TablePartitioningT) string {
+	var keys []string
+	if partitioning.PartitionColumn != "" {
+		keys = append(keys, fmt.Sprintf(`DATE_TRUNC('%s', %q)`, strings.ToUpper(partitioning.PartitionGranularity), partitioning.PartitionColumn))
+	}
+	for _, column := range partitioning.ClusteringColumns {
+		keys = append(keys, fmt.Sprintf(`%q`, column))
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`CLUSTER BY (%s)`, strings.Join(keys, ", "))
+}
+
 // schemaIdentifier returns [DATABASE_NAME].[NAMESPACE] format to access the schema directly.
 func (sf *HandleT) schemaIdentifier() string {
 	return fmt.Sprintf(`"%s"`,
@@ -141,7 +158,7 @@ func (sf *HandleT) schemaIdentifier() string {
 
 func (sf *HandleT) createTable(tableName string, columns map[string]string) (err error) {
 	schemaIdentifier := sf.schemaIdentifier()
-	sqlStatement := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s."%s" ( %v )`, schemaIdentifier, tableName, ColumnsWithDataTypes(columns, ""))
+	sqlStatement := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s."%s" ( %v ) %s`, schemaIdentifier, tableName, ColumnsWithDataTypes(columns, ""), clusterByClause(warehouseutils.GetTablePartitioning(sf.Warehouse, tableName, columns)))
 	pkgLogger.Infof("Creating table in snowflake for SF:%s : %v", sf.Warehouse.Destination.ID, sqlStatement)
 	_, err = sf.Db.Exec(sqlStatement)
 	return
@@ -297,7 +314,7 @@ func (sf *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 		pkgLogger.Infof("SF: Running COPY command for table:%s at %s\n", tableName, sanitisedSQLStmt)
 	}
 
-	_, err = dbHandle.Exec(sqlStatement)
+	copyQueryID, err := execWithQueryID(dbHandle, sqlStatement)
 	if err != nil {
 		pkgLogger.Errorf("SF: Error running COPY command: %v\n", err)
 		return
@@ -352,16 +369,107 @@ func (sf *HandleT) loadTable(tableName string, tableSchemaInUpload warehouseutil
 	}
 
 	pkgLogger.Infof("SF: Dedup records for table:%s using staging table: %s\n", tableName, sqlStatement)
-	_, err = dbHandle.Exec(sqlStatement)
+	mergeQueryID, err := execWithQueryID(dbHandle, sqlStatement)
 	if err != nil {
 		pkgLogger.Errorf("SF: Error running MERGE for dedup: %v\n", err)
 		return
 	}
 
+	sf.loadCosts.Add(tableName, sf.loadCost(dbHandle, copyQueryID, mergeQueryID))
 	pkgLogger.Infof("SF: Complete load for table:%s\n", tableName)
 	return
 }
 
+// execWithQueryID executes the statement and returns the id snowflake assigned to the query.
+func execWithQueryID(dbHandle *sql.DB, sqlStatement string) (queryID string, err error) {
+	queryIDChan := make(chan string, 1)
+	_, err = dbHandle.ExecContext(snowflake.WithQueryIDChan(context.Background(), queryIDChan), sqlStatement)
+	select {
+	case queryID = <-queryIDChan:
+	default:
+	}
+	return
+}
+
+// warehouseSizeCredits are the credits billed per hour of compute by size of warehouse, with dashes removed from the sizes.
+var warehouseSizeCredits = map[string]float64{
+	"XSMALL":   1,
+	"SMALL":    2,
+	"MEDIUM":   4,
+	"LARGE":    8,
+	"XLARGE":   16,
+	"2XLARGE":  32,
+	"XXLARGE":  32,
+	"3XLARGE":  64,
+	"XXXLARGE": 64,
+	"4XLARGE":  128,
+	"5XLARGE":  256,
+	"6XLARGE":  512,
+}
+
+// computeCredits estimates the compute credits of a query from its execution time and the size of the warehouse running it.
+// It is an upper bound, since queries running concurrently on the warehouse share its credits.
+func computeCredits(warehouseSize string, executionTime time.Duration) float64 {
+	creditsPerHour := warehouseSizeCredits[strings.ReplaceAll(strings.ToUpper(warehouseSize), "-", "")]
+	return creditsPerHour * executionTime.Hours()
+}
+
+// loadCost returns the query ids of the load queries along with their execution time and the credits they used,
+// i.e. the compute credits estimated from the execution time and size of the warehouse, plus the cloud services credits.
+// Both are looked up from the query history on a best effort basis, since it may lag behind.
+func (*HandleT) loadCost(dbHandle *sql.DB, queryIDs ...string) (cost warehouseutils.LoadCostT) {
+	for _, queryID := range queryIDs {
+		if queryID != "" {
+			cost.QueryIDs = append(cost.QueryIDs, queryID)
+		}
+	}
+	if len(cost.QueryIDs) == 0 {
+		return
+	}
+
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  COALESCE(WAREHOUSE_SIZE, ''),
+		  COALESCE(EXECUTION_TIME, 0),
+		  COALESCE(CREDITS_USED_CLOUD_SERVICES, 0)
+		FROM
+		  TABLE(INFORMATION_SCHEMA.QUERY_HISTORY_BY_USER(RESULT_LIMIT => 1000))
+		WHERE
+		  QUERY_ID IN (%s);
+`,
+		misc.SingleQuoteLiteralJoin(cost.QueryIDs),
+	)
+	rows, err := dbHandle.Query(sqlStatement)
+	if err != nil {
+		pkgLogger.Warnf("SF: Error fetching credits used for queries %v: %v", cost.QueryIDs, err)
+		return
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			warehouseSize        string
+			executionTimeMs      int64
+			cloudServicesCredits float64
+		)
+		if err := rows.Scan(&warehouseSize, &executionTimeMs, &cloudServicesCredits); err != nil {
+			pkgLogger.Warnf("SF: Error scanning credits used for queries %v: %v", cost.QueryIDs, err)
+			return
+		}
+		cost.QueryTimeMs += executionTimeMs
+		cost.Credits += computeCredits(warehouseSize, time.Duration(executionTimeMs)*time.Millisecond) + cloudServicesCredits
+	}
+	if err := rows.Err(); err != nil {
+		pkgLogger.Warnf("SF: Error fetching credits used for queries %v: %v", cost.QueryIDs, err)
+	}
+	return
+}
+
+// LoadTableCost returns the query ids and credits of the last load of the table.
+func (sf *HandleT) LoadTableCost(tableName string) (warehouseutils.LoadCostT, bool) {
+	return sf.loadCosts.Pop(tableName)
+}
+
 func (sf *HandleT) LoadIdentityMergeRulesTable() (err error) {
 	pkgLogger.Infof("SF: Starting load for table:%s\n", identityMergeRulesTable)
 
@@ -530,7 +638,7 @@ func (sf *HandleT) loadUserTables() (errorMap map[string]error) {
 		strings.Join(identifyColNames, ","), // 7
 	)
 	pkgLogger.Infof("SF: Creating staging table for users: %s\n", sqlStatement)
-	_, err = resp.dbHandle.Exec(sqlStatement)
+	stagingQueryID, err := execWithQueryID(resp.dbHandle, sqlStatement)
 	if err != nil {
 		pkgLogger.Errorf("SF: Error creating temporary table for table:%s: %v\n", usersTable, err)
 		errorMap[usersTable] = err
@@ -560,12 +668,13 @@ func (sf *HandleT) loadUserTables() (errorMap map[string]error) {
 									WHEN NOT MATCHED THEN
 									INSERT (%[3]s) VALUES (%[6]s)`, usersTable, stagingTableName, columnNamesStr, primaryKey, columnsWithValues, stagingColumnValues, schemaIdentifier)
 	pkgLogger.Infof("SF: Dedup records for table:%s using staging table: %s\n", usersTable, sqlStatement)
-	_, err = resp.dbHandle.Exec(sqlStatement)
+	mergeQueryID, err := execWithQueryID(resp.dbHandle, sqlStatement)
 	if err != nil {
 		pkgLogger.Errorf("SF: Error running MERGE for dedup: %v\n", err)
 		errorMap[usersTable] = err
 		return errorMap
 	}
+	sf.loadCosts.Add(usersTable, sf.loadCost(resp.dbHandle, stagingQueryID, mergeQueryID))
 	pkgLogger.Infof("SF: Complete load for table:%s", usersTable)
 	return errorMap
 }
@@ -25,7 +25,9 @@ func getFirstStagedEventAt(stagingFileID int64) (time.Time, error) {
 	return firstEventAt, err
 }
 
-func getTotalEventsStaged(startFileID, endFileID int64) (total int64, err error) {
+// getTotalEventsStaged returns the events of the staging files in the range, which were restored for the backfill if any,
+// since the ids of the restored staging files interleave with the ones of regular uploads.
+func getTotalEventsStaged(startFileID, endFileID int64, backfillID string) (total int64, err error) {
 	sqlStatement := fmt.Sprintf(`
 		SELECT 
 		  sum(total_events) 
@@ -33,11 +35,13 @@ func getTotalEventsStaged(startFileID, endFileID int64) (total int64, err error)
 		  %[1]s 
 		WHERE 
 		  id >= %[2]v 
-		  AND id <= %[3]v;
+		  AND id <= %[3]v 
+		  AND COALESCE(metadata ->> 'backfill_id', '') = '%[4]s';
 `,
 		warehouseutils.WarehouseStagingFilesTable,
 		startFileID,
 		endFileID,
+		backfillID,
 	)
 
 	err = dbHandle.QueryRow(sqlStatement).Scan(&total)
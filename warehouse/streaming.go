@@ -0,0 +1,336 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/services/stats"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/jobs"
+	"github.com/rudderlabs/rudder-server/warehouse/manager"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var errStreamingNotEnabled = errors.New("streaming is not enabled for destination")
+
+// streamer loads batches of events streamed by the batch router straight into the warehouse,
+// bypassing staging files, load files and uploads for destinations with streaming enabled.
+type streamer struct {
+	stats      stats.Stats
+	newManager func(destType string) (manager.ManagerI, error)
+
+	connectionLocksMu sync.Mutex
+	connectionLocks   map[string]*sync.Mutex
+}
+
+func newStreamer() *streamer {
+	return &streamer{
+		stats:           stats.Default,
+		newManager:      manager.New,
+		connectionLocks: make(map[string]*sync.Mutex),
+	}
+}
+
+// connectionLock serializes the batches of a source and destination, since they may evolve the schema.
+func (s *streamer) connectionLock(warehouse warehouseutils.Warehouse) *sync.Mutex {
+	s.connectionLocksMu.Lock()
+	defer s.connectionLocksMu.Unlock()
+
+	key := warehouse.Source.ID + "_" + warehouse.Destination.ID
+	if _, ok := s.connectionLocks[key]; !ok {
+		s.connectionLocks[key] = &sync.Mutex{}
+	}
+	return s.connectionLocks[key]
+}
+
+// streamingBatchSchema returns the schema of the events in the batch along with their rows per table.
+func streamingBatchSchema(events []warehouseutils.StreamingEventT) (warehouseutils.SchemaT, map[string][]warehouseutils.StreamingEventT) {
+	batchSchema := warehouseutils.SchemaT{}
+	eventsByTable := make(map[string][]warehouseutils.StreamingEventT)
+	for _, event := range events {
+		tableName := event.Metadata.Table
+		if tableName == "" {
+			continue
+		}
+		if _, ok := batchSchema[tableName]; !ok {
+			batchSchema[tableName] = make(map[string]string)
+		}
+		for columnName, columnType := range event.Metadata.Columns {
+			// same as for staging files, string columns are altered to text if any event needs it
+			if existingType, ok := batchSchema[tableName][columnName]; !ok || (existingType == "string" && columnType == "text") {
+				batchSchema[tableName][columnName] = columnType
+			}
+		}
+		eventsByTable[tableName] = append(eventsByTable[tableName], event)
+	}
+	return batchSchema, eventsByTable
+}
+
+// streamingRows converts the values of the events to the types of the columns in the table schema.
+// Values which can't be converted are loaded as nulls and returned as rows of the discards table, like uploads do.
+func streamingRows(tableName string, tableSchema warehouseutils.TableSchemaT, events []warehouseutils.StreamingEventT, uuidTS time.Time) (rows, discards []map[string]interface{}) {
+	rows = make([]map[string]interface{}, 0, len(events))
+	for _, event := range events {
+		row := make(map[string]interface{}, len(event.Data))
+		for columnName, value := range event.Data {
+			columnType, ok := tableSchema[columnName]
+			if !ok {
+				continue
+			}
+			eventColumnType := event.Metadata.Columns[columnName]
+			// numbers are decoded as floats, so int values need no conversion for float columns
+			if columnType == string(model.FloatDataType) && (eventColumnType == string(model.IntDataType) || eventColumnType == string(model.BigIntDataType)) {
+				row[columnName] = value
+				continue
+			}
+			if value != nil && eventColumnType != "" && eventColumnType != columnType {
+				convertedValue, err := HandleSchemaChange(model.SchemaType(columnType), model.SchemaType(eventColumnType), value)
+				if err != nil {
+					rowID, hasID := event.Data["id"]
+					receivedAt, hasReceivedAt := event.Data["received_at"]
+					if hasID && hasReceivedAt {
+						discards = append(discards, map[string]interface{}{
+							"column_name":  columnName,
+							"column_value": fmt.Sprintf("%v", value),
+							"received_at":  receivedAt,
+							"row_id":       rowID,
+							"table_name":   tableName,
+							"uuid_ts":      uuidTS.Format(misc.RFC3339Milli),
+						})
+					}
+					continue
+				}
+				value = convertedValue
+			}
+			row[columnName] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, discards
+}
+
+// mergeStreamingUsers merges the rows of the same user in the batch into one, in the order of the events,
+// with the non null traits of the later rows overriding the ones of the earlier rows.
+func mergeStreamingUsers(rows []map[string]interface{}) []map[string]interface{} {
+	merged := make([]map[string]interface{}, 0, len(rows))
+	userRows := make(map[interface{}]map[string]interface{})
+	for _, row := range rows {
+		userID, ok := row["id"]
+		if !ok || userID == nil {
+			merged = append(merged, row)
+			continue
+		}
+		userRow, ok := userRows[userID]
+		if !ok {
+			userRows[userID] = row
+			merged = append(merged, row)
+			continue
+		}
+		for columnName, value := range row {
+			if value != nil {
+				userRow[columnName] = value
+			}
+		}
+	}
+	return merged
+}
+
+// updateSchema evolves the schema of the warehouse for the batch schema the same way uploads do,
+// returning the updated schema.
+func (*streamer) updateSchema(job *UploadJobT, currentSchema, batchSchema warehouseutils.SchemaT) (warehouseutils.SchemaT, error) {
+	mergedSchema := mergeSchema(currentSchema, []warehouseutils.SchemaT{batchSchema}, warehouseutils.SchemaT{}, job.warehouse.Type)
+
+	tableNames := make([]string, 0, len(mergedSchema))
+	for tableName := range mergedSchema {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	var schemaChanged bool
+	updatedSchema := warehouseutils.SchemaT{}
+	for tableName, columnMap := range currentSchema {
+		updatedSchema[tableName] = columnMap
+	}
+	for _, tableName := range tableNames {
+		tableSchemaDiff := getTableSchemaDiff(tableName, currentSchema, mergedSchema)
+		if !tableSchemaDiff.Exists {
+			continue
+		}
+		if err := job.updateTableSchema(tableName, tableSchemaDiff); err != nil {
+			return nil, fmt.Errorf("updating schema of table %s: %w", tableName, err)
+		}
+		updatedSchema[tableName] = tableSchemaDiff.UpdatedSchema
+		schemaChanged = true
+	}
+	if schemaChanged {
+		if err := job.schemaHandle.updateLocalSchema(updatedSchema); err != nil {
+			return nil, fmt.Errorf("updating local schema: %w", err)
+		}
+	}
+	return updatedSchema, nil
+}
+
+// loadRows loads the rows into the table, keeping only the columns of the batch schema of the table
+func (*streamer) loadRows(job *UploadJobT, streamLoader manager.StreamLoader, tableName string, batchTableSchema warehouseutils.TableSchemaT, updatedSchema warehouseutils.SchemaT, rows []map[string]interface{}) error {
+	tableSchema := make(warehouseutils.TableSchemaT, len(batchTableSchema))
+	for columnName := range batchTableSchema {
+		tableSchema[columnName] = updatedSchema[tableName][columnName]
+	}
+	if err := streamLoader.LoadStreamingBatch(tableName, tableSchema, rows); err != nil {
+		return fmt.Errorf("loading streaming batch into table %s: %w", tableName, err)
+	}
+	job.counterStat("streaming_rows_loaded", tag{name: "tableName", value: tableName}).Count(len(rows))
+	return nil
+}
+
+// load evolves the schema of the warehouse for the events in the batch and loads them into their tables,
+// with a transaction per table. Values which can't be converted to the types of their columns are loaded
+// into the discards table afterwards.
+func (s *streamer) load(warehouse warehouseutils.Warehouse, events []warehouseutils.StreamingEventT) error {
+	if !warehouseutils.IsStreamingEnabled(warehouse.Type, warehouse.Destination.Config) {
+		return errStreamingNotEnabled
+	}
+
+	lock := s.connectionLock(warehouse)
+	lock.Lock()
+	defer lock.Unlock()
+
+	whManager, err := s.newManager(warehouse.Type)
+	if err != nil {
+		return err
+	}
+	streamLoader, ok := whManager.(manager.StreamLoader)
+	if !ok {
+		return errStreamingNotEnabled
+	}
+	if err := whManager.Setup(warehouse, &jobs.WhAsyncJob{}); err != nil {
+		return fmt.Errorf("setting up warehouse manager: %w", err)
+	}
+	defer whManager.Cleanup()
+
+	sh := SchemaHandleT{warehouse: warehouse}
+	localSchema := sh.getLocalSchema()
+	if len(localSchema) == 0 {
+		if err := whManager.CreateSchema(); err != nil {
+			return fmt.Errorf("creating schema: %w", err)
+		}
+		if localSchema, sh.unrecognizedSchemaInWarehouse, err = sh.fetchSchemaFromWarehouse(whManager); err != nil {
+			return fmt.Errorf("fetching schema from warehouse: %w", err)
+		}
+	}
+
+	job := &UploadJobT{
+		warehouse:    warehouse,
+		whManager:    whManager,
+		schemaHandle: &sh,
+		stats:        s.stats,
+		upload: &Upload{
+			WorkspaceID:     warehouse.WorkspaceID,
+			SourceID:        warehouse.Source.ID,
+			DestinationID:   warehouse.Destination.ID,
+			DestinationType: warehouse.Type,
+		},
+	}
+
+	batchSchema, eventsByTable := streamingBatchSchema(events)
+	updatedSchema, err := s.updateSchema(job, localSchema, batchSchema)
+	if err != nil {
+		return err
+	}
+
+	tableNames := make([]string, 0, len(batchSchema))
+	for tableName := range batchSchema {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+
+	usersTable := warehouseutils.ToProviderCase(warehouse.Type, warehouseutils.UsersTable)
+	uuidTS := timeutil.Now()
+	var discards []map[string]interface{}
+	for _, tableName := range tableNames {
+		rows, tableDiscards := streamingRows(tableName, updatedSchema[tableName], eventsByTable[tableName], uuidTS)
+		if tableName == usersTable {
+			rows = mergeStreamingUsers(rows)
+		}
+		if err := s.loadRows(job, streamLoader, tableName, batchSchema[tableName], updatedSchema, rows); err != nil {
+			return err
+		}
+		if len(tableDiscards) > 0 {
+			job.counterStat("streaming_values_discarded", tag{name: "tableName", value: tableName}).Count(len(tableDiscards))
+		}
+		discards = append(discards, tableDiscards...)
+	}
+	if len(discards) == 0 {
+		return nil
+	}
+
+	discardsTable := warehouseutils.ToProviderCase(warehouse.Type, warehouseutils.DiscardsTable)
+	discardsSchema := warehouseutils.SchemaT{discardsTable: warehouseutils.DiscardsSchema}
+	if updatedSchema, err = s.updateSchema(job, updatedSchema, discardsSchema); err != nil {
+		return err
+	}
+	return s.loadRows(job, streamLoader, discardsTable, discardsSchema[discardsTable], updatedSchema, discards)
+}
+
+// streamingHandler loads a batch of events streamed by the batch router into the warehouse
+func (s *streamer) streamingHandler(w http.ResponseWriter, r *http.Request) {
+	pkgLogger.LogRequest(r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error reading body: %v", err)
+		http.Error(w, "can't read body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	var batch warehouseutils.StreamingBatch
+	if err := json.Unmarshal(body, &batch); err != nil {
+		pkgLogger.Errorf("[WH]: Error unmarshalling body: %v", err)
+		http.Error(w, "can't unmarshall body", http.StatusBadRequest)
+		return
+	}
+
+	connectionsMapLock.RLock()
+	warehouse, err := getDestinationFromConnectionMap(batch.BatchDestination.Destination.ID, batch.BatchDestination.Source.ID)
+	connectionsMapLock.RUnlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	loadTimeStat := s.stats.NewTaggedStat("warehouse_streaming_batch_load_time", stats.TimerType, stats.Tags{
+		"module":      moduleName,
+		"destType":    warehouse.Type,
+		"workspaceId": warehouse.WorkspaceID,
+		"destID":      warehouse.Destination.ID,
+		"sourceID":    warehouse.Source.ID,
+	})
+	loadTimeStat.Start()
+	err = s.load(warehouse, batch.Events)
+	loadTimeStat.End()
+
+	if errors.Is(err, errStreamingNotEnabled) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		pkgLogger.Errorf("[WH]: Error loading streaming batch for %s: %v", warehouse.Identifier, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
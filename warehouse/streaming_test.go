@@ -0,0 +1,95 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func streamingEvents(t *testing.T, payload string) []warehouseutils.StreamingEventT {
+	t.Helper()
+
+	var events []warehouseutils.StreamingEventT
+	require.NoError(t, json.Unmarshal([]byte(payload), &events))
+	return events
+}
+
+func TestStreamingBatchSchema(t *testing.T) {
+	events := streamingEvents(t, `[
+		{"metadata": {"table": "tracks", "columns": {"id": "string", "event": "string"}}, "data": {"id": "1", "event": "a"}},
+		{"metadata": {"table": "tracks", "columns": {"id": "string", "event": "text", "revenue": "float"}}, "data": {"id": "2", "event": "b", "revenue": 1.5}},
+		{"metadata": {"table": "pages", "columns": {"id": "string"}}, "data": {"id": "3"}},
+		{"metadata": {"columns": {"id": "string"}}, "data": {"id": "4"}}
+	]`)
+
+	batchSchema, eventsByTable := streamingBatchSchema(events)
+	require.Equal(t, warehouseutils.SchemaT{
+		"tracks": {"id": "string", "event": "text", "revenue": "float"},
+		"pages":  {"id": "string"},
+	}, batchSchema)
+	require.Len(t, eventsByTable["tracks"], 2)
+	require.Len(t, eventsByTable["pages"], 1)
+}
+
+func TestStreamingRows(t *testing.T) {
+	uuidTS := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := streamingEvents(t, `[
+		{"metadata": {"table": "tracks", "columns": {"id": "string", "revenue": "int", "count": "float", "label": "int", "extra": "string"}}, "data": {"id": "1", "revenue": 10, "count": 2.5, "label": 3, "extra": "x"}}
+	]`)
+	tableSchema := warehouseutils.TableSchemaT{"id": "string", "revenue": "float", "count": "int", "label": "string"}
+
+	rows, discards := streamingRows("tracks", tableSchema, events, uuidTS)
+	require.Equal(t, []map[string]interface{}{
+		{"id": "1", "revenue": float64(10), "count": 2, "label": "3"},
+	}, rows)
+	require.Empty(t, discards)
+
+	events = streamingEvents(t, `[
+		{"metadata": {"table": "tracks", "columns": {"id": "string", "received_at": "datetime", "revenue": "string"}}, "data": {"id": "1", "received_at": "2022-01-01T00:00:00.000Z", "revenue": "ten"}},
+		{"metadata": {"table": "tracks", "columns": {"id": "string", "revenue": "string"}}, "data": {"id": "2", "revenue": "ten"}}
+	]`)
+	tableSchema["received_at"] = "datetime"
+	rows, discards = streamingRows("tracks", tableSchema, events, uuidTS)
+	require.Equal(t, []map[string]interface{}{
+		{"id": "1", "received_at": "2022-01-01T00:00:00.000Z"},
+		{"id": "2"},
+	}, rows)
+	require.Equal(t, []map[string]interface{}{
+		{
+			"column_name":  "revenue",
+			"column_value": "ten",
+			"received_at":  "2022-01-01T00:00:00.000Z",
+			"row_id":       "1",
+			"table_name":   "tracks",
+			"uuid_ts":      "2022-01-01T00:00:00.000Z",
+		},
+	}, discards, "values of rows without id or received_at can't be discarded")
+}
+
+func TestMergeStreamingUsers(t *testing.T) {
+	rows := mergeStreamingUsers([]map[string]interface{}{
+		{"id": "u1", "email": "a@example.com", "name": "a"},
+		{"id": "u2", "email": "b@example.com"},
+		{"id": "u1", "email": "c@example.com", "name": nil, "plan": "pro"},
+		{"email": "d@example.com"},
+	})
+	require.Equal(t, []map[string]interface{}{
+		{"id": "u1", "email": "c@example.com", "name": "a", "plan": "pro"},
+		{"id": "u2", "email": "b@example.com"},
+		{"email": "d@example.com"},
+	}, rows)
+}
+
+func TestStreamingHandlerMethod(t *testing.T) {
+	Init4()
+	req := httptest.NewRequest(http.MethodGet, "/v1/warehouse/stream", http.NoBody)
+	resp := httptest.NewRecorder()
+	newStreamer().streamingHandler(resp, req)
+	require.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
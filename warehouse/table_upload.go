@@ -2,8 +2,10 @@ package warehouse
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/utils/timeutil"
@@ -163,6 +165,96 @@ func (tableUpload *TableUploadT) getTotalEvents() (int64, error) {
 	return total.Int64, err
 }
 
+func (tableUpload *TableUploadT) setRowCounts(rowsBeforeLoad, rowsAfterLoad, rowsMismatch int64) (err error) {
+	sqlStatement := fmt.Sprintf(`
+		UPDATE 
+		  %s 
+		SET 
+		  rows_before_load = $1, 
+		  rows_after_load = $2, 
+		  rows_mismatch = $3, 
+		  updated_at = $4 
+		WHERE 
+		  wh_upload_id = $5 
+		  AND table_name = $6;
+`,
+		warehouseutils.WarehouseTableUploadsTable,
+	)
+	_, err = dbHandle.Exec(
+		sqlStatement,
+		rowsBeforeLoad,
+		rowsAfterLoad,
+		rowsMismatch,
+		timeutil.Now(),
+		tableUpload.uploadID,
+		tableUpload.tableName,
+	)
+	return err
+}
+
+// setLoadCost stores the duration and warehouse cost of loading the table, along with the bytes of its load files in the upload.
+func (tableUpload *TableUploadT) setLoadCost(job *UploadJobT, loadDuration time.Duration, cost warehouseutils.LoadCostT) (err error) {
+	loadCost, err := json.Marshal(cost)
+	if err != nil {
+		return fmt.Errorf("marshalling load cost: %w", err)
+	}
+
+	subQuery := fmt.Sprintf(`
+		WITH row_numbered_load_files as (
+		  SELECT 
+			metadata, 
+			row_number() OVER (
+			  PARTITION BY staging_file_id, 
+			  table_name 
+			  ORDER BY 
+				id DESC
+			) AS row_number 
+		  FROM 
+			%[1]s 
+		  WHERE 
+			staging_file_id IN (%[2]v) 
+			AND table_name = '%[3]s'
+		) 
+		SELECT 
+		  COALESCE(sum((metadata ->> 'content_length')::bigint), 0) as total 
+		FROM 
+		  row_numbered_load_files 
+		WHERE 
+		  row_number = 1
+`,
+		warehouseutils.WarehouseLoadFilesTable,
+		misc.IntArrayToString(job.stagingFileIDs, ","),
+		tableUpload.tableName,
+	)
+
+	sqlStatement := fmt.Sprintf(`
+		UPDATE 
+		  %[1]s 
+		SET 
+		  load_duration_ms = $1, 
+		  load_cost = $2, 
+		  bytes_loaded = subquery.total, 
+		  updated_at = $3 
+		FROM 
+		  (%[2]s) AS subquery 
+		WHERE 
+		  wh_upload_id = $4 
+		  AND table_name = $5;
+`,
+		warehouseutils.WarehouseTableUploadsTable,
+		subQuery,
+	)
+	_, err = dbHandle.Exec(
+		sqlStatement,
+		loadDuration.Milliseconds(),
+		loadCost,
+		timeutil.Now(),
+		tableUpload.uploadID,
+		tableUpload.tableName,
+	)
+	return err
+}
+
 func (tableUpload *TableUploadT) setError(status string, statusError error) (err error) {
 	tableName := tableUpload.tableName
 	uploadID := tableUpload.uploadID
@@ -173,7 +265,10 @@ func (tableUpload *TableUploadT) setError(status string, statusError error) (err
 		SET 
 		  status = $1, 
 		  updated_at = $2, 
-		  error = $3 
+		  error = $3, 
+		  attempts = attempts + 1, 
+		  first_failed_at = COALESCE(first_failed_at, $2), 
+		  next_retry_time = NULL 
 		WHERE 
 		  wh_upload_id = $4 
 		  AND table_name = $5;
@@ -192,6 +287,80 @@ func (tableUpload *TableUploadT) setError(status string, statusError error) (err
 	return err
 }
 
+// setNextRetryTime schedules the retry of a failed table upload of a partially exported upload
+func (tableUpload *TableUploadT) setNextRetryTime(nextRetryTime time.Time) (err error) {
+	sqlStatement := fmt.Sprintf(`
+		UPDATE
+		  %s
+		SET
+		  next_retry_time = $1,
+		  updated_at = $2
+		WHERE
+		  wh_upload_id = $3
+		  AND table_name = $4;
+`,
+		warehouseutils.WarehouseTableUploadsTable,
+	)
+	_, err = dbHandle.Exec(
+		sqlStatement,
+		nextRetryTime,
+		timeutil.Now(),
+		tableUpload.uploadID,
+		tableUpload.tableName,
+	)
+	return err
+}
+
+// tableUploadRetryT is the retry state of a table upload
+type tableUploadRetryT struct {
+	tableName     string
+	status        string
+	attempts      int
+	firstFailedAt time.Time
+	nextRetryTime time.Time
+}
+
+// getTableUploadRetries returns the retry state of the table uploads of an upload
+func getTableUploadRetries(uploadID int64) ([]tableUploadRetryT, error) {
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  table_name,
+		  status,
+		  attempts,
+		  first_failed_at,
+		  next_retry_time
+		FROM
+		  %s
+		WHERE
+		  wh_upload_id = $1;
+`,
+		warehouseutils.WarehouseTableUploadsTable,
+	)
+	rows, err := dbHandle.Query(sqlStatement, uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("querying table upload retries: %w", err)
+	}
+	defer rows.Close()
+
+	var retries []tableUploadRetryT
+	for rows.Next() {
+		var (
+			retry                        tableUploadRetryT
+			firstFailedAt, nextRetryTime sql.NullTime
+		)
+		if err := rows.Scan(&retry.tableName, &retry.status, &retry.attempts, &firstFailedAt, &nextRetryTime); err != nil {
+			return nil, fmt.Errorf("scanning table upload retries: %w", err)
+		}
+		retry.firstFailedAt = firstFailedAt.Time
+		retry.nextRetryTime = nextRetryTime.Time
+		retries = append(retries, retry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating table upload retries: %w", err)
+	}
+	return retries, nil
+}
+
 func (tableUpload *TableUploadT) updateTableEventsCount(job *UploadJobT) (err error) {
 	subQuery := fmt.Sprintf(`
 		WITH row_numbered_load_files as (
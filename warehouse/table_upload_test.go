@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -103,6 +104,35 @@ var _ = Describe("TableUpload", func() {
 				Expect(err).To(BeNil())
 			})
 
+			It("Setting row counts", func() {
+				err := tu.setRowCounts(10, 13, 1)
+				Expect(err).To(BeNil())
+
+				var rowsLoaded, rowsMismatch int64
+				err = pgResource.DB.QueryRow(`SELECT rows_after_load - rows_before_load, rows_mismatch FROM wh_table_uploads WHERE wh_upload_id = $1 AND table_name = $2`, uploadID, tableName).Scan(&rowsLoaded, &rowsMismatch)
+				Expect(err).To(BeNil())
+				Expect(rowsLoaded).To(BeEquivalentTo(3))
+				Expect(rowsMismatch).To(BeEquivalentTo(1))
+			})
+
+			It("Setting load cost", func() {
+				err := tu.setLoadCost(&UploadJobT{
+					stagingFileIDs: stagingFileIDs,
+					upload: &Upload{
+						ID: uploadID,
+					},
+				}, 1500*time.Millisecond, warehouseutils.LoadCostT{QueryIDs: []string{"query-1"}, Credits: 0.5})
+				Expect(err).To(BeNil())
+
+				var loadDurationMs, bytesLoaded int64
+				var credits float64
+				err = pgResource.DB.QueryRow(`SELECT load_duration_ms, bytes_loaded, (load_cost ->> 'credits')::double precision FROM wh_table_uploads WHERE wh_upload_id = $1 AND table_name = $2`, uploadID, tableName).Scan(&loadDurationMs, &bytesLoaded, &credits)
+				Expect(err).To(BeNil())
+				Expect(loadDurationMs).To(BeEquivalentTo(1500))
+				Expect(bytesLoaded).To(BeEquivalentTo(0))
+				Expect(credits).To(BeEquivalentTo(0.5))
+			})
+
 			Describe("Getting number of events", func() {
 				var job *UploadJobT
 
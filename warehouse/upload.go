@@ -53,6 +53,7 @@ const (
 	UserTableUploadExportingFailed     = "exporting_user_tables_failed"
 	IdentityTableUploadExportingFailed = "exporting_identities_failed"
 	TableUploadExported                = "exported_data"
+	TableUploadAborted                 = "aborted"
 )
 
 const (
@@ -103,6 +104,8 @@ type Upload struct {
 	SourceJobID     string
 	SourceJobRunID  string
 	LoadFileType    string
+	// BackfillID is set for uploads loading staging files restored from the archive
+	BackfillID string
 }
 
 type tableNameT string
@@ -139,8 +142,12 @@ const (
 	MergedSchemaField          = "mergedschema"
 	UploadLastExecAtField      = "last_exec_at"
 	UploadInProgress           = "in_progress"
+	UploadMetadataField        = "metadata"
 )
 
+// ColumnTypeConflictsKey is the key of the upload metadata under which the resolved column type conflicts are recorded.
+const ColumnTypeConflictsKey = "column_type_conflicts"
+
 var (
 	alwaysMarkExported                               = []string{warehouseutils.DiscardsTable}
 	warehousesToAlwaysRegenerateAllLoadFilesOnResume = []string{warehouseutils.SNOWFLAKE, warehouseutils.BQ}
@@ -231,7 +238,46 @@ func (job *UploadJobT) generateUploadSchema(schemaHandle *SchemaHandleT) error {
 	}
 	// set upload schema
 	err := job.setUploadSchema(schemaHandle.uploadSchema)
-	return err
+	if err != nil {
+		return err
+	}
+	return job.recordColumnTypeConflicts(schemaHandle.columnTypeConflicts)
+}
+
+// recordColumnTypeConflicts stores the resolution of column type conflicts in the upload's metadata, keyed by column.
+// Conflicts don't fail the upload and are resolved the same way on every attempt, hence they are only recorded once.
+func (job *UploadJobT) recordColumnTypeConflicts(conflicts []columnTypeConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(job.upload.Metadata, &metadata); err != nil || metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	if _, ok := metadata[ColumnTypeConflictsKey]; ok {
+		return nil
+	}
+
+	resolutions := make(map[string]columnTypeConflict)
+	var messages []string
+	for _, conflict := range conflicts {
+		job.counterStat("column_type_conflicts", tag{name: "tableName", value: conflict.TableName}, tag{name: "resolution", value: conflict.Resolution}).Increment()
+		resolutions[fmt.Sprintf("%s.%s", conflict.TableName, conflict.ColumnName)] = conflict
+		messages = append(messages, conflict.String())
+	}
+	pkgLogger.Infof("[WH]: Resolved column type conflicts for upload %d of %s: %s", job.upload.ID, job.warehouse.Identifier, strings.Join(messages, "; "))
+
+	metadata[ColumnTypeConflictsKey] = resolutions
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("marshalling upload metadata: %w", err)
+	}
+	if err := job.setUploadColumns(UploadColumnsOpts{Fields: []UploadColumnT{{Column: UploadMetadataField, Value: metadataJSON}}}); err != nil {
+		return fmt.Errorf("recording column type conflicts: %w", err)
+	}
+	job.upload.Metadata = metadataJSON
+	return nil
 }
 
 func (job *UploadJobT) initTableUploads() error {
@@ -288,13 +334,15 @@ func (job *UploadJobT) getTotalRowsInStagingFiles() int64 {
 		  ST.id >= %[2]v
 		  AND ST.id <= %[3]v
 		  AND ST.source_id = '%[4]s'
-		  AND ST.destination_id = '%[5]s';
+		  AND ST.destination_id = '%[5]s'
+		  AND COALESCE(ST.metadata ->> 'backfill_id', '') = '%[6]s';
 	`,
 		warehouseutils.WarehouseStagingFilesTable,
 		job.upload.StartStagingFileID,
 		job.upload.EndStagingFileID,
 		job.warehouse.Source.ID,
 		job.warehouse.Destination.ID,
+		job.upload.BackfillID,
 	)
 	err := dbHandle.QueryRow(sqlStatement).Scan(&total)
 	if err != nil {
@@ -537,16 +585,28 @@ func (job *UploadJobT) run() (err error) {
 
 			rruntime.GoForWarehouse(func() {
 				specialTables := append(userTables, identityTables...)
-				err = job.exportRegularTables(specialTables, loadFilesTableMap)
-				if err != nil {
+				// errors of regular tables are kept per table, so that they can be handled on a per table basis
+				regularTableErrors := job.exportRegularTables(specialTables, loadFilesTableMap)
+				if len(regularTableErrors) > 0 {
 					loadErrorLock.Lock()
-					loadErrors = append(loadErrors, err)
+					loadErrors = append(loadErrors, regularTableErrors...)
 					loadErrorLock.Unlock()
 				}
 				wg.Done()
 			})
 
 			wg.Wait()
+			if enablePartialExports {
+				var partiallyExported bool
+				loadErrors, partiallyExported, err = job.exportPartially(loadErrors, append(userTables, identityTables...))
+				if err != nil {
+					break
+				}
+				if partiallyExported {
+					newStatus = model.PartiallyExportedData
+					break
+				}
+			}
 			if len(loadErrors) > 0 {
 				err = misc.ConcatErrors(loadErrors)
 				break
@@ -602,14 +662,14 @@ func (job *UploadJobT) run() (err error) {
 		// record metric for time taken by the current state
 		job.timerStat(nextUploadState.inProgress).SendTiming(time.Since(stateStartTime))
 
-		if newStatus == model.ExportedData {
+		if newStatus == model.ExportedData || newStatus == model.PartiallyExportedData {
 			break
 		}
 
 		nextUploadState = getNextUploadState(newStatus)
 	}
 
-	if newStatus != model.ExportedData {
+	if newStatus != model.ExportedData && newStatus != model.PartiallyExportedData {
 		return fmt.Errorf("upload Job failed: %w", err)
 	}
 
@@ -663,7 +723,9 @@ func (job *UploadJobT) exportIdentities() (err error) {
 	return
 }
 
-func (job *UploadJobT) exportRegularTables(specialTables []string, loadFilesTableMap map[tableNameT]bool) (err error) {
+// exportRegularTables loads all tables except the special ones, returning the errors of the tables failing to load.
+// The errors are either a TableSkipError or a TableLoadError, identifying the table they belong to.
+func (job *UploadJobT) exportRegularTables(specialTables []string, loadFilesTableMap map[tableNameT]bool) []error {
 	//[]string{job.identifiesTableName(), job.usersTableName(), job.identityMergeRulesTableName(), job.identityMappingsTableName()}
 	// Export all other tables
 	loadTimeStat := job.timerStat("other_tables_load_time")
@@ -672,13 +734,7 @@ func (job *UploadJobT) exportRegularTables(specialTables []string, loadFilesTabl
 
 	loadErrors := job.loadAllTablesExcept(specialTables, loadFilesTableMap)
 	job.hasAllTablesSkipped = areAllTableSkipErrors(loadErrors)
-
-	if len(loadErrors) > 0 {
-		err = misc.ConcatErrors(loadErrors)
-		return
-	}
-
-	return
+	return loadErrors
 }
 
 func areAllTableSkipErrors(loadErrors []error) bool {
@@ -700,12 +756,14 @@ type TableUploadStatusT struct {
 	tableName     string
 	status        string
 	error         string
+	nextRetryTime sql.NullTime
 }
 
 // TableUploadStatusInfoT captures the status and error for [uploadID][tableName]
 type TableUploadStatusInfoT struct {
-	status string
-	error  string
+	status        string
+	error         string
+	nextRetryTime sql.NullTime
 }
 
 // TableUploadIDInfoT captures the uploadID and error for [uploadID][tableName]
@@ -725,7 +783,8 @@ func (job *UploadJobT) fetchPendingUploadTableStatus() []*TableUploadStatusT {
 		  UT.namespace,
 		  TU.table_name,
 		  TU.status,
-		  TU.error
+		  TU.error,
+		  TU.next_retry_time
 		FROM
 		  %[1]s UT
 		  INNER JOIN %[2]s TU ON UT.id = TU.wh_upload_id
@@ -771,6 +830,7 @@ func (job *UploadJobT) fetchPendingUploadTableStatus() []*TableUploadStatusT {
 			&tableUploadStatus.tableName,
 			&tableUploadStatus.status,
 			&tableUploadStatus.error,
+			&tableUploadStatus.nextRetryTime,
 		)
 		if err != nil {
 			panic(err)
@@ -788,8 +848,9 @@ func getTableUploadStatusMap(tableUploadStatuses []*TableUploadStatusT) map[int6
 			tableUploadStatus[tUploadStatus.uploadID] = make(map[string]*TableUploadStatusInfoT)
 		}
 		tableUploadStatus[tUploadStatus.uploadID][tUploadStatus.tableName] = &TableUploadStatusInfoT{
-			status: tUploadStatus.status,
-			error:  tUploadStatus.error,
+			status:        tUploadStatus.status,
+			error:         tUploadStatus.error,
+			nextRetryTime: tUploadStatus.nextRetryTime,
 		}
 	}
 	return tableUploadStatus
@@ -814,6 +875,12 @@ func (job *UploadJobT) getTablesToSkip() (map[string]*TableUploadIDInfoT, map[st
 			if uploadID == job.upload.ID && status == TableUploadExported { // Current upload and table upload succeeded
 				currentlySucceededTableMap[tableName] = true
 			}
+			if uploadID == job.upload.ID && status == TableUploadAborted { // Current upload and table upload aborted after its retries
+				currentlySucceededTableMap[tableName] = true
+			}
+			if uploadID == job.upload.ID && tableStatus.nextRetryTime.Valid && tableStatus.nextRetryTime.Time.After(timeutil.Now()) { // Current upload and table upload not due for retry yet
+				currentlySucceededTableMap[tableName] = true
+			}
 		}
 	}
 	return previouslyFailedTableMap, currentlySucceededTableMap
@@ -857,6 +924,19 @@ func (job *UploadJobT) updateTableSchema(tName string, tableSchemaDiff warehouse
 			break
 		}
 	}
+	if err != nil {
+		return err
+	}
+
+	for columnName, columnType := range tableSchemaDiff.ColumnsToBeWidened {
+		err = job.whManager.AlterColumn(tName, columnName, columnType)
+		if err != nil {
+			err = fmt.Errorf("widening column %s in table %s.%s to %s: %w", columnName, job.warehouse.Namespace, tName, columnType, err)
+			pkgLogger.Errorf("[WH]: %v", err)
+			break
+		}
+		job.counterStat("columns_widened", tag{name: "tableName", value: tName}).Increment()
+	}
 
 	return err
 }
@@ -907,6 +987,20 @@ func (tse *TableSkipError) Error() string {
 	return fmt.Sprintf("Skipping %s table because it previously failed to load in an earlier job: %d with error: %s", tse.tableName, tse.previousJobID, tse.previousJobError)
 }
 
+// TableLoadError is a custom error type to capture the table a load error belongs to
+type TableLoadError struct {
+	tableName string
+	err       error
+}
+
+func (tle *TableLoadError) Error() string {
+	return tle.err.Error()
+}
+
+func (tle *TableLoadError) Unwrap() error {
+	return tle.err
+}
+
 func (job *UploadJobT) loadAllTablesExcept(skipLoadForTables []string, loadFilesTableMap map[tableNameT]bool) []error {
 	uploadSchema := job.upload.UploadSchema
 	var parallelLoads int
@@ -966,7 +1060,7 @@ func (job *UploadJobT) loadAllTablesExcept(skipLoadForTables []string, loadFiles
 
 			if err != nil {
 				loadErrorLock.Lock()
-				loadErrors = append(loadErrors, err)
+				loadErrors = append(loadErrors, &TableLoadError{tableName: tName, err: err})
 				loadErrorLock.Unlock()
 			}
 			wg.Done()
@@ -1034,48 +1128,27 @@ func (job *UploadJobT) loadTable(tName string) (alteredSchema bool, err error) {
 	pkgLogger.Infof(`[WH]: Starting load for table %s in namespace %s of destination %s:%s`, tName, job.warehouse.Namespace, job.warehouse.Type, job.warehouse.Destination.ID)
 	tableUpload.setStatus(TableUploadExecuting)
 
-	generateTableLoadCountVerificationsMetrics := config.GetBool("Warehouse.generateTableLoadCountMetrics", true)
+	generateTableLoadCountVerificationsMetrics := job.shouldGenerateTableLoadCountMetrics()
 
-	disableGenerateMetricsWorkspaceIDs := config.GetStringSlice("Warehouse.disableGenerateTableLoadCountMetricsWorkspaceIDs", nil)
-	if slices.Contains(disableGenerateMetricsWorkspaceIDs, job.upload.WorkspaceID) {
-		generateTableLoadCountVerificationsMetrics = false
-	}
-
-	var totalBeforeLoad, totalAfterLoad int64
+	var (
+		totalBeforeLoad   int64
+		countedBeforeLoad bool
+	)
 	if generateTableLoadCountVerificationsMetrics {
-		var errTotalCount error
-		totalBeforeLoad, errTotalCount = job.getTotalCount(tName)
-		if errTotalCount != nil {
-			pkgLogger.Errorf(`Error getting total count in table:%s before load: %v`, tName, errTotalCount)
-		}
+		totalBeforeLoad, countedBeforeLoad = job.getTotalCountBeforeLoad(tName)
 	}
 
+	loadStartTime := time.Now()
 	err = job.whManager.LoadTable(tName)
 	if err != nil {
 		tableUpload.setError(TableUploadExportingFailed, err)
 		return
 	}
+	job.recordLoadCost(tableUpload, time.Since(loadStartTime))
 
-	func() {
-		if !generateTableLoadCountVerificationsMetrics {
-			return
-		}
-		var errTotalCount error
-		totalAfterLoad, errTotalCount = job.getTotalCount(tName)
-		if errTotalCount != nil {
-			pkgLogger.Errorf(`Error getting total count in table:%s after load: %v`, tName, errTotalCount)
-			return
-		}
-		eventsInTableUpload, errEventCount := tableUpload.getTotalEvents()
-		if errEventCount != nil {
-			return
-		}
-
-		// TODO : Perform the comparison here in the codebase
-		job.guageStat(`pre_load_table_rows`, tag{name: "tableName", value: strings.ToLower(tName)}).Gauge(int(totalBeforeLoad))
-		job.guageStat(`post_load_table_rows_estimate`, tag{name: "tableName", value: strings.ToLower(tName)}).Gauge(int(totalBeforeLoad + eventsInTableUpload))
-		job.guageStat(`post_load_table_rows`, tag{name: "tableName", value: strings.ToLower(tName)}).Gauge(int(totalAfterLoad))
-	}()
+	if generateTableLoadCountVerificationsMetrics {
+		job.verifyTableLoadCounts(tableUpload, totalBeforeLoad, countedBeforeLoad)
+	}
 
 	tableUpload.setStatus(TableUploadExported)
 	numEvents, queryErr := tableUpload.getNumEvents()
@@ -1088,6 +1161,109 @@ func (job *UploadJobT) loadTable(tName string) (alteredSchema bool, err error) {
 	return
 }
 
+func (job *UploadJobT) shouldGenerateTableLoadCountMetrics() bool {
+	disableGenerateMetricsWorkspaceIDs := config.GetStringSlice("Warehouse.disableGenerateTableLoadCountMetricsWorkspaceIDs", nil)
+	if slices.Contains(disableGenerateMetricsWorkspaceIDs, job.upload.WorkspaceID) {
+		return false
+	}
+	return config.GetBool("Warehouse.generateTableLoadCountMetrics", true)
+}
+
+// getTotalCountBeforeLoad returns the rows of the table before its load, along with whether they could be counted
+func (job *UploadJobT) getTotalCountBeforeLoad(tName string) (int64, bool) {
+	totalBeforeLoad, err := job.getTotalCount(tName)
+	if err != nil {
+		pkgLogger.Errorf(`Error getting total count in table:%s before load: %v`, tName, err)
+		return 0, false
+	}
+	return totalBeforeLoad, true
+}
+
+// verifyTableLoadCounts counts the rows of the table after its load, sending them along with the estimate based on the events
+// in the load files, and reconciles them if the rows could be counted before the load.
+func (job *UploadJobT) verifyTableLoadCounts(tableUpload *TableUploadT, totalBeforeLoad int64, countedBeforeLoad bool) {
+	tName := tableUpload.tableName
+	totalAfterLoad, errTotalCount := job.getTotalCount(tName)
+	if errTotalCount != nil {
+		pkgLogger.Errorf(`Error getting total count in table:%s after load: %v`, tName, errTotalCount)
+		return
+	}
+	eventsInTableUpload, errEventCount := tableUpload.getTotalEvents()
+	if errEventCount != nil {
+		return
+	}
+
+	job.guageStat(`pre_load_table_rows`, tag{name: "tableName", value: strings.ToLower(tName)}).Gauge(int(totalBeforeLoad))
+	job.guageStat(`post_load_table_rows_estimate`, tag{name: "tableName", value: strings.ToLower(tName)}).Gauge(int(totalBeforeLoad + eventsInTableUpload))
+	job.guageStat(`post_load_table_rows`, tag{name: "tableName", value: strings.ToLower(tName)}).Gauge(int(totalAfterLoad))
+
+	if countedBeforeLoad {
+		job.reconcileTableLoad(tableUpload, eventsInTableUpload, totalBeforeLoad, totalAfterLoad)
+	}
+}
+
+// isTableMergedOnLoad returns true if the load merges the events into the existing rows of the table, which is always the case
+// of the users table, merged on the user id, and of the tables of destinations in merge load mode.
+func (job *UploadJobT) isTableMergedOnLoad(tableName string) bool {
+	if tableName == job.usersTableName() {
+		return true
+	}
+	return job.warehouse.GetLoadMode() == warehouseutils.MergeLoadMode && tableName != warehouseutils.DiscardsTable
+}
+
+// reconcileTableLoad stores the rows added to the table by the load along with their mismatch against the events in the load files.
+// A positive mismatch means fewer rows were added than events were loaded, which besides data loss happens for destinations
+// deduplicating events, while a negative one means rows were added to the table concurrently.
+//
+// Tables merged on load are skipped, as the rows they gain only account for the new keys among the events.
+func (job *UploadJobT) reconcileTableLoad(tableUpload *TableUploadT, eventsInTableUpload, totalBeforeLoad, totalAfterLoad int64) {
+	switch job.warehouse.Type {
+	case warehouseutils.S3_DATALAKE, warehouseutils.GCS_DATALAKE, warehouseutils.AZURE_DATALAKE:
+		return
+	}
+	if job.isTableMergedOnLoad(tableUpload.tableName) {
+		return
+	}
+
+	rowsMismatch := eventsInTableUpload - (totalAfterLoad - totalBeforeLoad)
+	if err := tableUpload.setRowCounts(totalBeforeLoad, totalAfterLoad, rowsMismatch); err != nil {
+		pkgLogger.Errorf(`Error setting row counts for table:%s in upload:%d: %v`, tableUpload.tableName, job.upload.ID, err)
+	}
+	if rowsMismatch == 0 {
+		return
+	}
+
+	pkgLogger.Warnf(`[WH]: Rows mismatch for table %s in upload %d of destination %s:%s, events: %d, rows before load: %d, rows after load: %d`,
+		tableUpload.tableName, job.upload.ID, job.warehouse.Type, job.warehouse.Destination.ID, eventsInTableUpload, totalBeforeLoad, totalAfterLoad)
+
+	tags := []tag{
+		{name: "tableName", value: strings.ToLower(tableUpload.tableName)},
+	}
+	job.guageStat(`load_table_rows_mismatch`, tags...).Gauge(int(rowsMismatch))
+	job.counterStat(`load_table_rows_mismatched`, tags...).Count(1)
+}
+
+// recordLoadCost stores the duration, bytes loaded and warehouse specific cost signals of loading a table
+func (job *UploadJobT) recordLoadCost(tableUpload *TableUploadT, loadDuration time.Duration) {
+	var cost warehouseutils.LoadCostT
+	if reporter, ok := job.whManager.(manager.LoadCostReporter); ok {
+		cost, _ = reporter.LoadTableCost(tableUpload.tableName)
+	}
+	if err := tableUpload.setLoadCost(job, loadDuration, cost); err != nil {
+		pkgLogger.Errorf(`Error setting load cost for table:%s in upload:%d: %v`, tableUpload.tableName, job.upload.ID, err)
+	}
+
+	tags := []tag{
+		{name: "tableName", value: strings.ToLower(tableUpload.tableName)},
+	}
+	if cost.Credits > 0 {
+		job.guageStat(`load_table_credits`, tags...).Gauge(cost.Credits)
+	}
+	if cost.BytesBilled > 0 {
+		job.counterStat(`load_table_bytes_billed`, tags...).Count(int(cost.BytesBilled))
+	}
+}
+
 // columnCountStat sent the column count for a table to statsd
 // skip sending for S3_DATALAKE, GCS_DATALAKE, AZURE_DATALAKE
 func (job *UploadJobT) columnCountStat(tableName string) {
@@ -1165,7 +1341,34 @@ func (job *UploadJobT) loadUserTables(loadFilesTableMap map[tableNameT]bool) ([]
 			return job.processLoadTableResponse(map[string]error{job.usersTableName(): err})
 		}
 	}
+
+	generateTableLoadCountVerificationsMetrics := job.shouldGenerateTableLoadCountMetrics()
+	userTableUploads := []*TableUploadT{identityTableUpload}
+	if _, ok := job.upload.UploadSchema[job.usersTableName()]; ok {
+		userTableUploads = append(userTableUploads, NewTableUpload(job.upload.ID, job.usersTableName()))
+	}
+	totalsBeforeLoad := make(map[string]int64)
+	countedBeforeLoad := make(map[string]bool)
+	if generateTableLoadCountVerificationsMetrics {
+		for _, tableUpload := range userTableUploads {
+			totalsBeforeLoad[tableUpload.tableName], countedBeforeLoad[tableUpload.tableName] = job.getTotalCountBeforeLoad(tableUpload.tableName)
+		}
+	}
+
+	loadStartTime := time.Now()
 	errorMap := job.whManager.LoadUserTables()
+	// user tables are loaded together, hence both are accounted the duration of the whole load
+	loadDuration := time.Since(loadStartTime)
+
+	for _, tableUpload := range userTableUploads {
+		if loadErr, ok := errorMap[tableUpload.tableName]; !ok || loadErr != nil {
+			continue
+		}
+		job.recordLoadCost(tableUpload, loadDuration)
+		if generateTableLoadCountVerificationsMetrics {
+			job.verifyTableLoadCounts(tableUpload, totalsBeforeLoad[tableUpload.tableName], countedBeforeLoad[tableUpload.tableName])
+		}
+	}
 
 	if alteredIdentitySchema || alteredUserSchema {
 		pkgLogger.Infof("loadUserTables: schema changed - updating local schema for %s", job.warehouse.Identifier)
@@ -1778,11 +1981,13 @@ func (job *UploadJobT) destinationRevisionIDMap() (revisionIDMap map[string]back
 		destinationID      string
 		startStagingFileID int64
 		endStagingFileID   int64
+		backfillID         string
 	}{
 		sourceID:           job.warehouse.Source.ID,
 		destinationID:      job.warehouse.Destination.ID,
 		startStagingFileID: job.upload.StartStagingFileID,
 		endStagingFileID:   job.upload.EndStagingFileID,
+		backfillID:         job.upload.BackfillID,
 	}
 	revisionIDs, err := distinctDestinationRevisionIdsFromStagingFiles(context.TODO(), revisionRequest)
 	if err != nil {
@@ -2083,7 +2288,7 @@ func (job *UploadJobT) bulkInsertLoadFileRecords(loadFiles []loadFileUploadOutpu
 	defer stmt.Close()
 
 	for _, loadFile := range loadFiles {
-		metadata := fmt.Sprintf(`{"content_length": %d, "destination_revision_id": %q, "use_rudder_storage": %t}`, loadFile.ContentLength, loadFile.DestinationRevisionID, loadFile.UseRudderStorage)
+		metadata := fmt.Sprintf(`{"content_length": %d, "total_rows": %d, "destination_revision_id": %q, "use_rudder_storage": %t}`, loadFile.ContentLength, loadFile.TotalRows, loadFile.DestinationRevisionID, loadFile.UseRudderStorage)
 		_, err = stmt.Exec(loadFile.StagingFileID, loadFile.Location, job.upload.SourceID, job.upload.DestinationID, job.upload.DestinationType, loadFile.TableName, loadFile.TotalRows, timeutil.Now(), metadata)
 		if err != nil {
 			pkgLogger.Errorf(`[WH]: Error copying row in pq.CopyIn for loadFiles: %v Error: %v`, loadFile, err)
@@ -2368,6 +2573,12 @@ func initializeStateMachine() {
 	}
 	stateTransitions[model.ExportedData] = exportDataState
 
+	// partially exported uploads export their pending tables again
+	partiallyExportedDataState := &uploadStateT{
+		completed: model.PartiallyExportedData,
+	}
+	stateTransitions[model.PartiallyExportedData] = partiallyExportedDataState
+
 	abortState := &uploadStateT{
 		completed: model.Aborted,
 	}
@@ -2380,6 +2591,7 @@ func initializeStateMachine() {
 	updateTableUploadCountsState.nextState = createRemoteSchemaState
 	createRemoteSchemaState.nextState = exportDataState
 	exportDataState.nextState = nil
+	partiallyExportedDataState.nextState = exportDataState
 	abortState.nextState = nil
 }
 
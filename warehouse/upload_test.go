@@ -2,6 +2,7 @@ package warehouse
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/rudderlabs/rudder-server/services/stats"
 	"github.com/rudderlabs/rudder-server/services/stats/memstats"
 	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -183,6 +185,25 @@ func TestColumnCountStat(t *testing.T) {
 	}
 }
 
+func TestIsTableMergedOnLoad(t *testing.T) {
+	job := UploadJobT{
+		warehouse: warehouseutils.Warehouse{
+			Type:        warehouseutils.SNOWFLAKE,
+			Destination: backendconfig.DestinationT{Config: map[string]interface{}{}},
+		},
+	}
+	require.True(t, job.isTableMergedOnLoad("USERS"), "users are merged on the user id")
+	require.False(t, job.isTableMergedOnLoad("IDENTIFIES"))
+	require.False(t, job.isTableMergedOnLoad("TRACKS"))
+
+	job.warehouse.Type = warehouseutils.POSTGRES
+	job.warehouse.Destination.Config[warehouseutils.LoadModeConfig] = warehouseutils.MergeLoadMode
+	require.True(t, job.isTableMergedOnLoad("users"))
+	require.True(t, job.isTableMergedOnLoad("identifies"))
+	require.True(t, job.isTableMergedOnLoad("tracks"))
+	require.False(t, job.isTableMergedOnLoad(warehouseutils.DiscardsTable), "discards are appended in merge mode as well")
+}
+
 var _ = Describe("Upload", Ordered, func() {
 	var (
 		sourceID        = "test-sourceID"
@@ -314,6 +335,22 @@ var _ = Describe("Upload", Ordered, func() {
 		}))
 	})
 
+	It("Records column type conflicts once in the upload metadata", func() {
+		conflict := columnTypeConflict{TableName: "tracks", ColumnName: "price", WarehouseType: "int", StagingType: "string", Resolution: columnTypeDiscarded}
+		Expect(job.recordColumnTypeConflicts([]columnTypeConflict{conflict})).To(BeNil())
+
+		widened := conflict
+		widened.Resolution = columnTypeWidened
+		Expect(job.recordColumnTypeConflicts([]columnTypeConflict{widened})).To(BeNil())
+
+		var metadata json.RawMessage
+		Expect(pgResource.DB.QueryRow(`SELECT metadata FROM wh_uploads WHERE id = 1`).Scan(&metadata)).To(BeNil())
+		Expect(gjson.GetBytes(metadata, ColumnTypeConflictsKey).Raw).To(MatchJSON(`{
+			"tracks.price": {"table_name": "tracks", "column_name": "price", "warehouse_type": "int", "staging_type": "string", "resolution": "discarded"}
+		}`))
+		Expect(job.getUploadTimings()).To(HaveLen(1), "no timing is recorded for the conflicts")
+	})
+
 	Describe("Staging files and load files events match", func() {
 		When("Matched", func() {
 			It("Should not send stats", func() {
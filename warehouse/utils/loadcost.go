@@ -0,0 +1,53 @@
+package warehouseutils
+
+import "sync"
+
+// LoadCostT holds the warehouse specific cost signals of loading a table, where the warehouse reports them.
+type LoadCostT struct {
+	// QueryIDs are the ids of the queries or jobs run in the warehouse to load the table
+	QueryIDs []string `json:"queryIds,omitempty"`
+	// Credits used by the queries, e.g. for snowflake the compute credits estimated from the execution time and
+	// warehouse size of the queries, plus their cloud services credits
+	Credits float64 `json:"credits,omitempty"`
+	// BytesBilled by the queries, e.g. for bigquery
+	BytesBilled int64 `json:"bytesBilled,omitempty"`
+	// QueryTimeMs is the time spent running the queries, e.g. for redshift and snowflake
+	QueryTimeMs int64 `json:"queryTimeMs,omitempty"`
+}
+
+// Add accumulates the cost signals of other into cost.
+func (cost *LoadCostT) Add(other LoadCostT) {
+	cost.QueryIDs = append(cost.QueryIDs, other.QueryIDs...)
+	cost.Credits += other.Credits
+	cost.BytesBilled += other.BytesBilled
+	cost.QueryTimeMs += other.QueryTimeMs
+}
+
+// LoadCosts records the load costs of tables, safe for concurrent use since tables are loaded in parallel.
+type LoadCosts struct {
+	mu    sync.Mutex
+	costs map[string]LoadCostT
+}
+
+// Add accumulates the cost of loading a table.
+func (c *LoadCosts) Add(tableName string, cost LoadCostT) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.costs == nil {
+		c.costs = make(map[string]LoadCostT)
+	}
+	tableCost := c.costs[tableName]
+	tableCost.Add(cost)
+	c.costs[tableName] = tableCost
+}
+
+// Pop returns the cost of loading a table and forgets it, so that retried loads are not accounted twice.
+func (c *LoadCosts) Pop(tableName string) (LoadCostT, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cost, ok := c.costs[tableName]
+	delete(c.costs, tableName)
+	return cost, ok
+}
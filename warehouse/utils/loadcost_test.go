@@ -0,0 +1,26 @@
+package warehouseutils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestLoadCosts(t *testing.T) {
+	var costs warehouseutils.LoadCosts
+
+	_, ok := costs.Pop("tracks")
+	require.False(t, ok)
+
+	costs.Add("tracks", warehouseutils.LoadCostT{QueryIDs: []string{"copy"}, QueryTimeMs: 100})
+	costs.Add("tracks", warehouseutils.LoadCostT{QueryIDs: []string{"merge"}, Credits: 0.25, BytesBilled: 1024, QueryTimeMs: 50})
+
+	cost, ok := costs.Pop("tracks")
+	require.True(t, ok)
+	require.Equal(t, warehouseutils.LoadCostT{QueryIDs: []string{"copy", "merge"}, Credits: 0.25, BytesBilled: 1024, QueryTimeMs: 150}, cost)
+
+	_, ok = costs.Pop("tracks")
+	require.False(t, ok)
+}
@@ -0,0 +1,181 @@
+package warehouseutils
+
+import (
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// Destination config keys for the partitioning and clustering of the tables created in the warehouse
+const (
+	PartitionColumnConfig      = "partitionColumn"
+	PartitionGranularityConfig = "partitionGranularity"
+	ClusteringColumnsConfig    = "clusteringColumns"
+)
+
+const (
+	PartitionGranularityHour  = "hour"
+	PartitionGranularityDay   = "day"
+	PartitionGranularityMonth = "month"
+	PartitionGranularityYear  = "year"
+)
+
+const (
+	bqMaxClusteringColumns         = 4
+	bqIngestionTimePartitionColumn = "_PARTITIONTIME"
+)
+
+// PartitioningDestinations are the warehouses creating tables with the configured partitioning and clustering.
+var PartitioningDestinations = []string{BQ, SNOWFLAKE, RS, CLICKHOUSE, DELTALAKE}
+
+// TablePartitioningT is how a table is partitioned and clustered in the warehouse.
+// Warehouses without native partitions, like snowflake and redshift, lead the clustering or sort keys with the partition column.
+type TablePartitioningT struct {
+	PartitionColumn      string   `json:"partitionColumn,omitempty"`
+	PartitionGranularity string   `json:"partitionGranularity,omitempty"`
+	ClusteringColumns    []string `json:"clusteringColumns,omitempty"`
+}
+
+// IsEmpty returns true if neither a partition column nor clustering columns are configured for the table.
+func (p TablePartitioningT) IsEmpty() bool {
+	return p.PartitionColumn == "" && len(p.ClusteringColumns) == 0
+}
+
+// GetTablePartitioning returns the partitioning and clustering configured for the destination, applicable to a table with the columns in columnMap.
+// The partition column is only used if it is a datetime column of the table, and clustering columns missing in the table are skipped.
+// Warehouse specific restrictions apply:
+//   - bigquery users and identifies tables stay ingestion-time partitioned, and at most 4 clustering columns are allowed
+//   - clickhouse users table keeps its sort key and partitions, since rows are aggregated by id, and clustering columns
+//     are only used as primary key if they are a prefix of the sort key, which rows are deduplicated by
+//   - deltalake tables are partitioned by a date, so hourly partitions fall back to daily ones, and clustering is not supported
+func GetTablePartitioning(warehouse Warehouse, tableName string, columnMap map[string]string) TablePartitioningT {
+	var partitioning TablePartitioningT
+	if !misc.Contains(PartitioningDestinations, warehouse.Type) {
+		return partitioning
+	}
+	if strings.HasPrefix(strings.ToLower(tableName), CTStagingTablePrefix) {
+		return partitioning
+	}
+	switch warehouse.Type {
+	case BQ:
+		if tableName == UsersTable || tableName == IdentifiesTable {
+			return partitioning
+		}
+	case CLICKHOUSE:
+		if tableName == UsersTable {
+			return partitioning
+		}
+	}
+
+	config := warehouse.Destination.Config
+	if partitionColumn, _ := config[PartitionColumnConfig].(string); strings.TrimSpace(partitionColumn) != "" {
+		partitionColumn = ToProviderCase(warehouse.Type, strings.TrimSpace(partitionColumn))
+		if columnMap[partitionColumn] == "datetime" {
+			partitioning.PartitionColumn = partitionColumn
+			partitioning.PartitionGranularity = getPartitionGranularity(config)
+		}
+	}
+
+	seen := map[string]bool{partitioning.PartitionColumn: true}
+	for _, clusteringColumn := range getClusteringColumns(config) {
+		clusteringColumn = ToProviderCase(warehouse.Type, clusteringColumn)
+		if _, ok := columnMap[clusteringColumn]; !ok || seen[clusteringColumn] {
+			continue
+		}
+		seen[clusteringColumn] = true
+		partitioning.ClusteringColumns = append(partitioning.ClusteringColumns, clusteringColumn)
+	}
+
+	switch warehouse.Type {
+	case BQ:
+		if len(partitioning.ClusteringColumns) > bqMaxClusteringColumns {
+			partitioning.ClusteringColumns = partitioning.ClusteringColumns[:bqMaxClusteringColumns]
+		}
+	case DELTALAKE:
+		if partitioning.PartitionGranularity == PartitionGranularityHour {
+			partitioning.PartitionGranularity = PartitionGranularityDay
+		}
+		partitioning.ClusteringColumns = nil
+	case CLICKHOUSE:
+		if !isPrefix(partitioning.ClusteringColumns, clickhouseSortKey(tableName)) {
+			partitioning.ClusteringColumns = nil
+		}
+	}
+	return partitioning
+}
+
+// GetTableLayout returns the partitioning and clustering of a table created in the warehouse, that is the configured one
+// along with the default partitioning of the warehouse if no partition column is configured for the table:
+//   - bigquery tables are ingestion-time partitioned by day on the _PARTITIONTIME pseudo column
+//   - clickhouse and deltalake tables are partitioned by the day of received_at
+func GetTableLayout(warehouse Warehouse, tableName string, columnMap map[string]string) TablePartitioningT {
+	partitioning := GetTablePartitioning(warehouse, tableName, columnMap)
+	if partitioning.PartitionColumn != "" {
+		return partitioning
+	}
+	switch warehouse.Type {
+	case BQ:
+		partitioning.PartitionColumn = bqIngestionTimePartitionColumn
+		partitioning.PartitionGranularity = PartitionGranularityDay
+	case CLICKHOUSE, DELTALAKE:
+		if _, ok := columnMap[ToProviderCase(warehouse.Type, "received_at")]; ok {
+			partitioning.PartitionColumn = ToProviderCase(warehouse.Type, "received_at")
+			partitioning.PartitionGranularity = PartitionGranularityDay
+		}
+	}
+	return partitioning
+}
+
+// clickhouseSortKey returns the sort key of the clickhouse tables, which ReplacingMergeTree dedupes rows by
+func clickhouseSortKey(tableName string) []string {
+	if tableName == DiscardsTable {
+		return []string{"received_at"}
+	}
+	return []string{"received_at", "id"}
+}
+
+func isPrefix(prefix, columns []string) bool {
+	if len(prefix) > len(columns) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != columns[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func getPartitionGranularity(config map[string]interface{}) string {
+	granularity, _ := config[PartitionGranularityConfig].(string)
+	switch granularity = strings.ToLower(strings.TrimSpace(granularity)); granularity {
+	case PartitionGranularityHour, PartitionGranularityMonth, PartitionGranularityYear:
+		return granularity
+	}
+	return PartitionGranularityDay
+}
+
+// getClusteringColumns returns the clustering columns configured either as a list or as a comma separated string
+func getClusteringColumns(config map[string]interface{}) []string {
+	var columns []string
+	switch value := config[ClusteringColumnsConfig].(type) {
+	case string:
+		columns = strings.Split(value, ",")
+	case []interface{}:
+		for _, column := range value {
+			if column, ok := column.(string); ok {
+				columns = append(columns, column)
+			}
+		}
+	case []string:
+		columns = value
+	}
+
+	clusteringColumns := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column = strings.TrimSpace(column); column != "" {
+			clusteringColumns = append(clusteringColumns, column)
+		}
+	}
+	return clusteringColumns
+}
@@ -0,0 +1,167 @@
+package warehouseutils_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/config/backend-config"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestGetTablePartitioning(t *testing.T) {
+	columns := map[string]string{
+		"id":          "string",
+		"event":       "string",
+		"user_id":     "string",
+		"received_at": "datetime",
+		"sent_at":     "string",
+	}
+
+	testCases := []struct {
+		name      string
+		destType  string
+		tableName string
+		config    map[string]interface{}
+		columns   map[string]string
+		want      warehouseutils.TablePartitioningT
+	}{
+		{
+			name:      "not configured",
+			destType:  warehouseutils.BQ,
+			tableName: "tracks",
+			config:    map[string]interface{}{},
+			columns:   columns,
+		},
+		{
+			name:      "partition column with default granularity and clustering columns",
+			destType:  warehouseutils.RS,
+			tableName: "tracks",
+			config: map[string]interface{}{
+				"partitionColumn":   "received_at",
+				"clusteringColumns": []interface{}{"event", "user_id", "missing", "event"},
+			},
+			columns: columns,
+			want: warehouseutils.TablePartitioningT{
+				PartitionColumn:      "received_at",
+				PartitionGranularity: "day",
+				ClusteringColumns:    []string{"event", "user_id"},
+			},
+		},
+		{
+			name:      "comma separated clustering columns in provider case",
+			destType:  warehouseutils.SNOWFLAKE,
+			tableName: "TRACKS",
+			config: map[string]interface{}{
+				"partitionColumn":      "received_at",
+				"partitionGranularity": "Month",
+				"clusteringColumns":    "event, user_id",
+			},
+			columns: map[string]string{"RECEIVED_AT": "datetime", "EVENT": "string", "USER_ID": "string"},
+			want: warehouseutils.TablePartitioningT{
+				PartitionColumn:      "RECEIVED_AT",
+				PartitionGranularity: "month",
+				ClusteringColumns:    []string{"EVENT", "USER_ID"},
+			},
+		},
+		{
+			name:      "partition column which is not a datetime",
+			destType:  warehouseutils.CLICKHOUSE,
+			tableName: "tracks",
+			config:    map[string]interface{}{"partitionColumn": "sent_at", "partitionGranularity": "invalid"},
+			columns:   columns,
+		},
+		{
+			name:      "bigquery users table stays ingestion-time partitioned",
+			destType:  warehouseutils.BQ,
+			tableName: "users",
+			config:    map[string]interface{}{"partitionColumn": "received_at"},
+			columns:   columns,
+		},
+		{
+			name:      "bigquery allows at most 4 clustering columns",
+			destType:  warehouseutils.BQ,
+			tableName: "tracks",
+			config:    map[string]interface{}{"clusteringColumns": "id,event,user_id,sent_at,received_at"},
+			columns:   columns,
+			want: warehouseutils.TablePartitioningT{
+				ClusteringColumns: []string{"id", "event", "user_id", "sent_at"},
+			},
+		},
+		{
+			name:      "deltalake partitions by date without clustering",
+			destType:  warehouseutils.DELTALAKE,
+			tableName: "tracks",
+			config: map[string]interface{}{
+				"partitionColumn":      "received_at",
+				"partitionGranularity": "hour",
+				"clusteringColumns":    "event",
+			},
+			columns: columns,
+			want: warehouseutils.TablePartitioningT{
+				PartitionColumn:      "received_at",
+				PartitionGranularity: "day",
+			},
+		},
+		{
+			name:      "clickhouse clustering columns which are not a prefix of the sort key",
+			destType:  warehouseutils.CLICKHOUSE,
+			tableName: "tracks",
+			config:    map[string]interface{}{"partitionColumn": "received_at", "clusteringColumns": "event,received_at"},
+			columns:   columns,
+			want: warehouseutils.TablePartitioningT{
+				PartitionColumn:      "received_at",
+				PartitionGranularity: "day",
+			},
+		},
+		{
+			name:      "clickhouse clustering columns which are a prefix of the sort key",
+			destType:  warehouseutils.CLICKHOUSE,
+			tableName: "tracks",
+			config:    map[string]interface{}{"clusteringColumns": "received_at"},
+			columns:   columns,
+			want: warehouseutils.TablePartitioningT{
+				ClusteringColumns: []string{"received_at"},
+			},
+		},
+		{
+			name:      "unsupported destination",
+			destType:  warehouseutils.POSTGRES,
+			tableName: "tracks",
+			config:    map[string]interface{}{"partitionColumn": "received_at"},
+			columns:   columns,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			warehouse := warehouseutils.Warehouse{
+				Type: tc.destType,
+				Destination: backendconfig.DestinationT{
+					Config: tc.config,
+				},
+			}
+			partitioning := warehouseutils.GetTablePartitioning(warehouse, tc.tableName, tc.columns)
+			require.Equal(t, tc.want, partitioning)
+			require.Equal(t, tc.want.PartitionColumn == "" && len(tc.want.ClusteringColumns) == 0, partitioning.IsEmpty())
+		})
+	}
+}
+
+func TestGetTableLayout(t *testing.T) {
+	columns := map[string]string{"id": "string", "event": "string", "received_at": "datetime"}
+	layout := func(destType string, config map[string]interface{}, tableName string, columns map[string]string) warehouseutils.TablePartitioningT {
+		return warehouseutils.GetTableLayout(warehouseutils.Warehouse{
+			Type:        destType,
+			Destination: backendconfig.DestinationT{Config: config},
+		}, tableName, columns)
+	}
+
+	require.Equal(t, warehouseutils.TablePartitioningT{PartitionColumn: "_PARTITIONTIME", PartitionGranularity: "day"}, layout(warehouseutils.BQ, map[string]interface{}{}, "users", columns))
+	require.Equal(t, warehouseutils.TablePartitioningT{PartitionColumn: "_PARTITIONTIME", PartitionGranularity: "day", ClusteringColumns: []string{"event"}}, layout(warehouseutils.BQ, map[string]interface{}{"clusteringColumns": "event"}, "tracks", columns))
+	require.Equal(t, warehouseutils.TablePartitioningT{PartitionColumn: "received_at", PartitionGranularity: "day"}, layout(warehouseutils.DELTALAKE, map[string]interface{}{}, "tracks", columns))
+	require.Equal(t, warehouseutils.TablePartitioningT{PartitionColumn: "received_at", PartitionGranularity: "month"}, layout(warehouseutils.CLICKHOUSE, map[string]interface{}{"partitionColumn": "received_at", "partitionGranularity": "month"}, "tracks", columns))
+	require.True(t, layout(warehouseutils.CLICKHOUSE, map[string]interface{}{}, "tracks", map[string]string{"id": "string"}).IsEmpty())
+	require.True(t, layout(warehouseutils.SNOWFLAKE, map[string]interface{}{}, "TRACKS", columns).IsEmpty())
+}
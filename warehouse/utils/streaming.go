@@ -0,0 +1,66 @@
+package warehouseutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// EnableStreamingConfig enables the streaming micro-batch load path for a destination, bypassing staging and load files.
+const EnableStreamingConfig = "enableStreaming"
+
+// StreamingDestinations are the warehouses supporting the streaming micro-batch load path.
+var StreamingDestinations = []string{POSTGRES, CLICKHOUSE}
+
+// IsStreamingEnabled returns true if events for the destination are streamed into the warehouse.
+func IsStreamingEnabled(destType string, config map[string]interface{}) bool {
+	if !misc.Contains(StreamingDestinations, destType) {
+		return false
+	}
+	enabled, _ := config[EnableStreamingConfig].(bool)
+	return enabled
+}
+
+// StreamingEventT is an event as generated by the warehouse transformer, along with the table and columns it is loaded into.
+type StreamingEventT struct {
+	Metadata struct {
+		Table   string            `json:"table"`
+		Columns map[string]string `json:"columns"`
+	} `json:"metadata"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// StreamingBatch is a batch of events the batch router streams into the warehouse.
+type StreamingBatch struct {
+	WorkspaceID      string
+	BatchDestination DestinationT
+	Events           []StreamingEventT
+}
+
+// StreamingValueToString formats a value of a streamed event the same way as it is written into csv load files,
+// so that warehouses can convert it using the column type. Nil values are formatted as empty strings.
+func StreamingValueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case map[string]interface{}, []interface{}:
+		marshalled, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(marshalled)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
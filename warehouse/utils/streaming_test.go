@@ -0,0 +1,36 @@
+package warehouseutils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestIsStreamingEnabled(t *testing.T) {
+	require.True(t, warehouseutils.IsStreamingEnabled(warehouseutils.POSTGRES, map[string]interface{}{"enableStreaming": true}))
+	require.False(t, warehouseutils.IsStreamingEnabled(warehouseutils.POSTGRES, map[string]interface{}{}))
+	require.False(t, warehouseutils.IsStreamingEnabled(warehouseutils.SNOWFLAKE, map[string]interface{}{"enableStreaming": true}))
+}
+
+func TestStreamingValueToString(t *testing.T) {
+	testCases := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: nil, want: ""},
+		{value: "text", want: "text"},
+		{value: float64(10), want: "10"},
+		{value: 1.25, want: "1.25"},
+		{value: true, want: "true"},
+		{value: time.Date(2022, 12, 6, 10, 0, 0, 0, time.UTC), want: "2022-12-06T10:00:00Z"},
+		{value: map[string]interface{}{"a": float64(1)}, want: `{"a":1}`},
+		{value: []interface{}{"a", "b"}, want: `["a","b"]`},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, warehouseutils.StreamingValueToString(tc.value))
+	}
+}
@@ -77,6 +77,27 @@ const (
 	ExcludeWindow           = "excludeWindow"
 	ExcludeWindowStartTime  = "excludeWindowStartTime"
 	ExcludeWindowEndTime    = "excludeWindowEndTime"
+	SyncCronExpression      = "syncCronExpression"
+	BlackoutWindows         = "blackoutWindows"
+	BlackoutWindowStartTime = "startTime"
+	BlackoutWindowEndTime   = "endTime"
+	BlackoutWindowDays      = "days"
+)
+
+// Load modes
+const (
+	LoadModeConfig         = "loadMode"
+	MergePrimaryKeysConfig = "mergePrimaryKeys"
+	AppendLoadMode         = "append"
+	MergeLoadMode          = "merge"
+)
+
+// Column type conflict policies
+const (
+	ColumnTypeConflictPolicyConfig = "columnTypeConflictPolicy"
+	DiscardConflictPolicy          = "discard"
+	WidenConflictPolicy            = "widen"
+	ShadowColumnConflictPolicy     = "shadow"
 )
 
 const (
@@ -248,6 +269,48 @@ func (w *Warehouse) GetBoolDestinationConfig(key string) bool {
 	return false
 }
 
+// GetLoadMode returns the configured load mode of the destination, defaulting to append.
+func (w *Warehouse) GetLoadMode() string {
+	if mode, _ := w.Destination.Config[LoadModeConfig].(string); strings.EqualFold(mode, MergeLoadMode) {
+		return MergeLoadMode
+	}
+	return AppendLoadMode
+}
+
+// GetMergePrimaryKeys returns the columns on which rows of tableName are merged in merge load mode.
+// Keys are configured per table either as a comma separated string or as a list of column names,
+// falling back to defaultKeys for tables without any configured keys.
+func (w *Warehouse) GetMergePrimaryKeys(tableName string, defaultKeys []string) []string {
+	keysMap := GetConfigValueAsMap(MergePrimaryKeysConfig, w.Destination.Config)
+	value, ok := keysMap[tableName]
+	if !ok {
+		value, ok = keysMap[strings.ToLower(tableName)]
+	}
+	if !ok {
+		return defaultKeys
+	}
+
+	var keys []string
+	switch v := value.(type) {
+	case string:
+		for _, key := range strings.Split(v, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+	case []interface{}:
+		for _, key := range v {
+			if key, ok := key.(string); ok && strings.TrimSpace(key) != "" {
+				keys = append(keys, strings.TrimSpace(key))
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return defaultKeys
+	}
+	return keys
+}
+
 type DestinationT struct {
 	Source      backendconfig.SourceT
 	Destination backendconfig.DestinationT
@@ -320,6 +383,7 @@ type TableSchemaDiffT struct {
 	ColumnMap                      map[string]string
 	UpdatedSchema                  map[string]string
 	StringColumnsToBeAlteredToText []string
+	ColumnsToBeWidened             map[string]string
 }
 
 type QueryResult struct {
@@ -715,6 +779,16 @@ func GetConfigValueAsMap(key string, config map[string]interface{}) map[string]i
 	return value
 }
 
+// GetColumnTypeConflictPolicy returns how values conflicting with the type of an existing column are handled, defaulting to discarding them.
+func GetColumnTypeConflictPolicy(config map[string]interface{}) string {
+	policy, _ := config[ColumnTypeConflictPolicyConfig].(string)
+	switch policy = strings.ToLower(policy); policy {
+	case WidenConflictPolicy, ShadowColumnConflictPolicy:
+		return policy
+	}
+	return DiscardConflictPolicy
+}
+
 func SortColumnKeysFromColumnMap(columnMap map[string]string) []string {
 	columnKeys := make([]string, 0, len(columnMap))
 	for k := range columnMap {
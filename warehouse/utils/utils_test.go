@@ -1042,6 +1042,69 @@ func TestWarehouseT_GetBoolDestinationConfig(t *testing.T) {
 	}
 }
 
+func TestWarehouse_GetLoadMode(t *testing.T) {
+	inputs := []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{config: nil, expected: AppendLoadMode},
+		{config: map[string]interface{}{LoadModeConfig: "append"}, expected: AppendLoadMode},
+		{config: map[string]interface{}{LoadModeConfig: "merge"}, expected: MergeLoadMode},
+		{config: map[string]interface{}{LoadModeConfig: "MERGE"}, expected: MergeLoadMode},
+		{config: map[string]interface{}{LoadModeConfig: "upsert"}, expected: AppendLoadMode},
+		{config: map[string]interface{}{LoadModeConfig: true}, expected: AppendLoadMode},
+	}
+	for _, input := range inputs {
+		warehouse := Warehouse{Destination: backendconfig.DestinationT{Config: input.config}}
+		require.Equal(t, input.expected, warehouse.GetLoadMode())
+	}
+}
+
+func TestWarehouse_GetMergePrimaryKeys(t *testing.T) {
+	config := map[string]interface{}{
+		MergePrimaryKeysConfig: map[string]interface{}{
+			"orders":   "order_id, store_id",
+			"products": []interface{}{"sku", "region"},
+			"pages":    "",
+			"screens":  []interface{}{},
+		},
+	}
+	inputs := []struct {
+		tableName string
+		expected  []string
+	}{
+		{tableName: "orders", expected: []string{"order_id", "store_id"}},
+		{tableName: "ORDERS", expected: []string{"order_id", "store_id"}},
+		{tableName: "products", expected: []string{"sku", "region"}},
+		{tableName: "pages", expected: []string{"id"}},
+		{tableName: "screens", expected: []string{"id"}},
+		{tableName: "tracks", expected: []string{"id"}},
+	}
+	warehouse := Warehouse{Destination: backendconfig.DestinationT{Config: config}}
+	for _, input := range inputs {
+		require.Equal(t, input.expected, warehouse.GetMergePrimaryKeys(input.tableName, []string{"id"}))
+	}
+
+	warehouse = Warehouse{Destination: backendconfig.DestinationT{}}
+	require.Equal(t, []string{"id"}, warehouse.GetMergePrimaryKeys("orders", []string{"id"}))
+}
+
+func TestGetColumnTypeConflictPolicy(t *testing.T) {
+	inputs := []struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		{config: nil, expected: DiscardConflictPolicy},
+		{config: map[string]interface{}{ColumnTypeConflictPolicyConfig: "discard"}, expected: DiscardConflictPolicy},
+		{config: map[string]interface{}{ColumnTypeConflictPolicyConfig: "widen"}, expected: WidenConflictPolicy},
+		{config: map[string]interface{}{ColumnTypeConflictPolicyConfig: "Shadow"}, expected: ShadowColumnConflictPolicy},
+		{config: map[string]interface{}{ColumnTypeConflictPolicyConfig: "unknown"}, expected: DiscardConflictPolicy},
+	}
+	for _, input := range inputs {
+		require.Equal(t, input.expected, GetColumnTypeConflictPolicy(input.config))
+	}
+}
+
 func TestGetLoadFileFormat(t *testing.T) {
 	inputs := []struct {
 		whType   string
@@ -0,0 +1,207 @@
+package validations
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+const (
+	privilegeCreateSchema = "CREATE SCHEMA"
+	privilegeCreateTable  = "CREATE TABLE"
+	privilegeAlterTable   = "ALTER TABLE"
+	privilegeDelete       = "DELETE"
+	privilegeDropTable    = "DROP TABLE"
+)
+
+type privilegeCheck struct {
+	Privilege string `json:"privilege"`
+	Granted   bool   `json:"granted"`
+	Error     string `json:"error,omitempty"`
+}
+
+// verifyingPrivileges checks the privileges needed by the warehouse loads in the test namespace.
+// All privileges are checked, unless the test table can't be created, so that the missing ones are reported at once.
+func (ct *CTHandleT) verifyingPrivileges() (stepDetails, error) {
+	if err := ct.initManager(); err != nil {
+		return nil, err
+	}
+
+	var privileges []privilegeCheck
+	check := func(privilege string, f func() error) bool {
+		result := privilegeCheck{Privilege: privilege, Granted: true}
+		if err := f(); err != nil {
+			result.Granted = false
+			result.Error = err.Error()
+		}
+		privileges = append(privileges, result)
+		return result.Granted
+	}
+
+	tableName := stagingTableName()
+	check(privilegeCreateSchema, ct.manager.CreateSchema)
+	if check(privilegeCreateTable, func() error { return ct.manager.CreateTable(tableName, TestTableSchemaMap) }) {
+		check(privilegeAlterTable, func() error {
+			for columnName, columnType := range AlterColumnMap {
+				if err := ct.manager.AddColumns(tableName, []warehouseutils.ColumnInfo{{Name: columnName, Type: columnType}}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		check(privilegeDelete, func() error { return ct.deleteFromTable(tableName) })
+		check(privilegeDropTable, func() error { return ct.manager.DropTable(tableName) })
+	}
+
+	details := stepDetails{"privileges": privileges}
+	var missing []string
+	for _, privilege := range privileges {
+		if !privilege.Granted {
+			missing = append(missing, privilege.Privilege)
+		}
+	}
+	if len(missing) > 0 {
+		return details, fmt.Errorf("missing privileges: %s", strings.Join(missing, ", "))
+	}
+	return details, nil
+}
+
+func (ct *CTHandleT) deleteFromTable(tableName string) error {
+	client, err := ct.manager.Connect(ct.warehouse)
+	if err != nil {
+		return fmt.Errorf("connecting to warehouse: %w", err)
+	}
+	defer client.Close()
+
+	_, err = client.Query(deleteStatement(ct.warehouse.Type, ct.warehouse.Namespace, tableName))
+	return err
+}
+
+// deleteStatement returns the statement deleting the test row from the table
+func deleteStatement(destType, namespace, tableName string) string {
+	switch destType {
+	case warehouseutils.BQ, warehouseutils.DELTALAKE:
+		return fmt.Sprintf("DELETE FROM `%s`.`%s` WHERE `id` = 1", namespace, tableName)
+	case warehouseutils.CLICKHOUSE:
+		return fmt.Sprintf("ALTER TABLE `%s`.`%s` DELETE WHERE `id` = 1", namespace, tableName)
+	default:
+		return fmt.Sprintf(`DELETE FROM %q.%q WHERE "id" = 1`, namespace, tableName)
+	}
+}
+
+// verifyingStagingBucketLifecycle checks that the lifecycle policy of the bucket doesn't expire staging files before they are loaded.
+// Providers which can't report their lifecycle policy are skipped.
+func (ct *CTHandleT) verifyingStagingBucketLifecycle() (stepDetails, error) {
+	fm, err := fileManager(ct.infoRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	lm, ok := fm.(filemanager.LifecycleManager)
+	if !ok {
+		return stepDetails{"supported": false}, nil
+	}
+
+	rules, err := lm.GetLifecycleRules(context.TODO())
+	if err != nil {
+		return nil, fmt.Errorf("fetching bucket lifecycle rules: %w", err)
+	}
+
+	stagingPrefix := path.Join(fm.GetConfiguredPrefix(), stagingFolderName)
+	expiringRules := expiringLifecycleRules(rules, stagingPrefix, minStagingFileRetention)
+	details := stepDetails{
+		"supported":     true,
+		"stagingPrefix": stagingPrefix,
+		"minRetention":  minStagingFileRetention.String(),
+		"rules":         rules,
+		"expiringRules": expiringRules,
+	}
+	if len(expiringRules) > 0 {
+		return details, fmt.Errorf("lifecycle rule %s expires staging files under %s after %d days, less than the retention of %s", expiringRules[0].ID, stagingPrefix, expiringRules[0].ExpirationDays, minStagingFileRetention)
+	}
+	return details, nil
+}
+
+// expiringLifecycleRules returns the enabled rules expiring objects under the prefix before the minimum retention
+func expiringLifecycleRules(rules []filemanager.LifecycleRule, prefix string, minRetention time.Duration) []filemanager.LifecycleRule {
+	var expiringRules []filemanager.LifecycleRule
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if !strings.HasPrefix(prefix, rule.Prefix) && !strings.HasPrefix(rule.Prefix, prefix) {
+			continue
+		}
+		if time.Duration(rule.ExpirationDays)*24*time.Hour < minRetention {
+			expiringRules = append(expiringRules, rule)
+		}
+	}
+	return expiringRules
+}
+
+// verifyingClockSkew compares the current time of the warehouse to the local one.
+// The local time is taken half way through the query, so that the round trip is not accounted as skew.
+func (ct *CTHandleT) verifyingClockSkew() (stepDetails, error) {
+	if err := ct.initManager(); err != nil {
+		return nil, err
+	}
+
+	client, err := ct.manager.Connect(ct.warehouse)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to warehouse: %w", err)
+	}
+	defer client.Close()
+
+	startTime := timeutil.Now()
+	result, err := client.Query(currentTimestampStatement(ct.warehouse.Type))
+	if err != nil {
+		return nil, fmt.Errorf("querying warehouse time: %w", err)
+	}
+	roundTrip := timeutil.Now().Sub(startTime)
+	if len(result.Values) == 0 || len(result.Values[0]) == 0 {
+		return nil, fmt.Errorf("querying warehouse time: no rows returned")
+	}
+	warehouseMillis, err := strconv.ParseFloat(result.Values[0][0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing warehouse time %q: %w", result.Values[0][0], err)
+	}
+
+	warehouseTime := time.UnixMilli(int64(warehouseMillis)).UTC()
+	localTime := startTime.Add(roundTrip / 2)
+	skew := warehouseTime.Sub(localTime)
+	details := stepDetails{
+		"warehouseTime": warehouseTime,
+		"localTime":     localTime,
+		"roundTrip":     roundTrip.String(),
+		"skew":          skew.String(),
+	}
+	if skew.Abs() > maxClockSkew {
+		return details, fmt.Errorf("warehouse clock is skewed by %s, more than %s", skew, maxClockSkew)
+	}
+	return details, nil
+}
+
+// currentTimestampStatement returns the statement querying the current unix time of the warehouse in milliseconds
+func currentTimestampStatement(destType string) string {
+	switch destType {
+	case warehouseutils.BQ:
+		return `SELECT UNIX_MILLIS(CURRENT_TIMESTAMP())`
+	case warehouseutils.CLICKHOUSE:
+		return `SELECT toUnixTimestamp64Milli(now64(3))`
+	case warehouseutils.DELTALAKE:
+		return `SELECT unix_millis(current_timestamp())`
+	case warehouseutils.SNOWFLAKE:
+		return `SELECT DATE_PART(EPOCH_MILLISECOND, CURRENT_TIMESTAMP())`
+	case warehouseutils.MSSQL, warehouseutils.AZURE_SYNAPSE:
+		return `SELECT DATEDIFF_BIG(MILLISECOND, '1970-01-01', SYSUTCDATETIME())`
+	default:
+		return `SELECT EXTRACT(EPOCH FROM CURRENT_TIMESTAMP) * 1000`
+	}
+}
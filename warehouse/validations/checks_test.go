@@ -0,0 +1,46 @@
+package validations
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rudderlabs/rudder-server/services/filemanager"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var _ = Describe("Checks", func() {
+	DescribeTable("Delete statement", func(destinationType, expected string) {
+		Expect(deleteStatement(destinationType, "namespace", "setup_test_staging_1")).To(Equal(expected))
+	},
+		Entry("RS", warehouseutils.RS, `DELETE FROM "namespace"."setup_test_staging_1" WHERE "id" = 1`),
+		Entry("BQ", warehouseutils.BQ, "DELETE FROM `namespace`.`setup_test_staging_1` WHERE `id` = 1"),
+		Entry("CLICKHOUSE", warehouseutils.CLICKHOUSE, "ALTER TABLE `namespace`.`setup_test_staging_1` DELETE WHERE `id` = 1"),
+	)
+
+	DescribeTable("Expiring lifecycle rules", func(rules []filemanager.LifecycleRule, expectedIDs []string) {
+		var ids []string
+		for _, rule := range expiringLifecycleRules(rules, "prefix/rudder-warehouse-staging-logs", 72*time.Hour) {
+			ids = append(ids, rule.ID)
+		}
+		Expect(ids).To(Equal(expectedIDs))
+	},
+		Entry("no rules", nil, nil),
+		Entry("bucket wide rule", []filemanager.LifecycleRule{
+			{ID: "all", Enabled: true, ExpirationDays: 1},
+		}, []string{"all"}),
+		Entry("disabled rule", []filemanager.LifecycleRule{
+			{ID: "all", Enabled: false, ExpirationDays: 1},
+		}, nil),
+		Entry("rule after retention", []filemanager.LifecycleRule{
+			{ID: "all", Enabled: true, ExpirationDays: 3},
+			{ID: "week", Enabled: true, ExpirationDays: 7},
+		}, nil),
+		Entry("rules on other prefixes", []filemanager.LifecycleRule{
+			{ID: "logs", Prefix: "prefix/rudder-logs", Enabled: true, ExpirationDays: 1},
+			{ID: "staging", Prefix: "prefix/rudder-warehouse-staging-logs/source", Enabled: true, ExpirationDays: 1},
+			{ID: "parent", Prefix: "prefix/", Enabled: true, ExpirationDays: 2},
+		}, []string{"staging", "parent"}),
+	)
+})
@@ -0,0 +1,406 @@
+package validations
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// sampleUploadJob is the upload job of a single load file sampled by the validations.
+// The load file is loaded into a test table through the regular load path of the warehouse.
+type sampleUploadJob struct {
+	*CTUploadJob
+	tableName   string
+	tableSchema warehouseutils.TableSchemaT
+	loadFile    warehouseutils.LoadFileT
+}
+
+func (job *sampleUploadJob) GetSchemaInWarehouse() warehouseutils.SchemaT {
+	return warehouseutils.SchemaT{job.tableName: job.tableSchema}
+}
+
+func (job *sampleUploadJob) GetLocalSchema() warehouseutils.SchemaT {
+	return job.GetSchemaInWarehouse()
+}
+
+func (job *sampleUploadJob) GetTableSchemaInWarehouse(tableName string) warehouseutils.TableSchemaT {
+	return job.GetSchemaInWarehouse()[tableName]
+}
+
+func (job *sampleUploadJob) GetTableSchemaInUpload(tableName string) warehouseutils.TableSchemaT {
+	return job.GetSchemaInWarehouse()[tableName]
+}
+
+func (job *sampleUploadJob) GetLoadFilesMetadata(options warehouseutils.GetLoadFilesOptionsT) []warehouseutils.LoadFileT {
+	if options.Table != "" && options.Table != job.tableName {
+		return []warehouseutils.LoadFileT{}
+	}
+	return []warehouseutils.LoadFileT{job.loadFile}
+}
+
+func (job *sampleUploadJob) GetSampleLoadFileLocation(tableName string) (string, error) {
+	if tableName != job.tableName {
+		return "", fmt.Errorf("no load file found for table:%s", tableName)
+	}
+	return job.loadFile.Location, nil
+}
+
+func (job *sampleUploadJob) GetSingleLoadFile(tableName string) (warehouseutils.LoadFileT, error) {
+	if tableName != job.tableName {
+		return warehouseutils.LoadFileT{}, fmt.Errorf("no load file found for table:%s", tableName)
+	}
+	return job.loadFile, nil
+}
+
+type sampleLoadResult struct {
+	rows           int
+	loadedRows     int64
+	bytes          int64
+	uploadDuration time.Duration
+	loadDuration   time.Duration
+}
+
+// loadSample writes the rows into a load file and loads it into a test table with the given schema
+func (ct *CTHandleT) loadSample(tableSchema warehouseutils.TableSchemaT, rows []map[string]interface{}) (result sampleLoadResult, err error) {
+	filePath, err := createSampleLoadFile(ct.infoRequest, tableSchema, rows)
+	if err != nil {
+		return result, fmt.Errorf("creating load file: %w", err)
+	}
+	defer misc.RemoveFilePaths(filePath)
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		return result, fmt.Errorf("reading load file size: %w", err)
+	}
+
+	uploadStart := timeutil.Now()
+	uploadOutput, err := uploadLoadFile(ct.infoRequest, filePath)
+	if err != nil {
+		return result, fmt.Errorf("uploading load file: %w", err)
+	}
+	defer deleteLoadFile(ct.infoRequest, uploadOutput.ObjectName)
+	result.uploadDuration = timeutil.Now().Sub(uploadStart)
+
+	tableName := stagingTableName()
+	err = ct.initManagerWithUploader(&sampleUploadJob{
+		CTUploadJob: &CTUploadJob{infoRequest: ct.infoRequest},
+		tableName:   tableName,
+		tableSchema: tableSchema,
+		loadFile:    warehouseutils.LoadFileT{Location: uploadOutput.Location},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if err = ct.manager.CreateTable(tableName, tableSchema); err != nil {
+		return result, fmt.Errorf("creating table: %w", err)
+	}
+	defer func() { _ = ct.manager.DropTable(tableName) }()
+
+	loadStart := timeutil.Now()
+	if err = ct.manager.LoadTable(tableName); err != nil {
+		return result, fmt.Errorf("loading table: %w", err)
+	}
+	result.loadDuration = timeutil.Now().Sub(loadStart)
+
+	result.loadedRows, err = ct.manager.GetTotalCountInTable(context.TODO(), tableName)
+	if err != nil {
+		return result, fmt.Errorf("counting loaded rows: %w", err)
+	}
+	result.rows = len(rows)
+	result.bytes = fileInfo.Size()
+	return result, nil
+}
+
+// deleteLoadFile deletes the uploaded load file from the object storage, logging any failure since the sample is loaded already.
+func deleteLoadFile(req *DestinationValidationRequest, objectName string) {
+	fm, err := fileManager(req)
+	if err != nil {
+		pkgLogger.Warnf("[DCT]: Failed to initiate file manager to delete load file %s with error: %s", objectName, err.Error())
+		return
+	}
+	if err = fm.DeleteObjects(context.TODO(), []string{objectName}); err != nil {
+		pkgLogger.Warnf("[DCT]: Failed to delete load file %s with error: %s", objectName, err.Error())
+	}
+}
+
+// createSampleLoadFile writes the rows into a load file of the destination, converting their values the same way as staging files are processed.
+func createSampleLoadFile(req *DestinationValidationRequest, tableSchema warehouseutils.TableSchemaT, rows []map[string]interface{}) (filePath string, err error) {
+	destinationType := req.Destination.DestinationDefinition.Name
+	loadFileType := warehouseutils.GetLoadFileType(destinationType)
+
+	tmpDirPath, err := misc.CreateTMPDIR()
+	if err != nil {
+		return "", err
+	}
+	filePath = fmt.Sprintf("%v/%v/%v.%v.%v.%v", tmpDirPath, connectionTestingFolder, destinationType, warehouseutils.RandHex(), time.Now().Unix(), warehouseutils.GetLoadFileFormat(destinationType))
+	if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return "", err
+	}
+
+	var writer warehouseutils.LoadFileWriterI
+	if loadFileType == warehouseutils.LOAD_FILE_TYPE_PARQUET {
+		writer, err = warehouseutils.CreateParquetWriter(tableSchema, filePath, destinationType)
+	} else {
+		writer, err = misc.CreateGZ(filePath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	loadTime := timeutil.Now()
+	sortedColumns := warehouseutils.SortColumnKeysFromColumnMap(tableSchema)
+	for _, row := range rows {
+		eventLoader := warehouseutils.GetNewEventLoader(destinationType, loadFileType, writer)
+		for _, columnName := range sortedColumns {
+			if eventLoader.IsLoadTimeColumn(columnName) {
+				eventLoader.AddColumn(columnName, tableSchema[columnName], loadTime.Format(eventLoader.GetLoadTimeFormat(columnName)))
+				continue
+			}
+			columnVal, ok := sampleColumnValue(tableSchema[columnName], row[columnName])
+			if !ok {
+				eventLoader.AddEmptyColumn(columnName)
+				continue
+			}
+			eventLoader.AddColumn(columnName, tableSchema[columnName], columnVal)
+		}
+		if err = eventLoader.Write(); err != nil {
+			_ = writer.Close()
+			return "", err
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return "", err
+	}
+	return filePath, nil
+}
+
+// sampleColumnValue converts the value of a column as decoded from a staging file to the one written into the load file.
+// It returns false if the value is missing or can't be converted to the column type.
+func sampleColumnValue(columnType string, columnVal interface{}) (interface{}, bool) {
+	switch val := columnVal.(type) {
+	case nil:
+		return nil, false
+	case float64:
+		if model.SchemaType(columnType) == model.IntDataType || model.SchemaType(columnType) == model.BigIntDataType {
+			return int(val), true
+		}
+	case []interface{}:
+		marshalledVal, err := json.Marshal(val)
+		if err != nil {
+			return nil, false
+		}
+		return string(marshalledVal), true
+	default:
+		if model.SchemaType(columnType) == model.IntDataType || model.SchemaType(columnType) == model.BigIntDataType {
+			return nil, false
+		}
+	}
+	return columnVal, true
+}
+
+// estimatingLoadThroughput loads a batch of generated rows, estimating the throughput of uploading and loading load files
+func (ct *CTHandleT) estimatingLoadThroughput() (stepDetails, error) {
+	destinationType := ct.infoRequest.Destination.DestinationDefinition.Name
+	tableSchema := warehouseutils.TableSchemaT{
+		warehouseutils.ToProviderCase(destinationType, "id"):          "string",
+		warehouseutils.ToProviderCase(destinationType, "received_at"): "datetime",
+		warehouseutils.ToProviderCase(destinationType, "val"):         "string",
+	}
+
+	receivedAt := timeutil.Now().Format(time.RFC3339)
+	rows := make([]map[string]interface{}, 0, throughputSampleRows)
+	for i := 0; i < throughputSampleRows; i++ {
+		rows = append(rows, map[string]interface{}{
+			warehouseutils.ToProviderCase(destinationType, "id"):          strconv.Itoa(i + 1),
+			warehouseutils.ToProviderCase(destinationType, "received_at"): receivedAt,
+			warehouseutils.ToProviderCase(destinationType, "val"):         TestPayloadMap["val"],
+		})
+	}
+
+	result, err := ct.loadSample(tableSchema, rows)
+	if err != nil {
+		return nil, err
+	}
+	return throughputDetails(result), nil
+}
+
+// throughputDetails estimates the throughput of the warehouse from the time taken to upload and load the sample
+func throughputDetails(result sampleLoadResult) stepDetails {
+	details := stepDetails{
+		"rows":           result.rows,
+		"loadedRows":     result.loadedRows,
+		"bytes":          result.bytes,
+		"uploadDuration": result.uploadDuration.String(),
+		"loadDuration":   result.loadDuration.String(),
+	}
+	if totalSeconds := (result.uploadDuration + result.loadDuration).Seconds(); totalSeconds > 0 {
+		details["estimatedRowsPerSecond"] = float64(result.rows) / totalSeconds
+		details["estimatedBytesPerSecond"] = float64(result.bytes) / totalSeconds
+	}
+	return details
+}
+
+// verifyingDryRunLoad loads a sample of the last staging file of the destination into a test table.
+// It is skipped if the destination has no uploads yet.
+func (ct *CTHandleT) verifyingDryRunLoad() (stepDetails, error) {
+	if ct.DB == nil {
+		return stepDetails{"skipped": true, "reason": "warehouse database not available"}, nil
+	}
+
+	location, err := lastUploadStagingFile(ct.DB, ct.infoRequest.Destination.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return stepDetails{"skipped": true, "reason": "no uploads found for destination"}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching staging file of last upload: %w", err)
+	}
+
+	sample, err := ct.sampleStagingFile(location)
+	if err != nil {
+		return nil, err
+	}
+	if len(sample.rows) == 0 {
+		return stepDetails{"skipped": true, "reason": "no events found in staging file", "stagingFile": location}, nil
+	}
+
+	result, err := ct.loadSample(sample.tableSchema, sample.rows)
+	details := stepDetails{
+		"stagingFile": location,
+		"table":       sample.tableName,
+		"columns":     len(sample.tableSchema),
+		"sampledRows": len(sample.rows),
+	}
+	if err != nil {
+		return details, err
+	}
+	details["loadedRows"] = result.loadedRows
+	if result.loadedRows != int64(len(sample.rows)) {
+		return details, fmt.Errorf("loaded %d rows of the %d sampled from staging file", result.loadedRows, len(sample.rows))
+	}
+	return details, nil
+}
+
+// lastUploadStagingFile returns the location of the last staging file of the latest upload of the destination
+func lastUploadStagingFile(db *sql.DB, destinationID string) (location string, err error) {
+	sqlStatement := fmt.Sprintf(`
+		SELECT
+		  location
+		FROM
+		  %[1]s
+		WHERE
+		  id = (
+			SELECT
+			  end_staging_file_id
+			FROM
+			  %[2]s
+			WHERE
+			  destination_id = $1
+			ORDER BY
+			  id DESC
+			LIMIT
+			  1
+		  );
+`,
+		warehouseutils.WarehouseStagingFilesTable,
+		warehouseutils.WarehouseUploadsTable,
+	)
+	err = db.QueryRow(sqlStatement, destinationID).Scan(&location)
+	return
+}
+
+type stagingFileSample struct {
+	tableName   string
+	tableSchema warehouseutils.TableSchemaT
+	rows        []map[string]interface{}
+}
+
+func (ct *CTHandleT) sampleStagingFile(location string) (stagingFileSample, error) {
+	fm, err := fileManager(ct.infoRequest)
+	if err != nil {
+		return stagingFileSample{}, err
+	}
+
+	tmpDirPath, err := misc.CreateTMPDIR()
+	if err != nil {
+		return stagingFileSample{}, err
+	}
+	filePath := fmt.Sprintf("%v/%v/%v.%v.json.gz", tmpDirPath, connectionTestingFolder, warehouseutils.RandHex(), time.Now().Unix())
+	if err = os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return stagingFileSample{}, err
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return stagingFileSample{}, err
+	}
+	defer misc.RemoveFilePaths(filePath)
+	defer func() { _ = file.Close() }()
+
+	if err = fm.Download(context.TODO(), file, fm.GetDownloadKeyFromFileLocation(location)); err != nil {
+		return stagingFileSample{}, fmt.Errorf("downloading staging file: %w", err)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return stagingFileSample{}, err
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return stagingFileSample{}, fmt.Errorf("reading staging file: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	return sampleStagingEvents(reader, dryRunSampleRows)
+}
+
+// sampleStagingEvents reads up to maxRows events of the first table in the staging file, along with the union of their columns.
+// Identity merge rules are skipped, since they aren't loaded as they are.
+func sampleStagingEvents(r io.Reader, maxRows int) (stagingFileSample, error) {
+	sample := stagingFileSample{
+		tableSchema: warehouseutils.TableSchemaT{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	maxCapacity := maxStagingFileReadBufferInK * 1024
+	scanner.Buffer(make([]byte, maxCapacity), maxCapacity)
+	for len(sample.rows) < maxRows && scanner.Scan() {
+		var event warehouseutils.StreamingEventT
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		tableName := event.Metadata.Table
+		if tableName == "" || strings.EqualFold(tableName, warehouseutils.IdentityMergeRulesTable) {
+			continue
+		}
+		if sample.tableName == "" {
+			sample.tableName = tableName
+		}
+		if tableName != sample.tableName {
+			continue
+		}
+		for columnName, columnType := range event.Metadata.Columns {
+			if _, ok := sample.tableSchema[columnName]; !ok {
+				sample.tableSchema[columnName] = columnType
+			}
+		}
+		sample.rows = append(sample.rows, event.Data)
+	}
+	if err := scanner.Err(); err != nil {
+		return stagingFileSample{}, fmt.Errorf("reading staging file: %w", err)
+	}
+	return sample, nil
+}
@@ -0,0 +1,72 @@
+package validations
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+var _ = Describe("Sample", func() {
+	BeforeEach(func() {
+		maxStagingFileReadBufferInK = 1024
+	})
+
+	It("Samples events of the first table in staging file", func() {
+		stagingFile := strings.Join([]string{
+			`{"metadata":{"table":"rudder_identity_merge_rules","columns":{"merge_property_1_type":"string"}},"data":{"merge_property_1_type":"email"}}`,
+			`{"metadata":{"table":"tracks","columns":{"id":"string","received_at":"datetime"}},"data":{"id":"1","received_at":"2022-12-01T00:00:00Z"}}`,
+			`not a json line`,
+			`{"metadata":{"table":"pages","columns":{"id":"string","name":"string"}},"data":{"id":"2","name":"home"}}`,
+			`{"metadata":{"table":"tracks","columns":{"id":"string","event":"string","received_at":"int"}},"data":{"id":"3","event":"clicked"}}`,
+			`{"metadata":{"table":"tracks","columns":{"id":"string"}},"data":{"id":"4"}}`,
+		}, "\n")
+
+		sample, err := sampleStagingEvents(strings.NewReader(stagingFile), 2)
+		Expect(err).To(BeNil())
+		Expect(sample.tableName).To(Equal("tracks"))
+		Expect(sample.tableSchema).To(Equal(warehouseutils.TableSchemaT{
+			"id":          "string",
+			"event":       "string",
+			"received_at": "datetime",
+		}))
+		Expect(sample.rows).To(Equal([]map[string]interface{}{
+			{"id": "1", "received_at": "2022-12-01T00:00:00Z"},
+			{"id": "3", "event": "clicked"},
+		}))
+	})
+
+	DescribeTable("Sample column value", func(columnType string, columnVal, expectedVal interface{}, expectedOk bool) {
+		val, ok := sampleColumnValue(columnType, columnVal)
+		Expect(ok).To(Equal(expectedOk))
+		if !expectedOk {
+			Expect(val).To(BeNil())
+			return
+		}
+		Expect(val).To(Equal(expectedVal))
+	},
+		Entry("missing", "string", nil, nil, false),
+		Entry("int", "int", float64(10), 10, true),
+		Entry("bigint", "bigint", float64(10), 10, true),
+		Entry("float", "float", 1.5, 1.5, true),
+		Entry("int from string", "int", "10", nil, false),
+		Entry("array", "string", []interface{}{"a", "b"}, `["a","b"]`, true),
+		Entry("string", "string", "a", "a", true),
+	)
+
+	It("Estimates throughput", func() {
+		details := throughputDetails(sampleLoadResult{
+			rows:           1000,
+			loadedRows:     1000,
+			bytes:          4000,
+			uploadDuration: time.Second,
+			loadDuration:   time.Second,
+		})
+		Expect(details).To(HaveKeyWithValue("estimatedRowsPerSecond", float64(500)))
+		Expect(details).To(HaveKeyWithValue("estimatedBytesPerSecond", float64(2000)))
+		Expect(details).To(HaveKeyWithValue("loadDuration", "1s"))
+	})
+})
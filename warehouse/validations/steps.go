@@ -24,40 +24,82 @@ func (ct *CTHandleT) validationSteps() []*validationStep {
 	steps := []*validationStep{{
 		ID:        1,
 		Name:      verifyingObjectStorage,
-		Validator: ct.verifyingObjectStorage,
+		Validator: withoutDetails(ct.verifyingObjectStorage),
 	}}
 
 	// Time window destination contains only object storage verification
 	if misc.Contains(warehouseutils.TimeWindowDestinations, ct.infoRequest.Destination.DestinationDefinition.Name) {
-		return steps
+		return append(steps, &validationStep{
+			ID:        2,
+			Name:      verifyingStagingBucketLifecycle,
+			Extended:  true,
+			Validator: ct.verifyingStagingBucketLifecycle,
+		})
 	}
 
 	steps = append(steps,
 		&validationStep{
 			ID:        2,
 			Name:      verifyingConnections,
-			Validator: ct.verifyingConnections,
+			Validator: withoutDetails(ct.verifyingConnections),
 		},
 		&validationStep{
 			ID:        3,
 			Name:      verifyingCreateSchema,
-			Validator: ct.verifyingCreateSchema,
+			Validator: withoutDetails(ct.verifyingCreateSchema),
 		},
 		&validationStep{
 			ID:        4,
 			Name:      verifyingCreateAndAlterTable,
-			Validator: ct.verifyingCreateAlterTable,
+			Validator: withoutDetails(ct.verifyingCreateAlterTable),
 		},
 		&validationStep{
 			ID:        5,
 			Name:      verifyingFetchSchema,
-			Validator: ct.verifyingFetchSchema,
+			Validator: withoutDetails(ct.verifyingFetchSchema),
 		},
 		&validationStep{
 			ID:        6,
 			Name:      verifyingLoadTable,
-			Validator: ct.verifyingLoadTable,
+			Validator: withoutDetails(ct.verifyingLoadTable),
+		},
+		&validationStep{
+			ID:        7,
+			Name:      verifyingPrivileges,
+			Extended:  true,
+			Validator: ct.verifyingPrivileges,
+		},
+		&validationStep{
+			ID:        8,
+			Name:      verifyingStagingBucketLifecycle,
+			Extended:  true,
+			Validator: ct.verifyingStagingBucketLifecycle,
+		},
+		&validationStep{
+			ID:        9,
+			Name:      verifyingClockSkew,
+			Extended:  true,
+			Validator: ct.verifyingClockSkew,
+		},
+		&validationStep{
+			ID:        10,
+			Name:      estimatingLoadThroughput,
+			Extended:  true,
+			Validator: ct.estimatingLoadThroughput,
+		},
+		&validationStep{
+			ID:        11,
+			Name:      verifyingDryRunLoad,
+			Extended:  true,
+			Validator: ct.verifyingDryRunLoad,
 		},
 	)
 	return steps
 }
+
+// withoutDetails adapts a step reporting no structured results to a validator
+func withoutDetails(f func() error) validator {
+	return func() (stepDetails, error) {
+		return nil, f()
+	}
+}
@@ -1,6 +1,8 @@
 package validations
 
 import (
+	"encoding/json"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
@@ -30,6 +32,7 @@ var _ = Describe("Steps", func() {
 	},
 		Entry("S3_DATALAKE", "S3_DATALAKE", []string{
 			verifyingObjectStorage,
+			verifyingStagingBucketLifecycle,
 		}),
 		Entry("RS", "RS", []string{
 			verifyingObjectStorage,
@@ -38,6 +41,37 @@ var _ = Describe("Steps", func() {
 			verifyingCreateAndAlterTable,
 			verifyingFetchSchema,
 			verifyingLoadTable,
+			verifyingPrivileges,
+			verifyingStagingBucketLifecycle,
+			verifyingClockSkew,
+			estimatingLoadThroughput,
+			verifyingDryRunLoad,
 		}),
 	)
+
+	DescribeTable("Extended validation steps", func(step string, extended bool, expectedSteps []string) {
+		req, err := json.Marshal(DestinationValidationRequest{
+			Destination: backendconfig.DestinationT{
+				DestinationDefinition: backendconfig.DestinationDefinitionT{
+					Name: "S3_DATALAKE",
+				},
+			},
+			Extended: extended,
+		})
+		Expect(err).To(BeNil())
+
+		ct := &CTHandleT{infoRequest: &DestinationValidationRequest{}}
+		Expect(json.Unmarshal(req, ct.infoRequest)).To(BeNil())
+
+		var names []string
+		for _, s := range ct.selectedSteps(step) {
+			names = append(names, s.Name)
+		}
+		Expect(names).To(Equal(expectedSteps))
+	},
+		Entry("all steps", "", false, []string{verifyingObjectStorage}),
+		Entry("all extended steps", "", true, []string{verifyingObjectStorage, verifyingStagingBucketLifecycle}),
+		Entry("extended step by id", "2", false, []string{verifyingStagingBucketLifecycle}),
+		Entry("invalid step", "3", false, nil),
+	)
 })
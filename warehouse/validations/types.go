@@ -1,6 +1,7 @@
 package validations
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -19,17 +20,26 @@ type validationFunc struct {
 
 type DestinationValidationRequest struct {
 	Destination backendconfig.DestinationT `json:"destination"`
+	// Extended runs the extended steps too when validating all steps
+	Extended bool `json:"extended,omitempty"`
 }
 
 type validationStep struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	Success   bool      `json:"success"`
-	Error     string    `json:"error"`
-	Validator validator `json:"-"`
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	// Extended steps are only run if requested explicitly, either by their id or with an extended validation request,
+	// since they are costly or don't verify the credentials of the destination.
+	Extended  bool        `json:"extended,omitempty"`
+	Details   stepDetails `json:"details,omitempty"`
+	Validator validator   `json:"-"`
 }
 
-type validator func() error
+// stepDetails are the structured results of a validation step
+type stepDetails map[string]interface{}
+
+type validator func() (stepDetails, error)
 
 type validationStepsResponse struct {
 	Steps []*validationStep `json:"steps"`
@@ -84,6 +94,8 @@ type DestinationValidationResponse struct {
 }
 
 type CTHandleT struct {
+	// DB is used to look up the staging files of previous uploads, if set
+	DB               *sql.DB
 	infoRequest      *DestinationValidationRequest
 	warehouse        warehouseutils.Warehouse
 	manager          manager.WarehouseOperations
@@ -39,36 +39,16 @@ func (ct *CTHandleT) validateDestinationFunc(req json.RawMessage, step string) (
 	)
 
 	resp := DestinationValidationResponse{}
-	// check if req has specified a step in query params
-	if step != "" {
-		stepI, err := strconv.Atoi(step)
-		if err != nil {
-			resp.Error = fmt.Sprintf("%s: %s", warehouseutils.CTInvalidStep, step)
-			return json.Marshal(resp)
-		}
-
-		// get validation step
-		var v *validationStep
-		for _, s := range ct.validationSteps() {
-			if s.ID == stepI {
-				v = s
-				break
-			}
-		}
-
-		if v == nil {
-			resp.Error = fmt.Sprintf("%s: %s", warehouseutils.CTInvalidStep, step)
-			return json.Marshal(resp)
-		}
-
-		resp.Steps = append(resp.Steps, v)
-	} else {
-		resp.Steps = ct.validationSteps()
+	resp.Steps = ct.selectedSteps(step)
+	if len(resp.Steps) == 0 {
+		resp.Error = fmt.Sprintf("%s: %s", warehouseutils.CTInvalidStep, step)
+		return json.Marshal(resp)
 	}
 
 	// Iterate over all selected steps and validate
 	for idx, s := range resp.Steps {
-		stepError := s.Validator()
+		details, stepError := s.Validator()
+		resp.Steps[idx].Details = details
 		if stepError != nil {
 			resp.Steps[idx].Error = stepError.Error()
 			pkgLogger.Errorf("error occurred while destination configuration validation for destinationId: %s, destinationType: %s, step: %s with error: %s",
@@ -97,12 +77,38 @@ func (ct *CTHandleT) validateDestinationFunc(req json.RawMessage, step string) (
 	return json.Marshal(resp)
 }
 
+// selectedSteps returns the step specified in query params, if any, or all the steps to validate otherwise.
+// Extended steps are only part of all the steps for extended validation requests.
+func (ct *CTHandleT) selectedSteps(step string) []*validationStep {
+	if step == "" {
+		var steps []*validationStep
+		for _, s := range ct.validationSteps() {
+			if !s.Extended || ct.infoRequest.Extended {
+				steps = append(steps, s)
+			}
+		}
+		return steps
+	}
+
+	stepI, err := strconv.Atoi(step)
+	if err != nil {
+		return nil
+	}
+	for _, s := range ct.validationSteps() {
+		if s.ID == stepI {
+			return []*validationStep{s}
+		}
+	}
+	return nil
+}
+
 func (ct *CTHandleT) verifyingObjectStorage() (err error) {
 	// creating load file
 	tempPath, err := CreateTempLoadFile(ct.infoRequest)
 	if err != nil {
 		return
 	}
+	defer misc.RemoveFilePaths(tempPath)
 
 	// uploading load file to object storage
 	uploadOutput, err := uploadLoadFile(ct.infoRequest, tempPath)
@@ -116,6 +122,13 @@ func (ct *CTHandleT) verifyingObjectStorage() (err error) {
 }
 
 func (ct *CTHandleT) initManager() (err error) {
+	return ct.initManagerWithUploader(&CTUploadJob{
+		infoRequest: ct.infoRequest,
+	})
+}
+
+// initManagerWithUploader sets up the manager with the uploader providing the schema and load files of the tables to load
+func (ct *CTHandleT) initManagerWithUploader(uploader warehouseutils.UploaderI) (err error) {
 	ct.warehouse = warehouse(ct.infoRequest)
 
 	// adding ssh tunnelling info, given we have
@@ -140,9 +153,7 @@ func (ct *CTHandleT) initManager() (err error) {
 	ct.manager.SetConnectionTimeout(warehouseutils.TestConnectionTimeout)
 
 	// setting up the manager
-	err = ct.manager.Setup(ct.warehouse, &CTUploadJob{
-		infoRequest: ct.infoRequest,
-	})
+	err = ct.manager.Setup(ct.warehouse, uploader)
 	return
 }
 
@@ -214,6 +225,7 @@ func (ct *CTHandleT) verifyingLoadTable() (err error) {
 	if err != nil {
 		return
 	}
+	defer misc.RemoveFilePaths(tempPath)
 
 	// uploading load file
 	uploadOutput, err := uploadLoadFile(ct.infoRequest, tempPath)
@@ -297,7 +309,6 @@ func uploadLoadFile(req *DestinationValidationRequest, filePath string) (uploadO
 	}
 
 	// cleanup
-	defer misc.RemoveFilePaths(filePath)
 	defer func() { _ = uploadFile.Close() }()
 
 	// uploading file to object storage
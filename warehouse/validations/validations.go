@@ -17,6 +17,13 @@ var (
 	pkgLogger               logger.Logger
 	fileManagerFactory      filemanager.FileManagerFactory
 	fileManagerTimeout      time.Duration
+
+	stagingFolderName           string
+	maxClockSkew                time.Duration
+	minStagingFileRetention     time.Duration
+	throughputSampleRows        int
+	dryRunSampleRows            int
+	maxStagingFileReadBufferInK int
 )
 
 var (
@@ -41,6 +48,12 @@ const (
 	verifyingCreateAndAlterTable = "Verifying Create and Alter Table"
 	verifyingFetchSchema         = "Verifying Fetch Schema"
 	verifyingLoadTable           = "Verifying Load Table"
+
+	verifyingPrivileges             = "Verifying Privileges"
+	verifyingStagingBucketLifecycle = "Verifying Staging Bucket Lifecycle"
+	verifyingClockSkew              = "Verifying Clock Skew"
+	estimatingLoadThroughput        = "Estimating Load Throughput"
+	verifyingDryRunLoad             = "Verifying Dry Run Load"
 )
 
 func Init() {
@@ -48,6 +61,13 @@ func Init() {
 	pkgLogger = logger.NewLogger().Child("warehouse").Child("validations")
 	fileManagerFactory = filemanager.DefaultFileManagerFactory
 	fileManagerTimeout = 15 * time.Second
+
+	stagingFolderName = config.GetString("WAREHOUSE_STAGING_BUCKET_FOLDER_NAME", "rudder-warehouse-staging-logs")
+	maxClockSkew = config.GetDuration("Warehouse.validations.maxClockSkew", 5, time.Minute)
+	minStagingFileRetention = config.GetDuration("Warehouse.validations.minStagingFileRetention", 72, time.Hour)
+	throughputSampleRows = config.GetInt("Warehouse.validations.throughputSampleRows", 1000)
+	dryRunSampleRows = config.GetInt("Warehouse.validations.dryRunSampleRows", 100)
+	maxStagingFileReadBufferInK = config.GetInt("Warehouse.maxStagingFileReadBufferCapacityInK", 10240)
 }
 
 // Validating Facade for Global invoking validation
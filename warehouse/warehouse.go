@@ -87,6 +87,7 @@ var (
 	numLoadFileUploadWorkers            int
 	slaveUploadTimeout                  time.Duration
 	tableCountQueryTimeout              time.Duration
+	identityMappingsExportTimeout       time.Duration
 	runningMode                         string
 	uploadStatusTrackFrequency          time.Duration
 	uploadAllocatorSleep                time.Duration
@@ -98,6 +99,7 @@ var (
 	maxParallelJobCreation              int
 	enableJitterForSyncs                bool
 	asyncWh                             *jobs.AsyncJobWhT
+	schemaDriftWh                       *schemaDriftDetector
 	configBackendURL                    string
 	enableTunnelling                    bool
 )
@@ -209,6 +211,9 @@ func loadConfig() {
 	config.RegisterIntConfigVariable(8, &maxParallelJobCreation, true, 1, "Warehouse.maxParallelJobCreation")
 	config.RegisterBoolConfigVariable(false, &enableJitterForSyncs, true, "Warehouse.enableJitterForSyncs")
 	config.RegisterDurationConfigVariable(30, &tableCountQueryTimeout, true, time.Second, []string{"Warehouse.tableCountQueryTimeout", "Warehouse.tableCountQueryTimeoutInS"}...)
+	config.RegisterDurationConfigVariable(10, &identityMappingsExportTimeout, true, time.Minute, "Warehouse.identityMappingsExportTimeout")
+	loadSchemaDriftConfig()
+	loadPartialExportConfig()
 
 	appName = misc.DefaultString("rudder-server").OnError(os.Hostname())
 }
@@ -458,6 +463,7 @@ func (wh *HandleT) getPendingStagingFiles(ctx context.Context, warehouse warehou
 	  UT.destination_type = '%[2]s'
 	  AND UT.source_id = '%[3]s'
 	  AND UT.destination_id = '%[4]s'
+	  AND UT.metadata ->> 'backfill_id' IS NULL
 	ORDER BY
 	  UT.id DESC;
 `,
@@ -1058,6 +1064,7 @@ func (wh *HandleT) getUploadsToProcess(ctx context.Context, availableWorkers int
 		upload.SourceJobRunID = gjson.GetBytes(upload.Metadata, "source_job_run_id").String()
 		// load file type
 		upload.LoadFileType = gjson.GetBytes(upload.Metadata, "load_file_type").String()
+		upload.BackfillID = gjson.GetBytes(upload.Metadata, "backfill_id").String()
 
 		_, upload.FirstAttemptAt = warehouseutils.TimingFromJSONString(firstTiming)
 		var lastStatus string
@@ -1098,13 +1105,25 @@ func (wh *HandleT) getUploadsToProcess(ctx context.Context, availableWorkers int
 		upload.SourceType = warehouse.Source.SourceDefinition.Name
 		upload.SourceCategory = warehouse.Source.SourceDefinition.Category
 
-		stagingFilesList, err := wh.stagingRepo.GetInRange(
-			ctx,
-			warehouse.Source.ID,
-			warehouse.Destination.ID,
-			upload.StartStagingFileID,
-			upload.EndStagingFileID,
-		)
+		var stagingFilesList []model.StagingFile
+		if upload.BackfillID != "" {
+			// backfills can load into a namespace other than the configured one
+			warehouse.Namespace = upload.Namespace
+			stagingFilesList, err = wh.stagingRepo.GetForBackfill(
+				ctx,
+				warehouse.Source.ID,
+				warehouse.Destination.ID,
+				upload.BackfillID,
+			)
+		} else {
+			stagingFilesList, err = wh.stagingRepo.GetInRange(
+				ctx,
+				warehouse.Source.ID,
+				warehouse.Destination.ID,
+				upload.StartStagingFileID,
+				upload.EndStagingFileID,
+			)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -1797,7 +1816,8 @@ func getPendingStagingFileCount(sourceOrDestId string, isSourceId bool) (fileCou
 		FROM
 		  %[1]s
 		WHERE
-		  %[2]s = $1;
+		  %[2]s = $1
+		  AND metadata ->> 'backfill_id' IS NULL;
 `,
 		warehouseutils.WarehouseUploadsTable,
 		sourceOrDestColumn,
@@ -1819,7 +1839,8 @@ func getPendingStagingFileCount(sourceOrDestId string, isSourceId bool) (fileCou
 		  %[1]s
 		WHERE
 		  id > %[2]v
-		  AND %[3]s = $1;
+		  AND %[3]s = $1
+		  AND metadata ->> 'backfill_id' IS NULL;
 `,
 		warehouseutils.WarehouseStagingFilesTable,
 		lastStagingFileID,
@@ -2049,6 +2070,26 @@ func startWebHandler(ctx context.Context) error {
 			// Warehouse Async Job end-points
 			mux.HandleFunc("/v1/warehouse/jobs", asyncWh.AddWarehouseJobHandler)           // FIXME: add degraded mode
 			mux.HandleFunc("/v1/warehouse/jobs/status", asyncWh.StatusWarehouseJobHandler) // FIXME: add degraded mode
+			mux.HandleFunc("/v1/warehouse/schema-drift", schemaDriftWh.schemaDriftHandler)
+			mux.HandleFunc("/v1/warehouse/schedule", uploadScheduleHandler)
+			mux.HandleFunc("/v1/warehouse/load-costs", loadCostsHandler)
+			mux.HandleFunc("/v1/warehouse/stream", newStreamer().streamingHandler)
+			mux.HandleFunc("/v1/warehouse/identity", identityResolveHandler)
+			mux.HandleFunc("/v1/warehouse/identity/export", identityExportHandler)
+			mux.HandleFunc("/v1/warehouse/schema", schemaHandler)
+			mux.HandleFunc("/v1/warehouse/jobs/backfill", (&backfiller{
+				db: dbHandle,
+				archive: &archive.Archiver{
+					DB:          dbHandle,
+					Stats:       stats.Default,
+					Logger:      pkgLogger.Child("archiver"),
+					FileManager: filemanager.DefaultFileManagerFactory,
+					Multitenant: tenantManager,
+				},
+				stagingRepo: &repo.StagingFiles{
+					DB: dbHandle,
+				},
+			}).backfillHandler)
 
 			pkgLogger.Infof("WH: Starting warehouse master service in %d", webPort)
 		} else {
@@ -2269,6 +2310,12 @@ func Start(ctx context.Context, app app.App) error {
 		asyncWh = jobs.InitWarehouseJobsAPI(ctx, dbHandle, &notifier)
 		jobs.WithConfig(asyncWh, config.Default)
 
+		schemaDriftWh = newSchemaDriftDetector(dbHandle, stats.Default)
+		g.Go(misc.WithBugsnagForWarehouse(func() error {
+			schemaDriftWh.run(ctx)
+			return nil
+		}))
+
 		g.Go(misc.WithBugsnagForWarehouse(func() error {
 			return asyncWh.InitAsyncJobRunner()
 		}))
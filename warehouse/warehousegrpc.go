@@ -96,6 +96,7 @@ func (*warehouseGRPC) TriggerWHUpload(_ context.Context, request *proto.WHUpload
 
 func (grpc *warehouseGRPC) Validate(_ context.Context, req *proto.WHValidationRequest) (*proto.WHValidationResponse, error) {
 	handleT := validations.CTHandleT{
+		DB:               dbHandle,
 		EnableTunnelling: grpc.EnableTunnelling,
 		CPClient:         grpc.CPClient,
 	}